@@ -112,8 +112,16 @@ type DecodeContext struct {
 	// error.
 	defaultDocumentType reflect.Type
 
+	// defaultNumberType specifies the Go type to decode BSON "int32", "int64", and "double" values into when the
+	// destination is typed as "interface{}". If nil, each BSON type is decoded into its corresponding Go type.
+	defaultNumberType reflect.Type
+
 	binaryAsSlice bool
 
+	// canonicalExtJSON causes json.RawMessage values to be decoded as canonical Extended JSON instead of relaxed
+	// Extended JSON.
+	canonicalExtJSON bool
+
 	// a false value results in a decoding error.
 	objectIDAsHexString bool
 
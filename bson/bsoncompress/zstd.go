@@ -0,0 +1,74 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package bsoncompress provides a zstd bson.FieldCompressor for struct fields with a
+// `bsonCompress:"zstd"` struct tag. It is a separate package so that the bson package does not
+// depend on a compression library. Register the compressor on a registry and configure the
+// registry on a mongo.Client with the SetRegistry option:
+//
+//	reg := bson.NewRegistry()
+//	bsoncompress.Register(reg)
+//	opts := options.Client().SetRegistry(reg)
+package bsoncompress
+
+import (
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Zstd is the name under which Register registers the zstd FieldCompressor.
+const Zstd = "zstd"
+
+// Register registers the zstd FieldCompressor on reg under the name "zstd".
+func Register(reg *bson.Registry) {
+	reg.RegisterFieldCompressor(Zstd, zstdCompressor{})
+}
+
+var (
+	zstdEncoderOnce sync.Once
+	zstdEncoder     *zstd.Encoder
+	zstdEncoderErr  error
+
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+)
+
+func getZstdEncoder() (*zstd.Encoder, error) {
+	zstdEncoderOnce.Do(func() {
+		zstdEncoder, zstdEncoderErr = zstd.NewWriter(nil)
+	})
+	return zstdEncoder, zstdEncoderErr
+}
+
+func getZstdDecoder() (*zstd.Decoder, error) {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, zstdDecoderErr = zstd.NewReader(nil)
+	})
+	return zstdDecoder, zstdDecoderErr
+}
+
+type zstdCompressor struct{}
+
+// Compress implements the bson.FieldCompressor interface.
+func (zstdCompressor) Compress(dst, src []byte) ([]byte, error) {
+	enc, err := getZstdEncoder()
+	if err != nil {
+		return nil, err
+	}
+	return enc.EncodeAll(src, dst), nil
+}
+
+// Decompress implements the bson.FieldCompressor interface.
+func (zstdCompressor) Decompress(src []byte) ([]byte, error) {
+	dec, err := getZstdDecoder()
+	if err != nil {
+		return nil, err
+	}
+	return dec.DecodeAll(src, nil)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bsoncompress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestZstd(t *testing.T) {
+	t.Parallel()
+
+	type compressed struct {
+		ID      int32
+		Payload string `bson:"payload" bsonCompress:"zstd"`
+	}
+
+	reg := bson.NewRegistry()
+	Register(reg)
+
+	payload := strings.Repeat(`{"key":"value","n":12345},`, 10000)
+	in := compressed{ID: 1, Payload: payload}
+
+	buf := new(bytes.Buffer)
+	enc := bson.NewEncoder(bson.NewDocumentWriter(buf))
+	enc.SetRegistry(reg)
+	require.NoError(t, enc.Encode(in), "Encode error")
+	assert.Less(t, buf.Len(), len(payload)/10, "expected the marshaled document to be compressed")
+
+	val := bson.Raw(buf.Bytes()).Lookup("payload")
+	subtype, data, ok := val.BinaryOK()
+	require.True(t, ok, "expected payload to be binary, got %v", val.Type)
+	assert.Equal(t, bson.TypeBinaryUserDefined, subtype)
+	assert.Equal(t, byte(bson.TypeString), data[0])
+
+	var out compressed
+	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(buf.Bytes())))
+	dec.SetRegistry(reg)
+	require.NoError(t, dec.Decode(&out), "Decode error")
+	assert.Equal(t, in, out)
+}
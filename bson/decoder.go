@@ -93,6 +93,14 @@ func (d *Decoder) DefaultDocumentM() {
 	d.dc.defaultDocumentType = reflect.TypeOf(M{})
 }
 
+// DefaultNumberType causes the Decoder to unmarshal BSON "int32", "int64", and "double" values into
+// t when the destination is typed as "interface{}" (e.g. the values of a bson.M). t must be a Go
+// integer or float type. Decoding a BSON "double" with a fractional part into an integer type
+// returns an error unless AllowTruncatingDoubles is also set.
+func (d *Decoder) DefaultNumberType(t reflect.Type) {
+	d.dc.defaultNumberType = t
+}
+
 // AllowTruncatingDoubles causes the Decoder to truncate the fractional part of BSON "double" values
 // when attempting to unmarshal them into a Go integer (int, int8, int16, int32, or int64) struct
 // field. The truncation logic does not apply to BSON "decimal128" values.
@@ -106,6 +114,13 @@ func (d *Decoder) BinaryAsSlice() {
 	d.dc.binaryAsSlice = true
 }
 
+// CanonicalExtJSON causes the Decoder to unmarshal BSON documents into json.RawMessage values as
+// canonical Extended JSON instead of relaxed Extended JSON. It only has an effect if the codec
+// registered by RegisterJSONRawMessage is used.
+func (d *Decoder) CanonicalExtJSON() {
+	d.dc.canonicalExtJSON = true
+}
+
 // ObjectIDAsHexString causes the Decoder to decode object IDs to their hex representation.
 func (d *Decoder) ObjectIDAsHexString() {
 	d.dc.objectIDAsHexString = true
@@ -559,6 +559,50 @@ func TestDecoderConfiguration(t *testing.T) {
 				{Key: "myDocument", Value: M{"myString": "test value"}},
 			},
 		},
+		// Test that DefaultNumberType causes the Decoder to decode all BSON numeric values into the
+		// configured Go type when there is no type information.
+		{
+			description: "DefaultNumberType float64",
+			configure: func(dec *Decoder) {
+				dec.DefaultNumberType(reflect.TypeOf(float64(0)))
+			},
+			input: bsoncore.NewDocumentBuilder().
+				AppendInt32("myInt32", 1).
+				AppendInt64("myInt64", 2).
+				AppendDouble("myDouble", 3.5).
+				AppendString("myString", "test value").
+				Build(),
+			decodeInto: func() interface{} { return M{} },
+			want: M{
+				"myInt32":  float64(1),
+				"myInt64":  float64(2),
+				"myDouble": float64(3.5),
+				"myString": "test value",
+			},
+		},
+		{
+			description: "DefaultNumberType int64 with AllowTruncatingDoubles",
+			configure: func(dec *Decoder) {
+				dec.DefaultNumberType(reflect.TypeOf(int64(0)))
+				dec.AllowTruncatingDoubles()
+			},
+			input: bsoncore.NewDocumentBuilder().
+				AppendInt32("myInt32", 1).
+				AppendInt64("myInt64", 2).
+				AppendDouble("myDouble", 3.5).
+				AppendArray("myArray", bsoncore.NewArrayBuilder().
+					AppendInt32(4).
+					AppendDouble(5).
+					Build()).
+				Build(),
+			decodeInto: func() interface{} { return M{} },
+			want: M{
+				"myInt32":  int64(1),
+				"myInt64":  int64(2),
+				"myDouble": int64(3),
+				"myArray":  A{int64(4), int64(5)},
+			},
+		},
 		// Test that ObjectIDAsHexString causes the Decoder to decode object ID to hex.
 		{
 			description: "ObjectIDAsHexString",
@@ -673,6 +717,20 @@ func TestDecoderConfiguration(t *testing.T) {
 		const want = "error decoding key id: decoding an object ID into a string is not supported by default (set Decoder.ObjectIDAsHexString to enable decoding as a hexadecimal string)"
 		assert.EqualError(t, err, want)
 	})
+	t.Run("DefaultNumberType int64 without truncation", func(t *testing.T) {
+		t.Parallel()
+
+		input := bsoncore.NewDocumentBuilder().
+			AppendDouble("myDouble", 3.5).
+			Build()
+
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(input)))
+		dec.DefaultNumberType(reflect.TypeOf(int64(0)))
+
+		var got M
+		err := dec.Decode(&got)
+		assert.ErrorIs(t, err, errCannotTruncate)
+	})
 	t.Run("DefaultDocumentM top-level", func(t *testing.T) {
 		t.Parallel()
 
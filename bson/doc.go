@@ -108,6 +108,27 @@
 //     error will be returned. This tag can be used with fields that are pointers to structs. If an inlined pointer field
 //     is nil, it will not be marshaled. For fields that are not maps or structs, this tag is ignored.
 //
+//  5. objectid: If the objectid struct tag is specified on a string field, the field holds the hexadecimal
+//     representation of an ObjectID. When marshaling, the string is validated and stored as a BSON ObjectID; an error
+//     wrapping [ErrInvalidHex] is returned if it is not a 24-character hexadecimal string. When unmarshaling, a BSON
+//     ObjectID is decoded as its hexadecimal representation, as if [Decoder.ObjectIDAsHexString] were set. Using this
+//     tag on a field that is not a string returns an error.
+//
+// A field's value can also be compressed by adding a separate "bsonCompress" struct tag whose value names a
+// [FieldCompressor] registered on the [Registry] with [Registry.RegisterFieldCompressor]. No FieldCompressor is
+// registered by default; the bson/bsoncompress package registers a zstd FieldCompressor under the name "zstd". For
+// example:
+//
+//	type Event struct {
+//	    ID      string
+//	    Payload string `bson:"payload" bsonCompress:"zstd"`
+//	}
+//
+// When marshaling, the field's value is encoded as usual, compressed, and stored as a BSON binary value with subtype
+// [TypeBinaryUserDefined]. When unmarshaling, the binary value is decompressed and decoded into the field. This is
+// useful for large values such as JSON blobs stored as strings. Note that the server only sees opaque binary data for
+// compressed fields, so they cannot be queried, indexed, or used in aggregations by their original value.
+//
 // # Raw BSON
 //
 // The Raw family of types is used to validate and retrieve elements from a slice of bytes. This
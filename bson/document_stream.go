@@ -0,0 +1,124 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrTruncatedDocument is returned by a DocumentStream when the stream ends partway through a
+// document.
+var ErrTruncatedDocument = errors.New("truncated BSON document")
+
+// defaultMaxStreamDocumentSize is the default maximum size of a document read by a
+// DocumentStream. It is the server's maximum BSON document size plus the 16KiB of headroom the
+// server allows for internal documents, such as those in the oplog.
+const defaultMaxStreamDocumentSize = 16*1024*1024 + 16*1024
+
+// DocumentStream reads a sequence of length-prefixed BSON documents from an io.Reader, such as
+// a file written by mongodump. Documents are read one at a time using Next:
+//
+//	ds := bson.NewDocumentStream(f)
+//	for ds.Next() {
+//		doc := ds.Current()
+//		// ...
+//	}
+//	if err := ds.Err(); err != nil {
+//		// ...
+//	}
+type DocumentStream struct {
+	r       io.Reader
+	current Raw
+	offset  int64
+	maxSize int32
+	err     error
+}
+
+// NewDocumentStream creates a DocumentStream that reads documents from r.
+func NewDocumentStream(r io.Reader) *DocumentStream {
+	return &DocumentStream{r: r, maxSize: defaultMaxStreamDocumentSize}
+}
+
+// SetMaxDocumentSize sets the maximum size in bytes of a document read from the stream. Next
+// fails without allocating the document if its length prefix is larger. The default is 16MiB
+// plus 16KiB, the largest document the server can store.
+func (ds *DocumentStream) SetMaxDocumentSize(size int32) {
+	ds.maxSize = size
+}
+
+// Next reads the next document from the stream. It returns false when the end of the stream is
+// reached or an error occurs. Err should be called after Next returns false to distinguish
+// between the two cases.
+func (ds *DocumentStream) Next() bool {
+	ds.current = nil
+	if ds.err != nil {
+		return false
+	}
+	if ds.r == nil {
+		ds.err = ErrNilReader
+		return false
+	}
+
+	var lengthBytes [4]byte
+	n, err := io.ReadFull(ds.r, lengthBytes[:])
+	switch {
+	case err == io.EOF:
+		return false
+	case err == io.ErrUnexpectedEOF:
+		ds.err = fmt.Errorf("%w at offset %d: read %d of 4 length bytes", ErrTruncatedDocument, ds.offset, n)
+		return false
+	case err != nil:
+		ds.err = err
+		return false
+	}
+
+	length := int32(binary.LittleEndian.Uint32(lengthBytes[:]))
+	if length < 5 {
+		ds.err = fmt.Errorf("invalid BSON document length %d at offset %d", length, ds.offset)
+		return false
+	}
+	if length > ds.maxSize {
+		ds.err = fmt.Errorf("BSON document length %d at offset %d exceeds the maximum document size of %d bytes",
+			length, ds.offset, ds.maxSize)
+		return false
+	}
+
+	doc := make([]byte, length)
+	copy(doc, lengthBytes[:])
+	n, err = io.ReadFull(ds.r, doc[4:])
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		ds.err = fmt.Errorf("%w at offset %d: read %d of %d bytes", ErrTruncatedDocument, ds.offset, n+4, length)
+		return false
+	case err != nil:
+		ds.err = err
+		return false
+	}
+	if doc[length-1] != 0x00 {
+		ds.err = fmt.Errorf("BSON document at offset %d is missing null terminator", ds.offset)
+		return false
+	}
+
+	ds.offset += int64(length)
+	ds.current = doc
+	return true
+}
+
+// Current returns the document read by the most recent successful call to Next. The returned
+// document is not reused by subsequent calls to Next.
+func (ds *DocumentStream) Current() Raw {
+	return ds.current
+}
+
+// Err returns the first error encountered while reading the stream. It returns nil if the
+// stream ended cleanly after a complete document.
+func (ds *DocumentStream) Err() error {
+	return ds.err
+}
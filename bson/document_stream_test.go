@@ -0,0 +1,114 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestDocumentStream(t *testing.T) {
+	t.Parallel()
+
+	var docs []Raw
+	var stream []byte
+	for i := int32(0); i < 3; i++ {
+		b, err := Marshal(D{{"_id", i}, {"name", "doc"}})
+		require.NoError(t, err, "Marshal error")
+		docs = append(docs, b)
+		stream = append(stream, b...)
+	}
+
+	readAll := func(ds *DocumentStream) []Raw {
+		var got []Raw
+		for ds.Next() {
+			got = append(got, ds.Current())
+		}
+		return got
+	}
+
+	t.Run("multiple documents", func(t *testing.T) {
+		t.Parallel()
+
+		ds := NewDocumentStream(bytes.NewReader(stream))
+		got := readAll(ds)
+		require.NoError(t, ds.Err(), "unexpected error")
+		assert.Equal(t, docs, got)
+		assert.False(t, ds.Next(), "expected Next to return false after the end of the stream")
+	})
+	t.Run("empty stream", func(t *testing.T) {
+		t.Parallel()
+
+		ds := NewDocumentStream(bytes.NewReader(nil))
+		assert.False(t, ds.Next(), "expected Next to return false for an empty stream")
+		assert.NoError(t, ds.Err(), "unexpected error")
+	})
+	t.Run("truncated tail", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name string
+			tail []byte
+		}{
+			{"partial length", docs[0][:2]},
+			{"partial body", docs[0][:len(docs[0])-3]},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				truncated := append(append([]byte{}, stream...), tc.tail...)
+				ds := NewDocumentStream(bytes.NewReader(truncated))
+				got := readAll(ds)
+				assert.Equal(t, docs, got, "expected all complete documents to be read")
+				assert.True(t, errors.Is(ds.Err(), ErrTruncatedDocument), "expected ErrTruncatedDocument, got %v", ds.Err())
+				assert.Nil(t, ds.Current(), "expected no current document after an error")
+			})
+		}
+	})
+	t.Run("invalid length", func(t *testing.T) {
+		t.Parallel()
+
+		ds := NewDocumentStream(bytes.NewReader([]byte{0x02, 0x00, 0x00, 0x00}))
+		assert.False(t, ds.Next(), "expected Next to return false for an invalid length")
+		assert.Error(t, ds.Err(), "expected an error for an invalid length")
+	})
+	t.Run("length exceeds the maximum document size", func(t *testing.T) {
+		t.Parallel()
+
+		ds := NewDocumentStream(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0x7F}))
+		assert.False(t, ds.Next(), "expected Next to return false for a length over the default maximum")
+		assert.ErrorContains(t, ds.Err(), "exceeds the maximum document size")
+
+		doc := D{{"x", int32(1)}}
+		b, err := Marshal(doc)
+		require.NoError(t, err, "Marshal error")
+
+		ds = NewDocumentStream(bytes.NewReader(b))
+		ds.SetMaxDocumentSize(int32(len(b)) - 1)
+		assert.False(t, ds.Next(), "expected Next to return false for a length over the configured maximum")
+		assert.ErrorContains(t, ds.Err(), "exceeds the maximum document size")
+
+		ds = NewDocumentStream(bytes.NewReader(b))
+		ds.SetMaxDocumentSize(int32(len(b)))
+		require.True(t, ds.Next(), "Next error: %v", ds.Err())
+		assert.Equal(t, Raw(b), ds.Current(), "unexpected document")
+	})
+	t.Run("missing null terminator", func(t *testing.T) {
+		t.Parallel()
+
+		ds := NewDocumentStream(bytes.NewReader([]byte{0x05, 0x00, 0x00, 0x00, 0x01}))
+		assert.False(t, ds.Next(), "expected Next to return false for a missing null terminator")
+		assert.Error(t, ds.Err(), "expected an error for a missing null terminator")
+	})
+}
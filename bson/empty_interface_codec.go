@@ -40,6 +40,13 @@ func (eic *emptyInterfaceCodec) EncodeValue(ec EncodeContext, vw ValueWriter, va
 }
 
 func (eic *emptyInterfaceCodec) getEmptyInterfaceDecodeType(dc DecodeContext, valueType Type) (reflect.Type, error) {
+	if dc.defaultNumberType != nil {
+		switch valueType {
+		case TypeInt32, TypeInt64, TypeDouble:
+			return dc.defaultNumberType, nil
+		}
+	}
+
 	isDocument := valueType == Type(0) || valueType == TypeEmbeddedDocument
 	if isDocument {
 		if dc.defaultDocumentType != nil {
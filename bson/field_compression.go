@@ -0,0 +1,104 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// FieldCompressor compresses and decompresses the values of struct fields with a "bsonCompress" struct tag. A
+// FieldCompressor is registered on a Registry with RegisterFieldCompressor under the name used in the struct tag.
+// Implementations must be safe for concurrent use.
+type FieldCompressor interface {
+	// Compress appends the compressed form of src to dst and returns the result.
+	Compress(dst, src []byte) ([]byte, error)
+
+	// Decompress returns the decompressed form of src.
+	Decompress(src []byte) ([]byte, error)
+}
+
+// RegisterFieldCompressor registers the provided FieldCompressor for struct fields tagged with
+// `bsonCompress:"<name>"`. No FieldCompressor is registered by default; the bson/bsoncompress package provides a
+// zstd FieldCompressor.
+//
+// RegisterFieldCompressor should not be called concurrently with any other Registry method.
+func (r *Registry) RegisterFieldCompressor(name string, fc FieldCompressor) {
+	r.fieldCompressors.Store(name, fc)
+}
+
+// lookupFieldCompressor returns the FieldCompressor registered for name.
+func (r *Registry) lookupFieldCompressor(name string) (FieldCompressor, error) {
+	if fc, ok := r.fieldCompressors.Load(name); ok {
+		return fc.(FieldCompressor), nil
+	}
+	return nil, fmt.Errorf("no FieldCompressor registered for bsonCompress value %q", name)
+}
+
+// encodeCompressedValue encodes val with enc and writes the result, compressed with the
+// FieldCompressor registered for compress, to vw as a BSON binary value with subtype
+// TypeBinaryUserDefined. The first byte of the binary data is the BSON type of the encoded value
+// and the remaining bytes are the compressed value.
+func encodeCompressedValue(ec EncodeContext, vw ValueWriter, compress string, enc ValueEncoder, val reflect.Value) error {
+	fc, err := ec.lookupFieldCompressor(compress)
+	if err != nil {
+		return err
+	}
+
+	evw := vwPool.Get().(*valueWriter)
+	defer putValueWriter(evw)
+
+	evw.reset(nil)
+	evw.push(mElement)
+	if err := enc.EncodeValue(ec, evw, val); err != nil {
+		return err
+	}
+
+	// The encoded element has an empty key, so the value starts after the type byte and the
+	// key's null terminator.
+	t, value := Type(evw.buf[0]), evw.buf[2:]
+
+	data := make([]byte, 1, 1+len(value)/2)
+	data[0] = byte(t)
+	data, err = fc.Compress(data, value)
+	if err != nil {
+		return fmt.Errorf("error compressing field value: %w", err)
+	}
+
+	return vw.WriteBinaryWithSubtype(data, TypeBinaryUserDefined)
+}
+
+// decodeCompressedValue reads a value written by encodeCompressedValue from vr, decompresses it
+// with the FieldCompressor registered for compress and decodes it into val with dec.
+func decodeCompressedValue(dc DecodeContext, vr ValueReader, compress string, dec ValueDecoder, val reflect.Value) error {
+	if vr.Type() != TypeBinary {
+		return fmt.Errorf("cannot decode %v into a compressed field, expected binary", vr.Type())
+	}
+	b, subtype, err := vr.ReadBinary()
+	if err != nil {
+		return err
+	}
+	if subtype != TypeBinaryUserDefined {
+		return fmt.Errorf("cannot decode binary subtype %#x into a compressed field, expected subtype %#x",
+			subtype, TypeBinaryUserDefined)
+	}
+	if len(b) < 1 {
+		return fmt.Errorf("compressed field value is empty")
+	}
+
+	fc, err := dc.lookupFieldCompressor(compress)
+	if err != nil {
+		return err
+	}
+	value, err := fc.Decompress(b[1:])
+	if err != nil {
+		return fmt.Errorf("error decompressing field value: %w", err)
+	}
+
+	return dec.DecodeValue(dc, newValueReader(Type(b[0]), bytes.NewReader(value)), val)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+// reverseCompressor is a FieldCompressor that reverses the bytes of the value.
+type reverseCompressor struct{}
+
+func (reverseCompressor) Compress(dst, src []byte) ([]byte, error) {
+	for i := len(src) - 1; i >= 0; i-- {
+		dst = append(dst, src[i])
+	}
+	return dst, nil
+}
+
+func (reverseCompressor) Decompress(src []byte) ([]byte, error) {
+	return reverseCompressor{}.Compress(nil, src)
+}
+
+func TestFieldCompression(t *testing.T) {
+	t.Parallel()
+
+	type compressed struct {
+		ID      int32
+		Payload string  `bson:"payload" bsonCompress:"reverse"`
+		Extra   *string `bson:"extra,omitempty" bsonCompress:"reverse"`
+	}
+
+	reg := NewRegistry()
+	reg.RegisterFieldCompressor("reverse", reverseCompressor{})
+
+	marshal := func(t *testing.T, reg *Registry, val interface{}) ([]byte, error) {
+		t.Helper()
+
+		buf := new(bytes.Buffer)
+		enc := NewEncoder(NewDocumentWriter(buf))
+		enc.SetRegistry(reg)
+		err := enc.Encode(val)
+		return buf.Bytes(), err
+	}
+	unmarshal := func(t *testing.T, reg *Registry, b []byte, val interface{}) error {
+		t.Helper()
+
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(b)))
+		dec.SetRegistry(reg)
+		return dec.Decode(val)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		extra := "extra"
+		in := compressed{ID: 1, Payload: "payload", Extra: &extra}
+
+		b, err := marshal(t, reg, in)
+		require.NoError(t, err, "Marshal error")
+
+		val := Raw(b).Lookup("payload")
+		subtype, data, ok := val.BinaryOK()
+		require.True(t, ok, "expected payload to be binary, got %v", val.Type)
+		assert.Equal(t, TypeBinaryUserDefined, subtype)
+		assert.Equal(t, byte(TypeString), data[0])
+		assert.Contains(t, string(data[1:]), "daolyap", "expected the value to be passed through the compressor")
+
+		var out compressed
+		err = unmarshal(t, reg, b, &out)
+		require.NoError(t, err, "Unmarshal error")
+		assert.Equal(t, in, out)
+	})
+	t.Run("omitempty nil pointer", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := marshal(t, reg, compressed{ID: 1, Payload: "payload"})
+		require.NoError(t, err, "Marshal error")
+
+		_, err = Raw(b).LookupErr("extra")
+		assert.Error(t, err, "expected extra to be omitted")
+
+		var out compressed
+		err = unmarshal(t, reg, b, &out)
+		require.NoError(t, err, "Unmarshal error")
+		assert.Equal(t, compressed{ID: 1, Payload: "payload"}, out)
+	})
+	t.Run("uncompressed value", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := Marshal(D{{"payload", "payload"}})
+		require.NoError(t, err, "Marshal error")
+
+		var out compressed
+		err = unmarshal(t, reg, b, &out)
+		assert.ErrorContains(t, err, "cannot decode string into a compressed field")
+	})
+	t.Run("compressor error", func(t *testing.T) {
+		t.Parallel()
+
+		errCompress := errors.New("compress error")
+		reg := NewRegistry()
+		reg.RegisterFieldCompressor("reverse", failingCompressor{err: errCompress})
+
+		_, err := marshal(t, reg, compressed{Payload: "payload"})
+		assert.ErrorIs(t, err, errCompress)
+	})
+	t.Run("objectid tag", func(t *testing.T) {
+		t.Parallel()
+
+		type compressedObjectID struct {
+			ID string `bson:"id,objectid" bsonCompress:"reverse"`
+		}
+
+		const want = "objectid and bsonCompress struct tags cannot be used together on field ID"
+		_, err := marshal(t, reg, compressedObjectID{ID: NewObjectID().Hex()})
+		assert.ErrorContains(t, err, want)
+
+		b, err := Marshal(D{{"id", NewObjectID()}})
+		require.NoError(t, err, "Marshal error")
+		var out compressedObjectID
+		err = unmarshal(t, reg, b, &out)
+		assert.ErrorContains(t, err, want)
+	})
+	t.Run("no compressor registered", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Marshal(compressed{Payload: "payload"})
+		assert.ErrorContains(t, err, `no FieldCompressor registered for bsonCompress value "reverse"`)
+	})
+}
+
+type failingCompressor struct {
+	err error
+}
+
+func (fc failingCompressor) Compress([]byte, []byte) ([]byte, error) { return nil, fc.err }
+func (fc failingCompressor) Decompress([]byte) ([]byte, error)       { return nil, fc.err }
@@ -0,0 +1,110 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// RegisterJSONRawMessage registers a codec on reg that stores json.RawMessage values as BSON
+// documents instead of binary. When marshaling, the value must contain a JSON object, which is
+// parsed as relaxed or canonical Extended JSON. When unmarshaling, documents are written as relaxed
+// Extended JSON, or as canonical Extended JSON if [Decoder.CanonicalExtJSON] is set. Binary values
+// written without this codec are still decoded as their raw bytes.
+//
+// The codec is not registered by default because it changes how json.RawMessage values are stored.
+func RegisterJSONRawMessage(reg *Registry) {
+	reg.RegisterTypeEncoder(tJSONRawMessage, ValueEncoderFunc(jsonRawMessageEncodeValue))
+	reg.RegisterTypeDecoder(tJSONRawMessage, decodeAdapter{jsonRawMessageDecodeValue, jsonRawMessageDecodeType})
+}
+
+// jsonRawMessageEncodeValue is the ValueEncoderFunc for json.RawMessage. The value must contain a
+// JSON object, which is parsed as relaxed or canonical Extended JSON and written as a BSON document.
+func jsonRawMessageEncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tJSONRawMessage {
+		return ValueEncoderError{Name: "JSONRawMessageEncodeValue", Types: []reflect.Type{tJSONRawMessage}, Received: val}
+	}
+	msg := val.Interface().(json.RawMessage)
+	if trimmed := bytes.TrimSpace(msg); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return vw.WriteNull()
+	}
+
+	ejvr, err := newExtJSONValueReader(bytes.NewReader(msg), false)
+	if err != nil {
+		return err
+	}
+	doc, err := copyDocumentToBytes(ejvr)
+	if err != nil {
+		return fmt.Errorf("cannot encode json.RawMessage as a BSON document: %w", err)
+	}
+
+	return copyDocumentFromBytes(vw, doc)
+}
+
+func jsonRawMessageDecodeType(dc DecodeContext, vr ValueReader, t reflect.Type) (reflect.Value, error) {
+	if t != tJSONRawMessage {
+		return emptyValue, ValueDecoderError{
+			Name:     "JSONRawMessageDecodeValue",
+			Types:    []reflect.Type{tJSONRawMessage},
+			Received: reflect.Zero(t),
+		}
+	}
+
+	var msg json.RawMessage
+	switch vrType := vr.Type(); vrType {
+	case TypeEmbeddedDocument:
+		doc, err := copyDocumentToBytes(vr)
+		if err != nil {
+			return emptyValue, err
+		}
+		ejvw := newExtJSONWriterFromSlice(nil, dc.canonicalExtJSON, false)
+		if err := copyDocumentFromBytes(ejvw, doc); err != nil {
+			return emptyValue, err
+		}
+		msg = ejvw.buf
+	case TypeBinary:
+		// Without this codec, json.RawMessage values are stored as binary, so keep decoding them.
+		data, subtype, err := vr.ReadBinary()
+		if err != nil {
+			return emptyValue, err
+		}
+		if subtype != TypeBinaryGeneric && subtype != TypeBinaryBinaryOld {
+			return emptyValue, decodeBinaryError{subtype: subtype, typeName: "json.RawMessage"}
+		}
+		msg = json.RawMessage(data)
+	case TypeNull:
+		if err := vr.ReadNull(); err != nil {
+			return emptyValue, err
+		}
+	case TypeUndefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return emptyValue, err
+		}
+	default:
+		return emptyValue, fmt.Errorf("cannot decode %v into a json.RawMessage", vrType)
+	}
+
+	return reflect.ValueOf(msg), nil
+}
+
+// jsonRawMessageDecodeValue is the ValueDecoderFunc for json.RawMessage.
+func jsonRawMessageDecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tJSONRawMessage {
+		return ValueDecoderError{Name: "JSONRawMessageDecodeValue", Types: []reflect.Type{tJSONRawMessage}, Received: val}
+	}
+
+	elem, err := jsonRawMessageDecodeType(dc, vr, tJSONRawMessage)
+	if err != nil {
+		return err
+	}
+
+	val.Set(elem)
+	return nil
+}
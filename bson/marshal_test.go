@@ -8,6 +8,7 @@ package bson
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -326,3 +327,124 @@ func TestMarshalConcurrently(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestMarshalJSONRawMessage(t *testing.T) {
+	t.Parallel()
+
+	type passthrough struct {
+		Name  string
+		Attrs json.RawMessage
+	}
+
+	reg := NewRegistry()
+	RegisterJSONRawMessage(reg)
+
+	marshal := func(t *testing.T, val interface{}) []byte {
+		t.Helper()
+
+		buf := new(bytes.Buffer)
+		enc := NewEncoder(NewDocumentWriter(buf))
+		enc.SetRegistry(reg)
+		require.NoError(t, enc.Encode(val), "Encode error")
+		return buf.Bytes()
+	}
+	newDecoder := func(b []byte) *Decoder {
+		dec := NewDecoder(NewDocumentReader(bytes.NewReader(b)))
+		dec.SetRegistry(reg)
+		return dec
+	}
+
+	oid, err := ObjectIDFromHex("5f1d3c1e2a9b8c7d6e5f4a3b")
+	require.NoError(t, err, "ObjectIDFromHex error")
+
+	attrs := bsoncore.NewDocumentBuilder().
+		AppendInt32("count", 1).
+		AppendObjectID("ref", oid).
+		AppendDocument("nested", bsoncore.NewDocumentBuilder().AppendInt64("n", 2).Build()).
+		Build()
+	doc := bsoncore.NewDocumentBuilder().
+		AppendString("name", "widget").
+		AppendDocument("attrs", attrs).
+		Build()
+
+	testCases := []struct {
+		name      string
+		canonical bool
+		want      string
+	}{
+		{
+			name: "relaxed",
+			want: `{"count":1,"ref":{"$oid":"5f1d3c1e2a9b8c7d6e5f4a3b"},"nested":{"n":2}}`,
+		},
+		{
+			name:      "canonical",
+			canonical: true,
+			want: `{"count":{"$numberInt":"1"},"ref":{"$oid":"5f1d3c1e2a9b8c7d6e5f4a3b"},` +
+				`"nested":{"n":{"$numberLong":"2"}}}`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dec := newDecoder(doc)
+			if tc.canonical {
+				dec.CanonicalExtJSON()
+			}
+
+			var got passthrough
+			err := dec.Decode(&got)
+			require.NoError(t, err, "Decode error")
+			assert.Equal(t, "widget", got.Name, "unexpected name")
+			assert.Equal(t, tc.want, string(got.Attrs), "unexpected Extended JSON")
+
+			roundtrip := marshal(t, got)
+			if tc.canonical {
+				assert.Equal(t, bsoncore.Document(doc), bsoncore.Document(roundtrip), "expected documents to match")
+			} else {
+				// Relaxed Extended JSON does not preserve numeric types, so only compare values.
+				var want, gotM M
+				require.NoError(t, Unmarshal(doc, &want), "Unmarshal error")
+				require.NoError(t, Unmarshal(roundtrip, &gotM), "Unmarshal error")
+				assert.Equal(t, want["name"], gotM["name"], "unexpected name after round trip")
+				assert.Equal(t, oid, gotM["attrs"].(D)[1].Value, "unexpected ObjectID after round trip")
+			}
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		t.Parallel()
+
+		b := marshal(t, passthrough{Name: "widget"})
+		assert.Equal(t, TypeNull, Raw(b).Lookup("attrs").Type, "expected nil json.RawMessage to marshal as null")
+
+		var got passthrough
+		err := newDecoder(b).Decode(&got)
+		require.NoError(t, err, "Decode error")
+		assert.Nil(t, got.Attrs, "expected BSON null to unmarshal as nil")
+	})
+	t.Run("non-object", func(t *testing.T) {
+		t.Parallel()
+
+		enc := NewEncoder(NewDocumentWriter(new(bytes.Buffer)))
+		enc.SetRegistry(reg)
+		err := enc.Encode(passthrough{Attrs: json.RawMessage(`[1, 2]`)})
+		assert.Error(t, err, "expected error marshaling a JSON array")
+	})
+	t.Run("binary written by the default registry", func(t *testing.T) {
+		t.Parallel()
+
+		in := passthrough{Name: "widget", Attrs: json.RawMessage(`[1, 2]`)}
+		b, err := Marshal(in)
+		require.NoError(t, err, "Marshal error")
+		assert.Equal(t, TypeBinary, Raw(b).Lookup("attrs").Type,
+			"expected the default registry to marshal json.RawMessage as binary")
+
+		var got passthrough
+		err = newDecoder(b).Decode(&got)
+		require.NoError(t, err, "Decode error")
+		assert.Equal(t, in, got, "expected binary to decode as the raw bytes")
+	})
+}
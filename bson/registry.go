@@ -88,6 +88,7 @@ type Registry struct {
 	kindEncoders      *kindEncoderCache
 	kindDecoders      *kindDecoderCache
 	typeMap           sync.Map // map[Type]reflect.Type
+	fieldCompressors  sync.Map // map[string]FieldCompressor
 }
 
 // NewRegistry creates a new empty Registry.
@@ -0,0 +1,227 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"time"
+)
+
+var (
+	tIP       = reflect.TypeOf(net.IP(nil))
+	tDuration = reflect.TypeOf(time.Duration(0))
+)
+
+// DurationFormat specifies how the codec registered by RegisterStdlibCodecs
+// encodes time.Duration values.
+type DurationFormat int
+
+// These constants are the supported DurationFormat values.
+const (
+	// DurationNanoseconds encodes a time.Duration as a BSON int64 number of
+	// nanoseconds. This matches the default encoding of time.Duration.
+	DurationNanoseconds DurationFormat = iota
+
+	// DurationString encodes a time.Duration as a BSON string in the format
+	// returned by time.Duration.String, e.g. "1h30m0s".
+	DurationString
+)
+
+// RegisterStdlibCodecs registers codecs for standard library types that have
+// no natural BSON representation on reg:
+//
+//   - net.IP is encoded as a BSON string, e.g. "192.0.2.1" or "2001:db8::1",
+//     instead of as binary data. A nil net.IP is encoded as BSON null.
+//   - time.Duration is encoded in the given format.
+//
+// The decoders accept every representation that the encoders produce,
+// regardless of format, so data written in either DurationFormat can be read.
+// The net.IP decoder also accepts 4 or 16 byte binary values, which is how
+// net.IP is encoded by default.
+func RegisterStdlibCodecs(reg *Registry, format DurationFormat) {
+	ipc := &ipCodec{}
+	reg.RegisterTypeEncoder(tIP, ipc)
+	reg.RegisterTypeDecoder(tIP, ipc)
+
+	dc := &durationCodec{format: format}
+	reg.RegisterTypeEncoder(tDuration, dc)
+	reg.RegisterTypeDecoder(tDuration, dc)
+}
+
+// ipCodec is the Codec used for net.IP values by RegisterStdlibCodecs.
+type ipCodec struct{}
+
+var _ typeDecoder = &ipCodec{}
+
+// EncodeValue is the ValueEncoderFunc for net.IP.
+func (*ipCodec) EncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tIP {
+		return ValueEncoderError{Name: "IPEncodeValue", Types: []reflect.Type{tIP}, Received: val}
+	}
+	if val.IsNil() {
+		return vw.WriteNull()
+	}
+
+	ip := val.Interface().(net.IP)
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return fmt.Errorf("cannot encode net.IP of length %d", len(ip))
+	}
+	return vw.WriteString(ip.String())
+}
+
+func (*ipCodec) decodeType(_ DecodeContext, vr ValueReader, t reflect.Type) (reflect.Value, error) {
+	if t != tIP {
+		return emptyValue, ValueDecoderError{
+			Name:     "IPDecodeValue",
+			Types:    []reflect.Type{tIP},
+			Received: reflect.Zero(t),
+		}
+	}
+
+	var ip net.IP
+	switch vrType := vr.Type(); vrType {
+	case TypeString:
+		str, err := vr.ReadString()
+		if err != nil {
+			return emptyValue, err
+		}
+		if ip = net.ParseIP(str); ip == nil {
+			return emptyValue, fmt.Errorf("cannot decode %q into a net.IP", str)
+		}
+	case TypeBinary:
+		data, _, err := vr.ReadBinary()
+		if err != nil {
+			return emptyValue, err
+		}
+		if len(data) != net.IPv4len && len(data) != net.IPv6len {
+			return emptyValue, fmt.Errorf("cannot decode binary of length %d into a net.IP", len(data))
+		}
+		ip = append(net.IP(nil), data...)
+	case TypeNull:
+		if err := vr.ReadNull(); err != nil {
+			return emptyValue, err
+		}
+	case TypeUndefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return emptyValue, err
+		}
+	default:
+		return emptyValue, fmt.Errorf("cannot decode %v into a net.IP", vrType)
+	}
+
+	return reflect.ValueOf(ip), nil
+}
+
+// DecodeValue is the ValueDecoderFunc for net.IP.
+func (ic *ipCodec) DecodeValue(dc DecodeContext, vr ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tIP {
+		return ValueDecoderError{Name: "IPDecodeValue", Types: []reflect.Type{tIP}, Received: val}
+	}
+
+	elem, err := ic.decodeType(dc, vr, tIP)
+	if err != nil {
+		return err
+	}
+
+	val.Set(elem)
+	return nil
+}
+
+// durationCodec is the Codec used for time.Duration values by
+// RegisterStdlibCodecs.
+type durationCodec struct {
+	format DurationFormat
+}
+
+var _ typeDecoder = &durationCodec{}
+
+// EncodeValue is the ValueEncoderFunc for time.Duration.
+func (dc *durationCodec) EncodeValue(_ EncodeContext, vw ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tDuration {
+		return ValueEncoderError{Name: "DurationEncodeValue", Types: []reflect.Type{tDuration}, Received: val}
+	}
+
+	d := time.Duration(val.Int())
+	if dc.format == DurationString {
+		return vw.WriteString(d.String())
+	}
+	return vw.WriteInt64(int64(d))
+}
+
+func (dc *durationCodec) decodeType(_ DecodeContext, vr ValueReader, t reflect.Type) (reflect.Value, error) {
+	if t != tDuration {
+		return emptyValue, ValueDecoderError{
+			Name:     "DurationDecodeValue",
+			Types:    []reflect.Type{tDuration},
+			Received: reflect.Zero(t),
+		}
+	}
+
+	var d time.Duration
+	switch vrType := vr.Type(); vrType {
+	case TypeString:
+		str, err := vr.ReadString()
+		if err != nil {
+			return emptyValue, err
+		}
+		d, err = time.ParseDuration(str)
+		if err != nil {
+			return emptyValue, err
+		}
+	case TypeInt64:
+		i64, err := vr.ReadInt64()
+		if err != nil {
+			return emptyValue, err
+		}
+		d = time.Duration(i64)
+	case TypeInt32:
+		i32, err := vr.ReadInt32()
+		if err != nil {
+			return emptyValue, err
+		}
+		d = time.Duration(i32)
+	case TypeDouble:
+		f64, err := vr.ReadDouble()
+		if err != nil {
+			return emptyValue, err
+		}
+		if f64 != math.Trunc(f64) || f64 < math.MinInt64 || f64 >= math.MaxInt64 {
+			return emptyValue, fmt.Errorf("cannot decode double %v into a time.Duration", f64)
+		}
+		d = time.Duration(f64)
+	case TypeNull:
+		if err := vr.ReadNull(); err != nil {
+			return emptyValue, err
+		}
+	case TypeUndefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return emptyValue, err
+		}
+	default:
+		return emptyValue, fmt.Errorf("cannot decode %v into a time.Duration", vrType)
+	}
+
+	return reflect.ValueOf(d), nil
+}
+
+// DecodeValue is the ValueDecoderFunc for time.Duration.
+func (dc *durationCodec) DecodeValue(dctx DecodeContext, vr ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tDuration {
+		return ValueDecoderError{Name: "DurationDecodeValue", Types: []reflect.Type{tDuration}, Received: val}
+	}
+
+	elem, err := dc.decodeType(dctx, vr, tDuration)
+	if err != nil {
+		return err
+	}
+
+	val.Set(elem)
+	return nil
+}
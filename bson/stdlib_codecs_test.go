@@ -0,0 +1,196 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func marshalWithRegistry(t *testing.T, reg *Registry, val interface{}) []byte {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	enc := NewEncoder(NewDocumentWriter(buf))
+	enc.SetRegistry(reg)
+	require.NoError(t, enc.Encode(val), "Encode error")
+	return buf.Bytes()
+}
+
+func TestStdlibCodecs(t *testing.T) {
+	t.Parallel()
+
+	type ipDoc struct {
+		IP net.IP
+	}
+	type durationDoc struct {
+		D time.Duration
+	}
+
+	t.Run("net.IP round trip", func(t *testing.T) {
+		t.Parallel()
+
+		reg := NewRegistry()
+		RegisterStdlibCodecs(reg, DurationNanoseconds)
+
+		testCases := []struct {
+			name string
+			ip   net.IP
+			want interface{}
+		}{
+			{"IPv4", net.ParseIP("192.0.2.1"), "192.0.2.1"},
+			{"IPv4 4-byte form", net.IPv4(192, 0, 2, 1).To4(), "192.0.2.1"},
+			{"IPv6", net.ParseIP("2001:db8::1"), "2001:db8::1"},
+			{"IPv6 loopback", net.IPv6loopback, "::1"},
+			{"nil", nil, nil},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				data := marshalWithRegistry(t, reg, ipDoc{IP: tc.ip})
+				got := Raw(data).Lookup("ip")
+				if tc.want == nil {
+					assert.Equal(t, TypeNull, got.Type, "expected nil net.IP to be encoded as null")
+				} else {
+					assert.Equal(t, tc.want, got.StringValue(), "unexpected encoded value")
+				}
+
+				var doc ipDoc
+				err := unmarshalWithRegistry(t, reg, data, &doc)
+				require.NoError(t, err, "Decode error")
+				assert.True(t, tc.ip.Equal(doc.IP), "expected %v, got %v", tc.ip, doc.IP)
+			})
+		}
+	})
+	t.Run("net.IP decodes binary", func(t *testing.T) {
+		t.Parallel()
+
+		want := net.ParseIP("2001:db8::1")
+		data := marshalWithRegistry(t, NewRegistry(), ipDoc{IP: want})
+		assert.Equal(t, TypeBinary, Raw(data).Lookup("ip").Type, "expected net.IP to be binary by default")
+
+		reg := NewRegistry()
+		RegisterStdlibCodecs(reg, DurationNanoseconds)
+
+		var doc ipDoc
+		err := unmarshalWithRegistry(t, reg, data, &doc)
+		require.NoError(t, err, "Decode error")
+		assert.True(t, want.Equal(doc.IP), "expected %v, got %v", want, doc.IP)
+	})
+	t.Run("net.IP invalid string", func(t *testing.T) {
+		t.Parallel()
+
+		reg := NewRegistry()
+		RegisterStdlibCodecs(reg, DurationNanoseconds)
+
+		data, err := Marshal(D{{"ip", "not an ip"}})
+		require.NoError(t, err, "Marshal error")
+
+		var doc ipDoc
+		err = unmarshalWithRegistry(t, reg, data, &doc)
+		assert.ErrorContains(t, err, `cannot decode "not an ip" into a net.IP`)
+	})
+	t.Run("time.Duration round trip", func(t *testing.T) {
+		t.Parallel()
+
+		durations := []time.Duration{
+			0,
+			time.Nanosecond,
+			1500 * time.Millisecond,
+			90 * time.Minute,
+			-2 * time.Second,
+			time.Duration(1<<63 - 1),
+		}
+		formats := []struct {
+			name   string
+			format DurationFormat
+			want   func(time.Duration) interface{}
+			get    func(RawValue) interface{}
+		}{
+			{
+				name:   "nanoseconds",
+				format: DurationNanoseconds,
+				want:   func(d time.Duration) interface{} { return int64(d) },
+				get:    func(v RawValue) interface{} { return v.Int64() },
+			},
+			{
+				name:   "string",
+				format: DurationString,
+				want:   func(d time.Duration) interface{} { return d.String() },
+				get:    func(v RawValue) interface{} { return v.StringValue() },
+			},
+		}
+		for _, f := range formats {
+			f := f
+
+			t.Run(f.name, func(t *testing.T) {
+				t.Parallel()
+
+				reg := NewRegistry()
+				RegisterStdlibCodecs(reg, f.format)
+
+				for _, d := range durations {
+					data := marshalWithRegistry(t, reg, durationDoc{D: d})
+					assert.Equal(t, f.want(d), f.get(Raw(data).Lookup("d")), "unexpected encoded value for %v", d)
+
+					var doc durationDoc
+					err := unmarshalWithRegistry(t, reg, data, &doc)
+					require.NoError(t, err, "Decode error")
+					assert.Equal(t, d, doc.D, "unexpected decoded duration")
+				}
+			})
+		}
+	})
+	t.Run("time.Duration decodes other types", func(t *testing.T) {
+		t.Parallel()
+
+		reg := NewRegistry()
+		RegisterStdlibCodecs(reg, DurationNanoseconds)
+
+		testCases := []struct {
+			name    string
+			val     interface{}
+			want    time.Duration
+			wantErr string
+		}{
+			{name: "int32", val: int32(1000), want: time.Microsecond},
+			{name: "integral double", val: float64(5e9), want: 5 * time.Second},
+			{name: "fractional double", val: 1.5, wantErr: "cannot decode double 1.5 into a time.Duration"},
+			{name: "string", val: "1h2m3s", want: time.Hour + 2*time.Minute + 3*time.Second},
+			{name: "invalid string", val: "soon", wantErr: `invalid duration "soon"`},
+			{name: "null", val: nil, want: 0},
+			{name: "boolean", val: true, wantErr: "cannot decode boolean into a time.Duration"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				data, err := Marshal(D{{"d", tc.val}})
+				require.NoError(t, err, "Marshal error")
+
+				var doc durationDoc
+				err = unmarshalWithRegistry(t, reg, data, &doc)
+				if tc.wantErr != "" {
+					assert.ErrorContains(t, err, tc.wantErr)
+					return
+				}
+				require.NoError(t, err, "Decode error")
+				assert.Equal(t, tc.want, doc.D, "unexpected decoded duration")
+			})
+		}
+	})
+}
@@ -33,6 +33,16 @@ func (sc *stringCodec) EncodeValue(_ EncodeContext, vw ValueWriter, val reflect.
 	return vw.WriteString(val.String())
 }
 
+// encodeObjectIDHex encodes the string val as an ObjectID. It returns an error wrapping
+// ErrInvalidHex if val is not a 24-character hexadecimal string.
+func encodeObjectIDHex(vw ValueWriter, val reflect.Value) error {
+	oid, err := ObjectIDFromHex(val.String())
+	if err != nil {
+		return fmt.Errorf("%w: cannot encode %q as an ObjectID", ErrInvalidHex, val.String())
+	}
+	return vw.WriteObjectID(oid)
+}
+
 func (sc *stringCodec) decodeType(dc DecodeContext, vr ValueReader, t reflect.Type) (reflect.Value, error) {
 	if t.Kind() != reflect.String {
 		return emptyValue, ValueDecoderError{
@@ -177,7 +177,14 @@ func (sc *structCodec) EncodeValue(ec EncodeContext, vw ValueWriter, val reflect
 			omitZeroStruct:          ec.omitZeroStruct,
 			useJSONStructTags:       ec.useJSONStructTags,
 		}
-		err = encoder.EncodeValue(ectx, vw2, rv)
+		switch {
+		case desc.objectID:
+			err = encodeObjectIDHex(vw2, rv)
+		case desc.compress != "":
+			err = encodeCompressedValue(ectx, vw2, desc.compress, encoder, rv)
+		default:
+			err = encoder.EncodeValue(ectx, vw2, rv)
+		}
 		if err != nil {
 			return err
 		}
@@ -344,8 +351,10 @@ func (sc *structCodec) DecodeValue(dc DecodeContext, vr ValueReader, val reflect
 			Registry:            dc.Registry,
 			truncate:            fd.truncate || dc.truncate,
 			defaultDocumentType: dc.defaultDocumentType,
+			defaultNumberType:   dc.defaultNumberType,
 			binaryAsSlice:       dc.binaryAsSlice,
-			objectIDAsHexString: dc.objectIDAsHexString,
+			canonicalExtJSON:    dc.canonicalExtJSON,
+			objectIDAsHexString: fd.objectID || dc.objectIDAsHexString,
 			useJSONStructTags:   dc.useJSONStructTags,
 			useLocalTimeZone:    dc.useLocalTimeZone,
 			zeroMaps:            dc.zeroMaps,
@@ -356,7 +365,11 @@ func (sc *structCodec) DecodeValue(dc DecodeContext, vr ValueReader, val reflect
 			return newDecodeError(fd.name, errNoDecoder{Type: field.Elem().Type()})
 		}
 
-		err = fd.decoder.DecodeValue(dctx, vr, field.Elem())
+		if fd.compress != "" {
+			err = decodeCompressedValue(dctx, vr, fd.compress, fd.decoder, field.Elem())
+		} else {
+			err = fd.decoder.DecodeValue(dctx, vr, field.Elem())
+		}
 		if err != nil {
 			return newDecodeError(fd.name, err)
 		}
@@ -410,6 +423,8 @@ type fieldDescription struct {
 	omitEmpty bool
 	minSize   bool
 	truncate  bool
+	objectID  bool   // string field holding the hex representation of an ObjectID
+	compress  string // FieldCompressor name from the "bsonCompress" struct tag
 	inline    []int
 	encoder   ValueEncoder
 	decoder   ValueDecoder
@@ -523,6 +538,16 @@ func (sc *structCodec) describeStructSlow(
 		description.omitEmpty = stags.OmitEmpty
 		description.minSize = stags.MinSize
 		description.truncate = stags.Truncate
+		description.compress = stags.Compress
+		description.objectID = stags.ObjectID
+		if description.objectID && sfType.Kind() != reflect.String {
+			return nil, fmt.Errorf("(struct %s) objectid struct tag can only be used on string fields, field %s has type %s",
+				t.String(), sf.Name, sfType)
+		}
+		if description.objectID && description.compress != "" {
+			return nil, fmt.Errorf("(struct %s) objectid and bsonCompress struct tags cannot be used together on field %s",
+				t.String(), sf.Name)
+		}
 
 		if stags.Inline {
 			sd.inline = true
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
 )
 
 func TestIsZero(t *testing.T) {
@@ -158,3 +159,73 @@ func TestIsZero(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectIDStructTag(t *testing.T) {
+	t.Parallel()
+
+	type hexID struct {
+		ID string `bson:"_id,objectid"`
+	}
+
+	t.Run("valid hex", func(t *testing.T) {
+		t.Parallel()
+
+		const hex = "5ef7fdd91c19e3222b41b839"
+		b, err := Marshal(hexID{ID: hex})
+		require.NoError(t, err, "Marshal error")
+
+		oid, ok := Raw(b).Lookup("_id").ObjectIDOK()
+		require.True(t, ok, "expected _id to be encoded as an ObjectID")
+		assert.Equal(t, hex, oid.Hex())
+
+		var got hexID
+		err = Unmarshal(b, &got)
+		require.NoError(t, err, "Unmarshal error")
+		assert.Equal(t, hexID{ID: hex}, got)
+	})
+	t.Run("invalid hex", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name string
+			id   string
+		}{
+			{"empty", ""},
+			{"too short", "5ef7fdd91c19e3222b41b8"},
+			{"too long", "5ef7fdd91c19e3222b41b83900"},
+			{"non-hex characters", "5ef7fdd91c19e3222b41b8zz"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := Marshal(hexID{ID: tc.id})
+				assert.ErrorIs(t, err, ErrInvalidHex)
+			})
+		}
+	})
+	t.Run("omitempty", func(t *testing.T) {
+		t.Parallel()
+
+		type optionalHexID struct {
+			ID string `bson:"_id,omitempty,objectid"`
+		}
+
+		b, err := Marshal(optionalHexID{})
+		require.NoError(t, err, "Marshal error")
+		_, err = Raw(b).LookupErr("_id")
+		assert.Error(t, err, "expected _id to be omitted")
+	})
+	t.Run("non-string field", func(t *testing.T) {
+		t.Parallel()
+
+		type intID struct {
+			ID int `bson:"_id,objectid"`
+		}
+
+		_, err := Marshal(intID{ID: 1})
+		assert.ErrorContains(t, err, "objectid struct tag can only be used on string fields")
+	})
+}
@@ -35,6 +35,12 @@ import (
 //
 //	Skip       This struct field should be skipped. This is usually denoted by parsing a "-"
 //	           for the name.
+//
+//	ObjectID   The field is a string holding the hexadecimal representation of an ObjectID.
+//	           It is validated and marshaled as a BSON ObjectID.
+//
+//	Compress   The name of the FieldCompressor applied to the field's value, read from a
+//	           separate "bsonCompress" struct tag. It cannot be combined with ObjectID.
 type structTags struct {
 	Name      string
 	OmitEmpty bool
@@ -42,6 +48,8 @@ type structTags struct {
 	Truncate  bool
 	Inline    bool
 	Skip      bool
+	ObjectID  bool
+	Compress  string
 }
 
 // DefaultStructTagParser is the StructTagParser used by the StructCodec by default.
@@ -75,7 +83,7 @@ func parseStructTags(sf reflect.StructField) (*structTags, error) {
 	if !ok && !strings.Contains(string(sf.Tag), ":") && len(sf.Tag) > 0 {
 		tag = string(sf.Tag)
 	}
-	return parseTags(key, tag)
+	return parseCompressTag(sf, key, tag)
 }
 
 // jsonStructTagParser has the same behavior as DefaultStructTagParser
@@ -91,7 +99,19 @@ func parseJSONStructTags(sf reflect.StructField) (*structTags, error) {
 		tag = string(sf.Tag)
 	}
 
-	return parseTags(key, tag)
+	return parseCompressTag(sf, key, tag)
+}
+
+// parseCompressTag parses the key and tag with parseTags and sets Compress from the
+// "bsonCompress" struct tag of sf.
+func parseCompressTag(sf reflect.StructField, key string, tag string) (*structTags, error) {
+	st, err := parseTags(key, tag)
+	if err != nil || st.Skip {
+		return st, err
+	}
+
+	st.Compress = sf.Tag.Get("bsonCompress")
+	return st, nil
 }
 
 func parseTags(key string, tag string) (*structTags, error) {
@@ -114,6 +134,8 @@ func parseTags(key string, tag string) (*structTags, error) {
 			st.Truncate = true
 		case "inline":
 			st.Inline = true
+		case "objectid":
+			st.ObjectID = true
 		}
 	}
 
@@ -68,6 +68,12 @@ func TestStructTagParsers(t *testing.T) {
 			&structTags{Name: "foo", OmitEmpty: true, MinSize: true, Truncate: true, Inline: true},
 			parseStructTags,
 		},
+		{
+			"default objectid",
+			reflect.StructField{Name: "foo", Tag: reflect.StructTag(`bson:"_id,omitempty,objectid"`)},
+			&structTags{Name: "_id", OmitEmpty: true, ObjectID: true},
+			parseStructTags,
+		},
 		{
 			"default ignore xml",
 			reflect.StructField{Name: "foo", Tag: reflect.StructTag(`xml:"bar"`)},
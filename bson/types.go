@@ -88,6 +88,7 @@ var tByteSlice = reflect.TypeOf([]byte(nil))
 var tByte = reflect.TypeOf(byte(0x00))
 var tURL = reflect.TypeOf(url.URL{})
 var tJSONNumber = reflect.TypeOf(json.Number(""))
+var tJSONRawMessage = reflect.TypeOf(json.RawMessage(nil))
 
 var tValueMarshaler = reflect.TypeOf((*ValueMarshaler)(nil)).Elem()
 var tValueUnmarshaler = reflect.TypeOf((*ValueUnmarshaler)(nil)).Elem()
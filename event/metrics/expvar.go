@@ -0,0 +1,41 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+)
+
+// publishMu serializes PublishExpvar calls so that checking whether a name is
+// already published and publishing it are atomic.
+var publishMu sync.Mutex
+
+// PublishExpvar publishes the connection pool metrics recorded by the Collector
+// to the expvar package under name, e.g. "mongodb.pools". The published value
+// is computed from a new Snapshot each time it is read, so it is always up to
+// date. It is a JSON object that maps each server address to its PoolSnapshot,
+// with durations in nanoseconds.
+//
+// Publishing is opt-in and is intended for quick debugging through the
+// /debug/vars endpoint served by the expvar package. Because expvar variables
+// cannot be removed, the name should be unique for the lifetime of the
+// process. PublishExpvar returns an error if a variable with the same name is
+// already published.
+func (c *Collector) PublishExpvar(name string) error {
+	publishMu.Lock()
+	defer publishMu.Unlock()
+
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar variable %q is already published", name)
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return c.Snapshot().Pools
+	}))
+	return nil
+}
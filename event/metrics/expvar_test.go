@@ -0,0 +1,64 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestCollector_PublishExpvar(t *testing.T) {
+	t.Parallel()
+
+	const name = "mongodb.test.pools"
+	const addr = "localhost:27017"
+
+	c := NewCollector()
+	require.NoError(t, c.PublishExpvar(name), "PublishExpvar error")
+	assert.Error(t, c.PublishExpvar(name), "expected an error when publishing the same name twice")
+
+	read := func() map[string]PoolSnapshot {
+		t.Helper()
+
+		v := expvar.Get(name)
+		require.NotNil(t, v, "expected %q to be published", name)
+
+		var pools map[string]PoolSnapshot
+		err := json.Unmarshal([]byte(v.String()), &pools)
+		require.NoError(t, err, "error decoding %s", v.String())
+		return pools
+	}
+
+	assert.Len(t, read(), 0, "expected no pools before any events")
+
+	pm := c.PoolMonitor(nil)
+	pm.Event(&event.PoolEvent{Type: event.ConnectionCreated, Address: addr})
+	pm.Event(&event.PoolEvent{Type: event.ConnectionCheckedOut, Address: addr, WaitDuration: 3 * time.Millisecond})
+
+	pool := read()[addr]
+	assert.Equal(t, int64(1), pool.Connections, "unexpected connections")
+	assert.Equal(t, int64(1), pool.CheckedOut, "unexpected checked out connections")
+	assert.Equal(t, uint64(1), pool.CheckOuts, "unexpected check outs")
+	assert.Equal(t, uint64(1), pool.CheckOutWait.Count, "unexpected check out wait count")
+	assert.Equal(t, 3*time.Millisecond, pool.CheckOutWait.Sum, "unexpected check out wait sum")
+
+	// The published value reflects later events.
+	pm.Event(&event.PoolEvent{Type: event.ConnectionCheckedIn, Address: addr})
+	pm.Event(&event.PoolEvent{Type: event.ConnectionCheckedOut, Address: addr, WaitDuration: 2 * time.Millisecond})
+
+	pool = read()[addr]
+	assert.Equal(t, int64(1), pool.CheckedOut, "unexpected checked out connections")
+	assert.Equal(t, uint64(2), pool.CheckOuts, "unexpected check outs")
+	assert.Equal(t, uint64(2), pool.CheckOutWait.Count, "unexpected check out wait count")
+	assert.Equal(t, 5*time.Millisecond, pool.CheckOutWait.Sum, "unexpected check out wait sum")
+}
@@ -0,0 +1,375 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package metrics provides a Collector that aggregates command, connection
+// pool, and server monitoring events into metrics.
+//
+// The Collector does not depend on any metrics library. Instead, Snapshot
+// returns the current values as plain Go types that map directly onto common
+// metric kinds (histograms, gauges, and counters), so they can be exported to
+// systems such as Prometheus from a custom collector:
+//
+//	collector := metrics.NewCollector()
+//	opts := collector.Install(options.Client().ApplyURI(uri))
+//	client, err := mongo.Connect(opts)
+//	...
+//	snapshot := collector.Snapshot()
+//
+// For quick debugging, PublishExpvar publishes the connection pool metrics to
+// the expvar package, so they are served at /debug/vars along with the other
+// exported variables of the process.
+//
+// Monitors that are already configured on the ClientOptions are preserved and
+// continue to receive every event.
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DefaultBuckets are the default upper bounds of the duration histograms.
+var DefaultBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// HistogramSnapshot is a point-in-time copy of a duration histogram.
+type HistogramSnapshot struct {
+	// Buckets are the upper bounds of the histogram buckets in ascending order.
+	Buckets []time.Duration
+
+	// Counts are the cumulative number of observations less than or equal to
+	// the corresponding bucket's upper bound.
+	Counts []uint64
+
+	// Count is the total number of observations.
+	Count uint64
+
+	// Sum is the sum of all observed durations.
+	Sum time.Duration
+}
+
+// CommandSnapshot contains the metrics for a single command name.
+type CommandSnapshot struct {
+	Duration  HistogramSnapshot
+	Succeeded uint64
+	Failed    uint64
+}
+
+// PoolSnapshot contains the metrics for the connection pool of a single server.
+type PoolSnapshot struct {
+	// Connections is the number of open connections in the pool.
+	Connections int64
+
+	// CheckedOut is the number of connections currently checked out of the pool.
+	CheckedOut int64
+
+	// Created and Closed are the total number of connections created and
+	// closed, which together describe connection churn.
+	Created uint64
+	Closed  uint64
+
+	// CheckOuts is the total number of successful connection check outs.
+	CheckOuts uint64
+
+	// CheckOutFailures is the total number of failed connection check outs.
+	CheckOutFailures uint64
+
+	// CheckOutWait is the time check outs spent waiting for a connection to
+	// become available, including check outs that failed.
+	CheckOutWait HistogramSnapshot
+
+	// Cleared is the total number of times the pool was cleared.
+	Cleared uint64
+}
+
+// ServerSnapshot contains the heartbeat metrics for a single server.
+type ServerSnapshot struct {
+	Heartbeat           HistogramSnapshot
+	HeartbeatsSucceeded uint64
+	HeartbeatsFailed    uint64
+}
+
+// Snapshot is a point-in-time copy of all metrics recorded by a Collector.
+type Snapshot struct {
+	// Commands contains command metrics keyed by command name.
+	Commands map[string]CommandSnapshot
+
+	// Pools contains connection pool metrics keyed by server address.
+	Pools map[string]PoolSnapshot
+
+	// Servers contains server heartbeat metrics keyed by server address.
+	Servers map[string]ServerSnapshot
+}
+
+type histogram struct {
+	buckets []time.Duration
+	counts  []uint64 // non-cumulative; the last element counts observations above every bucket
+	count   uint64
+	sum     time.Duration
+}
+
+func newHistogram(buckets []time.Duration) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)+1),
+	}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	idx := sort.Search(len(h.buckets), func(i int) bool { return d <= h.buckets[i] })
+	h.counts[idx]++
+	h.count++
+	h.sum += d
+}
+
+func (h *histogram) snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Buckets: append([]time.Duration(nil), h.buckets...),
+		Counts:  make([]uint64, len(h.buckets)),
+		Count:   h.count,
+		Sum:     h.sum,
+	}
+	var cumulative uint64
+	for i := range h.buckets {
+		cumulative += h.counts[i]
+		s.Counts[i] = cumulative
+	}
+	return s
+}
+
+type commandStats struct {
+	duration  *histogram
+	succeeded uint64
+	failed    uint64
+}
+
+type poolStats struct {
+	PoolSnapshot
+	checkOutWait *histogram
+}
+
+type serverStats struct {
+	heartbeat *histogram
+	succeeded uint64
+	failed    uint64
+}
+
+// Collector records metrics from command, connection pool, and server
+// monitoring events. A Collector is safe for concurrent use and can be shared
+// by multiple clients.
+type Collector struct {
+	buckets []time.Duration
+
+	mu       sync.Mutex
+	commands map[string]*commandStats
+	pools    map[string]*poolStats
+	servers  map[string]*serverStats
+}
+
+// NewCollector creates a Collector. The duration histograms use the given
+// bucket upper bounds, or DefaultBuckets if none are provided.
+func NewCollector(buckets ...time.Duration) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]time.Duration(nil), buckets...)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+
+	return &Collector{
+		buckets:  buckets,
+		commands: make(map[string]*commandStats),
+		pools:    make(map[string]*poolStats),
+		servers:  make(map[string]*serverStats),
+	}
+}
+
+// Install configures opts to report events to the Collector. Any command,
+// pool, or server monitor already set on opts continues to receive events.
+func (c *Collector) Install(opts *options.ClientOptions) *options.ClientOptions {
+	opts.SetMonitor(c.CommandMonitor(opts.Monitor))
+	opts.SetPoolMonitor(c.PoolMonitor(opts.PoolMonitor))
+	opts.SetServerMonitor(c.ServerMonitor(opts.ServerMonitor))
+
+	return opts
+}
+
+// CommandMonitor returns a CommandMonitor that records command metrics and
+// then forwards each event to next, if it is not nil.
+func (c *Collector) CommandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			if next != nil && next.Started != nil {
+				next.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			c.recordCommand(&evt.CommandFinishedEvent, true)
+			if next != nil && next.Succeeded != nil {
+				next.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			c.recordCommand(&evt.CommandFinishedEvent, false)
+			if next != nil && next.Failed != nil {
+				next.Failed(ctx, evt)
+			}
+		},
+	}
+}
+
+// PoolMonitor returns a PoolMonitor that records connection pool metrics and
+// then forwards each event to next, if it is not nil.
+func (c *Collector) PoolMonitor(next *event.PoolMonitor) *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			c.recordPoolEvent(evt)
+			if next != nil && next.Event != nil {
+				next.Event(evt)
+			}
+		},
+	}
+}
+
+// ServerMonitor returns a ServerMonitor that records server heartbeat metrics
+// and then forwards each event to next, if it is not nil.
+func (c *Collector) ServerMonitor(next *event.ServerMonitor) *event.ServerMonitor {
+	sm := &event.ServerMonitor{
+		ServerHeartbeatSucceeded: func(evt *event.ServerHeartbeatSucceededEvent) {
+			c.recordHeartbeat(evt.Address.String(), evt.Duration, true)
+			if next != nil && next.ServerHeartbeatSucceeded != nil {
+				next.ServerHeartbeatSucceeded(evt)
+			}
+		},
+		ServerHeartbeatFailed: func(evt *event.ServerHeartbeatFailedEvent) {
+			c.recordHeartbeat(evt.Address.String(), evt.Duration, false)
+			if next != nil && next.ServerHeartbeatFailed != nil {
+				next.ServerHeartbeatFailed(evt)
+			}
+		},
+	}
+	if next != nil {
+		sm.ServerDescriptionChanged = next.ServerDescriptionChanged
+		sm.ServerOpening = next.ServerOpening
+		sm.ServerClosed = next.ServerClosed
+		sm.TopologyDescriptionChanged = next.TopologyDescriptionChanged
+		sm.TopologyOpening = next.TopologyOpening
+		sm.TopologyClosed = next.TopologyClosed
+		sm.ServerHeartbeatStarted = next.ServerHeartbeatStarted
+	}
+	return sm
+}
+
+func (c *Collector) recordCommand(evt *event.CommandFinishedEvent, succeeded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.commands[evt.CommandName]
+	if !ok {
+		stats = &commandStats{duration: newHistogram(c.buckets)}
+		c.commands[evt.CommandName] = stats
+	}
+	stats.duration.observe(evt.Duration)
+	if succeeded {
+		stats.succeeded++
+	} else {
+		stats.failed++
+	}
+}
+
+func (c *Collector) recordPoolEvent(evt *event.PoolEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.pools[evt.Address]
+	if !ok {
+		stats = &poolStats{checkOutWait: newHistogram(c.buckets)}
+		c.pools[evt.Address] = stats
+	}
+
+	switch evt.Type {
+	case event.ConnectionCreated:
+		stats.Created++
+		stats.Connections++
+	case event.ConnectionClosed:
+		stats.Closed++
+		stats.Connections--
+	case event.ConnectionCheckedOut:
+		stats.CheckedOut++
+		stats.CheckOuts++
+		stats.checkOutWait.observe(evt.WaitDuration)
+	case event.ConnectionCheckedIn:
+		stats.CheckedOut--
+	case event.ConnectionCheckOutFailed:
+		stats.CheckOutFailures++
+		stats.checkOutWait.observe(evt.WaitDuration)
+	case event.ConnectionPoolCleared:
+		stats.Cleared++
+	}
+}
+
+func (c *Collector) recordHeartbeat(addr string, d time.Duration, succeeded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.servers[addr]
+	if !ok {
+		stats = &serverStats{heartbeat: newHistogram(c.buckets)}
+		c.servers[addr] = stats
+	}
+	stats.heartbeat.observe(d)
+	if succeeded {
+		stats.succeeded++
+	} else {
+		stats.failed++
+	}
+}
+
+// Snapshot returns a copy of the metrics recorded so far.
+func (c *Collector) Snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := Snapshot{
+		Commands: make(map[string]CommandSnapshot, len(c.commands)),
+		Pools:    make(map[string]PoolSnapshot, len(c.pools)),
+		Servers:  make(map[string]ServerSnapshot, len(c.servers)),
+	}
+	for name, stats := range c.commands {
+		s.Commands[name] = CommandSnapshot{
+			Duration:  stats.duration.snapshot(),
+			Succeeded: stats.succeeded,
+			Failed:    stats.failed,
+		}
+	}
+	for addr, stats := range c.pools {
+		pool := stats.PoolSnapshot
+		pool.CheckOutWait = stats.checkOutWait.snapshot()
+		s.Pools[addr] = pool
+	}
+	for addr, stats := range c.servers {
+		s.Servers[addr] = ServerSnapshot{
+			Heartbeat:           stats.heartbeat.snapshot(),
+			HeartbeatsSucceeded: stats.succeeded,
+			HeartbeatsFailed:    stats.failed,
+		}
+	}
+	return s
+}
@@ -0,0 +1,161 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("command metrics", func(t *testing.T) {
+		t.Parallel()
+
+		var started, succeeded, failed int
+		user := &event.CommandMonitor{
+			Started:   func(context.Context, *event.CommandStartedEvent) { started++ },
+			Succeeded: func(context.Context, *event.CommandSucceededEvent) { succeeded++ },
+			Failed:    func(context.Context, *event.CommandFailedEvent) { failed++ },
+		}
+
+		c := NewCollector(10*time.Millisecond, time.Millisecond)
+		cm := c.CommandMonitor(user)
+
+		ctx := context.Background()
+		cm.Started(ctx, &event.CommandStartedEvent{CommandName: "find"})
+		cm.Succeeded(ctx, &event.CommandSucceededEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: 500 * time.Microsecond},
+		})
+		cm.Succeeded(ctx, &event.CommandSucceededEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: 5 * time.Millisecond},
+		})
+		cm.Failed(ctx, &event.CommandFailedEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: time.Second},
+			Failure:              errors.New("failed"),
+		})
+
+		assert.Equal(t, 1, started, "expected user Started to be called")
+		assert.Equal(t, 2, succeeded, "expected user Succeeded to be called")
+		assert.Equal(t, 1, failed, "expected user Failed to be called")
+
+		find, ok := c.Snapshot().Commands["find"]
+		require.True(t, ok, "expected metrics for find command")
+		assert.Equal(t, uint64(2), find.Succeeded, "unexpected succeeded count")
+		assert.Equal(t, uint64(1), find.Failed, "unexpected failed count")
+
+		want := HistogramSnapshot{
+			Buckets: []time.Duration{time.Millisecond, 10 * time.Millisecond},
+			Counts:  []uint64{1, 2},
+			Count:   3,
+			Sum:     500*time.Microsecond + 5*time.Millisecond + time.Second,
+		}
+		assert.Equal(t, want, find.Duration, "unexpected duration histogram")
+	})
+	t.Run("pool metrics", func(t *testing.T) {
+		t.Parallel()
+
+		var events []string
+		user := &event.PoolMonitor{
+			Event: func(evt *event.PoolEvent) { events = append(events, evt.Type) },
+		}
+
+		c := NewCollector()
+		pm := c.PoolMonitor(user)
+
+		const addr = "localhost:27017"
+		types := []string{
+			event.ConnectionPoolCreated,
+			event.ConnectionCreated,
+			event.ConnectionCreated,
+			event.ConnectionCheckedOut,
+			event.ConnectionCheckedOut,
+			event.ConnectionCheckedIn,
+			event.ConnectionCheckOutFailed,
+			event.ConnectionClosed,
+			event.ConnectionPoolCleared,
+		}
+		for _, typ := range types {
+			pm.Event(&event.PoolEvent{Type: typ, Address: addr})
+		}
+
+		assert.Equal(t, types, events, "expected user monitor to receive all events")
+
+		got := c.Snapshot().Pools[addr]
+		assert.Equal(t, uint64(3), got.CheckOutWait.Count, "expected the wait of every check out to be observed")
+
+		want := PoolSnapshot{
+			Connections:      1,
+			CheckedOut:       1,
+			Created:          2,
+			Closed:           1,
+			CheckOuts:        2,
+			CheckOutFailures: 1,
+			Cleared:          1,
+		}
+		got.CheckOutWait = HistogramSnapshot{}
+		assert.Equal(t, want, got, "unexpected pool metrics")
+	})
+	t.Run("server metrics", func(t *testing.T) {
+		t.Parallel()
+
+		var opened int
+		user := &event.ServerMonitor{
+			ServerOpening: func(*event.ServerOpeningEvent) { opened++ },
+		}
+
+		c := NewCollector()
+		sm := c.ServerMonitor(user)
+
+		sm.ServerOpening(&event.ServerOpeningEvent{})
+		sm.ServerHeartbeatSucceeded(&event.ServerHeartbeatSucceededEvent{
+			ConnectionID: "custom-connection-id-1",
+			Address:      address.Address("localhost:27017"),
+			Duration:     time.Millisecond,
+		})
+		sm.ServerHeartbeatFailed(&event.ServerHeartbeatFailedEvent{
+			ConnectionID: "custom-connection-id-2",
+			Address:      address.Address("localhost:27017"),
+			Duration:     time.Millisecond,
+		})
+
+		assert.Equal(t, 1, opened, "expected user ServerOpening to be called")
+
+		server, ok := c.Snapshot().Servers["localhost:27017"]
+		require.True(t, ok, "expected metrics for localhost:27017")
+		assert.Equal(t, uint64(1), server.HeartbeatsSucceeded, "unexpected succeeded count")
+		assert.Equal(t, uint64(1), server.HeartbeatsFailed, "unexpected failed count")
+		assert.Equal(t, uint64(2), server.Heartbeat.Count, "unexpected heartbeat count")
+	})
+	t.Run("Install preserves monitors", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+		opts := options.Client().SetPoolMonitor(&event.PoolMonitor{
+			Event: func(*event.PoolEvent) { called = true },
+		})
+
+		c := NewCollector()
+		c.Install(opts)
+
+		require.NotNil(t, opts.Monitor, "expected command monitor to be set")
+		require.NotNil(t, opts.ServerMonitor, "expected server monitor to be set")
+
+		opts.PoolMonitor.Event(&event.PoolEvent{Type: event.ConnectionCreated, Address: "a"})
+		assert.True(t, called, "expected existing pool monitor to be called")
+		assert.Equal(t, uint64(1), c.Snapshot().Pools["a"].Created, "unexpected created count")
+	})
+}
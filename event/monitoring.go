@@ -21,6 +21,8 @@ type CommandStartedEvent struct {
 	CommandName  string
 	RequestID    int64
 	ConnectionID string
+	// Address is the address of the server to which the command was sent.
+	Address address.Address
 	// ServerConnectionID64 contains the connection ID from the server of the operation. If the server does not
 	// return this value (e.g. on MDB < 4.2), it is unset.
 	ServerConnectionID *int64
@@ -36,6 +38,8 @@ type CommandFinishedEvent struct {
 	DatabaseName string
 	RequestID    int64
 	ConnectionID string
+	// Address is the address of the server to which the command was sent.
+	Address address.Address
 	// ServerConnectionID64 contains the connection ID from the server of the operation. If the server does not
 	// return this value (e.g. on MDB < 4.2), it is unset.
 	ServerConnectionID *int64
@@ -71,6 +75,17 @@ const (
 	ReasonConnectionErrored = "connectionError"
 	ReasonTimedOut          = "timeout"
 	ReasonError             = "error"
+	ReasonPoolExhausted     = "poolExhausted"
+	ReasonWaitQueueFull     = "waitQueueFull"
+)
+
+// strings for the phases of connection establishment reported in pool events
+const (
+	PhaseDNS       = "dns"
+	PhaseDial      = "dial"
+	PhaseTLS       = "tls"
+	PhaseHandshake = "handshake"
+	PhaseAuth      = "auth"
 )
 
 // strings for pool command monitoring types
@@ -102,7 +117,15 @@ type PoolEvent struct {
 	ConnectionID int64               `json:"connectionId"`
 	PoolOptions  *MonitorPoolOptions `json:"options"`
 	Duration     time.Duration       `json:"duration"`
-	Reason       string              `json:"reason"`
+	// WaitDuration is only set if the Type is ConnectionCheckedOut or ConnectionCheckOutFailed. It is the time the
+	// checkout spent blocked waiting for a connection to become available, measured from the checkout request until a
+	// connection or error was granted. It is zero if a connection was available immediately.
+	WaitDuration time.Duration `json:"waitDuration"`
+	Reason       string        `json:"reason"`
+	// Phase is only set if the Type is ConnectionClosed and the connection was closed because it could not be
+	// established. It is the phase of connection establishment that failed: PhaseDNS, PhaseDial, PhaseTLS,
+	// PhaseHandshake or PhaseAuth.
+	Phase string `json:"phase"`
 	// ServiceID is only set if the Type is PoolCleared and the server is deployed behind a load balancer. This field
 	// can be used to distinguish between individual servers in a load balanced deployment.
 	ServiceID    *bson.ObjectID `json:"serviceId"`
@@ -154,24 +177,27 @@ type TopologyClosedEvent struct {
 
 // ServerHeartbeatStartedEvent is an event generated when the heartbeat is started.
 type ServerHeartbeatStartedEvent struct {
-	ConnectionID string // The address this heartbeat was sent to with a unique identifier
-	Awaited      bool   // If this heartbeat was awaitable
+	ConnectionID string          // The address this heartbeat was sent to with a unique identifier
+	Address      address.Address // The address of the server this heartbeat was sent to
+	Awaited      bool            // If this heartbeat was awaitable
 }
 
 // ServerHeartbeatSucceededEvent is an event generated when the heartbeat succeeds.
 type ServerHeartbeatSucceededEvent struct {
 	Duration     time.Duration
 	Reply        ServerDescription
-	ConnectionID string // The address this heartbeat was sent to with a unique identifier
-	Awaited      bool   // If this heartbeat was awaitable
+	ConnectionID string          // The address this heartbeat was sent to with a unique identifier
+	Address      address.Address // The address of the server this heartbeat was sent to
+	Awaited      bool            // If this heartbeat was awaitable
 }
 
 // ServerHeartbeatFailedEvent is an event generated when the heartbeat fails.
 type ServerHeartbeatFailedEvent struct {
 	Duration     time.Duration
 	Failure      error
-	ConnectionID string // The address this heartbeat was sent to with a unique identifier
-	Awaited      bool   // If this heartbeat was awaitable
+	ConnectionID string          // The address this heartbeat was sent to with a unique identifier
+	Address      address.Address // The address of the server this heartbeat was sent to
+	Awaited      bool            // If this heartbeat was awaitable
 }
 
 // ServerMonitor represents a monitor that is triggered for different server events. The client
@@ -0,0 +1,206 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package tracing provides a CommandMonitor that creates a span for each
+// command sent to the server, with the attributes defined by the OpenTelemetry
+// semantic conventions for database client spans.
+//
+// The package does not depend on OpenTelemetry. Spans are created through the
+// Tracer interface, which an OpenTelemetry trace.Tracer can be adapted to:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attrs []tracing.Attribute) (context.Context, tracing.Span) {
+//		kvs := make([]attribute.KeyValue, 0, len(attrs))
+//		for _, attr := range attrs {
+//			switch v := attr.Value.(type) {
+//			case string:
+//				kvs = append(kvs, attribute.String(attr.Key, v))
+//			case int64:
+//				kvs = append(kvs, attribute.Int64(attr.Key, v))
+//			}
+//		}
+//		ctx, span := t.tracer.Start(ctx, name,
+//			trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(kvs...))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ span trace.Span }
+//
+//	func (s otelSpan) RecordError(err error) {
+//		s.span.RecordError(err)
+//		s.span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s otelSpan) End() { s.span.End() }
+//
+// The Monitor is then installed on the client options:
+//
+//	monitor := tracing.NewMonitor(otelTracer{otel.Tracer("mongodb")})
+//	client, err := mongo.Connect(monitor.Install(options.Client().ApplyURI(uri)))
+//
+// A command monitor that is already configured on the ClientOptions is
+// preserved and continues to receive every event.
+package tracing
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Attribute keys set on command spans.
+const (
+	AttributeDBSystem           = "db.system"
+	AttributeDBOperation        = "db.operation"
+	AttributeDBName             = "db.name"
+	AttributeNetworkPeerAddress = "network.peer.address"
+	AttributeNetworkPeerPort    = "network.peer.port"
+)
+
+// DBSystem is the value of the "db.system" attribute.
+const DBSystem = "mongodb"
+
+// Attribute is a span attribute. Value is either a string or an int64.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// RecordError records that the command failed with err.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a client span with the given name and attributes as a
+	// child of any span in ctx.
+	Start(ctx context.Context, name string, attrs []Attribute) (context.Context, Span)
+}
+
+// spanKey identifies the span of an in-progress command. Connection and
+// request IDs are only unique within a client, so the key also includes the ID
+// of the CommandMonitor that reported the command.
+type spanKey struct {
+	monitorID    uint64
+	connectionID string
+	requestID    int64
+}
+
+// Monitor creates a span for each command. A Monitor is safe for concurrent
+// use and can be shared by multiple clients.
+type Monitor struct {
+	tracer Tracer
+
+	mu       sync.Mutex
+	spans    map[spanKey]Span
+	monitors uint64
+}
+
+// NewMonitor creates a Monitor that starts spans with tracer.
+func NewMonitor(tracer Tracer) *Monitor {
+	return &Monitor{
+		tracer: tracer,
+		spans:  make(map[spanKey]Span),
+	}
+}
+
+// Install configures opts to report command events to the Monitor. Any command
+// monitor already set on opts continues to receive events.
+func (m *Monitor) Install(opts *options.ClientOptions) *options.ClientOptions {
+	return opts.SetMonitor(m.CommandMonitor(opts.Monitor))
+}
+
+// CommandMonitor returns a CommandMonitor that starts a span when a command is
+// started, ends it when the command succeeds or fails, and forwards each event
+// to next, if it is not nil. Each client must be configured with its own
+// CommandMonitor.
+func (m *Monitor) CommandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	m.mu.Lock()
+	m.monitors++
+	id := m.monitors
+	m.mu.Unlock()
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			m.start(ctx, id, evt)
+			if next != nil && next.Started != nil {
+				next.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			m.end(id, &evt.CommandFinishedEvent, nil)
+			if next != nil && next.Succeeded != nil {
+				next.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			m.end(id, &evt.CommandFinishedEvent, evt.Failure)
+			if next != nil && next.Failed != nil {
+				next.Failed(ctx, evt)
+			}
+		},
+	}
+}
+
+func (m *Monitor) start(ctx context.Context, monitorID uint64, evt *event.CommandStartedEvent) {
+	attrs := []Attribute{
+		{Key: AttributeDBSystem, Value: DBSystem},
+		{Key: AttributeDBOperation, Value: evt.CommandName},
+		{Key: AttributeDBName, Value: evt.DatabaseName},
+	}
+	if host, port, ok := peer(evt.Address); ok {
+		attrs = append(attrs,
+			Attribute{Key: AttributeNetworkPeerAddress, Value: host},
+			Attribute{Key: AttributeNetworkPeerPort, Value: port})
+	}
+
+	_, span := m.tracer.Start(ctx, evt.CommandName+" "+evt.DatabaseName, attrs)
+
+	m.mu.Lock()
+	m.spans[spanKey{monitorID: monitorID, connectionID: evt.ConnectionID, requestID: evt.RequestID}] = span
+	m.mu.Unlock()
+}
+
+func (m *Monitor) end(monitorID uint64, evt *event.CommandFinishedEvent, failure error) {
+	key := spanKey{monitorID: monitorID, connectionID: evt.ConnectionID, requestID: evt.RequestID}
+
+	m.mu.Lock()
+	span, ok := m.spans[key]
+	delete(m.spans, key)
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	if failure != nil {
+		span.RecordError(failure)
+	}
+	span.End()
+}
+
+// peer returns the host and port of the server from its address.
+func peer(addr address.Address) (string, int64, bool) {
+	host, portStr, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "", 0, false
+	}
+	port, err := strconv.ParseInt(portStr, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return host, port, true
+}
@@ -0,0 +1,173 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type recordedSpan struct {
+	name   string
+	attrs  []Attribute
+	errors []error
+	ended  bool
+}
+
+func (s *recordedSpan) RecordError(err error) { s.errors = append(s.errors, err) }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+// spanRecorder is a Tracer that records the spans it starts.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *spanRecorder) Start(ctx context.Context, name string, attrs []Attribute) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	span := &recordedSpan{name: name, attrs: attrs}
+	r.spans = append(r.spans, span)
+	return ctx, span
+}
+
+func TestMonitor(t *testing.T) {
+	t.Parallel()
+
+	started := func(requestID int64) *event.CommandStartedEvent {
+		return &event.CommandStartedEvent{
+			CommandName:  "find",
+			DatabaseName: "db",
+			RequestID:    requestID,
+			ConnectionID: "custom-connection-id",
+			Address:      address.Address("localhost:27017"),
+		}
+	}
+	finished := func(requestID int64) event.CommandFinishedEvent {
+		return event.CommandFinishedEvent{
+			CommandName:  "find",
+			DatabaseName: "db",
+			RequestID:    requestID,
+			ConnectionID: "custom-connection-id",
+			Address:      address.Address("localhost:27017"),
+		}
+	}
+	wantAttrs := []Attribute{
+		{Key: AttributeDBSystem, Value: "mongodb"},
+		{Key: AttributeDBOperation, Value: "find"},
+		{Key: AttributeDBName, Value: "db"},
+		{Key: AttributeNetworkPeerAddress, Value: "localhost"},
+		{Key: AttributeNetworkPeerPort, Value: int64(27017)},
+	}
+
+	t.Run("succeeded", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &spanRecorder{}
+		cm := NewMonitor(recorder).CommandMonitor(nil)
+
+		ctx := context.Background()
+		cm.Started(ctx, started(1))
+		require.Len(t, recorder.spans, 1, "expected a span to be started")
+		span := recorder.spans[0]
+		assert.False(t, span.ended, "expected span to be in progress")
+
+		cm.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished(1)})
+		assert.Equal(t, "find db", span.name, "unexpected span name")
+		assert.Equal(t, wantAttrs, span.attrs, "unexpected span attributes")
+		assert.Len(t, span.errors, 0, "expected no errors to be recorded")
+		assert.True(t, span.ended, "expected span to be ended")
+	})
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &spanRecorder{}
+		cm := NewMonitor(recorder).CommandMonitor(nil)
+
+		failure := errors.New("command failed")
+		ctx := context.Background()
+		cm.Started(ctx, started(1))
+		cm.Started(ctx, started(2))
+		cm.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished(2), Failure: failure})
+
+		require.Len(t, recorder.spans, 2, "expected two spans to be started")
+		assert.False(t, recorder.spans[0].ended, "expected the span of the other command to be in progress")
+		assert.Equal(t, []error{failure}, recorder.spans[1].errors, "expected the failure to be recorded")
+		assert.True(t, recorder.spans[1].ended, "expected span to be ended")
+	})
+	t.Run("unknown peer", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &spanRecorder{}
+		cm := NewMonitor(recorder).CommandMonitor(nil)
+
+		evt := started(1)
+		evt.Address = ""
+		cm.Started(context.Background(), evt)
+
+		require.Len(t, recorder.spans, 1, "expected a span to be started")
+		assert.Equal(t, wantAttrs[:3], recorder.spans[0].attrs, "expected no network attributes")
+	})
+	t.Run("shared by multiple clients", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &spanRecorder{}
+		monitor := NewMonitor(recorder)
+		client1 := monitor.Install(options.Client()).Monitor
+		client2 := monitor.Install(options.Client()).Monitor
+
+		// Both clients report a command with the same connection and request IDs.
+		ctx := context.Background()
+		client1.Started(ctx, started(1))
+		client2.Started(ctx, started(1))
+		require.Len(t, recorder.spans, 2, "expected two spans to be started")
+
+		client2.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished(1)})
+		assert.False(t, recorder.spans[0].ended, "expected the span of the first client to be in progress")
+		assert.True(t, recorder.spans[1].ended, "expected the span of the second client to be ended")
+
+		client1.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished(1)})
+		assert.True(t, recorder.spans[0].ended, "expected the span of the first client to be ended")
+	})
+	t.Run("chains with the user monitor", func(t *testing.T) {
+		t.Parallel()
+
+		var started, succeeded, failed int
+		user := &event.CommandMonitor{
+			Started:   func(context.Context, *event.CommandStartedEvent) { started++ },
+			Succeeded: func(context.Context, *event.CommandSucceededEvent) { succeeded++ },
+			Failed:    func(context.Context, *event.CommandFailedEvent) { failed++ },
+		}
+
+		recorder := &spanRecorder{}
+		opts := NewMonitor(recorder).Install(options.Client().SetMonitor(user))
+
+		ctx := context.Background()
+		opts.Monitor.Started(ctx, &event.CommandStartedEvent{CommandName: "ping", RequestID: 1})
+		opts.Monitor.Succeeded(ctx, &event.CommandSucceededEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "ping", RequestID: 1},
+		})
+		opts.Monitor.Failed(ctx, &event.CommandFailedEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "ping", RequestID: 2},
+		})
+
+		assert.Equal(t, 1, started, "expected user Started to be called")
+		assert.Equal(t, 1, succeeded, "expected user Succeeded to be called")
+		assert.Equal(t, 1, failed, "expected user Failed to be called")
+		require.Len(t, recorder.spans, 1, "expected a span to be started")
+		assert.True(t, recorder.spans[0].ended, "expected span to be ended")
+	})
+}
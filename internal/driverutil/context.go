@@ -0,0 +1,33 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driverutil
+
+import (
+	"context"
+	"time"
+)
+
+// contextKey is a custom type used to prevent key collisions when using the
+// context package.
+type contextKey string
+
+const contextKeyLocalThreshold contextKey = "localThreshold"
+
+// WithLocalThreshold adds a per-operation local threshold to the context.
+func WithLocalThreshold(ctx context.Context, threshold time.Duration) context.Context {
+	return context.WithValue(ctx, contextKeyLocalThreshold, threshold)
+}
+
+// LocalThreshold returns the per-operation local threshold from the context.
+func LocalThreshold(ctx context.Context) (time.Duration, bool) {
+	threshold := ctx.Value(contextKeyLocalThreshold)
+	if threshold == nil {
+		return 0, false
+	}
+
+	return threshold.(time.Duration), true
+}
@@ -300,6 +300,13 @@ func NewServerDescription(addr address.Address, response bson.Raw) description.S
 				}
 				desc.LastWriteTime = time.Unix(dt/1000, dt%1000*1000000).UTC()
 			}
+		case "localTime":
+			dt, ok := element.Value().DateTimeOK()
+			if !ok {
+				desc.LastError = fmt.Errorf("expected 'localTime' to be a datetime but it's a BSON %s", element.Value().Type)
+				return desc
+			}
+			desc.LocalTime = time.Unix(dt/1000, dt%1000*1000000).UTC()
 		case "logicalSessionTimeoutMinutes":
 			i64, ok := element.Value().AsInt64OK()
 			if !ok {
@@ -0,0 +1,67 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driverutil
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/ptrutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+func TestNewServerDescription(t *testing.T) {
+	t.Run("logicalSessionTimeoutMinutes", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			value   bsoncore.Value
+			want    *int64
+			wantErr bool
+		}{
+			{
+				name:  "int32",
+				value: bsoncore.Value{Type: bsoncore.TypeInt32, Data: bsoncore.AppendInt32(nil, 30)},
+				want:  ptrutil.Ptr[int64](30),
+			},
+			{
+				name:  "int64",
+				value: bsoncore.Value{Type: bsoncore.TypeInt64, Data: bsoncore.AppendInt64(nil, 45)},
+				want:  ptrutil.Ptr[int64](45),
+			},
+			{
+				name:    "string",
+				value:   bsoncore.Value{Type: bsoncore.TypeString, Data: bsoncore.AppendString(nil, "30")},
+				wantErr: true,
+			},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				hello := bsoncore.NewDocumentBuilder().
+					AppendInt32("ok", 1).
+					AppendBoolean("isWritablePrimary", true).
+					AppendInt32("maxWireVersion", 21).
+					AppendValue("logicalSessionTimeoutMinutes", tc.value).
+					Build()
+
+				desc := NewServerDescription(address.Address("localhost:27017"), bson.Raw(hello))
+				if tc.wantErr {
+					assert.NotNil(t, desc.LastError, "expected an error parsing logicalSessionTimeoutMinutes")
+					return
+				}
+
+				assert.Nil(t, desc.LastError, "unexpected error: %v", desc.LastError)
+				assert.Equal(t, tc.want, desc.SessionTimeoutMinutes,
+					"expected SessionTimeoutMinutes %v, got %v", tc.want, desc.SessionTimeoutMinutes)
+			})
+		}
+	})
+}
@@ -347,6 +347,22 @@ func TestClient(t *testing.T) {
 			assert.Nil(mt, err, "Disconnect error: %v", err)
 		})
 	})
+	mt.RunOpts("acquire connection", mtest.NewOptions().MinServerVersion("3.6"), func(mt *mtest.T) {
+		conn, err := mt.Client.AcquireConnection(context.Background(), readpref.Primary())
+		require.NoError(mt, err, "AcquireConnection error")
+		defer func() {
+			err := conn.Close()
+			assert.NoError(mt, err, "Close error")
+		}()
+
+		cmd := bsoncore.NewDocumentBuilder().AppendInt32("ping", 1).Build()
+		res, err := conn.RunRawCommand(context.Background(), "admin", cmd)
+		require.NoError(mt, err, "RunRawCommand error")
+
+		ok, err := res.LookupErr("ok")
+		require.NoError(mt, err, "expected reply %s to contain ok", res)
+		assert.Equal(mt, 1.0, ok.Double(), "expected ping to succeed, got reply %s", res)
+	})
 	mt.RunOpts("end sessions", mtest.NewOptions().MinServerVersion("3.6"), func(mt *mtest.T) {
 		_, err := mt.Client.ListDatabases(context.Background(), bson.D{})
 		assert.Nil(mt, err, "ListDatabases error: %v", err)
@@ -28,26 +28,32 @@ const (
 	ConnectionCheckoutFailed         = "Connection checkout failed"
 	ConnectionCheckedOut             = "Connection checked out"
 	ConnectionCheckedIn              = "Connection checked in"
+	ConnectionPinLeaked              = "Connection pinned longer than the leak detection threshold"
 	ServerSelectionFailed            = "Server selection failed"
 	ServerSelectionStarted           = "Server selection started"
 	ServerSelectionSucceeded         = "Server selection succeeded"
 	ServerSelectionWaiting           = "Waiting for suitable server to become available"
 	TopologyClosed                   = "Stopped topology monitoring"
 	TopologyDescriptionChanged       = "Topology description changed"
+	TopologyDuplicateHostsRemoved    = "Duplicate hosts removed from the seed list"
 	TopologyOpening                  = "Starting topology monitoring"
+	TopologyServerClockSkewed        = "Server clock skew exceeds the warning threshold"
 	TopologyServerClosed             = "Stopped server monitoring"
 	TopologyServerHeartbeatFailed    = "Server heartbeat failed"
 	TopologyServerHeartbeatStarted   = "Server heartbeat started"
 	TopologyServerHeartbeatSucceeded = "Server heartbeat succeeded"
 	TopologyServerOpening            = "Starting server monitoring"
+	TopologySRVRecordsTruncated      = "SRV records truncated"
 )
 
 const (
 	KeyAwaited             = "awaited"
+	KeyClockSkewMS         = "clockSkewMS"
 	KeyCommand             = "command"
 	KeyCommandName         = "commandName"
 	KeyDatabaseName        = "databaseName"
 	KeyDriverConnectionID  = "driverConnectionId"
+	KeyDuplicateHosts      = "duplicateHosts"
 	KeyDurationMS          = "durationMS"
 	KeyError               = "error"
 	KeyFailure             = "failure"
@@ -65,6 +71,9 @@ const (
 	KeyReply               = "reply"
 	KeyRequestID           = "requestId"
 	KeySelector            = "selector"
+	KeySRVRecordsDiscarded = "srvRecordsDiscarded"
+	KeySRVRecordsProcessed = "srvRecordsProcessed"
+	KeyStackTrace          = "stackTrace"
 	KeyServerConnectionID  = "serverConnectionId"
 	KeyServerHost          = "serverHost"
 	KeyServerPort          = "serverPort"
@@ -83,13 +92,15 @@ func (kvs *KeyValues) Add(key string, value interface{}) {
 }
 
 const (
-	ReasonConnClosedStale              = "Connection became stale because the pool was cleared"
-	ReasonConnClosedIdle               = "Connection has been available but unused for longer than the configured max idle time"
-	ReasonConnClosedError              = "An error occurred while using the connection"
-	ReasonConnClosedPoolClosed         = "Connection pool was closed"
-	ReasonConnCheckoutFailedTimout     = "Wait queue timeout elapsed without a connection becoming available"
-	ReasonConnCheckoutFailedError      = "An error occurred while trying to establish a new connection"
-	ReasonConnCheckoutFailedPoolClosed = "Connection pool was closed"
+	ReasonConnClosedStale                 = "Connection became stale because the pool was cleared"
+	ReasonConnClosedIdle                  = "Connection has been available but unused for longer than the configured max idle time"
+	ReasonConnClosedError                 = "An error occurred while using the connection"
+	ReasonConnClosedPoolClosed            = "Connection pool was closed"
+	ReasonConnCheckoutFailedTimout        = "Wait queue timeout elapsed without a connection becoming available"
+	ReasonConnCheckoutFailedError         = "An error occurred while trying to establish a new connection"
+	ReasonConnCheckoutFailedPoolClosed    = "Connection pool was closed"
+	ReasonConnCheckoutFailedExhausted     = "Connection pool was exhausted and the wait queue is configured to fail fast"
+	ReasonConnCheckoutFailedWaitQueueFull = "Connection pool wait queue was full"
 )
 
 // Component is an enumeration representing the "components" which can be
@@ -0,0 +1,146 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package serverselector
+
+import (
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+)
+
+// Reasons reported by Explain for a server that was not selected.
+const (
+	ReasonUnknown    = "server type is unknown"
+	ReasonServerType = "server type does not match the selector"
+	ReasonTags       = "server tags do not match the read preference tag sets"
+	ReasonStale      = "estimated staleness exceeds maxStalenessSeconds"
+	ReasonLatency    = "server is outside the latency window"
+	ReasonSelector   = "server was excluded by a custom selector"
+)
+
+// Exclusion describes why a server was not selected.
+type Exclusion struct {
+	Addr   address.Address
+	Reason string
+}
+
+// Explain runs selector against topo and returns, for each server that is not
+// selected, the reason it was excluded. Servers of kind Unknown are excluded
+// before the selector is applied, matching the topology. If selector is a
+// Composite, each server is attributed to the first stage that removes it. The
+// exclusions are returned in the order of topo.Servers.
+func Explain(selector description.ServerSelector, topo description.Topology) []Exclusion {
+	reasons := make(map[address.Address]string, len(topo.Servers))
+	candidates := make([]description.Server, 0, len(topo.Servers))
+	for _, s := range topo.Servers {
+		if s.Kind == description.Unknown {
+			reasons[s.Addr] = ReasonUnknown
+			continue
+		}
+		candidates = append(candidates, s)
+	}
+
+	for _, stage := range flatten(selector, nil) {
+		selected, err := stage.SelectServer(topo, candidates)
+		if err != nil {
+			// The error is reported by server selection itself.
+			break
+		}
+		for _, s := range candidates {
+			if !containsAddr(selected, s.Addr) {
+				reasons[s.Addr] = explainStage(stage, topo, s, candidates)
+			}
+		}
+		candidates = selected
+	}
+
+	var exclusions []Exclusion
+	for _, s := range topo.Servers {
+		if reason, ok := reasons[s.Addr]; ok {
+			exclusions = append(exclusions, Exclusion{Addr: s.Addr, Reason: reason})
+		}
+	}
+	return exclusions
+}
+
+// flatten appends the stages of selector to dst, expanding nested Composite
+// selectors.
+func flatten(selector description.ServerSelector, dst []description.ServerSelector) []description.ServerSelector {
+	composite, ok := selector.(*Composite)
+	if !ok {
+		return append(dst, selector)
+	}
+	for _, sel := range composite.Selectors {
+		dst = flatten(sel, dst)
+	}
+	return dst
+}
+
+// explainStage returns the reason stage removed s from candidates.
+func explainStage(
+	stage description.ServerSelector,
+	topo description.Topology,
+	s description.Server,
+	candidates []description.Server,
+) string {
+	switch stage := stage.(type) {
+	case *Latency:
+		return ReasonLatency
+	case *Write:
+		return ReasonServerType
+	case *ReadPref:
+		return explainReadPref(stage, topo, s, candidates)
+	default:
+		return ReasonSelector
+	}
+}
+
+// explainReadPref returns the reason the read preference stage removed s from
+// candidates. Only secondaries in a replica set can be excluded for staleness
+// or tags; any other server is excluded because of its type.
+func explainReadPref(
+	selector *ReadPref,
+	topo description.Topology,
+	s description.Server,
+	candidates []description.Server,
+) string {
+	switch topo.Kind {
+	case description.TopologyKindReplicaSetNoPrimary, description.TopologyKindReplicaSetWithPrimary:
+	default:
+		return ReasonServerType
+	}
+	if s.Kind != description.ServerKindRSSecondary {
+		return ReasonServerType
+	}
+	if selector.IsOutputAggregate && !supportsSecondaryOutputAggregate(candidates) {
+		return ReasonServerType
+	}
+
+	rp := selector.ReadPref
+	switch rp.Mode() {
+	case readpref.PrimaryMode:
+		return ReasonServerType
+	case readpref.PrimaryPreferredMode:
+		if len(selectByKind(candidates, description.ServerKindRSPrimary)) > 0 {
+			return ReasonServerType
+		}
+	}
+
+	if !containsAddr(selectSecondaries(rp, candidates), s.Addr) {
+		return ReasonStale
+	}
+	return ReasonTags
+}
+
+func containsAddr(servers []description.Server, addr address.Address) bool {
+	for _, s := range servers {
+		if s.Addr == addr {
+			return true
+		}
+	}
+	return false
+}
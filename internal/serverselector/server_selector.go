@@ -307,6 +307,17 @@ func selectByTagSet(candidates []description.Server, tagSets []tag.Set) []descri
 	return []description.Server{}
 }
 
+// supportsSecondaryOutputAggregate reports whether all candidates are 5.0+ and
+// can run an aggregate with an output stage on a secondary.
+func supportsSecondaryOutputAggregate(candidates []description.Server) bool {
+	for _, s := range candidates {
+		if s.WireVersion.Max < 13 {
+			return false
+		}
+	}
+	return true
+}
+
 func selectForReplicaSet(
 	rp *readpref.ReadPref,
 	isOutputAggregate bool,
@@ -319,12 +330,8 @@ func selectForReplicaSet(
 
 	// If underlying operation is an aggregate with an output stage, only apply read preference
 	// if all candidates are 5.0+. Otherwise, operate under primary read preference.
-	if isOutputAggregate {
-		for _, s := range candidates {
-			if s.WireVersion.Max < 13 {
-				return selectByKind(candidates, description.ServerKindRSPrimary), nil
-			}
-		}
+	if isOutputAggregate && !supportsSecondaryOutputAggregate(candidates) {
+		return selectByKind(candidates, description.ServerKindRSPrimary), nil
 	}
 
 	switch rp.Mode() {
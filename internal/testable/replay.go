@@ -0,0 +1,145 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package testable provides fakes for testing code that communicates with a
+// MongoDB deployment without a running server.
+package testable
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
+)
+
+// ErrNoResponsesRemaining is returned by ReplayConnectionSource.DialContext
+// when every recorded response has already been replayed.
+var ErrNoResponsesRemaining = errors.New("no recorded responses remaining")
+
+// ReplayConnectionSource replays recorded server responses to the driver. Each
+// wire message written to a connection dialed from the source is answered with
+// the next recorded response, in the order the responses were recorded. The
+// responses are shared by every connection dialed from the source, so a
+// recording of a complete interaction (e.g. the connection handshake followed
+// by the commands of a test) is replayed deterministically.
+//
+// The responseTo field of each response is rewritten to the request ID of the
+// wire message it answers, so recordings can be replayed regardless of the
+// request IDs chosen by the driver. Each request must be answered by exactly
+// one response, so requests with the moreToCome flag set are not supported.
+//
+// ReplayConnectionSource implements the topology.Dialer interface. It plugs
+// into the connectionConfig of a connection through topology.WithDialer, for
+// example as part of the options passed to topology.NewServer:
+//
+//	src := testable.NewReplayConnectionSource(helloReply, findReply)
+//	srv := topology.NewServer(addr, bson.NewObjectID(), connectTimeout,
+//		topology.WithConnectionOptions(func(opts ...topology.ConnectionOption) []topology.ConnectionOption {
+//			return append(opts, topology.WithDialer(func(topology.Dialer) topology.Dialer { return src }))
+//		}),
+//	)
+//
+// Because the server monitor also dials connections and sends heartbeats, the
+// recording must include its responses unless monitoring is disabled, e.g. by
+// using a load balanced server.
+type ReplayConnectionSource struct {
+	mu        sync.Mutex
+	responses [][]byte
+	requests  [][]byte
+}
+
+// NewReplayConnectionSource creates a ReplayConnectionSource that replays the
+// given wire messages in order.
+func NewReplayConnectionSource(responses ...[]byte) *ReplayConnectionSource {
+	return &ReplayConnectionSource{responses: responses}
+}
+
+// DialContext returns a connection that replays the recorded responses.
+func (s *ReplayConnectionSource) DialContext(context.Context, string, string) (net.Conn, error) {
+	if s.Remaining() == 0 {
+		return nil, ErrNoResponsesRemaining
+	}
+
+	client, server := net.Pipe()
+	go s.serve(server)
+	return client, nil
+}
+
+// Requests returns the wire messages that have been written to connections
+// dialed from the source, in the order they were written.
+func (s *ReplayConnectionSource) Requests() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([][]byte(nil), s.requests...)
+}
+
+// Remaining returns the number of recorded responses that have not been
+// replayed yet.
+func (s *ReplayConnectionSource) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.responses)
+}
+
+// next records req and returns the response to it, or false if no responses
+// remain.
+func (s *ReplayConnectionSource) next(req []byte) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+	if len(s.responses) == 0 {
+		return nil, false
+	}
+	res := s.responses[0]
+	s.responses = s.responses[1:]
+	return res, true
+}
+
+// serve answers each wire message read from conn with the next recorded
+// response. It closes conn when the driver closes the connection or no
+// responses remain.
+func (s *ReplayConnectionSource) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		req, err := drivertest.ReadWireMessage(conn)
+		if err != nil {
+			return
+		}
+		res, ok := s.next(req)
+		if !ok {
+			return
+		}
+
+		// Copy the response so the recording is not modified and can be reused.
+		res = append([]byte(nil), res...)
+		_, requestID, _, _, _, ok := wiremessage.ReadHeader(req)
+		if ok && len(res) >= 12 {
+			binary.LittleEndian.PutUint32(res[8:12], uint32(requestID))
+		}
+		if _, err := conn.Write(res); err != nil {
+			return
+		}
+	}
+}
+
+// OpMsgReply returns an OP_MSG wire message containing doc, which can be used
+// as a recorded response.
+func OpMsgReply(doc bsoncore.Document) []byte {
+	idx, wm := wiremessage.AppendHeaderStart(nil, 0, 0, wiremessage.OpMsg)
+	wm = wiremessage.AppendMsgFlags(wm, 0)
+	wm = wiremessage.AppendMsgSectionType(wm, wiremessage.SingleDocument)
+	wm = bsoncore.AppendDocument(wm, doc)
+	return bsoncore.UpdateLength(wm, idx, int32(len(wm[idx:])))
+}
@@ -0,0 +1,148 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package testable_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/internal/testable"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/operation"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
+)
+
+func TestReplayConnectionSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hello and find", func(t *testing.T) {
+		t.Parallel()
+
+		helloReply := testable.OpMsgReply(bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 1).
+			AppendBoolean("isWritablePrimary", true).
+			AppendInt32("maxWireVersion", 21).
+			AppendObjectID("serviceId", bson.NewObjectID()).
+			AppendInt32("connectionId", 42).
+			Build())
+		doc := bsoncore.NewDocumentBuilder().AppendInt32("_id", 1).AppendString("x", "replayed").Build()
+		findReply := testable.OpMsgReply(bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 1).
+			AppendDocument("cursor", bsoncore.NewDocumentBuilder().
+				AppendInt64("id", 0).
+				AppendString("ns", "db.coll").
+				AppendArray("firstBatch", bsoncore.NewArrayBuilder().AppendDocument(doc).Build()).
+				Build()).
+			Build())
+
+		src := testable.NewReplayConnectionSource(helloReply, findReply)
+		srv := topology.NewServer(
+			address.Address("replay:27017"),
+			bson.NewObjectID(),
+			time.Second,
+			topology.WithServerLoadBalanced(func(bool) bool { return true }),
+			topology.WithConnectionOptions(func(opts ...topology.ConnectionOption) []topology.ConnectionOption {
+				return append(opts,
+					topology.WithDialer(func(topology.Dialer) topology.Dialer { return src }),
+					topology.WithHandshaker(func(topology.Handshaker) topology.Handshaker {
+						return operation.NewHello().LoadBalanced(true)
+					}),
+				)
+			}),
+		)
+		require.NoError(t, srv.Connect(nil), "Connect error")
+		defer func() { _ = srv.Disconnect(context.Background()) }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		conn, err := srv.Connection(ctx)
+		require.NoError(t, err, "Connection error")
+		defer conn.Close()
+
+		find := operation.NewFind(bsoncore.NewDocumentBuilder().Build()).
+			Database("db").
+			Collection("coll").
+			Deployment(driver.SingleConnectionDeployment{C: conn})
+		require.NoError(t, find.Execute(ctx), "Execute error")
+
+		cursor, err := find.Result(driver.CursorOptions{})
+		require.NoError(t, err, "Result error")
+		docs, err := cursor.Batch().Documents()
+		require.NoError(t, err, "Documents error")
+		require.Len(t, docs, 1, "expected one document in the first batch")
+		assert.Equal(t, "replayed", docs[0].Lookup("x").StringValue(), "unexpected document")
+
+		assert.Equal(t, 0, src.Remaining(), "expected all responses to be replayed")
+		requests := src.Requests()
+		require.Len(t, requests, 2, "expected a hello and a find request")
+		for i, cmd := range []string{"hello", "find"} {
+			assert.Equal(t, cmd, commandName(t, requests[i]), "unexpected command for request %d", i)
+		}
+	})
+	t.Run("responseTo matches request", func(t *testing.T) {
+		t.Parallel()
+
+		reply := testable.OpMsgReply(bsoncore.NewDocumentBuilder().AppendInt32("ok", 1).Build())
+		src := testable.NewReplayConnectionSource(reply)
+		conn, err := src.DialContext(context.Background(), "tcp", "replay:27017")
+		require.NoError(t, err, "DialContext error")
+		defer conn.Close()
+
+		idx, req := wiremessage.AppendHeaderStart(nil, 1234, 0, wiremessage.OpMsg)
+		req = wiremessage.AppendMsgFlags(req, 0)
+		req = wiremessage.AppendMsgSectionType(req, wiremessage.SingleDocument)
+		req = bsoncore.AppendDocument(req, bsoncore.NewDocumentBuilder().AppendInt32("ping", 1).Build())
+		req = bsoncore.UpdateLength(req, idx, int32(len(req[idx:])))
+		_, err = conn.Write(req)
+		require.NoError(t, err, "Write error")
+
+		res := make([]byte, len(reply))
+		_, err = conn.Read(res)
+		require.NoError(t, err, "Read error")
+		_, _, responseTo, _, _, ok := wiremessage.ReadHeader(res)
+		require.True(t, ok, "expected a valid wire message header")
+		assert.Equal(t, int32(1234), responseTo, "unexpected responseTo")
+
+		_, err = src.DialContext(context.Background(), "tcp", "replay:27017")
+		assert.ErrorIs(t, err, testable.ErrNoResponsesRemaining)
+	})
+}
+
+// commandName returns the name of the command in the OP_MSG or OP_QUERY wire
+// message wm.
+func commandName(t *testing.T, wm []byte) string {
+	t.Helper()
+
+	_, _, _, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	require.True(t, ok, "expected a valid wire message header")
+
+	var doc bsoncore.Document
+	switch opcode {
+	case wiremessage.OpMsg:
+		_, rem, _ = wiremessage.ReadMsgFlags(rem)
+		_, rem, _ = wiremessage.ReadMsgSectionType(rem)
+		doc, _, ok = wiremessage.ReadMsgSectionSingleDocument(rem)
+	case wiremessage.OpQuery:
+		_, rem, _ = wiremessage.ReadQueryFlags(rem)
+		_, rem, _ = wiremessage.ReadQueryFullCollectionName(rem)
+		_, rem, _ = wiremessage.ReadQueryNumberToSkip(rem)
+		_, rem, _ = wiremessage.ReadQueryNumberToReturn(rem)
+		doc, _, ok = wiremessage.ReadQueryQuery(rem)
+	}
+	require.True(t, ok, "expected a command document")
+	elem, err := doc.IndexErr(0)
+	require.NoError(t, err, "IndexErr error")
+	return elem.Key()
+}
@@ -40,6 +40,10 @@ type batchCursor interface {
 	// the cursor that implements it.
 	SetBatchSize(int32)
 
+	// SetTargetBatchBytes is a modifier function used to set the approximate
+	// number of bytes to fetch in each batch of the cursor that implements it.
+	SetTargetBatchBytes(int32)
+
 	// SetMaxAwaitTime will set the maximum amount of time the server will allow
 	// the operations to execute. The server will error if this field is set
 	// but the cursor is not configured with awaitData=true.
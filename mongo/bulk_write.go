@@ -39,6 +39,8 @@ type bulkWrite struct {
 	writeConcern             *writeconcern.WriteConcern
 	result                   BulkWriteResult
 	let                      interface{}
+	batchSize                int
+	batchFn                  func(*BulkWriteBatchResult) error
 }
 
 func (bw *bulkWrite) execute(ctx context.Context) error {
@@ -47,7 +49,7 @@ func (bw *bulkWrite) execute(ctx context.Context) error {
 		ordered = *bw.ordered
 	}
 
-	batches := createBatches(bw.models, ordered)
+	batches := splitBatches(createBatches(bw.models, ordered), bw.batchSize)
 	bw.result = BulkWriteResult{
 		UpsertedIDs: make(map[int64]interface{}),
 	}
@@ -65,19 +67,43 @@ func (bw *bulkWrite) execute(ctx context.Context) error {
 
 		batchRes, batchErr, err := bw.runBatch(ctx, batch)
 
+		// Adjust the counts before merging them so that the totals are
+		// consistent however the loop exits.
+		batchRes.MatchedCount -= batchRes.UpsertedCount
 		bw.mergeResults(batchRes)
 
 		bwErr.WriteConcernError = batchErr.WriteConcernError
 		bwErr.Labels = append(bwErr.Labels, batchErr.Labels...)
 
-		bwErr.WriteErrors = append(bwErr.WriteErrors, batchErr.WriteErrors...)
+		// When streaming, write errors are handed to the callback instead of
+		// being accumulated for the whole operation.
+		if bw.batchFn == nil {
+			bwErr.WriteErrors = append(bwErr.WriteErrors, batchErr.WriteErrors...)
+		}
 
 		commandErrorOccurred := err != nil && !errors.Is(err, driver.ErrUnacknowledgedWrite)
 		writeErrorOccurred := len(batchErr.WriteErrors) > 0 || batchErr.WriteConcernError != nil
+
+		if bw.batchFn != nil && !commandErrorOccurred {
+			batchRes.Acknowledged = err == nil
+			fnErr := bw.batchFn(&BulkWriteBatchResult{
+				Indexes:           batch.indexes,
+				Result:            batchRes,
+				WriteErrors:       batchErr.WriteErrors,
+				WriteConcernError: batchErr.WriteConcernError,
+			})
+			if fnErr != nil {
+				return fnErr
+			}
+		}
+
 		if !continueOnError && (commandErrorOccurred || writeErrorOccurred) {
 			if err != nil {
 				return err
 			}
+			if bw.batchFn != nil {
+				bwErr.WriteErrors = batchErr.WriteErrors
+			}
 
 			return bwErr
 		}
@@ -87,8 +113,6 @@ func (bw *bulkWrite) execute(ctx context.Context) error {
 		}
 	}
 
-	bw.result.MatchedCount -= bw.result.UpsertedCount
-
 	rr, err := processWriteError(lastErr)
 	if err != nil {
 		return err
@@ -584,6 +608,29 @@ func createOrderedBatches(models []WriteModel) []bulkWriteBatch {
 	return batches
 }
 
+// splitBatches splits each batch into batches of at most size models. If size
+// is not positive, batches is returned unmodified.
+func splitBatches(batches []bulkWriteBatch, size int) []bulkWriteBatch {
+	if size <= 0 {
+		return batches
+	}
+
+	var split []bulkWriteBatch
+	for _, batch := range batches {
+		for len(batch.models) > size {
+			split = append(split, bulkWriteBatch{
+				models:   batch.models[:size],
+				canRetry: batch.canRetry,
+				indexes:  batch.indexes[:size],
+			})
+			batch.models = batch.models[size:]
+			batch.indexes = batch.indexes[size:]
+		}
+		split = append(split, batch)
+	}
+	return split
+}
+
 func (bw *bulkWrite) mergeResults(newResult BulkWriteResult) {
 	bw.result.InsertedCount += newResult.InsertedCount
 	bw.result.MatchedCount += newResult.MatchedCount
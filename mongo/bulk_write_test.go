@@ -0,0 +1,178 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/xoptions"
+)
+
+func TestCollection_BulkWriteStream(t *testing.T) {
+	t.Parallel()
+
+	const numModels = 1000
+	const batchSize = 100
+
+	newCollection := func(t *testing.T, started *int, responses ...bson.D) *Collection {
+		t.Helper()
+
+		monitor := &event.CommandMonitor{
+			Started: func(context.Context, *event.CommandStartedEvent) {
+				*started++
+			},
+		}
+		opts := options.Client().SetMonitor(monitor)
+		err := xoptions.SetInternalClientOptions(opts, "deployment", drivertest.NewMockDeployment(responses...))
+		require.NoError(t, err, "SetInternalClientOptions error")
+		client, err := Connect(opts)
+		require.NoError(t, err, "Connect error")
+
+		return client.Database("db").Collection("coll")
+	}
+
+	models := make([]WriteModel, numModels)
+	for i := range models {
+		models[i] = NewInsertOneModel().SetDocument(bson.D{{"x", i}})
+	}
+
+	dupKeyResponse := bson.D{
+		{"ok", 1},
+		{"n", batchSize - 1},
+		{"writeErrors", bson.A{bson.D{{"index", 5}, {"code", 11000}, {"errmsg", "duplicate key"}}}},
+	}
+
+	t.Run("unordered results stream incrementally", func(t *testing.T) {
+		t.Parallel()
+
+		responses := make([]bson.D, 0, numModels/batchSize)
+		for i := 0; i < numModels/batchSize; i++ {
+			responses = append(responses, bson.D{{"ok", 1}, {"n", batchSize}})
+		}
+		responses[3] = dupKeyResponse
+
+		var started int
+		coll := newCollection(t, &started, responses...)
+
+		var batches []*BulkWriteBatchResult
+		fn := func(res *BulkWriteBatchResult) error {
+			batches = append(batches, res)
+			// Each batch must be reported before the next command is sent.
+			assert.Equal(t, len(batches), started, "expected batch to be reported after its command")
+			return nil
+		}
+
+		opts := options.BulkWrite().SetOrdered(false).SetBatchSize(batchSize)
+		res, err := coll.BulkWriteStream(context.Background(), models, fn, opts)
+		require.NoError(t, err, "BulkWriteStream error")
+
+		require.Len(t, batches, numModels/batchSize, "unexpected number of batches")
+		for i, batch := range batches {
+			require.Len(t, batch.Indexes, batchSize, "unexpected number of models in batch %d", i)
+			assert.Equal(t, i*batchSize, batch.Indexes[0], "unexpected first index in batch %d", i)
+			assert.True(t, batch.Result.Acknowledged, "expected batch %d to be acknowledged", i)
+		}
+
+		require.Len(t, batches[3].WriteErrors, 1, "expected a write error in batch 3")
+		assert.Equal(t, 3*batchSize+5, batches[3].WriteErrors[0].Index, "expected write error index to refer to models")
+		assert.Equal(t, int64(batchSize-1), batches[3].Result.InsertedCount, "unexpected inserted count in batch 3")
+		assert.Equal(t, int64(numModels-1), res.InsertedCount, "unexpected total inserted count")
+	})
+	t.Run("ordered stops after write error", func(t *testing.T) {
+		t.Parallel()
+
+		var started int
+		coll := newCollection(t, &started,
+			bson.D{{"ok", 1}, {"n", batchSize}},
+			dupKeyResponse,
+			bson.D{{"ok", 1}, {"n", batchSize}})
+
+		var batches int
+		fn := func(*BulkWriteBatchResult) error {
+			batches++
+			return nil
+		}
+
+		opts := options.BulkWrite().SetBatchSize(batchSize)
+		res, err := coll.BulkWriteStream(context.Background(), models, fn, opts)
+
+		var bwe BulkWriteException
+		require.True(t, errors.As(err, &bwe), "expected BulkWriteException, got %v", err)
+		require.Len(t, bwe.WriteErrors, 1, "expected only the failing batch's write errors")
+		assert.Equal(t, batchSize+5, bwe.WriteErrors[0].Index, "unexpected write error index")
+		assert.Equal(t, 2, batches, "expected execution to stop after the failing batch")
+		assert.Equal(t, 2, started, "expected no commands after the failing batch")
+		assert.Equal(t, int64(2*batchSize-1), res.InsertedCount, "unexpected total inserted count")
+	})
+	t.Run("callback error stops execution", func(t *testing.T) {
+		t.Parallel()
+
+		var started int
+		coll := newCollection(t, &started,
+			bson.D{{"ok", 1}, {"n", batchSize}},
+			bson.D{{"ok", 1}, {"n", batchSize}})
+
+		stop := errors.New("stop")
+		fn := func(*BulkWriteBatchResult) error { return stop }
+
+		opts := options.BulkWrite().SetOrdered(false).SetBatchSize(batchSize)
+		_, err := coll.BulkWriteStream(context.Background(), models, fn, opts)
+		assert.ErrorIs(t, err, stop, "expected callback error")
+		assert.Equal(t, 1, started, "expected no commands after the callback error")
+	})
+	t.Run("callback error keeps totals consistent", func(t *testing.T) {
+		t.Parallel()
+
+		var started int
+		coll := newCollection(t, &started, bson.D{
+			{"ok", 1},
+			{"n", 3},
+			{"nModified", 2},
+			{"upserted", bson.A{bson.D{{"index", 2}, {"_id", "upserted"}}}},
+		})
+
+		updates := []WriteModel{
+			NewUpdateOneModel().SetFilter(bson.D{{"x", 0}}).SetUpdate(bson.D{{"$set", bson.D{{"y", 1}}}}),
+			NewUpdateOneModel().SetFilter(bson.D{{"x", 1}}).SetUpdate(bson.D{{"$set", bson.D{{"y", 1}}}}),
+			NewUpdateOneModel().SetFilter(bson.D{{"x", 2}}).SetUpdate(bson.D{{"$set", bson.D{{"y", 1}}}}).SetUpsert(true),
+		}
+
+		stop := errors.New("stop")
+		var batch BulkWriteResult
+		fn := func(res *BulkWriteBatchResult) error {
+			batch = res.Result
+			return stop
+		}
+
+		res, err := coll.BulkWriteStream(context.Background(), updates, fn)
+		assert.ErrorIs(t, err, stop, "expected callback error")
+		assert.Equal(t, int64(2), batch.MatchedCount, "unexpected matched count in batch")
+		assert.Equal(t, int64(1), batch.UpsertedCount, "unexpected upserted count in batch")
+		require.NotNil(t, res, "expected a result")
+		assert.Equal(t, int64(2), res.MatchedCount, "unexpected total matched count")
+		assert.Equal(t, int64(1), res.UpsertedCount, "unexpected total upserted count")
+	})
+	t.Run("invalid batch size", func(t *testing.T) {
+		t.Parallel()
+
+		var started int
+		coll := newCollection(t, &started)
+
+		opts := options.BulkWrite().SetBatchSize(0)
+		_, err := coll.BulkWrite(context.Background(), models, opts)
+		assert.Error(t, err, "expected error for invalid batch size")
+		assert.Equal(t, 0, started, "expected no commands to be sent")
+	})
+}
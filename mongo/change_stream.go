@@ -7,6 +7,7 @@
 package mongo
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -160,6 +161,15 @@ func newChangeStream(ctx context.Context, config changeStreamConfig, pipeline in
 		cursorOptions: cursorOpts,
 	}
 
+	if cs.options.MaxAwaitTime != nil && *cs.options.MaxAwaitTime < 0 {
+		return nil, fmt.Errorf("MaxAwaitTime must not be negative, got %v", *cs.options.MaxAwaitTime)
+	}
+	// The Watch context only bounds the initial aggregate and each call to Next checks MaxAwaitTime
+	// against its own context, so only the client-level Timeout is checked here.
+	if !validChangeStreamTimeouts(context.Background(), cs) {
+		return nil, fmt.Errorf("MaxAwaitTime must be less than the operation timeout")
+	}
+
 	cs.sess = sessionFromContext(ctx)
 	if cs.sess == nil && cs.client.sessionPool != nil {
 		cs.sess = session.NewImplicitClientSession(cs.client.sessionPool, cs.client.id)
@@ -402,7 +412,17 @@ AggregateExecuteLoop:
 func (cs *ChangeStream) updatePbrtFromCommand() {
 	// Only cache the pbrt if an empty batch was returned and a pbrt was included
 	if pbrt := cs.cursor.PostBatchResumeToken(); cs.emptyBatch() && pbrt != nil {
-		cs.resumeToken = bson.Raw(pbrt)
+		cs.setResumeToken(bson.Raw(pbrt))
+	}
+}
+
+// setResumeToken caches token as the resume token and passes a copy of it to the ResumeTokenHandler if it differs
+// from the previously cached token.
+func (cs *ChangeStream) setResumeToken(token bson.Raw) {
+	changed := !bytes.Equal(cs.resumeToken, token)
+	cs.resumeToken = token
+	if changed && cs.options.ResumeTokenHandler != nil {
+		cs.options.ResumeTokenHandler(append(bson.Raw(nil), token...))
 	}
 }
 
@@ -425,7 +445,7 @@ func (cs *ChangeStream) storeResumeToken() error {
 		}
 	}
 
-	cs.resumeToken = tokenDoc
+	cs.setResumeToken(tokenDoc)
 	return nil
 }
 
@@ -11,8 +11,13 @@ import (
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/xoptions"
 )
 
 func TestChangeStream(t *testing.T) {
@@ -123,3 +128,182 @@ func TestValidChangeStreamTimeouts(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeStream_ResumeTokenHandler(t *testing.T) {
+	t.Parallel()
+
+	token := func(data string) bson.D {
+		return bson.D{{"_data", data}}
+	}
+	event := func(data string) bson.D {
+		return bson.D{{"_id", token(data)}, {"operationType", "insert"}}
+	}
+	cursor := func(batchName string, pbrt string, events ...interface{}) bson.D {
+		batch := append(bson.A{}, events...)
+		return bson.D{
+			{"ok", 1},
+			{"cursor", bson.D{
+				{"id", int64(1)},
+				{"ns", "db.coll"},
+				{batchName, batch},
+				{"postBatchResumeToken", token(pbrt)},
+			}},
+		}
+	}
+
+	opts := options.Client()
+	err := xoptions.SetInternalClientOptions(opts, "deployment", drivertest.NewMockDeployment(
+		cursor("firstBatch", "02", event("01"), event("02")),
+		cursor("nextBatch", "03"),
+		cursor("nextBatch", "05", event("04")),
+		bson.D{{"ok", 1}},
+	))
+	require.NoError(t, err, "SetInternalClientOptions error")
+	client, err := Connect(opts)
+	require.NoError(t, err, "Connect error")
+
+	var tokens []string
+	handler := func(token bson.Raw) {
+		tokens = append(tokens, token.Lookup("_data").StringValue())
+	}
+	cs, err := client.Database("db").Collection("coll").Watch(
+		context.Background(),
+		Pipeline{},
+		options.ChangeStream().SetResumeAfter(token("00")).SetResumeTokenHandler(handler))
+	require.NoError(t, err, "Watch error")
+
+	for i := 0; i < 3; i++ {
+		require.True(t, cs.Next(context.Background()), "expected event %d, got error %v", i, cs.Err())
+	}
+	require.NoError(t, cs.Close(context.Background()), "Close error")
+
+	assert.Equal(t, []string{"01", "02", "03", "05"}, tokens, "unexpected resume tokens")
+	for i := 1; i < len(tokens); i++ {
+		assert.Less(t, tokens[i-1], tokens[i], "expected resume tokens to be monotonic")
+	}
+	assert.Equal(t, "05", cs.ResumeToken().Lookup("_data").StringValue(), "unexpected cached resume token")
+}
+
+func TestChangeStream_MaxAwaitTime(t *testing.T) {
+	t.Parallel()
+
+	cursor := func(batchName string) bson.D {
+		return bson.D{
+			{"ok", 1},
+			{"cursor", bson.D{
+				{"id", int64(1)},
+				{"ns", "db.coll"},
+				{batchName, bson.A{bson.D{{"_id", bson.D{{"_data", "01"}}}}}},
+			}},
+		}
+	}
+
+	// watch opens a change stream with the given context, client Timeout and
+	// MaxAwaitTime and returns the commands sent to the server.
+	watch := func(
+		ctx context.Context,
+		t *testing.T,
+		timeout, maxAwaitTime time.Duration,
+	) (*ChangeStream, map[string]bson.Raw, error) {
+		t.Helper()
+
+		cmds := make(map[string]bson.Raw)
+		monitor := &event.CommandMonitor{
+			Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+				cmds[evt.CommandName] = evt.Command
+			},
+		}
+		opts := options.Client().SetMonitor(monitor)
+		if timeout != 0 {
+			opts.SetTimeout(timeout)
+		}
+		err := xoptions.SetInternalClientOptions(opts, "deployment", drivertest.NewMockDeployment(
+			cursor("firstBatch"),
+			cursor("nextBatch"),
+			bson.D{{"ok", 1}},
+		))
+		require.NoError(t, err, "SetInternalClientOptions error")
+		client, err := Connect(opts)
+		require.NoError(t, err, "Connect error")
+
+		cs, err := client.Database("db").Collection("coll").Watch(
+			ctx,
+			Pipeline{},
+			options.ChangeStream().SetMaxAwaitTime(maxAwaitTime))
+		return cs, cmds, err
+	}
+
+	t.Run("sent with getMore", func(t *testing.T) {
+		t.Parallel()
+
+		cs, cmds, err := watch(context.Background(), t, 0, 250*time.Millisecond)
+		require.NoError(t, err, "Watch error")
+		for i := 0; i < 2; i++ {
+			require.True(t, cs.Next(context.Background()), "expected event %d, got error %v", i, cs.Err())
+		}
+		require.NoError(t, cs.Close(context.Background()), "Close error")
+
+		_, err = cmds["aggregate"].LookupErr("maxTimeMS")
+		assert.Error(t, err, "expected maxTimeMS not to be sent with aggregate")
+		val, err := cmds["getMore"].LookupErr("maxTimeMS")
+		require.NoError(t, err, "expected maxTimeMS to be sent with getMore")
+		assert.Equal(t, int64(250), val.Int64(), "unexpected maxTimeMS")
+	})
+
+	testCases := []struct {
+		name         string
+		timeout      time.Duration
+		maxAwaitTime time.Duration
+		wantErr      string
+	}{
+		{
+			name:         "negative",
+			maxAwaitTime: -time.Second,
+			wantErr:      "MaxAwaitTime must not be negative",
+		},
+		{
+			name:         "equal to timeout",
+			timeout:      time.Second,
+			maxAwaitTime: time.Second,
+			wantErr:      "MaxAwaitTime must be less than the operation timeout",
+		},
+		{
+			name:         "greater than timeout",
+			timeout:      time.Second,
+			maxAwaitTime: time.Minute,
+			wantErr:      "MaxAwaitTime must be less than the operation timeout",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, cmds, err := watch(context.Background(), t, tc.timeout, tc.maxAwaitTime)
+			assert.ErrorContains(t, err, tc.wantErr)
+			assert.Len(t, cmds, 0, "expected no commands to be sent")
+		})
+	}
+
+	t.Run("zero", func(t *testing.T) {
+		t.Parallel()
+
+		cs, _, err := watch(context.Background(), t, 0, 0)
+		require.NoError(t, err, "Watch error")
+		require.NoError(t, cs.Close(context.Background()), "Close error")
+	})
+	t.Run("Watch context deadline shorter than MaxAwaitTime", func(t *testing.T) {
+		t.Parallel()
+
+		// The Watch context only bounds the initial aggregate, so its deadline
+		// is not compared against MaxAwaitTime.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cs, cmds, err := watch(ctx, t, 0, time.Minute)
+		require.NoError(t, err, "Watch error")
+		require.NoError(t, cs.Close(context.Background()), "Close error")
+		assert.Contains(t, cmds, "aggregate", "expected the aggregate to be sent")
+	})
+}
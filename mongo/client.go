@@ -11,6 +11,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -74,6 +77,9 @@ type Client struct {
 	httpClient     *http.Client
 	logger         *logger.Logger
 
+	// namespaceWriteConcerns maps database names and "db.coll" namespaces to their default write concerns.
+	namespaceWriteConcerns map[string]*writeconcern.WriteConcern
+
 	// in-use encryption fields
 	isAutoEncryptionSet bool
 	keyVaultClientFLE   *Client
@@ -84,6 +90,9 @@ type Client struct {
 	internalClientFLE   *Client
 	encryptedFieldsMap  map[string]interface{}
 	authenticator       driver.Authenticator
+
+	buildInfoMu sync.Mutex
+	buildInfo   *cachedBuildInfo
 }
 
 // Connect creates a new Client and then initializes it using the Connect method.
@@ -192,6 +201,12 @@ func newClient(opts ...*options.ClientOptions) (*Client, error) {
 	if clientOpts.WriteConcern != nil {
 		client.writeConcern = clientOpts.WriteConcern
 	}
+	if len(clientOpts.NamespaceWriteConcerns) > 0 {
+		client.namespaceWriteConcerns = make(map[string]*writeconcern.WriteConcern, len(clientOpts.NamespaceWriteConcerns))
+		for ns, wc := range clientOpts.NamespaceWriteConcerns {
+			client.namespaceWriteConcerns[ns] = wc
+		}
+	}
 	// AutoEncryptionOptions
 	if clientOpts.AutoEncryptionOptions != nil {
 		client.isAutoEncryptionSet = true
@@ -248,6 +263,11 @@ func newClient(opts ...*options.ClientOptions) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid logger options: %w", err)
 	}
+	if client.logger != nil {
+		for _, warning := range clientOpts.Warnings() {
+			client.logger.Print(logger.LevelInfo, logger.ComponentTopology, warning)
+		}
+	}
 
 	return client, nil
 }
@@ -431,6 +451,9 @@ func (c *Client) StartSession(opts ...options.Lister[options.SessionOptions]) (*
 	if sessArgs.Snapshot != nil {
 		coreOpts.Snapshot = sessArgs.Snapshot
 	}
+	if sessArgs.ServerAffinity != nil {
+		coreOpts.ServerAffinity = sessArgs.ServerAffinity
+	}
 
 	sess, err := session.NewClientSession(c.sessionPool, c.id, coreOpts)
 	if err != nil {
@@ -865,6 +888,290 @@ func (c *Client) NumberSessionsInProgress() int {
 	return int(c.sessionPool.CheckedOut())
 }
 
+// LogicalSessionTimeout returns the logical session timeout reported by the deployment via the
+// "logicalSessionTimeoutMinutes" field of the server handshake. Applications that manage explicit sessions can use
+// this value to avoid using a session after the server has expired it. If the deployment does not support sessions,
+// or no server has been discovered yet, this returns nil.
+func (c *Client) LogicalSessionTimeout() *time.Duration {
+	if c.sessionPool == nil {
+		return nil
+	}
+
+	minutes := c.sessionPool.TimeoutMinutes()
+	if minutes == nil {
+		return nil
+	}
+
+	timeout := time.Duration(*minutes) * time.Minute
+	return &timeout
+}
+
+// PoolStats is a point-in-time snapshot of the connection pool of a single server.
+type PoolStats struct {
+	// TotalConnections is the number of connections owned by the pool, including idle, in-use and pending
+	// connections.
+	TotalConnections int
+
+	// InUseConnections is the number of established connections that are currently checked out of the pool.
+	InUseConnections int
+
+	// IdleConnections is the number of connections available for check out.
+	IdleConnections int
+
+	// PendingConnections is the number of connections being established. It is bounded by the MaxConnecting client
+	// option.
+	PendingConnections int
+
+	// WaitQueueLength is the number of operations waiting to check out a connection.
+	WaitQueueLength int
+
+	// PinnedCursorConnections is the number of connections pinned to a cursor. This is only used in load balanced
+	// mode.
+	PinnedCursorConnections uint64
+
+	// PinnedTransactionConnections is the number of connections pinned to a transaction. This is only used in load
+	// balanced mode.
+	PinnedTransactionConnections uint64
+}
+
+// PoolStats returns a snapshot of the connection pool of every server known to the Client, keyed by server address.
+// It is safe to call concurrently with running operations. Counts are read from each pool one lock at a time, so
+// they are only approximately consistent with each other while connections are being checked in and out. If the
+// Client is not connected to a deployment managed by the driver, this returns nil.
+func (c *Client) PoolStats() map[string]PoolStats {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return nil
+	}
+
+	poolStats := topo.PoolStats()
+	stats := make(map[string]PoolStats, len(poolStats))
+	for addr, s := range poolStats {
+		stats[addr.String()] = PoolStats(s)
+	}
+	return stats
+}
+
+// OperationStats is a snapshot of the outcomes of the operations run against a single server over a rolling window.
+type OperationStats struct {
+	// Window is the period over which the outcomes are counted. It is set with the OperationErrorRateWindow client
+	// option.
+	Window time.Duration
+
+	// Successes is the number of operations that completed without an error within the window.
+	Successes int64
+
+	// Failures is the number of operations that completed with an error within the window. Operations cancelled
+	// through their context are not counted.
+	Failures int64
+}
+
+// ErrorRate returns the fraction of operations within the window that failed, in the range [0, 1]. If no operations
+// completed within the window, it returns 0.
+func (s OperationStats) ErrorRate() float64 {
+	return topology.OperationStats(s).ErrorRate()
+}
+
+// OperationStats returns a snapshot of the operation outcomes of every server known to the Client, keyed by server
+// address. Each attempt of an operation, including each retry, is counted against the server it was sent to, so the
+// stats can be used to build server selection filters or circuit breakers that avoid servers with a high error rate.
+// If the Client is not connected to a deployment managed by the driver, this returns nil.
+func (c *Client) OperationStats() map[string]OperationStats {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return nil
+	}
+
+	opStats := topo.OperationStats()
+	stats := make(map[string]OperationStats, len(opStats))
+	for addr, s := range opStats {
+		stats[addr.String()] = OperationStats(s)
+	}
+	return stats
+}
+
+// PrimeConnections establishes connections to each of the given hosts (e.g. "host1.example.com:27017") until each
+// host's connection pool holds at least n connections, limited by the MaxPoolSize client option. This is useful to
+// avoid connection establishment latency for the first operations sent to specific hosts, such as secondaries used for
+// analytics workloads. The connections are created by the hosts' connection pools and are available to any operation.
+//
+// Hosts are primed concurrently. A host that has not been discovered yet is waited for until ctx is done or the server
+// selection timeout expires. PrimeConnections returns the errors that occurred keyed by host, or nil if every host was
+// primed successfully.
+func (c *Client) PrimeConnections(ctx context.Context, hosts []string, n int) map[string]error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		errs := make(map[string]error, len(hosts))
+		for _, host := range hosts {
+			errs[host] = errors.New("connections can only be primed for deployments managed by the driver")
+		}
+		return errs
+	}
+
+	return topo.PrimeConnections(ctx, hosts, n)
+}
+
+// PauseCheckOuts pauses connection check outs from the connection pool of the given host (e.g.
+// "host1.example.com:27017"), for example during a maintenance window of that server. While paused, operations that
+// select the host fail fast with an error instead of waiting for a connection. Operations that already hold a connection
+// run to completion, and the pool's idle connections are kept. Use ResumeCheckOuts to allow check outs again.
+//
+// Server selection is not affected, so operations that can only be sent to the paused host fail until it is resumed.
+// PauseCheckOuts returns an error if the host is not part of the deployment.
+func (c *Client) PauseCheckOuts(host string) error {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return errors.New("check outs can only be paused for deployments managed by the driver")
+	}
+	return topo.PauseCheckOuts(host)
+}
+
+// ResumeCheckOuts resumes connection check outs from the connection pool of the given host after PauseCheckOuts.
+func (c *Client) ResumeCheckOuts(host string) error {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return errors.New("check outs can only be resumed for deployments managed by the driver")
+	}
+	return topo.ResumeCheckOuts(host)
+}
+
+// AcquireConnection selects a server using rp and checks a connection to it out of the server's connection pool. It is
+// intended for building custom operations on top of the driver, e.g. with Connection.RunRawCommand. If rp is nil, the
+// client's read preference is used.
+//
+// The caller owns the returned connection and must call Close on it exactly once when done, which returns it to the
+// pool. A connection that is not closed counts against the pool's maximum size for the lifetime of the Client. The
+// connection must not be used after it is closed or concurrently by multiple goroutines.
+//
+// The connection is not associated with any Session, so commands run over it are not part of a transaction and do not
+// send lsid or $clusterTime. When connected to a load balancer, a cursor or transaction started over the connection
+// can only be continued over the same connection, so the caller must keep it until the cursor is exhausted or the
+// transaction ends. The connection is not pinned by the driver, and Close always returns it to the pool.
+//
+// AcquireConnection returns an error if the Client is not connected to a deployment managed by the driver or was
+// configured with SetSingleConnection.
+func (c *Client) AcquireConnection(ctx context.Context, rp *readpref.ReadPref) (*topology.Connection, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if rp == nil {
+		rp = c.readPreference
+	}
+
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return nil, errors.New("connections can only be acquired from deployments managed by the driver")
+	}
+
+	selector := &serverselector.Composite{
+		Selectors: []description.ServerSelector{
+			&serverselector.ReadPref{ReadPref: rp},
+			&serverselector.Latency{Latency: c.localThreshold},
+		},
+	}
+	srv, err := topo.SelectServer(ctx, selector)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+
+	conn, err := srv.Connection(ctx)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+
+	tc, ok := conn.ReadWriteCloser.(*topology.Connection)
+	if !ok {
+		_ = conn.Close()
+		return nil, errors.New("connections cannot be acquired from a client configured with SetSingleConnection")
+	}
+	return tc, nil
+}
+
+// RescanTopology requests an immediate check of every server known to the Client instead of waiting for the next
+// heartbeat, e.g. after a known change to the deployment's infrastructure. It does not block; the topology is updated
+// in the background as the checks complete. Checks are requested at most once per second, and each server is never
+// checked more often than the minimum heartbeat interval (see ClientOptions.SetMinHeartbeatInterval).
+//
+// RescanTopology reports whether the checks were requested. It returns false if the call was rate limited, the
+// Client is disconnected, or the Client is not connected to a deployment managed by the driver.
+func (c *Client) RescanTopology() bool {
+	topo, ok := c.deployment.(*topology.Topology)
+	if !ok {
+		return false
+	}
+	return topo.Rescan()
+}
+
+// BuildInfo contains a subset of the result of the buildInfo command.
+type BuildInfo struct {
+	// Version is the server version string, e.g. "8.0.4".
+	Version string `bson:"version"`
+
+	// GitVersion is the commit identifier of the server build.
+	GitVersion string `bson:"gitVersion"`
+
+	// Modules lists the add-on modules of the server build, e.g. "enterprise".
+	Modules []string `bson:"modules"`
+}
+
+type cachedBuildInfo struct {
+	info BuildInfo
+	key  string
+}
+
+// BuildInfo returns information about the build of the server, preferring the primary. The result of the buildInfo
+// command is cached, and a new buildInfo command is only run when the servers known to the Client have changed, e.g.
+// because a server was added, removed, or restarted after an upgrade. If the Client is not connected to a deployment
+// managed by the driver, the result is cached for the lifetime of the Client.
+func (c *Client) BuildInfo(ctx context.Context) (BuildInfo, error) {
+	c.buildInfoMu.Lock()
+	defer c.buildInfoMu.Unlock()
+
+	if c.buildInfo != nil && c.buildInfo.key == c.buildInfoKey() {
+		return c.buildInfo.info, nil
+	}
+
+	cmdOpts := options.RunCmd().SetReadPreference(readpref.PrimaryPreferred())
+	res := c.Database("admin").RunCommand(ctx, bson.D{{"buildInfo", 1}}, cmdOpts)
+
+	var info BuildInfo
+	if err := res.Decode(&info); err != nil {
+		return BuildInfo{}, err
+	}
+
+	// Compute the key after running the command, as the command may have been the first to discover the servers.
+	c.buildInfo = &cachedBuildInfo{info: info, key: c.buildInfoKey()}
+	return info, nil
+}
+
+// buildInfoKey returns a key identifying the known servers of the deployment and, when reported, the processes they
+// run. It is empty if the deployment does not provide a topology description.
+func (c *Client) buildInfoKey() string {
+	describer, ok := c.deployment.(interface{ Description() description.Topology })
+	if !ok {
+		return ""
+	}
+
+	var servers []string
+	for _, desc := range describer.Description().Servers {
+		if desc.Kind == description.Unknown {
+			continue
+		}
+		server := desc.Addr.String()
+		if desc.TopologyVersion != nil {
+			server += "/" + desc.TopologyVersion.ProcessID.Hex()
+		}
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+	return strings.Join(servers, ",")
+}
+
 func (c *Client) createBaseCursorOptions() driver.CursorOptions {
 	return driver.CursorOptions{
 		CommandMonitor: c.monitor,
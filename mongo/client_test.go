@@ -24,10 +24,24 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/v2/tag"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mongocrypt"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/xoptions"
 )
 
+// describedDeployment is a mock deployment that reports a configurable topology description.
+type describedDeployment struct {
+	*drivertest.MockDeployment
+	desc description.Topology
+}
+
+func (d *describedDeployment) Description() description.Topology {
+	return d.desc
+}
+
 var bgCtx = context.Background()
 
 func setupClient(opts ...*options.ClientOptions) *Client {
@@ -433,6 +447,63 @@ func TestClient(t *testing.T) {
 			errmsg := `api version "badVersion" not supported; this driver version only supports API version "1"`
 			assert.Equal(t, errmsg, err.Error(), "expected error %v, got %v", errmsg, err.Error())
 		})
+		t.Run("per-operation override", func(t *testing.T) {
+			_, err := WithServerAPIOptions(context.Background(), getServerAPIOptions())
+			assert.Nil(t, err, "unexpected error from WithServerAPIOptions: %v", err)
+
+			_, err = WithServerAPIOptions(context.Background(), nil)
+			assert.Nil(t, err, "unexpected error from WithServerAPIOptions: %v", err)
+
+			_, err = WithServerAPIOptions(context.Background(), options.ServerAPI("badVersion"))
+			errmsg := `api version "badVersion" not supported; this driver version only supports API version "1"`
+			assert.EqualError(t, err, errmsg)
+		})
+		t.Run("per-operation strict override", func(t *testing.T) {
+			_, err := WithServerAPIStrict(context.Background(), true)
+			assert.NoError(t, err, "unexpected error from WithServerAPIStrict")
+
+			ctx, err := WithServerAPIOptions(context.Background(), getServerAPIOptions())
+			require.NoError(t, err, "WithServerAPIOptions error")
+			_, err = WithServerAPIStrict(ctx, true)
+			assert.NoError(t, err, "unexpected error from WithServerAPIStrict")
+
+			ctx, err = WithServerAPIOptions(context.Background(), nil)
+			require.NoError(t, err, "WithServerAPIOptions error")
+			_, err = WithServerAPIStrict(ctx, true)
+			assert.ErrorIs(t, err, driver.ErrServerAPIStrictWithoutVersion)
+		})
+		t.Run("strict override is sent", func(t *testing.T) {
+			cursorResponse := bson.D{
+				{"ok", 1},
+				{"cursor", bson.D{{"id", int64(0)}, {"ns", "db.coll"}, {"firstBatch", bson.A{}}}},
+			}
+
+			var started []*event.CommandStartedEvent
+			monitor := &event.CommandMonitor{
+				Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+					started = append(started, evt)
+				},
+			}
+			opts := options.Client().SetMonitor(monitor).
+				SetServerAPIOptions(options.ServerAPI(options.ServerAPIVersion1).SetStrict(true))
+			err := xoptions.SetInternalClientOptions(opts, "deployment",
+				drivertest.NewMockDeployment(cursorResponse, cursorResponse))
+			require.NoError(t, err, "SetInternalClientOptions error")
+			client, err := Connect(opts)
+			require.NoError(t, err, "Connect error")
+			coll := client.Database("db").Collection("coll")
+
+			ctx, err := WithServerAPIStrict(context.Background(), false)
+			require.NoError(t, err, "WithServerAPIStrict error")
+			_, err = coll.Find(ctx, bson.D{})
+			require.NoError(t, err, "Find error")
+			_, err = coll.Find(context.Background(), bson.D{})
+			require.NoError(t, err, "Find error")
+
+			require.Len(t, started, 2, "expected 2 commands to be sent")
+			assert.Equal(t, false, started[0].Command.Lookup("apiStrict").Boolean(), "expected the override to be sent")
+			assert.Equal(t, true, started[1].Command.Lookup("apiStrict").Boolean(), "expected the client setting to be sent")
+		})
 		t.Run("cannot modify options after client creation", func(t *testing.T) {
 			serverAPIOptions := getServerAPIOptions()
 			client, err := newClient(options.Client().SetServerAPIOptions(serverAPIOptions))
@@ -514,4 +585,52 @@ func TestClient(t *testing.T) {
 		errmsg := `invalid value "-1s" for "Timeout": value must be positive`
 		assert.Equal(t, errmsg, err.Error(), "expected error %v, got %v", errmsg, err.Error())
 	})
+	t.Run("BuildInfo is cached until the topology changes", func(t *testing.T) {
+		server := description.Server{
+			Addr:            "localhost:27017",
+			Kind:            description.ServerKindStandalone,
+			TopologyVersion: &description.TopologyVersion{ProcessID: bson.NewObjectID()},
+		}
+		d := &describedDeployment{
+			MockDeployment: drivertest.NewMockDeployment(),
+			desc:           description.Topology{Servers: []description.Server{server}},
+		}
+		d.AddResponses(
+			bson.D{{"ok", 1}, {"version", "7.0.0"}, {"gitVersion", "abc"}, {"modules", bson.A{}}},
+			bson.D{{"ok", 1}, {"version", "8.0.0"}, {"gitVersion", "def"}, {"modules", bson.A{"enterprise"}}},
+		)
+
+		opts := options.Client()
+		err := xoptions.SetInternalClientOptions(opts, "deployment", d)
+		require.NoError(t, err, "SetInternalClientOptions error")
+		client, err := Connect(opts)
+		require.NoError(t, err, "Connect error")
+
+		want := BuildInfo{Version: "7.0.0", GitVersion: "abc", Modules: []string{}}
+		for i := 0; i < 2; i++ {
+			got, err := client.BuildInfo(bgCtx)
+			require.NoError(t, err, "BuildInfo error")
+			assert.Equal(t, want, got, "unexpected build info")
+		}
+
+		// Simulate a restart of the server, e.g. after an upgrade.
+		server.TopologyVersion = &description.TopologyVersion{ProcessID: bson.NewObjectID()}
+		d.desc = description.Topology{Servers: []description.Server{server}}
+
+		want = BuildInfo{Version: "8.0.0", GitVersion: "def", Modules: []string{"enterprise"}}
+		got, err := client.BuildInfo(bgCtx)
+		require.NoError(t, err, "BuildInfo error")
+		assert.Equal(t, want, got, "expected build info to be refreshed")
+	})
+	t.Run("AcquireConnection requires a deployment managed by the driver", func(t *testing.T) {
+		opts := options.Client()
+		err := xoptions.SetInternalClientOptions(opts, "deployment", drivertest.NewMockDeployment())
+		require.NoError(t, err, "SetInternalClientOptions error")
+		client, err := Connect(opts)
+		require.NoError(t, err, "Connect error")
+
+		conn, err := client.AcquireConnection(bgCtx, nil)
+		assert.Nil(t, conn, "expected no connection")
+		assert.EqualError(t, err, "connections can only be acquired from deployments managed by the driver")
+	})
 }
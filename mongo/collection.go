@@ -16,6 +16,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/internal/csfle"
+	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
 	"go.mongodb.org/mongo-driver/v2/internal/mongoutil"
 	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -41,6 +42,7 @@ type Collection struct {
 	writeSelector  description.ServerSelector
 	bsonOpts       *options.BSONOptions
 	registry       *bson.Registry
+	uniqueKeyCheck *options.UniqueKeyCheck
 }
 
 // aggregateParams is used to store information to configure an Aggregate operation.
@@ -75,6 +77,9 @@ func newCollection(db *Database, name string, opts ...options.Lister[options.Col
 	}
 
 	wc := db.writeConcern
+	if nsWC, ok := db.client.namespaceWriteConcerns[db.name+"."+name]; ok && !db.explicitWriteConcern {
+		wc = nsWC
+	}
 	if args.WriteConcern != nil {
 		wc = args.WriteConcern
 	}
@@ -119,6 +124,7 @@ func newCollection(db *Database, name string, opts ...options.Lister[options.Col
 		writeSelector:  writeSelector,
 		bsonOpts:       bsonOpts,
 		registry:       reg,
+		uniqueKeyCheck: args.UniqueKeyCheck,
 	}
 
 	return coll
@@ -135,6 +141,7 @@ func (coll *Collection) copy() *Collection {
 		readSelector:   coll.readSelector,
 		writeSelector:  coll.writeSelector,
 		registry:       coll.registry,
+		uniqueKeyCheck: coll.uniqueKeyCheck,
 	}
 }
 
@@ -162,6 +169,10 @@ func (coll *Collection) Clone(opts ...options.Lister[options.CollectionOptions])
 		copyColl.registry = args.Registry
 	}
 
+	if args.UniqueKeyCheck != nil {
+		copyColl.uniqueKeyCheck = args.UniqueKeyCheck
+	}
+
 	copyColl.readSelector = &serverselector.Composite{
 		Selectors: []description.ServerSelector{
 			&serverselector.ReadPref{ReadPref: copyColl.readPreference},
@@ -192,6 +203,38 @@ func (coll *Collection) Database() *Database {
 func (coll *Collection) BulkWrite(ctx context.Context, models []WriteModel,
 	opts ...options.Lister[options.BulkWriteOptions]) (*BulkWriteResult, error) {
 
+	return coll.bulkWrite(ctx, models, nil, opts...)
+}
+
+// BulkWriteStream performs a bulk write operation like BulkWrite, but calls fn
+// with the result of each batch as soon as the server acknowledges it instead
+// of collecting every write error before returning. Use
+// options.BulkWriteOptions.SetBatchSize to control how many models are sent in
+// each batch.
+//
+// Batches are reported in the order in which they are executed. If the
+// operation is ordered, execution stops after the first batch that contains a
+// write error, and that batch's BulkWriteException is returned. If the
+// operation is unordered, write errors are only reported to fn and execution
+// continues with the remaining batches. If fn returns an error, no further
+// batches are executed and that error is returned.
+//
+// The returned BulkWriteResult contains the totals for every executed batch.
+func (coll *Collection) BulkWriteStream(ctx context.Context, models []WriteModel,
+	fn func(*BulkWriteBatchResult) error,
+	opts ...options.Lister[options.BulkWriteOptions]) (*BulkWriteResult, error) {
+
+	if fn == nil {
+		return nil, errors.New("fn must not be nil")
+	}
+
+	return coll.bulkWrite(ctx, models, fn, opts...)
+}
+
+func (coll *Collection) bulkWrite(ctx context.Context, models []WriteModel,
+	fn func(*BulkWriteBatchResult) error,
+	opts ...options.Lister[options.BulkWriteOptions]) (*BulkWriteResult, error) {
+
 	if len(models) == 0 {
 		return nil, fmt.Errorf("invalid models: %w", ErrEmptySlice)
 	}
@@ -244,6 +287,13 @@ func (coll *Collection) BulkWrite(ctx context.Context, models []WriteModel,
 		selector:                 selector,
 		writeConcern:             wc,
 		let:                      args.Let,
+		batchFn:                  fn,
+	}
+	if args.BatchSize != nil {
+		if *args.BatchSize <= 0 {
+			return nil, fmt.Errorf("invalid batch size %d: value must be positive", *args.BatchSize)
+		}
+		op.batchSize = *args.BatchSize
 	}
 
 	err = op.execute(ctx)
@@ -278,13 +328,18 @@ func (coll *Collection) insert(
 		result[i] = id
 	}
 
+	keys, err := coll.checkUniqueKeys(docs)
+	if err != nil {
+		return nil, err
+	}
+
 	sess := sessionFromContext(ctx)
 	if sess == nil && coll.client.sessionPool != nil {
 		sess = session.NewImplicitClientSession(coll.client.sessionPool, coll.client.id)
 		defer sess.EndSession()
 	}
 
-	err := coll.client.validSession(sess)
+	err = coll.client.validSession(sess)
 	if err != nil {
 		return nil, err
 	}
@@ -333,8 +388,12 @@ func (coll *Collection) insert(
 	err = op.Execute(ctx)
 	var wce driver.WriteCommandError
 	if !errors.As(err, &wce) {
+		if err == nil {
+			coll.recordUniqueKeys(keys, nil, false)
+		}
 		return result, err
 	}
+	coll.recordUniqueKeys(keys, wce.WriteErrors, args.Ordered == nil || *args.Ordered)
 
 	// remove the ids that had writeErrors from result
 	for i, we := range wce.WriteErrors {
@@ -351,6 +410,54 @@ func (coll *Collection) insert(
 	return result, err
 }
 
+// checkUniqueKeys extracts the unique keys of docs if a UniqueKeyCheck is configured and returns an error wrapping
+// ErrKnownDuplicateKey if any of them is known to exist in the collection. The returned map contains the key of each
+// document that has one, by index.
+func (coll *Collection) checkUniqueKeys(docs []bsoncore.Document) (map[int]string, error) {
+	if coll.uniqueKeyCheck == nil || coll.uniqueKeyCheck.KeyFunc == nil || coll.uniqueKeyCheck.Keys == nil {
+		return nil, nil
+	}
+
+	keys := make(map[int]string, len(docs))
+	for i, doc := range docs {
+		key, ok := coll.uniqueKeyCheck.KeyFunc(bson.Raw(doc))
+		if !ok {
+			continue
+		}
+		if coll.uniqueKeyCheck.Keys.Contains(key) {
+			return nil, fmt.Errorf("document at index %d has key %q: %w", i, key, ErrKnownDuplicateKey)
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// recordUniqueKeys adds the keys of the documents that are known to exist in the collection after an insert to the
+// UniqueKeySet: the documents that were inserted and the documents rejected with a duplicate key error. If ordered is
+// true, no documents after the first write error were attempted.
+func (coll *Collection) recordUniqueKeys(keys map[int]string, writeErrors driver.WriteErrors, ordered bool) {
+	if len(keys) == 0 {
+		return
+	}
+
+	failed := make(map[int]bool, len(writeErrors))
+	lastAttempted := -1
+	for i, we := range writeErrorsFromDriverWriteErrors(writeErrors) {
+		if ordered && i == 0 {
+			lastAttempted = we.Index
+		}
+		if !IsDuplicateKeyError(we) {
+			failed[we.Index] = true
+		}
+	}
+
+	for idx, key := range keys {
+		if (lastAttempted < 0 || idx <= lastAttempted) && !failed[idx] {
+			coll.uniqueKeyCheck.Keys.Add(key)
+		}
+	}
+}
+
 // InsertOne executes an insert command to insert a single document into the collection.
 //
 // The document parameter must be the document to be inserted. It cannot be nil. If the document does not have an _id
@@ -953,6 +1060,9 @@ func aggregate(a aggregateParams, opts ...options.Lister[options.AggregateOption
 	if err != nil {
 		return nil, err
 	}
+	if args.LocalThreshold != nil {
+		a.ctx = driverutil.WithLocalThreshold(a.ctx, *args.LocalThreshold)
+	}
 
 	cursorOpts := a.client.createBaseCursorOptions()
 
@@ -997,6 +1107,12 @@ func aggregate(a aggregateParams, opts ...options.Lister[options.AggregateOption
 	if args.MaxAwaitTime != nil {
 		cursorOpts.SetMaxAwaitTime(*args.MaxAwaitTime)
 	}
+	if args.MaxTime != nil {
+		op.MaxTime(args.MaxTime)
+	}
+	if args.IgnoreClientTimeout != nil {
+		op.IgnoreClientTimeout(*args.IgnoreClientTimeout)
+	}
 	if args.Comment != nil {
 		comment, err := marshalValue(args.Comment, a.bsonOpts, a.registry)
 		if err != nil {
@@ -1351,6 +1467,9 @@ func (coll *Collection) find(
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	if args.LocalThreshold != nil {
+		ctx = driverutil.WithLocalThreshold(ctx, *args.LocalThreshold)
+	}
 
 	f, err := marshal(filter, coll.bsonOpts, coll.registry)
 	if err != nil {
@@ -1458,6 +1577,12 @@ func (coll *Collection) find(
 	if args.MaxAwaitTime != nil {
 		cursorOpts.SetMaxAwaitTime(*args.MaxAwaitTime)
 	}
+	if args.MaxTime != nil {
+		op.MaxTime(args.MaxTime)
+	}
+	if args.IgnoreClientTimeout != nil {
+		op.IgnoreClientTimeout(*args.IgnoreClientTimeout)
+	}
 	if args.Min != nil {
 		min, err := marshal(args.Min, coll.bsonOpts, coll.registry)
 		if err != nil {
@@ -1522,7 +1647,10 @@ func newFindArgsFromFindOneArgs(args *options.FindOneOptions) *options.FindOptio
 		v.Collation = args.Collation
 		v.Comment = args.Comment
 		v.Hint = args.Hint
+		v.IgnoreClientTimeout = args.IgnoreClientTimeout
+		v.LocalThreshold = args.LocalThreshold
 		v.Max = args.Max
+		v.MaxTime = args.MaxTime
 		v.Min = args.Min
 		v.OplogReplay = args.OplogReplay
 		v.Projection = args.Projection
@@ -2113,7 +2241,19 @@ func (pss pinnedServerSelector) SelectServer(
 		return nil, nil
 	}
 
-	return pss.fallback.SelectServer(t, svrs)
+	selected, err := pss.fallback.SelectServer(t, svrs)
+	if err != nil || pss.session == nil || pss.session.LastServerAddr == nil {
+		return selected, err
+	}
+
+	// If the session has server affinity, prefer the server used by its previous operation as
+	// long as that server is still a suitable candidate.
+	for _, candidate := range selected {
+		if candidate.Addr == *pss.session.LastServerAddr {
+			return []description.Server{candidate}, nil
+		}
+	}
+	return selected, nil
 }
 
 func makePinnedSelector(sess *session.Client, fallback description.ServerSelector) pinnedServerSelector {
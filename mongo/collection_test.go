@@ -10,18 +10,29 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/ptrutil"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
+	"go.mongodb.org/mongo-driver/v2/internal/uuid"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/xoptions"
 )
 
 const (
@@ -81,6 +92,46 @@ func TestCollection(t *testing.T) {
 		}
 		compareColls(t, expected, coll)
 	})
+	t.Run("namespace write concerns", func(t *testing.T) {
+		wcClient := &writeconcern.WriteConcern{W: 1}
+		wcApp := writeconcern.Majority()
+		wcEvents := &writeconcern.WriteConcern{W: 0}
+		wcExplicit := &writeconcern.WriteConcern{W: 3}
+
+		client := setupClient(options.Client().
+			SetWriteConcern(wcClient).
+			SetNamespaceWriteConcern("app", wcApp).
+			SetNamespaceWriteConcern("app.events", wcEvents))
+
+		testCases := []struct {
+			name string
+			coll *Collection
+			want *writeconcern.WriteConcern
+		}{
+			{"client default", client.Database("other").Collection("events"), wcClient},
+			{"database default", client.Database("app").Collection("users"), wcApp},
+			{"collection default", client.Database("app").Collection("events"), wcEvents},
+			{
+				"database options override collection default",
+				client.Database("app", options.Database().SetWriteConcern(wcExplicit)).Collection("events"),
+				wcExplicit,
+			},
+			{
+				"collection options override collection default",
+				client.Database("app").Collection("events", options.Collection().SetWriteConcern(wcExplicit)),
+				wcExplicit,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				assert.Equal(t, tc.want, tc.coll.writeConcern, "unexpected write concern")
+			})
+		}
+
+		db := client.Database("app", options.Database().SetWriteConcern(wcExplicit))
+		assert.Equal(t, wcExplicit, db.writeConcern, "expected database options to override the database default")
+		assert.Equal(t, wcApp, client.Database("app").writeConcern, "expected the database default")
+	})
 	t.Run("replaceErrors for disconnected topology", func(t *testing.T) {
 		coll := setupColl("foo")
 		doc := bson.D{}
@@ -307,3 +358,505 @@ func TestNewFindArgsFromFindOneArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestPinnedServerSelectorAffinity(t *testing.T) {
+	t.Parallel()
+
+	primary := description.Server{Addr: address.Address("localhost:27017"), Kind: description.ServerKindRSPrimary}
+	secondaries := []description.Server{
+		{Addr: address.Address("localhost:27018"), Kind: description.ServerKindRSSecondary},
+		{Addr: address.Address("localhost:27019"), Kind: description.ServerKindRSSecondary},
+		{Addr: address.Address("localhost:27020"), Kind: description.ServerKindRSSecondary},
+	}
+	servers := append([]description.Server{primary}, secondaries...)
+	topo := description.Topology{Kind: description.TopologyKindReplicaSetWithPrimary, Servers: servers}
+
+	newSession := func(t *testing.T, affinity bool) *session.Client {
+		t.Helper()
+
+		id, _ := uuid.New()
+		sess, err := session.NewClientSession(&session.Pool{}, id, &session.ClientOptions{ServerAffinity: &affinity})
+		require.NoError(t, err, "NewClientSession error")
+		return sess
+	}
+	newSelector := func(sess *session.Client, rp *readpref.ReadPref) description.ServerSelector {
+		return makeReadPrefSelector(sess, &serverselector.Composite{
+			Selectors: []description.ServerSelector{
+				&serverselector.ReadPref{ReadPref: rp},
+				&serverselector.Latency{Latency: 15 * time.Millisecond},
+			},
+		}, 15*time.Millisecond)
+	}
+
+	t.Run("repeated reads prefer the same server", func(t *testing.T) {
+		t.Parallel()
+
+		sess := newSession(t, true)
+		selector := newSelector(sess, readpref.Secondary())
+
+		got, err := selector.SelectServer(topo, servers)
+		require.NoError(t, err, "SelectServer error")
+		assert.Equal(t, secondaries, got, "expected all secondaries before the first operation")
+
+		err = sess.ApplyCommand(secondaries[1])
+		require.NoError(t, err, "ApplyCommand error")
+		for i := 0; i < 3; i++ {
+			got, err = selector.SelectServer(topo, servers)
+			require.NoError(t, err, "SelectServer error")
+			assert.Equal(t, []description.Server{secondaries[1]}, got)
+		}
+	})
+	t.Run("falls back when the last server is not suitable", func(t *testing.T) {
+		t.Parallel()
+
+		sess := newSession(t, true)
+		err := sess.ApplyCommand(primary)
+		require.NoError(t, err, "ApplyCommand error")
+
+		got, err := newSelector(sess, readpref.Secondary()).SelectServer(topo, servers)
+		require.NoError(t, err, "SelectServer error")
+		assert.Equal(t, secondaries, got)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		sess := newSession(t, false)
+		err := sess.ApplyCommand(secondaries[1])
+		require.NoError(t, err, "ApplyCommand error")
+
+		got, err := newSelector(sess, readpref.Secondary()).SelectServer(topo, servers)
+		require.NoError(t, err, "SelectServer error")
+		assert.Equal(t, secondaries, got)
+	})
+}
+
+func TestCollection_MaxTime(t *testing.T) {
+	t.Parallel()
+
+	cursorResponse := bson.D{
+		{"ok", 1},
+		{"cursor", bson.D{{"id", int64(0)}, {"ns", "db.coll"}, {"firstBatch", bson.A{}}}},
+	}
+
+	// run executes fn against a client with a 1 second Timeout and returns the
+	// command sent to the server.
+	run := func(t *testing.T, fn func(*Collection) error) bson.Raw {
+		t.Helper()
+
+		var cmd bson.Raw
+		monitor := &event.CommandMonitor{
+			Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+				cmd = evt.Command
+			},
+		}
+		opts := options.Client().SetMonitor(monitor).SetTimeout(time.Second)
+		err := xoptions.SetInternalClientOptions(opts, "deployment", drivertest.NewMockDeployment(cursorResponse))
+		require.NoError(t, err, "SetInternalClientOptions error")
+		client, err := Connect(opts)
+		require.NoError(t, err, "Connect error")
+
+		require.NoError(t, fn(client.Database("db").Collection("coll")), "operation error")
+		require.NotNil(t, cmd, "expected a command to be sent")
+		return cmd
+	}
+
+	find := func(opts *options.FindOptionsBuilder) func(*Collection) error {
+		return func(coll *Collection) error {
+			_, err := coll.Find(context.Background(), bson.D{}, opts)
+			return err
+		}
+	}
+	findOne := func(opts *options.FindOneOptionsBuilder) func(*Collection) error {
+		return func(coll *Collection) error {
+			err := coll.FindOne(context.Background(), bson.D{}, opts).Err()
+			if errors.Is(err, ErrNoDocuments) {
+				return nil
+			}
+			return err
+		}
+	}
+	aggregate := func(opts *options.AggregateOptionsBuilder) func(*Collection) error {
+		return func(coll *Collection) error {
+			_, err := coll.Aggregate(context.Background(), Pipeline{}, opts)
+			return err
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		fn            func(*Collection) error
+		wantMaxTimeMS int64 // 0 means that maxTimeMS must not be sent
+	}{
+		{
+			name: "Find ignores MaxTime with client Timeout",
+			fn:   find(options.Find().SetMaxTime(time.Minute)),
+		},
+		{
+			name:          "Find honors MaxTime when ignoring client Timeout",
+			fn:            find(options.Find().SetMaxTime(time.Minute).SetIgnoreClientTimeout(true)),
+			wantMaxTimeMS: 60000,
+		},
+		{
+			name:          "FindOne honors MaxTime when ignoring client Timeout",
+			fn:            findOne(options.FindOne().SetMaxTime(time.Minute).SetIgnoreClientTimeout(true)),
+			wantMaxTimeMS: 60000,
+		},
+		{
+			name: "Aggregate ignores MaxTime with client Timeout",
+			fn:   aggregate(options.Aggregate().SetMaxTime(time.Minute)),
+		},
+		{
+			name:          "Aggregate honors MaxTime when ignoring client Timeout",
+			fn:            aggregate(options.Aggregate().SetMaxTime(time.Minute).SetIgnoreClientTimeout(true)),
+			wantMaxTimeMS: 60000,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := run(t, tc.fn)
+			val, err := cmd.LookupErr("maxTimeMS")
+			if tc.wantMaxTimeMS == 0 {
+				assert.ErrorIs(t, err, bsoncore.ErrElementNotFound, "expected maxTimeMS to be omitted")
+				return
+			}
+			require.NoError(t, err, "expected maxTimeMS to be sent")
+			assert.Equal(t, tc.wantMaxTimeMS, val.Int64(), "unexpected maxTimeMS")
+		})
+	}
+	t.Run("context deadline takes precedence over MaxTime", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := run(t, func(coll *Collection) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			opts := options.FindOne().SetMaxTime(time.Minute).SetIgnoreClientTimeout(true)
+			err := coll.FindOne(ctx, bson.D{}, opts).Err()
+			if errors.Is(err, ErrNoDocuments) {
+				return nil
+			}
+			return err
+		})
+		val, err := cmd.LookupErr("maxTimeMS")
+		require.NoError(t, err, "expected maxTimeMS to be sent")
+		assert.LessOrEqual(t, val.Int64(), int64(10000), "expected maxTimeMS to be calculated from the deadline")
+	})
+}
+
+func TestCollection_AfterClusterTime(t *testing.T) {
+	t.Parallel()
+
+	cursorResponse := bson.D{
+		{"ok", 1},
+		{"cursor", bson.D{{"id", int64(0)}, {"ns", "db.coll"}, {"firstBatch", bson.A{}}}},
+	}
+
+	t.Run("sent with reads", func(t *testing.T) {
+		t.Parallel()
+
+		var cmd bson.Raw
+		monitor := &event.CommandMonitor{
+			Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+				cmd = evt.Command
+			},
+		}
+		opts := options.Client().SetMonitor(monitor)
+		err := xoptions.SetInternalClientOptions(opts, "deployment", drivertest.NewMockDeployment(cursorResponse))
+		require.NoError(t, err, "SetInternalClientOptions error")
+		client, err := Connect(opts)
+		require.NoError(t, err, "Connect error")
+
+		ts := bson.Timestamp{T: 1700000000, I: 3}
+		ctx, err := WithAfterClusterTime(context.Background(), ts)
+		require.NoError(t, err, "WithAfterClusterTime error")
+
+		_, err = client.Database("db").Collection("coll").Find(ctx, bson.D{})
+		require.NoError(t, err, "Find error")
+		require.NotNil(t, cmd, "expected a command to be sent")
+
+		val, err := cmd.LookupErr("readConcern", "afterClusterTime")
+		require.NoError(t, err, "expected readConcern.afterClusterTime to be sent")
+		gotT, gotI := val.Timestamp()
+		assert.Equal(t, ts, bson.Timestamp{T: gotT, I: gotI}, "unexpected afterClusterTime")
+	})
+	t.Run("invalid timestamp", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := WithAfterClusterTime(context.Background(), bson.Timestamp{I: 1})
+		assert.EqualError(t, err, "afterClusterTime must be a timestamp with a non-zero T value")
+	})
+}
+
+// selectorRecordingDeployment is a MockDeployment that records the server
+// selector passed to SelectServer.
+type selectorRecordingDeployment struct {
+	*drivertest.MockDeployment
+	selector description.ServerSelector
+}
+
+func (d *selectorRecordingDeployment) SelectServer(
+	ctx context.Context,
+	selector description.ServerSelector,
+) (driver.Server, error) {
+	d.selector = selector
+	return d.MockDeployment.SelectServer(ctx, selector)
+}
+
+func TestCollection_LocalThreshold(t *testing.T) {
+	t.Parallel()
+
+	cursorResponse := bson.D{
+		{"ok", 1},
+		{"cursor", bson.D{{"id", int64(0)}, {"ns", "db.coll"}, {"firstBatch", bson.A{}}}},
+	}
+	topo := description.Topology{Kind: description.TopologyKindReplicaSetNoPrimary}
+	candidates := []description.Server{
+		{Addr: "fast:27017", Kind: description.ServerKindRSSecondary, AverageRTT: 5 * time.Millisecond, AverageRTTSet: true},
+		{Addr: "slow:27017", Kind: description.ServerKindRSSecondary, AverageRTT: 12 * time.Millisecond, AverageRTTSet: true},
+	}
+
+	testCases := []struct {
+		name string
+		fn   func(context.Context, *Collection) error
+		want []address.Address
+	}{
+		{
+			name: "Find uses the client window by default",
+			fn: func(ctx context.Context, coll *Collection) error {
+				_, err := coll.Find(ctx, bson.D{})
+				return err
+			},
+			want: []address.Address{"fast:27017", "slow:27017"},
+		},
+		{
+			name: "Find",
+			fn: func(ctx context.Context, coll *Collection) error {
+				_, err := coll.Find(ctx, bson.D{}, options.Find().SetLocalThreshold(0))
+				return err
+			},
+			want: []address.Address{"fast:27017"},
+		},
+		{
+			name: "FindOne",
+			fn: func(ctx context.Context, coll *Collection) error {
+				err := coll.FindOne(ctx, bson.D{}, options.FindOne().SetLocalThreshold(0)).Err()
+				if errors.Is(err, ErrNoDocuments) {
+					return nil
+				}
+				return err
+			},
+			want: []address.Address{"fast:27017"},
+		},
+		{
+			name: "Aggregate",
+			fn: func(ctx context.Context, coll *Collection) error {
+				_, err := coll.Aggregate(ctx, Pipeline{}, options.Aggregate().SetLocalThreshold(0))
+				return err
+			},
+			want: []address.Address{"fast:27017"},
+		},
+		{
+			name: "option takes precedence over the Context",
+			fn: func(ctx context.Context, coll *Collection) error {
+				ctx = WithLocalThreshold(ctx, 0)
+				_, err := coll.Find(ctx, bson.D{}, options.Find().SetLocalThreshold(time.Second))
+				return err
+			},
+			want: []address.Address{"fast:27017", "slow:27017"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &selectorRecordingDeployment{MockDeployment: drivertest.NewMockDeployment(cursorResponse)}
+			opts := options.Client()
+			err := xoptions.SetInternalClientOptions(opts, "deployment", d)
+			require.NoError(t, err, "SetInternalClientOptions error")
+			client, err := Connect(opts)
+			require.NoError(t, err, "Connect error")
+
+			coll := client.Database("db").Collection("coll",
+				options.Collection().SetReadPreference(readpref.Nearest()))
+			err = tc.fn(context.Background(), coll)
+			require.NoError(t, err, "operation error")
+			require.NotNil(t, d.selector, "expected a server to be selected")
+
+			selected, err := d.selector.SelectServer(topo, candidates)
+			require.NoError(t, err, "SelectServer error")
+
+			got := make([]address.Address, 0, len(selected))
+			for _, s := range selected {
+				got = append(got, s.Addr)
+			}
+			assert.Equal(t, tc.want, got, "unexpected selected servers")
+		})
+	}
+}
+
+// keySet is a UniqueKeySet backed by a map.
+type keySet struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (ks *keySet) Contains(key string) bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	return ks.keys[key]
+}
+
+func (ks *keySet) Add(key string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.keys[key] = true
+}
+
+func TestCollection_UniqueKeyCheck(t *testing.T) {
+	t.Parallel()
+
+	emailKey := func(doc bson.Raw) (string, bool) {
+		val, err := doc.LookupErr("email")
+		if err != nil {
+			return "", false
+		}
+		return val.StringValue(), true
+	}
+
+	// setup returns a collection that checks the email field against keys and
+	// a function that returns the number of commands sent to the server.
+	setup := func(t *testing.T, keys *keySet, responses ...bson.D) (*Collection, func() int) {
+		t.Helper()
+
+		var mu sync.Mutex
+		var started int
+		monitor := &event.CommandMonitor{
+			Started: func(context.Context, *event.CommandStartedEvent) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				started++
+			},
+		}
+		opts := options.Client().SetMonitor(monitor)
+		err := xoptions.SetInternalClientOptions(opts, "deployment", drivertest.NewMockDeployment(responses...))
+		require.NoError(t, err, "SetInternalClientOptions error")
+		client, err := Connect(opts)
+		require.NoError(t, err, "Connect error")
+
+		coll := client.Database("db").Collection("coll", options.Collection().SetUniqueKeyCheck(emailKey, keys))
+		return coll, func() int {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return started
+		}
+	}
+
+	t.Run("miss", func(t *testing.T) {
+		t.Parallel()
+
+		keys := &keySet{keys: map[string]bool{}}
+		coll, started := setup(t, keys, bson.D{{"ok", 1}, {"n", 1}})
+
+		_, err := coll.InsertOne(context.Background(), bson.D{{"email", "a@example.com"}})
+		require.NoError(t, err, "InsertOne error")
+		assert.Equal(t, 1, started(), "expected the insert to be sent")
+		assert.True(t, keys.Contains("a@example.com"), "expected the inserted key to be recorded")
+	})
+	t.Run("hit", func(t *testing.T) {
+		t.Parallel()
+
+		keys := &keySet{keys: map[string]bool{"a@example.com": true}}
+		coll, started := setup(t, keys)
+
+		_, err := coll.InsertOne(context.Background(), bson.D{{"email", "a@example.com"}})
+		assert.ErrorIs(t, err, ErrKnownDuplicateKey)
+		assert.False(t, IsDuplicateKeyError(err), "expected the error not to be a server duplicate key error")
+		assert.Equal(t, 0, started(), "expected no command to be sent")
+	})
+	t.Run("hit in batch", func(t *testing.T) {
+		t.Parallel()
+
+		keys := &keySet{keys: map[string]bool{"b@example.com": true}}
+		coll, started := setup(t, keys)
+
+		docs := []interface{}{
+			bson.D{{"email", "a@example.com"}},
+			bson.D{{"email", "b@example.com"}},
+		}
+		_, err := coll.InsertMany(context.Background(), docs)
+		assert.ErrorIs(t, err, ErrKnownDuplicateKey)
+		assert.ErrorContains(t, err, "document at index 1")
+		assert.Equal(t, 0, started(), "expected no command to be sent")
+		assert.False(t, keys.Contains("a@example.com"), "expected no keys to be recorded")
+	})
+	t.Run("documents without a key are not checked", func(t *testing.T) {
+		t.Parallel()
+
+		keys := &keySet{keys: map[string]bool{}}
+		coll, started := setup(t, keys, bson.D{{"ok", 1}, {"n", 1}})
+
+		_, err := coll.InsertOne(context.Background(), bson.D{{"name", "a"}})
+		require.NoError(t, err, "InsertOne error")
+		assert.Equal(t, 1, started(), "expected the insert to be sent")
+		assert.Len(t, keys.keys, 0, "expected no keys to be recorded")
+	})
+	t.Run("server write errors", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name    string
+			ordered bool
+			want    map[string]bool
+		}{
+			{
+				name:    "ordered",
+				ordered: true,
+				want:    map[string]bool{"a@example.com": true, "b@example.com": true},
+			},
+			{
+				name:    "unordered",
+				ordered: false,
+				want:    map[string]bool{"a@example.com": true, "b@example.com": true, "d@example.com": true},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				// The server rejects b with a duplicate key error and c with a
+				// validation error.
+				writeErrors := bson.A{
+					bson.D{{"index", 1}, {"code", 11000}, {"errmsg", "E11000 duplicate key error"}},
+				}
+				if !tc.ordered {
+					writeErrors = append(writeErrors,
+						bson.D{{"index", 2}, {"code", 121}, {"errmsg", "Document failed validation"}})
+				}
+				keys := &keySet{keys: map[string]bool{}}
+				coll, _ := setup(t, keys, bson.D{{"ok", 1}, {"n", 1}, {"writeErrors", writeErrors}})
+
+				docs := []interface{}{
+					bson.D{{"email", "a@example.com"}},
+					bson.D{{"email", "b@example.com"}},
+					bson.D{{"email", "c@example.com"}},
+					bson.D{{"email", "d@example.com"}},
+				}
+				_, err := coll.InsertMany(context.Background(), docs, options.InsertMany().SetOrdered(tc.ordered))
+				assert.True(t, IsDuplicateKeyError(err), "expected a duplicate key error, got %v", err)
+				assert.Equal(t, tc.want, keys.keys, "unexpected recorded keys")
+			})
+		}
+	})
+}
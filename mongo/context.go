@@ -0,0 +1,94 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/topology"
+)
+
+// WithLocalThreshold returns a Context that overrides the latency window used
+// when selecting a server for operations run with it. This can be used to
+// force a latency-critical operation onto the fastest suitable server, e.g. a
+// threshold of 0 only allows servers with the lowest average round trip time.
+//
+// The window is applied to the servers remaining after client-level server
+// selection, which uses the window configured with
+// [options.ClientOptions.SetLocalThreshold], so the effective window is the
+// smaller of the two. Operations run with a Context without a local threshold
+// use the client-level window. The LocalThreshold option of Find, FindOne and
+// Aggregate takes precedence over the threshold set on the Context.
+func WithLocalThreshold(parent context.Context, threshold time.Duration) context.Context {
+	return driverutil.WithLocalThreshold(parent, threshold)
+}
+
+// WithServerAPIOptions returns a Context that overrides the server API options
+// configured with [options.ClientOptions.SetServerAPIOptions] for operations
+// run with it. The override changes the API parameters sent with each command,
+// e.g. "apiVersion", but not those sent when establishing connections. If opts
+// is nil, commands are sent without any API parameters.
+//
+// WithServerAPIOptions returns an error if opts specifies an API version that
+// is not supported by the driver.
+func WithServerAPIOptions(parent context.Context, opts *options.ServerAPIOptions) (context.Context, error) {
+	if opts == nil {
+		return driver.WithServerAPI(parent, nil), nil
+	}
+	if err := opts.ServerAPIVersion.Validate(); err != nil {
+		return nil, err
+	}
+
+	return driver.WithServerAPI(parent, topology.ConvertToDriverAPIOptions(opts)), nil
+}
+
+// WithServerAPIStrict returns a Context that overrides the "apiStrict" parameter
+// sent with each command for operations run with it, e.g. to run a command that
+// is not part of the Stable API from a client that enables strict mode. It
+// applies on top of the server API options configured with
+// [options.ClientOptions.SetServerAPIOptions] or [WithServerAPIOptions]. The
+// server rejects "apiStrict" without an API version, so operations run with it
+// fail with driver.ErrServerAPIStrictWithoutVersion if no server API version is
+// configured.
+//
+// WithServerAPIStrict returns an error if parent overrides the server API
+// options with WithServerAPIOptions and the override has no API version.
+func WithServerAPIStrict(parent context.Context, strict bool) (context.Context, error) {
+	if sa, ok := driver.ServerAPIFromContext(parent); ok && (sa == nil || sa.ServerAPIVersion == "") {
+		return nil, driver.ErrServerAPIStrictWithoutVersion
+	}
+
+	return driver.WithServerAPIStrict(parent, strict), nil
+}
+
+// WithAfterClusterTime returns a Context that sets the "afterClusterTime" of
+// the read concern sent with read operations run with it, so the server waits
+// until its data reflects at least the given cluster time before running the
+// read. This allows causally consistent reads without an explicit session, e.g.
+// by passing the operation time of a write made by another client.
+//
+// The afterClusterTime is only sent with commands that include a read concern,
+// i.e. reads outside of transactions and the first command of a transaction.
+// If the operation runs in a causally consistent session, the later of ts and
+// the session's operation time is sent. It is not sent for snapshot sessions,
+// which read at a fixed cluster time.
+//
+// WithAfterClusterTime returns an error if ts is not a valid cluster time,
+// i.e. if its T field is 0.
+func WithAfterClusterTime(parent context.Context, ts bson.Timestamp) (context.Context, error) {
+	if ts.T == 0 {
+		return nil, errors.New("afterClusterTime must be a timestamp with a non-zero T value")
+	}
+
+	return driver.WithAfterClusterTime(parent, ts), nil
+}
@@ -247,9 +247,15 @@ func getDecoder(
 		if opts.BinaryAsSlice {
 			dec.BinaryAsSlice()
 		}
+		if opts.CanonicalExtJSON {
+			dec.CanonicalExtJSON()
+		}
 		if opts.DefaultDocumentM {
 			dec.DefaultDocumentM()
 		}
+		if opts.DefaultNumberType != nil {
+			dec.DefaultNumberType(opts.DefaultNumberType)
+		}
 		if opts.ObjectIDAsHexString {
 			dec.ObjectIDAsHexString()
 		}
@@ -395,6 +401,17 @@ func (c *Cursor) SetBatchSize(batchSize int32) {
 	c.bc.SetBatchSize(batchSize)
 }
 
+// SetTargetBatchBytes sets the approximate number of bytes to fetch from the
+// database with each iteration of the cursor's "Next" method. When set to a
+// positive value, the number of documents requested in each subsequent batch is
+// calculated from the average size of the documents returned so far, which
+// gives more predictable memory use than SetBatchSize for documents of varying
+// size. It takes precedence over SetBatchSize and only affects subsequent
+// document batches fetched from the database.
+func (c *Cursor) SetTargetBatchBytes(size int32) {
+	c.bc.SetTargetBatchBytes(size)
+}
+
 // SetMaxAwaitTime will set the maximum amount of time the server will allow the
 // operations to execute. The server will error if this field is set but the
 // cursor is not configured with awaitData=true.
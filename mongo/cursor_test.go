@@ -97,6 +97,7 @@ func (tbc *testBatchCursor) Close(context.Context) error {
 }
 
 func (tbc *testBatchCursor) SetBatchSize(int32)            {}
+func (tbc *testBatchCursor) SetTargetBatchBytes(int32)     {}
 func (tbc *testBatchCursor) SetComment(interface{})        {}
 func (tbc *testBatchCursor) SetMaxAwaitTime(time.Duration) {}
 
@@ -43,6 +43,10 @@ type Database struct {
 	writeSelector  description.ServerSelector
 	bsonOpts       *options.BSONOptions
 	registry       *bson.Registry
+
+	// explicitWriteConcern is true if the write concern was set through DatabaseOptions, in which
+	// case it takes precedence over namespace write concerns of the database's collections.
+	explicitWriteConcern bool
 }
 
 func newDatabase(client *Client, name string, opts ...options.Lister[options.DatabaseOptions]) *Database {
@@ -59,6 +63,9 @@ func newDatabase(client *Client, name string, opts ...options.Lister[options.Dat
 	}
 
 	wc := client.writeConcern
+	if nsWC, ok := client.namespaceWriteConcerns[name]; ok {
+		wc = nsWC
+	}
 	if args.WriteConcern != nil {
 		wc = args.WriteConcern
 	}
@@ -81,6 +88,8 @@ func newDatabase(client *Client, name string, opts ...options.Lister[options.Dat
 		writeConcern:   wc,
 		bsonOpts:       bsonOpts,
 		registry:       reg,
+
+		explicitWriteConcern: args.WriteConcern != nil,
 	}
 
 	db.readSelector = &serverselector.Composite{
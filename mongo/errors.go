@@ -24,6 +24,19 @@ import (
 // ErrClientDisconnected is returned when disconnected Client is used to run an operation.
 var ErrClientDisconnected = errors.New("client is disconnected")
 
+// ErrPoolExhausted is returned when an operation cannot check out a connection because the connection pool has reached
+// MaxPoolSize and the Client is configured to fail fast with ClientOptions.SetWaitQueueFailFast.
+var ErrPoolExhausted error = topology.ErrPoolExhausted
+
+// ErrWaitQueueFull is returned when an operation cannot check out a connection because the connection pool wait queue
+// has reached the size configured with ClientOptions.SetMaxWaitQueueSize.
+var ErrWaitQueueFull error = topology.ErrWaitQueueFull
+
+// ErrKnownDuplicateKey is returned, wrapped, by InsertOne and InsertMany if the unique key of a document is found in the
+// local set of known keys configured with CollectionOptionsBuilder.SetUniqueKeyCheck. No documents are sent to the
+// server in that case.
+var ErrKnownDuplicateKey = errors.New("unique key is known to exist in the collection")
+
 // InvalidArgumentError wraps an invalid argument error.
 type InvalidArgumentError struct {
 	wrapped error
@@ -175,7 +175,8 @@ func (iv IndexView) CreateOne(
 // the new indexes.
 //
 // For each IndexModel in the models parameter, the index name can be specified via the Options field. If a name is not
-// given, it will be generated from the Keys document.
+// given, it will be generated from the Keys document, unless the Verbatim option is set, in which case the name is
+// left to the server and an empty string is returned for that index.
 //
 // The opts parameter can be used to specify options for this operation (see the options.CreateIndexesOptions
 // documentation).
@@ -186,6 +187,12 @@ func (iv IndexView) CreateMany(
 	models []IndexModel,
 	opts ...options.Lister[options.CreateIndexesOptions],
 ) ([]string, error) {
+	args, err := mongoutil.NewOptions[options.CreateIndexesOptions](opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+	verbatim := args.Verbatim != nil && *args.Verbatim
+
 	names := make([]string, 0, len(models))
 
 	var indexes bsoncore.Document
@@ -205,7 +212,12 @@ func (iv IndexView) CreateMany(
 			return nil, err
 		}
 
-		name, err := getOrGenerateIndexName(keys, model)
+		var name string
+		if verbatim {
+			name, err = getIndexName(model)
+		} else {
+			name, err = getOrGenerateIndexName(keys, model)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -219,7 +231,9 @@ func (iv IndexView) CreateMany(
 		if model.Options == nil {
 			model.Options = options.Index()
 		}
-		model.Options.SetName(name)
+		if !verbatim {
+			model.Options.SetName(name)
+		}
 
 		optsDoc, err := iv.createOptionsDoc(model.Options)
 		if err != nil {
@@ -234,7 +248,7 @@ func (iv IndexView) CreateMany(
 		}
 	}
 
-	indexes, err := bsoncore.AppendArrayEnd(indexes, aidx)
+	indexes, err = bsoncore.AppendArrayEnd(indexes, aidx)
 	if err != nil {
 		return nil, err
 	}
@@ -261,11 +275,6 @@ func (iv IndexView) CreateMany(
 
 	selector := makePinnedSelector(sess, iv.coll.writeSelector)
 
-	args, err := mongoutil.NewOptions[options.CreateIndexesOptions](opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to construct options from builder: %w", err)
-	}
-
 	op := operation.NewCreateIndexes(indexes).
 		Session(sess).WriteConcern(wc).ClusterClock(iv.coll.client.clock).
 		Database(iv.coll.db.name).Collection(iv.coll.name).CommandMonitor(iv.coll.client.monitor).
@@ -466,6 +475,19 @@ func (iv IndexView) DropAll(
 	return iv.drop(ctx, "*", opts...)
 }
 
+// getIndexName returns the name specified in the options of the model, or an empty string if no name is specified.
+func getIndexName(model IndexModel) (string, error) {
+	args, err := mongoutil.NewOptions[options.IndexOptions](model.Options)
+	if err != nil {
+		return "", fmt.Errorf("failed to construct options from builder: %w", err)
+	}
+
+	if args != nil && args.Name != nil {
+		return *args.Name, nil
+	}
+	return "", nil
+}
+
 func getOrGenerateIndexName(keySpecDocument bsoncore.Document, model IndexModel) (string, error) {
 	args, err := mongoutil.NewOptions[options.IndexOptions](model.Options)
 	if err != nil {
@@ -0,0 +1,96 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/xoptions"
+)
+
+func TestIndexView_CreateMany(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		opts      *options.CreateIndexesOptionsBuilder
+		wantNames []string
+		wantSpecs []bson.Raw
+	}{
+		{
+			name:      "generates missing names",
+			opts:      options.CreateIndexes(),
+			wantNames: []string{"a_1_b_-1", "custom"},
+			wantSpecs: []bson.Raw{
+				marshalDoc(t, bson.D{{"key", bson.D{{"a", 1}, {"b", -1}}}, {"name", "a_1_b_-1"}}),
+				marshalDoc(t, bson.D{{"key", bson.D{{"c", 1}}}, {"name", "custom"}}),
+			},
+		},
+		{
+			name:      "verbatim",
+			opts:      options.CreateIndexes().SetVerbatim(true),
+			wantNames: []string{"", "custom"},
+			wantSpecs: []bson.Raw{
+				marshalDoc(t, bson.D{{"key", bson.D{{"a", 1}, {"b", -1}}}}),
+				marshalDoc(t, bson.D{{"key", bson.D{{"c", 1}}}, {"name", "custom"}}),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Capture range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var started *event.CommandStartedEvent
+			monitor := &event.CommandMonitor{
+				Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+					started = evt
+				},
+			}
+
+			opts := options.Client().SetMonitor(monitor)
+			err := xoptions.SetInternalClientOptions(opts, "deployment", drivertest.NewMockDeployment(bson.D{{"ok", 1}}))
+			require.NoError(t, err, "SetInternalClientOptions error")
+			client, err := Connect(opts)
+			require.NoError(t, err, "Connect error")
+
+			unnamed := IndexModel{Keys: bson.D{{"a", 1}, {"b", -1}}}
+			named := IndexModel{Keys: bson.D{{"c", 1}}, Options: options.Index().SetName("custom")}
+
+			names, err := client.Database("db").Collection("coll").Indexes().
+				CreateMany(context.Background(), []IndexModel{unnamed, named}, tc.opts)
+			require.NoError(t, err, "CreateMany error")
+			assert.Equal(t, tc.wantNames, names, "unexpected index names")
+
+			require.NotNil(t, started, "expected a command started event")
+			values, err := started.Command.Lookup("indexes").Array().Values()
+			require.NoError(t, err, "error reading indexes")
+			require.Len(t, values, len(tc.wantSpecs), "unexpected number of index specs")
+			for i, want := range tc.wantSpecs {
+				assert.Equal(t, want, bson.Raw(values[i].Document()), "unexpected index spec %d", i)
+			}
+			assert.Nil(t, unnamed.Options, "expected the index model not to be modified")
+		})
+	}
+}
+
+func marshalDoc(t *testing.T, doc bson.D) bson.Raw {
+	t.Helper()
+
+	b, err := bson.Marshal(doc)
+	require.NoError(t, err, "Marshal error")
+	return b
+}
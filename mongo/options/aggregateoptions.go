@@ -22,6 +22,9 @@ type AggregateOptions struct {
 	BypassDocumentValidation *bool
 	Collation                *Collation
 	MaxAwaitTime             *time.Duration
+	MaxTime                  *time.Duration
+	IgnoreClientTimeout      *bool
+	LocalThreshold           *time.Duration
 	Comment                  interface{}
 	Hint                     interface{}
 	Let                      interface{}
@@ -108,6 +111,27 @@ func (ao *AggregateOptionsBuilder) SetMaxAwaitTime(d time.Duration) *AggregateOp
 	return ao
 }
 
+// SetMaxTime sets the value for the MaxTime field. MaxTime is the maximum amount of time that the
+// server may spend executing the Aggregate operation, sent as the "maxTimeMS" command option. The
+// precedence of the time limits that apply to the operation is:
+//
+//  1. If the operation Context has a deadline, "maxTimeMS" is calculated from the deadline and
+//     MaxTime is ignored.
+//  2. Otherwise, if a Timeout is set on the Client, "maxTimeMS" is calculated from the Timeout
+//     and MaxTime is ignored, unless IgnoreClientTimeout is set to true.
+//  3. Otherwise, MaxTime is sent.
+//
+// The default value is nil, which means that the server does not limit the execution time.
+func (ao *AggregateOptionsBuilder) SetMaxTime(d time.Duration) *AggregateOptionsBuilder {
+	ao.Opts = append(ao.Opts, func(opts *AggregateOptions) error {
+		opts.MaxTime = &d
+
+		return nil
+	})
+
+	return ao
+}
+
 // SetComment sets the value for the Comment field. Specifies a string or document that will be included in
 // server logs, profiling logs, and currentOp queries to help trace the operation. The default is nil,
 // which means that no comment will be included in the logs.
@@ -135,6 +159,20 @@ func (ao *AggregateOptionsBuilder) SetHint(h interface{}) *AggregateOptionsBuild
 	return ao
 }
 
+// SetIgnoreClientTimeout sets the value for the IgnoreClientTimeout field. If true, the Timeout
+// set on the Client is not applied to the Aggregate operation, so that MaxTime is honored even if it
+// is longer than the client Timeout. A deadline on the operation Context is still honored and
+// takes precedence over MaxTime. The default value is false.
+func (ao *AggregateOptionsBuilder) SetIgnoreClientTimeout(b bool) *AggregateOptionsBuilder {
+	ao.Opts = append(ao.Opts, func(opts *AggregateOptions) error {
+		opts.IgnoreClientTimeout = &b
+
+		return nil
+	})
+
+	return ao
+}
+
 // SetLet sets the value for the Let field. Specifies parameters for the aggregate expression. This
 // option is only valid for MongoDB versions >= 5.0. Older servers will report an error for using this
 // option. This must be a document mapping parameter names to values. Values must be constant or closed
@@ -150,6 +188,21 @@ func (ao *AggregateOptionsBuilder) SetLet(let interface{}) *AggregateOptionsBuil
 	return ao
 }
 
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold overrides the latency
+// window used when selecting a server for the Aggregate operation, e.g. a threshold of 0 only allows
+// the suitable servers with the lowest average round trip time. It takes precedence over a threshold
+// set on the operation Context with mongo.WithLocalThreshold. The default value is nil, which means
+// that the window configured with ClientOptions.SetLocalThreshold is used.
+func (ao *AggregateOptionsBuilder) SetLocalThreshold(d time.Duration) *AggregateOptionsBuilder {
+	ao.Opts = append(ao.Opts, func(opts *AggregateOptions) error {
+		opts.LocalThreshold = &d
+
+		return nil
+	})
+
+	return ao
+}
+
 // SetCustom sets the value for the Custom field. Key-value pairs of the BSON map should correlate
 // with desired option names and values. Values must be Marshalable. Custom options may conflict
 // with non-custom options, and custom options bypass client-side validation. Prefer using non-custom
@@ -18,6 +18,7 @@ type BulkWriteOptions struct {
 	Comment                  interface{}
 	Ordered                  *bool
 	Let                      interface{}
+	BatchSize                *int
 }
 
 // BulkWriteOptionsBuilder contains options to configure bulk write operations.
@@ -92,3 +93,17 @@ func (b *BulkWriteOptionsBuilder) SetLet(let interface{}) *BulkWriteOptionsBuild
 
 	return b
 }
+
+// SetBatchSize sets the value for the BatchSize field. BatchSize specifies the maximum number of write models that
+// will be sent to the server in a single command. Smaller batches are acknowledged sooner, which allows
+// Collection.BulkWriteStream to report results incrementally. The default value is nil, which means that the batch
+// size is only limited by the server's maxWriteBatchSize and maxMessageSizeBytes.
+func (b *BulkWriteOptionsBuilder) SetBatchSize(size int) *BulkWriteOptionsBuilder {
+	b.Opts = append(b.Opts, func(opts *BulkWriteOptions) error {
+		opts.BatchSize = &size
+
+		return nil
+	})
+
+	return b
+}
@@ -23,6 +23,7 @@ type ChangeStreamOptions struct {
 	FullDocumentBeforeChange *FullDocument
 	MaxAwaitTime             *time.Duration
 	ResumeAfter              interface{}
+	ResumeTokenHandler       func(token bson.Raw)
 	ShowExpandedEvents       *bool
 	StartAtOperationTime     *bson.Timestamp
 	StartAfter               interface{}
@@ -102,7 +103,10 @@ func (cso *ChangeStreamOptionsBuilder) SetFullDocumentBeforeChange(fdbc FullDocu
 }
 
 // SetMaxAwaitTime sets the value for the MaxAwaitTime field. The maximum amount of time that the server should
-// wait for new documents to satisfy a tailable cursor query.
+// wait for new documents to satisfy a tailable cursor query. It is sent as the maxTimeMS value of each getMore
+// command. MaxAwaitTime must not be negative or Watch returns an error. If the client Timeout option is set,
+// MaxAwaitTime must also be less than it or Watch returns an error. If the context passed to Next or TryNext has a
+// deadline, MaxAwaitTime must be less than the time remaining until it.
 func (cso *ChangeStreamOptionsBuilder) SetMaxAwaitTime(d time.Duration) *ChangeStreamOptionsBuilder {
 	cso.Opts = append(cso.Opts, func(opts *ChangeStreamOptions) error {
 		opts.MaxAwaitTime = &d
@@ -122,6 +126,20 @@ func (cso *ChangeStreamOptionsBuilder) SetResumeAfter(rt interface{}) *ChangeStr
 	return cso
 }
 
+// SetResumeTokenHandler sets the value for the ResumeTokenHandler field. If set, fn is called with the new resume
+// token each time the change stream caches a resume token that differs from the previous one: after each event is
+// returned by Next or TryNext and after each empty batch that includes a post-batch resume token. Applications can
+// use fn to persist the token durably and pass the persisted token to SetResumeAfter or SetStartAfter when the
+// change stream is recreated. fn is called on the goroutine iterating the change stream, so it should return
+// quickly. The token passed to fn is a copy and can be retained.
+func (cso *ChangeStreamOptionsBuilder) SetResumeTokenHandler(fn func(token bson.Raw)) *ChangeStreamOptionsBuilder {
+	cso.Opts = append(cso.Opts, func(opts *ChangeStreamOptions) error {
+		opts.ResumeTokenHandler = fn
+		return nil
+	})
+	return cso
+}
+
 // SetShowExpandedEvents sets the value for the ShowExpandedEvents field. ShowExpandedEvents specifies whether
 // the server will return an expanded list of change stream events. Additional events include: createIndexes,
 // dropIndexes, modify, create, shardCollection, reshardCollection and refineCollectionShardKey. This option
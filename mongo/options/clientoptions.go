@@ -11,14 +11,18 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math"
 	"net"
 	"net/http"
+	"path"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -35,6 +39,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/connstring"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/ocsp"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
 )
 
@@ -58,6 +63,23 @@ const (
 	ServerMonitoringModeStream = connstring.ServerMonitoringModeStream
 )
 
+const (
+	// OCSPPolicySoftFail indicates that the driver contacts OCSP responders if the server does not staple a response,
+	// and continues the connection if the certificate status cannot be determined.
+	OCSPPolicySoftFail = ocsp.PolicySoftFail
+
+	// OCSPPolicyHardFail indicates that the driver contacts OCSP responders if the server does not staple a response,
+	// and fails the connection if the certificate status cannot be determined.
+	OCSPPolicyHardFail = ocsp.PolicyHardFail
+
+	// OCSPPolicyDisabled indicates that the driver never contacts OCSP responders. Stapled responses are still
+	// verified.
+	OCSPPolicyDisabled = ocsp.PolicyDisabled
+)
+
+// defaultTLSMinVersion is the minimum TLS version used for TLS configurations generated from URI options.
+const defaultTLSMinVersion = tls.VersionTLS12
+
 // ContextDialer is an interface that can be implemented by types that can create connections. It should be used to
 // provide a custom dialer when configuring a Client.
 //
@@ -119,6 +141,21 @@ type Credential struct {
 	OIDCHumanCallback       OIDCCallback
 }
 
+// CredentialProvider supplies the Credential used to authenticate new connections. It can be used instead of a static
+// Credential when secrets rotate, e.g. dynamic credentials issued by a secrets manager.
+//
+// Credential is called once before the handshake of every new connection, including connections created by the
+// connection pool in the background, so implementations must be safe for concurrent use. The driver does not cache the
+// returned Credential; implementations are responsible for caching credentials and refreshing them before they expire
+// to avoid a round trip to the secret store for every connection. Connections that are already established are not
+// re-authenticated when the provided credentials change.
+//
+// Mechanisms that require re-authentication, such as MONGODB-OIDC, should use the OIDC callbacks on Credential
+// instead.
+type CredentialProvider interface {
+	Credential(ctx context.Context) (*Credential, error)
+}
+
 // OIDCCallback is the type for both Human and Machine Callback flows.
 // RefreshToken will always be nil in the OIDCArgs for the Machine flow.
 type OIDCCallback func(context.Context, *OIDCArgs) (*OIDCCredential, error)
@@ -145,6 +182,26 @@ type IDPInfo struct {
 	RequestScopes []string
 }
 
+// CertificateExpiryCallback is the type of the callback invoked when a certificate used to establish a TLS connection
+// expires within the configured warning window. The host parameter is the address of the server the connection was
+// made to and isClientCert is true for the client's own certificates and false for certificates presented by the
+// server.
+type CertificateExpiryCallback func(host string, cert *x509.Certificate, isClientCert bool)
+
+// DialedConnCallback is the type of the callback invoked with the raw network connection of every new connection
+// immediately after it is dialed. The host parameter is the address of the server the connection was made to.
+type DialedConnCallback func(host string, conn net.Conn) error
+
+// ConnectionIDFormatter is the type of the function used to generate the identifiers of new connections. The host
+// parameter is the address of the server the connection is made to and seq is a process-wide sequence number that is
+// unique for every connection.
+type ConnectionIDFormatter func(host string, seq uint64) string
+
+// PoolGenerationCallback is the type of the callback invoked every time the generation of a server's connection pool
+// is incremented. The host parameter is the address of the server, serviceID is nil unless the deployment is behind a
+// load balancer, and err is the error that caused the pool to be cleared, if any.
+type PoolGenerationCallback func(host string, serviceID *bson.ObjectID, oldGeneration, newGeneration uint64, err error)
+
 // BSONOptions are optional BSON marshaling and unmarshaling behaviors.
 type BSONOptions struct {
 	// UseJSONStructTags causes the driver to fall back to using the "json"
@@ -206,11 +263,25 @@ type BSONOptions struct {
 	// instead of a bson.Binary.
 	BinaryAsSlice bool
 
+	// CanonicalExtJSON causes the driver to unmarshal BSON documents into
+	// json.RawMessage values as canonical Extended JSON instead of relaxed
+	// Extended JSON. It only has an effect if the registry has the codec
+	// registered by bson.RegisterJSONRawMessage.
+	CanonicalExtJSON bool
+
 	// DefaultDocumentM causes the driver to always unmarshal documents into the
 	// bson.M type. This behavior is restricted to data typed as
 	// "interface{}" or "map[string]interface{}".
 	DefaultDocumentM bool
 
+	// DefaultNumberType causes the driver to unmarshal BSON "int32", "int64",
+	// and "double" values into the given Go integer or float type (e.g.
+	// reflect.TypeOf(float64(0))) instead of int32, int64, and float64
+	// respectively. This behavior is restricted to data typed as
+	// "interface{}". Unmarshaling a BSON "double" with a fractional part into
+	// an integer type returns an error unless AllowTruncatingDoubles is set.
+	DefaultNumberType reflect.Type
+
 	// ObjectIDAsHexString causes the Decoder to decode object IDs to their hex
 	// representation.
 	ObjectIDAsHexString bool
@@ -244,46 +315,90 @@ type DriverInfo struct {
 // can be set through the ClientOptions setter functions. See each function for
 // documentation.
 type ClientOptions struct {
-	AppName                  *string
-	Auth                     *Credential
-	AutoEncryptionOptions    *AutoEncryptionOptions
-	ConnectTimeout           *time.Duration
-	Compressors              []string
-	Dialer                   ContextDialer
-	Direct                   *bool
-	DisableOCSPEndpointCheck *bool
-	DriverInfo               *DriverInfo
-	HeartbeatInterval        *time.Duration
-	Hosts                    []string
-	HTTPClient               *http.Client
-	LoadBalanced             *bool
-	LocalThreshold           *time.Duration
-	LoggerOptions            *LoggerOptions
-	MaxConnIdleTime          *time.Duration
-	MaxPoolSize              *uint64
-	MinPoolSize              *uint64
-	MaxConnecting            *uint64
-	PoolMonitor              *event.PoolMonitor
-	Monitor                  *event.CommandMonitor
-	ServerMonitor            *event.ServerMonitor
-	ReadConcern              *readconcern.ReadConcern
-	ReadPreference           *readpref.ReadPref
-	BSONOptions              *BSONOptions
-	Registry                 *bson.Registry
-	ReplicaSet               *string
-	RetryReads               *bool
-	RetryWrites              *bool
-	ServerAPIOptions         *ServerAPIOptions
-	ServerMonitoringMode     *string
-	ServerSelectionTimeout   *time.Duration
-	SRVMaxHosts              *int
-	SRVServiceName           *string
-	Timeout                  *time.Duration
-	TLSConfig                *tls.Config
-	TLCPConfig               *tlcp.Config
-	WriteConcern             *writeconcern.WriteConcern
-	ZlibLevel                *int
-	ZstdLevel                *int
+	AllowedTXTOptions               []string
+	AppName                         *string
+	Auth                            *Credential
+	AutoEncryptionOptions           *AutoEncryptionOptions
+	ClockSkewWarningThreshold       *time.Duration
+	ConnectTimeout                  *time.Duration
+	ConnectionIDFormatter           ConnectionIDFormatter
+	Compressors                     []string
+	CompressHeartbeats              *bool
+	CredentialProvider              CredentialProvider
+	Dialer                          ContextDialer
+	DialedConnCallback              DialedConnCallback
+	Direct                          *bool
+	DisableOCSPEndpointCheck        *bool
+	DriverInfo                      *DriverInfo
+	HedgeEnabled                    *bool
+	OCSPHostPolicies                map[string]string
+	HeartbeatInterval               *time.Duration
+	Hosts                           []string
+	HTTPClient                      *http.Client
+	KeepAliveCount                  *int
+	KeepAliveIdle                   *time.Duration
+	KeepAliveInterval               *time.Duration
+	LoadBalanced                    *bool
+	LocalThreshold                  *time.Duration
+	LoggerOptions                   *LoggerOptions
+	MaxConnIdleTime                 *time.Duration
+	MinHeartbeatInterval            *time.Duration
+	MaxPoolSize                     *uint64
+	MinPoolSize                     *uint64
+	MaxConnecting                   *uint64
+	MaxWaitQueueSize                *int
+	MaxUncompressedMessageSize      *int32
+	MaxConcurrentDials              *int
+	MaxConcurrentOperations         *int
+	MaxConcurrentOperationsFailFast *bool
+	MaxStreamingMonitors            *int
+	MessageChecksum                 *bool
+	NamespaceWriteConcerns          map[string]*writeconcern.WriteConcern
+	OperationErrorRateWindow        *time.Duration
+	WarmSpares                      *uint64
+	PinLeakThreshold                *time.Duration
+	PoolGenerationCallback          PoolGenerationCallback
+	PoolMonitor                     *event.PoolMonitor
+	Monitor                         *event.CommandMonitor
+	ServerMonitor                   *event.ServerMonitor
+	ReadConcern                     *readconcern.ReadConcern
+	ReadPreference                  *readpref.ReadPref
+	RequireKnownTopology            *bool
+	ReadTimeout                     *time.Duration
+	BSONOptions                     *BSONOptions
+	Registry                        *bson.Registry
+	ReplicaSet                      *string
+	RequestIDGenerator              func() int32
+	RequireCompression              *bool
+	RetryReads                      *bool
+	RetryWrites                     *bool
+	RTTSmoothingFactor              *float64
+	ScopedConnectionIDs             *bool
+	ServerAPIOptions                *ServerAPIOptions
+	ServerMonitoringMode            *string
+	ServerSelectionTimeout          *time.Duration
+	SingleConnection                *bool
+	SpeculativeAuth                 *bool
+	SRVMaxHosts                     *int
+	SRVMaxPollingFailures           *int
+	SRVPollingRetries               *int
+	SRVPollingRetryBackoff          *time.Duration
+	SRVServiceName                  *string
+	StrictCompressorLevels          *bool
+	Timeout                         *time.Duration
+	TLSConfig                       *tls.Config
+	TLSCertExpiryCallback           CertificateExpiryCallback
+	TLSCertExpiryWarningDays        *int
+	TLSKeyLogWriter                 io.Writer
+	TLSMinVersion                   *uint16
+	TLSRenegotiation                *tls.RenegotiationSupport
+	TLSVerifyPeerCertificate        func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+	TLCPConfig                      *tlcp.Config
+	WaitQueueFailFast               *bool
+	WriteConcern                    *writeconcern.WriteConcern
+	WriteTimeout                    *time.Duration
+	ZlibLevel                       *int
+	ZstdLevel                       *int
 
 	// Crypt specifies a custom driver.Crypt to be used to encrypt and decrypt documents. The default is no
 	// encryption.
@@ -450,7 +565,13 @@ func setURIOpts(uri string, opts *ClientOptions) error {
 	}
 
 	if connString.SSL {
-		tlsConfig := new(tls.Config)
+		tlsConfig := &tls.Config{MinVersion: defaultTLSMinVersion}
+		if opts.TLSMinVersion != nil {
+			tlsConfig.MinVersion = *opts.TLSMinVersion
+		}
+		if opts.TLSRenegotiation != nil {
+			tlsConfig.Renegotiation = *opts.TLSRenegotiation
+		}
 
 		if connString.SSLCaFileSet {
 			if err := addCACertFromFile(tlsConfig, connString.SSLCaFile); err != nil {
@@ -538,10 +659,10 @@ func (c *ClientOptions) Validate() error {
 		return c.err
 	}
 
-	// Direct connections cannot be made if multiple hosts are specified or an SRV
-	// URI is used.
+	// Direct connections cannot be made if multiple distinct hosts are specified
+	// or an SRV URI is used.
 	if c.Direct != nil && *c.Direct {
-		if len(c.Hosts) > 1 {
+		if hosts, _ := connstring.DeduplicateHosts(c.Hosts); len(hosts) > 1 {
 			return errors.New("a direct connection cannot be made if multiple hosts are specified")
 		}
 		if c.connString != nil && c.connString.Scheme == connstring.SchemeMongoDBSRV {
@@ -549,9 +670,44 @@ func (c *ClientOptions) Validate() error {
 		}
 	}
 
-	if c.HeartbeatInterval != nil && *c.HeartbeatInterval < (500*time.Millisecond) {
-		return fmt.Errorf("heartbeatFrequencyMS must exceed the minimum heartbeat interval of 500ms, got heartbeatFrequencyMS=%q",
-			*c.HeartbeatInterval)
+	minHeartbeatInterval := 500 * time.Millisecond
+	if m := c.MinHeartbeatInterval; m != nil {
+		if *m <= 0 {
+			return fmt.Errorf(`invalid value %v for "MinHeartbeatInterval": value must be positive`, *m)
+		}
+		minHeartbeatInterval = *m
+	}
+	if c.HeartbeatInterval != nil && *c.HeartbeatInterval < minHeartbeatInterval {
+		return fmt.Errorf("heartbeatFrequencyMS must exceed the minimum heartbeat interval of %v, got heartbeatFrequencyMS=%q",
+			minHeartbeatInterval, *c.HeartbeatInterval)
+	}
+
+	// Max staleness must account for the time between heartbeats plus the interval at which the primary
+	// performs idle writes (10s). Otherwise, server selection fails for every read.
+	if c.HeartbeatInterval != nil && c.ReadPreference != nil {
+		const idleWritePeriod = 10 * time.Second
+		if maxStaleness, ok := c.ReadPreference.MaxStaleness(); ok && maxStaleness < *c.HeartbeatInterval+idleWritePeriod {
+			return fmt.Errorf(
+				"max staleness (%s) must be greater than or equal to the heartbeat interval (%s) plus idle write period (%s)",
+				maxStaleness, *c.HeartbeatInterval, idleWritePeriod)
+		}
+	}
+
+	if f := c.RTTSmoothingFactor; f != nil && (*f <= 0 || *f > 1) {
+		return fmt.Errorf(`invalid value %v for "RTTSmoothingFactor": value must be greater than 0 and at most 1`, *f)
+	}
+
+	if c.RequireCompression != nil && *c.RequireCompression && len(c.Compressors) == 0 {
+		return errors.New(`"RequireCompression" is set but "Compressors" is empty`)
+	}
+
+	if c.StrictCompressorLevels != nil && *c.StrictCompressorLevels {
+		if c.ZlibLevel != nil && !stringSliceContains(c.Compressors, "zlib") {
+			return errors.New(`"ZlibLevel" is set but "zlib" is not included in "Compressors"`)
+		}
+		if c.ZstdLevel != nil && !stringSliceContains(c.Compressors, "zstd") {
+			return errors.New(`"ZstdLevel" is set but "zstd" is not included in "Compressors"`)
+		}
 	}
 
 	if c.MaxPoolSize != nil && c.MinPoolSize != nil && *c.MaxPoolSize != 0 &&
@@ -567,9 +723,13 @@ func (c *ClientOptions) Validate() error {
 		}
 	}
 
+	if c.SingleConnection != nil && *c.SingleConnection && (c.Direct == nil || !*c.Direct) {
+		return errors.New("a single connection can only be used with a direct connection to a single host")
+	}
+
 	// Validation for load-balanced mode.
 	if c.LoadBalanced != nil && *c.LoadBalanced {
-		if len(c.Hosts) > 1 {
+		if hosts, _ := connstring.DeduplicateHosts(c.Hosts); len(hosts) > 1 {
 			return connstring.ErrLoadBalancedWithMultipleHosts
 		}
 		if c.ReplicaSet != nil {
@@ -594,9 +754,90 @@ func (c *ClientOptions) Validate() error {
 		return fmt.Errorf("invalid server monitoring mode: %q", *mode)
 	}
 
+	for pattern, policy := range c.OCSPHostPolicies {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid OCSP host pattern %q: %w", pattern, err)
+		}
+		if !ocsp.IsValidPolicy(policy) {
+			return fmt.Errorf("invalid OCSP policy %q for host pattern %q", policy, pattern)
+		}
+	}
+
+	if v := c.TLSMinVersion; v != nil {
+		switch *v {
+		case tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+		default:
+			return fmt.Errorf("invalid TLS minimum version: %#04x", *v)
+		}
+	}
+
+	if c.AllowedTXTOptions != nil && c.connString != nil {
+		for _, opt := range c.connString.TXTOptions {
+			if !stringSliceContainsFold(c.AllowedTXTOptions, opt) {
+				return fmt.Errorf("option %q from the TXT record is not in the allowed TXT options", opt)
+			}
+		}
+	}
+
+	if n := c.SRVMaxPollingFailures; n != nil && *n < 0 {
+		return fmt.Errorf(`invalid value %d for "SRVMaxPollingFailures": value must not be negative`, *n)
+	}
+
+	if n := c.SRVPollingRetries; n != nil && *n < 0 {
+		return fmt.Errorf(`invalid value %d for "SRVPollingRetries": value must not be negative`, *n)
+	}
+
+	if size := c.MaxWaitQueueSize; size != nil && *size < 0 {
+		return fmt.Errorf(`invalid value %d for "MaxWaitQueueSize": value must not be negative`, *size)
+	}
+
+	if size := c.MaxUncompressedMessageSize; size != nil && *size < 0 {
+		return fmt.Errorf(`invalid value %d for "MaxUncompressedMessageSize": value must not be negative`, *size)
+	}
+
+	if n := c.MaxConcurrentDials; n != nil && *n < 0 {
+		return fmt.Errorf(`invalid value %d for "MaxConcurrentDials": value must not be negative`, *n)
+	}
+
+	if n := c.MaxStreamingMonitors; n != nil && *n < 0 {
+		return fmt.Errorf(`invalid value %d for "MaxStreamingMonitors": value must not be negative`, *n)
+	}
+
+	if n := c.MaxConcurrentOperations; n != nil && *n < 0 {
+		return fmt.Errorf(`invalid value %d for "MaxConcurrentOperations": value must not be negative`, *n)
+	}
+
+	if n := c.KeepAliveCount; n != nil && *n < 0 {
+		return fmt.Errorf(`invalid value %d for "KeepAliveCount": value must not be negative`, *n)
+	}
+
+	if r := c.TLSRenegotiation; r != nil {
+		switch *r {
+		case tls.RenegotiateNever, tls.RenegotiateOnceAsClient, tls.RenegotiateFreelyAsClient:
+		default:
+			return fmt.Errorf("invalid TLS renegotiation support: %d", *r)
+		}
+	}
+
+	if days := c.TLSCertExpiryWarningDays; days != nil && *days < 0 {
+		return fmt.Errorf(`invalid value %d for "TLSCertExpiryWarningDays": value must not be negative`, *days)
+	}
+
 	if to := c.Timeout; to != nil && *to < 0 {
 		return fmt.Errorf(`invalid value %q for "Timeout": value must be positive`, *to)
 	}
+	if to := c.ReadTimeout; to != nil && *to < 0 {
+		return fmt.Errorf(`invalid value %q for "ReadTimeout": value must be positive`, *to)
+	}
+	if to := c.WriteTimeout; to != nil && *to < 0 {
+		return fmt.Errorf(`invalid value %q for "WriteTimeout": value must be positive`, *to)
+	}
+
+	for _, opt := range c.durationOptions() {
+		if opt.value != nil && *opt.value < 0 {
+			return fmt.Errorf(`invalid value %v for %q: value must not be negative`, *opt.value, opt.name)
+		}
+	}
 
 	// OIDC Validation
 	if c.Auth != nil && c.Auth.AuthMechanism == auth.MongoDBOIDC {
@@ -653,6 +894,51 @@ func (c *ClientOptions) Validate() error {
 	return nil
 }
 
+// namedDuration is a duration option and the name of its ClientOptions field.
+type namedDuration struct {
+	name  string
+	value *time.Duration
+}
+
+// durationOptions returns all duration options of c.
+func (c *ClientOptions) durationOptions() []namedDuration {
+	return []namedDuration{
+		{"ClockSkewWarningThreshold", c.ClockSkewWarningThreshold},
+		{"ConnectTimeout", c.ConnectTimeout},
+		{"HeartbeatInterval", c.HeartbeatInterval},
+		{"KeepAliveIdle", c.KeepAliveIdle},
+		{"KeepAliveInterval", c.KeepAliveInterval},
+		{"LocalThreshold", c.LocalThreshold},
+		{"MaxConnIdleTime", c.MaxConnIdleTime},
+		{"MinHeartbeatInterval", c.MinHeartbeatInterval},
+		{"OperationErrorRateWindow", c.OperationErrorRateWindow},
+		{"PinLeakThreshold", c.PinLeakThreshold},
+		{"ReadTimeout", c.ReadTimeout},
+		{"SRVPollingRetryBackoff", c.SRVPollingRetryBackoff},
+		{"ServerSelectionTimeout", c.ServerSelectionTimeout},
+		{"Timeout", c.Timeout},
+		{"WriteTimeout", c.WriteTimeout},
+	}
+}
+
+// Warnings returns a message for each option that is valid but is unlikely to
+// behave as intended. Currently, a warning is returned for each duration option
+// that is greater than 0 but less than a millisecond, because durations are
+// rounded to whole milliseconds when they are serialized to "*MS" fields (e.g.
+// "connectTimeoutMS" or "maxTimeMS"). Warnings are logged at the info level
+// for the topology component when a Client is created.
+func (c *ClientOptions) Warnings() []string {
+	var warnings []string
+	for _, opt := range c.durationOptions() {
+		if opt.value != nil && *opt.value > 0 && *opt.value < time.Millisecond {
+			warnings = append(warnings, fmt.Sprintf(
+				"value %v for %q is less than a millisecond and will be rounded when serialized to milliseconds",
+				*opt.value, opt.name))
+		}
+	}
+	return warnings
+}
+
 // ApplyURI parses the given URI and sets options accordingly. The URI can contain host names, IPv4/IPv6 literals, or
 // an SRV record that will be resolved when the Client is created. When using an SRV record, TLS support is
 // implicitly enabled. Specify the "tls=false" URI option to override this.
@@ -677,6 +963,121 @@ func (c *ClientOptions) ApplyURI(uri string) *ClientOptions {
 	return c
 }
 
+// ValidateURI parses and validates the connection string uri without requiring a ClientOptions
+// to be constructed by the caller. It returns the first error that ApplyURI followed by Validate
+// would return, or nil if the connection string is valid. For "mongodb+srv" connection strings,
+// this performs the same SRV and TXT record lookups as ApplyURI.
+func ValidateURI(uri string) error {
+	return Client().ApplyURI(uri).Validate()
+}
+
+// jsonDuration is a time.Duration that is unmarshaled from a duration string
+// such as "30s" or "1m30s".
+type jsonDuration time.Duration
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *jsonDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(dur)
+	return nil
+}
+
+// clientOptionsJSON is the subset of ClientOptions that can be set from JSON.
+type clientOptionsJSON struct {
+	URI                    string        `json:"uri"`
+	AppName                *string       `json:"appName"`
+	MaxPoolSize            *uint64       `json:"maxPoolSize"`
+	MinPoolSize            *uint64       `json:"minPoolSize"`
+	MaxConnecting          *uint64       `json:"maxConnecting"`
+	MaxConnIdleTime        *jsonDuration `json:"maxConnIdleTime"`
+	ConnectTimeout         *jsonDuration `json:"connectTimeout"`
+	ServerSelectionTimeout *jsonDuration `json:"serverSelectionTimeout"`
+	HeartbeatInterval      *jsonDuration `json:"heartbeatInterval"`
+	Timeout                *jsonDuration `json:"timeout"`
+	Compressors            []string      `json:"compressors"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface so that ClientOptions
+// can be loaded from a configuration file. Only the following fields are
+// supported:
+//
+//	{
+//		"uri": "mongodb://localhost:27017",
+//		"appName": "example_application",
+//		"maxPoolSize": 100,
+//		"minPoolSize": 0,
+//		"maxConnecting": 2,
+//		"maxConnIdleTime": "5m",
+//		"connectTimeout": "30s",
+//		"serverSelectionTimeout": "30s",
+//		"heartbeatInterval": "10s",
+//		"timeout": "10s",
+//		"compressors": ["zstd", "snappy"]
+//	}
+//
+// Durations are strings accepted by time.ParseDuration. The "uri" field is
+// applied first with ApplyURI, so the other fields override the corresponding
+// URI options. Unknown fields are rejected. Any error is also recorded and can
+// be retrieved by calling Validate.
+//
+// Embedding ClientOptions in another struct promotes this method, which then
+// decodes the whole struct, so a configuration struct should use a named field
+// instead.
+func (c *ClientOptions) UnmarshalJSON(b []byte) error {
+	var cfg clientOptionsJSON
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		c.err = fmt.Errorf("error unmarshaling client options: %w", err)
+
+		return c.err
+	}
+
+	if cfg.URI != "" {
+		c.ApplyURI(cfg.URI)
+	}
+	if cfg.AppName != nil {
+		c.SetAppName(*cfg.AppName)
+	}
+	if cfg.MaxPoolSize != nil {
+		c.SetMaxPoolSize(*cfg.MaxPoolSize)
+	}
+	if cfg.MinPoolSize != nil {
+		c.SetMinPoolSize(*cfg.MinPoolSize)
+	}
+	if cfg.MaxConnecting != nil {
+		c.SetMaxConnecting(*cfg.MaxConnecting)
+	}
+	if cfg.MaxConnIdleTime != nil {
+		c.SetMaxConnIdleTime(time.Duration(*cfg.MaxConnIdleTime))
+	}
+	if cfg.ConnectTimeout != nil {
+		c.SetConnectTimeout(time.Duration(*cfg.ConnectTimeout))
+	}
+	if cfg.ServerSelectionTimeout != nil {
+		c.SetServerSelectionTimeout(time.Duration(*cfg.ServerSelectionTimeout))
+	}
+	if cfg.HeartbeatInterval != nil {
+		c.SetHeartbeatInterval(time.Duration(*cfg.HeartbeatInterval))
+	}
+	if cfg.Timeout != nil {
+		c.SetTimeout(time.Duration(*cfg.Timeout))
+	}
+	if cfg.Compressors != nil {
+		c.SetCompressors(cfg.Compressors)
+	}
+
+	return c.err
+}
+
 // SetAppName specifies an application name that is sent to the server when creating new connections. It is used by the
 // server to log connection and profiling information (e.g. slow query logs). This can also be set through the "appName"
 // URI option (e.g "appName=example_application"). The default is empty, meaning no app name will be sent.
@@ -695,6 +1096,23 @@ func (c *ClientOptions) SetAuth(auth Credential) *ClientOptions {
 	return c
 }
 
+// SetCredentialProvider specifies a CredentialProvider that is consulted for a fresh Credential before the handshake of
+// every new connection. If set, it takes precedence over the Credential specified with SetAuth or the URI. The default
+// is nil, meaning the static Credential is used. See the options.CredentialProvider documentation for caching and
+// refresh expectations.
+func (c *ClientOptions) SetCredentialProvider(provider CredentialProvider) *ClientOptions {
+	c.CredentialProvider = provider
+
+	return c
+}
+
+// SupportedCompressors returns the names of the compressors supported by the driver, in the form accepted by
+// SetCompressors and the "compressors" URI option. All compressors are implemented in Go, so the result does not
+// depend on build tags or cgo. Compression is only used if the server also supports the negotiated compressor.
+func SupportedCompressors() []string {
+	return []string{"snappy", "zlib", "zstd"}
+}
+
 // SetCompressors sets the compressors that can be used when communicating with a server. Valid values are:
 //
 // 1. "snappy"
@@ -709,6 +1127,9 @@ func (c *ClientOptions) SetAuth(auth Credential) *ClientOptions {
 // https://www.mongodb.com/docs/manual/reference/program/mongod/#cmdoption-mongod-networkmessagecompressors for more
 // information about configuring compression on the server and the server-side defaults.
 //
+// SupportedCompressors reports the compressors that are available in this build. Custom compressors registered with
+// driver.RegisterCompressor can also be used by name, as long as the server supports them too.
+//
 // This can also be set through the "compressors" URI option (e.g. "compressors=zstd,zlib,snappy"). The default is
 // an empty slice, meaning no compression will be enabled.
 func (c *ClientOptions) SetCompressors(comps []string) *ClientOptions {
@@ -717,6 +1138,29 @@ func (c *ClientOptions) SetCompressors(comps []string) *ClientOptions {
 	return c
 }
 
+// SetCompressHeartbeats specifies whether server monitoring heartbeats should be compressed when using the streaming
+// protocol. The initial handshake on a monitoring connection is always uncompressed because it is used to negotiate a
+// compressor. If this option is true and a compressor was negotiated, the awaitable hello commands that start
+// streaming are compressed, and the server compresses the hello responses it streams back. This has no effect unless
+// compressors are configured with SetCompressors, and it requires the streaming protocol, which is only supported by
+// MongoDB 4.4 and later, so polling heartbeats are never compressed. The default is false.
+func (c *ClientOptions) SetCompressHeartbeats(b bool) *ClientOptions {
+	c.CompressHeartbeats = &b
+
+	return c
+}
+
+// SetClockSkewWarningThreshold specifies the estimated clock skew between a server and the local clock above which a
+// warning is logged at the info level for the topology component. The skew is estimated from the "localTime" field of
+// each heartbeat reply and is reported in the ClockSkew field of the server descriptions in the topology. Causal
+// consistency and the expiry of OIDC access tokens both rely on clocks that are roughly in sync. If this is 0, no
+// warning is logged. The default is 1 minute.
+func (c *ClientOptions) SetClockSkewWarningThreshold(d time.Duration) *ClientOptions {
+	c.ClockSkewWarningThreshold = &d
+
+	return c
+}
+
 // SetConnectTimeout specifies a timeout that is used for creating connections to the server. This can be set through
 // ApplyURI with the "connectTimeoutMS" (e.g "connectTimeoutMS=30") option. If set to 0, no timeout will be used. The
 // default is 30 seconds.
@@ -726,6 +1170,30 @@ func (c *ClientOptions) SetConnectTimeout(d time.Duration) *ClientOptions {
 	return c
 }
 
+// SetConnectionIDFormatter specifies a function used to generate the identifiers of new connections, including
+// monitoring connections. Connection identifiers appear in logs and in the ConnectionID fields of command and server
+// heartbeat events. This is useful for embedding a process or pod identifier so identifiers from different processes
+// do not collide, or for generating deterministic identifiers in tests. The function must be safe for concurrent use.
+//
+// The default is nil, meaning identifiers have the form "<host>[-<seq>]". Monitors that need the server address should
+// use the Address fields of the events rather than parse it from a ConnectionID.
+func (c *ClientOptions) SetConnectionIDFormatter(fn ConnectionIDFormatter) *ClientOptions {
+	c.ConnectionIDFormatter = fn
+
+	return c
+}
+
+// SetScopedConnectionIDs specifies whether the sequence numbers of the connections created by the Client should be
+// taken from a counter owned by the Client rather than from the process-wide counter shared by all Clients. If true,
+// each Client numbers its connections from 1, so the identifiers of connections made by different Clients in the
+// same process do not interleave in logs and events. The sequence numbers are also passed to the function configured
+// with SetConnectionIDFormatter. The default is false.
+func (c *ClientOptions) SetScopedConnectionIDs(b bool) *ClientOptions {
+	c.ScopedConnectionIDs = &b
+
+	return c
+}
+
 // SetDialer specifies a custom ContextDialer to be used to create new connections to the server. This method overrides
 // the default net.Dialer, so dialer options such as Timeout, KeepAlive, Resolver, etc can be set.
 // See https://golang.org/pkg/net/#Dialer for more information about the net.Dialer type.
@@ -758,24 +1226,108 @@ func (c *ClientOptions) SetDirect(b bool) *ClientOptions {
 
 // SetHeartbeatInterval specifies the amount of time to wait between periodic background server checks. This can also be
 // set through the "heartbeatFrequencyMS" URI option (e.g. "heartbeatFrequencyMS=10000"). The default is 10 seconds.
-// The minimum is 500ms.
+// The minimum is 500ms unless it is lowered with SetMinHeartbeatInterval.
 func (c *ClientOptions) SetHeartbeatInterval(d time.Duration) *ClientOptions {
 	c.HeartbeatInterval = &d
 
 	return c
 }
 
+// SetMinHeartbeatInterval overrides the minimum heartbeat interval, which is both the lowest value accepted by
+// SetHeartbeatInterval and the minimum time between two checks of a server. Lowering it speeds up topology discovery
+// and recovery against local servers, at the cost of more monitoring traffic.
+//
+// This option is intended for testing only and should not be used in production deployments.
+//
+// This value must be positive. The default is 500ms.
+func (c *ClientOptions) SetMinHeartbeatInterval(d time.Duration) *ClientOptions {
+	c.MinHeartbeatInterval = &d
+
+	return c
+}
+
+// SetOperationErrorRateWindow specifies the period over which the outcomes of the operations run against each server are
+// counted to compute the server's error rate, which is reported by Client.OperationStats. The window advances in steps
+// of a tenth of its length. This value must not be negative. The default is 1 minute.
+func (c *ClientOptions) SetOperationErrorRateWindow(d time.Duration) *ClientOptions {
+	c.OperationErrorRateWindow = &d
+
+	return c
+}
+
+// SetRTTSmoothingFactor specifies the smoothing factor of the exponentially weighted moving average (EWMA) of each
+// server's round-trip time, which is the average RTT used to compute the latency window for server selection, e.g. for
+// the "nearest" read preference. Each new RTT sample is weighted by the factor and the previous average by one minus the
+// factor, so lower values smooth out transient RTT spikes at the cost of reacting more slowly to lasting changes. The
+// value must be greater than 0 and at most 1. The default is 0.2.
+func (c *ClientOptions) SetRTTSmoothingFactor(f float64) *ClientOptions {
+	c.RTTSmoothingFactor = &f
+
+	return c
+}
+
+// SetHedgeEnabled specifies whether hedged reads are enabled by default for reads sent to a sharded cluster. When
+// enabled, a mongos sends each eligible read to two replica set members and returns the first response. The setting
+// only applies to reads with a non-primary read preference that is sent to a mongos; it is ignored for primary reads and
+// for other topologies. A read preference created with readpref.WithHedgeEnabled, e.g. one configured on a Database,
+// Collection, RunCommand call, or transaction, overrides this setting. The default is nil, meaning no hedge document is
+// sent and the server default is used.
+//
+// Hedged reads are deprecated in MongoDB 8.0 and may be removed in a future MongoDB version.
+func (c *ClientOptions) SetHedgeEnabled(enabled bool) *ClientOptions {
+	c.HedgeEnabled = &enabled
+
+	return c
+}
+
 // SetHosts specifies a list of host names or IP addresses for servers in a cluster. Both IPv4 and IPv6 addresses are
 // supported. IPv6 literals must be enclosed in '[]' following RFC-2732 syntax.
 //
 // Hosts can also be specified as a comma-separated list in a URI. For example, to include "localhost:27017" and
-// "localhost:27018", a URI could be "mongodb://localhost:27017,localhost:27018". The default is ["localhost:27017"]
+// "localhost:27018", a URI could be "mongodb://localhost:27017,localhost:27018".
+//
+// Duplicate hosts, e.g. "localhost" and "localhost:27017", are only monitored once and count as a single host when
+// validating direct and load-balanced connections. The removed duplicates are logged at the info level for the
+// topology component. The default is ["localhost:27017"]
 func (c *ClientOptions) SetHosts(s []string) *ClientOptions {
 	c.Hosts = s
 
 	return c
 }
 
+// SetKeepAliveIdle specifies how long a connection must be idle before the operating system starts sending TCP
+// keep-alive probes on its socket (TCP_KEEPIDLE). The value is rounded up to a whole number of seconds. Together with
+// SetKeepAliveInterval and SetKeepAliveCount, this allows dead peers to be detected quickly, e.g. behind stateful
+// firewalls that silently drop idle connections.
+//
+// The keep-alive parameters are applied to the socket after it is dialed and are only supported on Linux and macOS.
+// On other platforms, or if a custom Dialer returns a connection that does not expose its socket, they are ignored.
+// The default value is 0, which means that the default of the Go runtime (15 seconds) is used.
+func (c *ClientOptions) SetKeepAliveIdle(d time.Duration) *ClientOptions {
+	c.KeepAliveIdle = &d
+
+	return c
+}
+
+// SetKeepAliveInterval specifies the interval between TCP keep-alive probes on a connection's socket
+// (TCP_KEEPINTVL). The value is rounded up to a whole number of seconds. See SetKeepAliveIdle for the platforms on
+// which keep-alive parameters are supported. The default value is 0, which means that the default of the Go runtime
+// (15 seconds) is used.
+func (c *ClientOptions) SetKeepAliveInterval(d time.Duration) *ClientOptions {
+	c.KeepAliveInterval = &d
+
+	return c
+}
+
+// SetKeepAliveCount specifies the number of unacknowledged TCP keep-alive probes after which the operating system
+// considers a connection dead and closes it (TCP_KEEPCNT). See SetKeepAliveIdle for the platforms on which keep-alive
+// parameters are supported. The default value is 0, which means that the default of the operating system is used.
+func (c *ClientOptions) SetKeepAliveCount(n int) *ClientOptions {
+	c.KeepAliveCount = &n
+
+	return c
+}
+
 // SetLoadBalanced specifies whether or not the MongoDB deployment is hosted behind a load balancer. This can also be
 // set through the "loadBalanced" URI option. The driver will error during Client configuration if this option is set
 // to true and one of the following conditions are met:
@@ -795,7 +1347,8 @@ func (c *ClientOptions) SetLoadBalanced(lb bool) *ClientOptions {
 // SetLocalThreshold specifies the width of the 'latency window': when choosing between multiple suitable servers for an
 // operation, this is the acceptable non-negative delta between shortest and longest average round-trip times. A server
 // within the latency window is selected randomly. This can also be set through the "localThresholdMS" URI option (e.g.
-// "localThresholdMS=15000"). The default is 15 milliseconds.
+// "localThresholdMS=15000"). The default is 15 milliseconds. The window can be narrowed for individual operations by
+// running them with a Context returned by mongo.WithLocalThreshold.
 func (c *ClientOptions) SetLocalThreshold(d time.Duration) *ClientOptions {
 	c.LocalThreshold = &d
 
@@ -831,6 +1384,13 @@ func (c *ClientOptions) SetMaxPoolSize(u uint64) *ClientOptions {
 // SetMinPoolSize specifies the minimum number of connections allowed in the driver's connection pool to each server. If
 // this is non-zero, each server's pool will be maintained in the background to ensure that the size does not fall below
 // the minimum. This can also be set through the "minPoolSize" URI option (e.g. "minPoolSize=100"). The default is 0.
+//
+// Connections created in the background are fully established, including the TLS and authentication handshakes, before
+// they are made available, so they are ready to use without additional round trips. Authentication uses the
+// credentials that are current when the connection is created, e.g. the result of the CredentialProvider. Note that
+// this authenticates every maintained connection eagerly: after a pool is cleared (e.g. during a failover), up to
+// MinPoolSize connections per server authenticate at once, which adds authentication load on the server that grows
+// with the number of clients.
 func (c *ClientOptions) SetMinPoolSize(u uint64) *ClientOptions {
 	c.MinPoolSize = &u
 
@@ -846,6 +1406,130 @@ func (c *ClientOptions) SetMaxConnecting(u uint64) *ClientOptions {
 	return c
 }
 
+// SetMaxConcurrentDials specifies the maximum number of connections that may be dialed concurrently across all servers
+// in the deployment, including monitoring connections. Unlike SetMaxConnecting, which limits the connections being
+// established to each server, this limit is shared by every server, so it can be used to avoid a burst of dials when
+// the Client starts or after a pool is cleared. A dial that cannot start waits until another dial completes, or fails
+// when its context is done or the connect timeout expires. Only the dial is limited; the TLS and MongoDB handshakes are
+// not.
+//
+// This value must not be negative. The default is 0, meaning the number of concurrent dials is not limited.
+func (c *ClientOptions) SetMaxConcurrentDials(n int) *ClientOptions {
+	c.MaxConcurrentDials = &n
+
+	return c
+}
+
+// SetMaxConcurrentOperations specifies the maximum number of operations that may execute concurrently across all
+// servers in the deployment. Unlike the connection pool size, which limits connections per server, this limit is shared
+// by every server, so it can be used to protect a downstream resource that all operations depend on. An operation that
+// cannot start waits until another operation completes, or fails when its context is done. Use
+// SetMaxConcurrentOperationsFailFast to fail immediately instead of waiting. Operations started while running another
+// operation, such as key vault queries for automatic encryption, share that operation's slot.
+//
+// This value must not be negative. The default is 0, meaning the number of concurrent operations is not limited.
+func (c *ClientOptions) SetMaxConcurrentOperations(n int) *ClientOptions {
+	c.MaxConcurrentOperations = &n
+
+	return c
+}
+
+// SetMaxConcurrentOperationsFailFast specifies whether an operation that cannot start because the limit set with
+// SetMaxConcurrentOperations has been reached fails immediately instead of waiting. The default is false.
+func (c *ClientOptions) SetMaxConcurrentOperationsFailFast(b bool) *ClientOptions {
+	c.MaxConcurrentOperationsFailFast = &b
+
+	return c
+}
+
+// SetMaxUncompressedMessageSize specifies the maximum size, in bytes, that a compressed message received from the server
+// may have once it is decompressed. The uncompressed size is declared in the header of each compressed message, so it
+// is checked before any memory is allocated to decompress the message, which protects against decompression bombs
+// sent by a malicious server. A message that exceeds the maximum causes the operation to fail with an error for which
+// errors.Is(err, driver.ErrUncompressedSizeTooLarge) is true and its connection to be closed. This value must not be
+// negative. The default is 0, meaning the maximum MongoDB message size of 48MB is used.
+func (c *ClientOptions) SetMaxUncompressedMessageSize(size int32) *ClientOptions {
+	c.MaxUncompressedMessageSize = &size
+
+	return c
+}
+
+// SetMessageChecksum specifies whether the driver should set the checksumPresent flag and append a CRC-32C checksum
+// to every OP_MSG it sends, so that the server can detect messages corrupted in transit. Checksums on replies are
+// verified whenever the server includes them, and an operation fails with an error for which
+// errors.Is(err, wiremessage.ErrMsgChecksumMismatch) is true if a reply does not match its checksum.
+//
+// Computing and verifying checksums costs CPU time proportional to the size of each message, which is noticeable for
+// large inserts and query results. Checksums are only accepted by servers that support OP_MSG (MongoDB 3.6 and later),
+// and the server does not add checksums to replies on TLS connections, which already protect message integrity. The
+// default is false.
+func (c *ClientOptions) SetMessageChecksum(b bool) *ClientOptions {
+	c.MessageChecksum = &b
+
+	return c
+}
+
+// SetMaxWaitQueueSize specifies the maximum number of goroutines that may wait to check out a connection from a
+// connection pool. When the wait queue is full, new check outs fail immediately with an error for which
+// errors.Is(err, mongo.ErrWaitQueueFull) is true, which prevents goroutines from piling up while a deployment is
+// unavailable or overloaded. The limit applies to each server's connection pool. If this is 0, the wait queue is
+// unbounded. The default is 0.
+func (c *ClientOptions) SetMaxWaitQueueSize(size int) *ClientOptions {
+	c.MaxWaitQueueSize = &size
+
+	return c
+}
+
+// SetWarmSpares specifies the number of idle connections that the driver's connection pool to each server keeps ready
+// for immediate checkout in addition to the connections required by MinPoolSize. That is, the pool keeps WarmSpares
+// more connections than the larger of MinPoolSize and the number of connections in use, so a pool with a MinPoolSize of
+// 10 and 2 warm spares opens 12 connections. Warm spare connections are fully established, including the TLS and
+// authentication handshakes, by a background routine so operations that would otherwise need a new connection, such as
+// the first operations after a failover, do not pay the connection setup cost. Warm spares are subject to
+// MaxConnIdleTime and are replaced when they are closed, and the pool never grows beyond MaxPoolSize to maintain them.
+// The default is 0.
+func (c *ClientOptions) SetWarmSpares(u uint64) *ClientOptions {
+	c.WarmSpares = &u
+
+	return c
+}
+
+// SetPinLeakThreshold specifies the duration after which a connection that is still pinned to a cursor or transaction
+// is reported as a likely leak. Connections are pinned to cursors and transactions when connected to a load balancer,
+// and a connection that is never unpinned usually means a cursor was not closed or a transaction was not committed or
+// aborted. When enabled, the driver captures a stack trace each time a connection is pinned and logs it at the info
+// level for the connection component once the threshold elapses. Capturing stack traces has a cost, so this should
+// only be enabled for debugging. If this is 0, pin leak detection is disabled. The default is 0.
+func (c *ClientOptions) SetPinLeakThreshold(d time.Duration) *ClientOptions {
+	c.PinLeakThreshold = &d
+
+	return c
+}
+
+// SetWaitQueueFailFast specifies whether checking out a connection from a connection pool that has reached
+// MaxPoolSize should fail immediately instead of waiting for a connection to be checked in. If true, operations that
+// cannot get a connection return an error for which errors.Is(err, mongo.ErrPoolExhausted) is true. This is useful for
+// applications that prefer shedding load over queuing. The default is false.
+func (c *ClientOptions) SetWaitQueueFailFast(b bool) *ClientOptions {
+	c.WaitQueueFailFast = &b
+
+	return c
+}
+
+// SetPoolGenerationCallback specifies a callback that is invoked every time the generation of a server's connection
+// pool is incremented. The driver increments the generation when it clears a pool, e.g. after a network error or a
+// "not writable primary" error during a failover, which marks all connections of the previous generation as stale so
+// they are closed instead of reused. The callback receives the previous and new generation and the error that caused
+// the pool to be cleared, if any, which makes pool clears observable.
+//
+// The callback is invoked synchronously by the goroutine that clears the pool, so it must be safe for concurrent use
+// and must not block. The default is nil.
+func (c *ClientOptions) SetPoolGenerationCallback(fn PoolGenerationCallback) *ClientOptions {
+	c.PoolGenerationCallback = fn
+
+	return c
+}
+
 // SetPoolMonitor specifies a PoolMonitor to receive connection pool events. See the event.PoolMonitor documentation
 // for more information about the structure of the monitor and events that can be received.
 func (c *ClientOptions) SetPoolMonitor(m *event.PoolMonitor) *ClientOptions {
@@ -878,6 +1562,40 @@ func (c *ClientOptions) SetReadConcern(rc *readconcern.ReadConcern) *ClientOptio
 	return c
 }
 
+// SetReadFromTags configures the client to read from replica set members that have all of the given tags, e.g.
+// map[string]string{"region": "east", "use": "reporting"}. It is a shorthand for setting a read preference with a
+// single tag set created by readpref.WithTagSets.
+//
+// If a non-primary read preference has already been set (e.g. through SetReadPreference or ApplyURI), its mode,
+// maxStaleness, and hedge options are kept and its tag sets are replaced with the given tags. Otherwise, the read
+// preference mode is secondaryPreferred, since tags cannot be used with the primary mode.
+func (c *ClientOptions) SetReadFromTags(tags map[string]string) *ClientOptions {
+	set := tag.NewTagSetFromMap(tags)
+	sort.Slice(set, func(i, j int) bool { return set[i].Name < set[j].Name })
+
+	mode := readpref.SecondaryPreferredMode
+	opts := []readpref.Option{readpref.WithTagSets(set)}
+	if rp := c.ReadPreference; rp != nil && rp.Mode() != readpref.PrimaryMode {
+		mode = rp.Mode()
+		if maxStaleness, ok := rp.MaxStaleness(); ok {
+			opts = append(opts, readpref.WithMaxStaleness(maxStaleness))
+		}
+		if hedgeEnabled := rp.HedgeEnabled(); hedgeEnabled != nil {
+			opts = append(opts, readpref.WithHedgeEnabled(*hedgeEnabled))
+		}
+	}
+
+	rp, err := readpref.New(mode, opts...)
+	if err != nil {
+		c.err = err
+
+		return c
+	}
+	c.ReadPreference = rp
+
+	return c
+}
+
 // SetReadPreference specifies the read preference to use for read operations. This can also be set through the
 // following URI options:
 //
@@ -923,6 +1641,16 @@ func (c *ClientOptions) SetReplicaSet(s string) *ClientOptions {
 	return c
 }
 
+// SetRequestIDGenerator specifies a function used to generate the request IDs of wire messages sent to the server.
+// The function must be safe for concurrent use and should return unique values for in-flight requests on a
+// connection. This is useful for deterministic testing and for correlating requests across processes. The default is
+// nil, meaning request IDs are taken from a process-wide atomic counter.
+func (c *ClientOptions) SetRequestIDGenerator(fn func() int32) *ClientOptions {
+	c.RequestIDGenerator = fn
+
+	return c
+}
+
 // SetRetryWrites specifies whether supported write operations should be retried once on certain errors, such as network
 // errors.
 //
@@ -939,6 +1667,17 @@ func (c *ClientOptions) SetRetryWrites(b bool) *ClientOptions {
 	return c
 }
 
+// SetRequireCompression specifies whether a connection should fail to be established if none of the compressors set
+// through SetCompressors is supported by the server. If true, the handshake fails with an error instead of the
+// connection silently communicating uncompressed, which is useful when compression is needed to keep traffic over a
+// bandwidth-constrained link within its limits. It is an error to set it to true without setting any compressors. The
+// default is false.
+func (c *ClientOptions) SetRequireCompression(b bool) *ClientOptions {
+	c.RequireCompression = &b
+
+	return c
+}
+
 // SetRetryReads specifies whether supported read operations should be retried once on certain errors, such as network
 // errors.
 //
@@ -962,6 +1701,31 @@ func (c *ClientOptions) SetServerSelectionTimeout(d time.Duration) *ClientOption
 	return c
 }
 
+// SetSingleConnection specifies whether the Client should run every operation over a single connection. If true, the
+// Client pins one connection from the connection pool and each operation waits for the previous operation to finish
+// using it, so commands are sent to the server one at a time and in the order in which they acquire the connection.
+// Cursors and sessions do not hold the connection between operations. If the connection is closed because of an
+// error, the next operation pins a new connection. Server monitoring uses separate connections.
+//
+// This option requires a direct connection to a single host (see SetDirect), so it cannot be used with multiple hosts,
+// SRV URIs, or load balanced deployments. It limits throughput to one operation at a time, so it should only be used
+// when strict ordering is required. The default is false.
+func (c *ClientOptions) SetSingleConnection(b bool) *ClientOptions {
+	c.SingleConnection = &b
+
+	return c
+}
+
+// SetSpeculativeAuth specifies whether the driver should include the first authentication message in the initial
+// connection handshake to save a network round trip. If false, authentication always runs as a separate conversation
+// after the handshake. If true, establishing a connection fails if the configured authentication mechanism does not
+// support speculative authentication. The default is to use speculative authentication when the mechanism supports it.
+func (c *ClientOptions) SetSpeculativeAuth(b bool) *ClientOptions {
+	c.SpeculativeAuth = &b
+
+	return c
+}
+
 // SetTimeout specifies the amount of time that a single operation run on this
 // Client can execute before returning an error. The deadline of any operation
 // run through the Client will be honored above any Timeout set on the Client;
@@ -972,13 +1736,48 @@ func (c *ClientOptions) SetServerSelectionTimeout(d time.Duration) *ClientOption
 //
 // If any Timeout is set (even 0) on the Client, the values of MaxTime on
 // operation options, TransactionOptions.MaxCommitTime and
-// SessionOptions.DefaultMaxCommitTime will be ignored.
+// SessionOptions.DefaultMaxCommitTime will be ignored. Find, FindOne and
+// Aggregate operations can opt out of the Timeout with the IgnoreClientTimeout
+// option, in which case their MaxTime is honored. A deadline on the operation
+// Context always takes precedence over both Timeout and MaxTime.
 func (c *ClientOptions) SetTimeout(d time.Duration) *ClientOptions {
 	c.Timeout = &d
 
 	return c
 }
 
+// SetReadTimeout specifies the amount of time that a single read operation
+// (e.g. Find, Aggregate without an output stage, CountDocuments, Distinct, or
+// listing collections, databases, and indexes) run on this Client can execute
+// before returning an error. If set, it takes precedence over Timeout for
+// read operations. Operations that are neither reads nor writes, such as
+// RunCommand, always use Timeout.
+//
+// As with Timeout, the deadline of an operation's Context is honored above
+// ReadTimeout; ReadTimeout is only applied if the Context has no deadline.
+// The default value is nil, meaning read operations use Timeout.
+func (c *ClientOptions) SetReadTimeout(d time.Duration) *ClientOptions {
+	c.ReadTimeout = &d
+
+	return c
+}
+
+// SetWriteTimeout specifies the amount of time that a single write operation
+// (e.g. insert, update, delete, findAndModify, aggregations with an output
+// stage, and committing or aborting a transaction) run on this Client can
+// execute before returning an error. If set, it takes precedence over Timeout
+// for write operations. Operations that are neither reads nor writes, such as
+// RunCommand, always use Timeout.
+//
+// As with Timeout, the deadline of an operation's Context is honored above
+// WriteTimeout; WriteTimeout is only applied if the Context has no deadline.
+// The default value is nil, meaning write operations use Timeout.
+func (c *ClientOptions) SetWriteTimeout(d time.Duration) *ClientOptions {
+	c.WriteTimeout = &d
+
+	return c
+}
+
 // SetTLSConfig specifies a tls.Config instance to use use to configure TLS on all connections created to the cluster.
 // This can also be set through the following URI options:
 //
@@ -1009,6 +1808,103 @@ func (c *ClientOptions) SetTLSConfig(cfg *tls.Config) *ClientOptions {
 	return c
 }
 
+// SetDialedConnCallback specifies a callback that is invoked with the net.Conn of every new connection immediately
+// after it is returned by the dialer. This is an advanced option intended for setting socket options that the driver
+// does not expose, such as SO_LINGER or socket buffer sizes, e.g. by asserting the connection to *net.TCPConn when
+// the default dialer is used.
+//
+// The callback receives the raw connection before it is wrapped for TLS or TLCP and before the MongoDB handshake is
+// performed, so writes and reads on the connection are not encrypted and are not framed as MongoDB wire messages. The
+// callback must not read from, write to, close, or set deadlines on the connection; doing so will corrupt the
+// connection and cause undefined behavior. The connection must not be retained after the callback returns.
+//
+// If the callback returns an error, the connection is closed and connection establishment fails with that error. The
+// callback is invoked concurrently for different connections, so it must be safe for concurrent use. The default is
+// nil.
+func (c *ClientOptions) SetDialedConnCallback(fn DialedConnCallback) *ClientOptions {
+	c.DialedConnCallback = fn
+
+	return c
+}
+
+// SetTLSCertExpiryCallback specifies a callback that is invoked after each TLS handshake for every certificate in the
+// server's certificate chain and every client certificate that expires within the warning window configured by
+// SetTLSCertExpiryWarningDays. This can be used to alert on certificates that need to be rotated. The callback is
+// invoked once per certificate for every new connection, so it must be safe for concurrent use and should not block.
+// The default is nil, meaning certificate expiration times are not checked.
+func (c *ClientOptions) SetTLSCertExpiryCallback(fn CertificateExpiryCallback) *ClientOptions {
+	c.TLSCertExpiryCallback = fn
+
+	return c
+}
+
+// SetTLSCertExpiryWarningDays specifies how many days before a certificate's expiration time the callback configured
+// by SetTLSCertExpiryCallback starts being invoked. This value must not be negative. The default is 30.
+func (c *ClientOptions) SetTLSCertExpiryWarningDays(days int) *ClientOptions {
+	c.TLSCertExpiryWarningDays = &days
+
+	return c
+}
+
+// SetTLSKeyLogWriter specifies a destination for the TLS master secrets of every TLS connection, written in NSS key
+// log format. This sets the KeyLogWriter field of the tls.Config generated from URI options or provided through
+// SetTLSConfig, and allows tools such as Wireshark to decrypt the traffic between the driver and the server for
+// debugging. To follow the SSLKEYLOGFILE convention, open the file named by that environment variable and pass it here.
+//
+// WARNING: Anyone with access to the key log can decrypt all traffic of the logged connections, including credentials
+// and application data. Never enable this in production and protect and delete the key log after debugging.
+//
+// The default is nil, meaning no key material is exported.
+func (c *ClientOptions) SetTLSKeyLogWriter(w io.Writer) *ClientOptions {
+	c.TLSKeyLogWriter = w
+
+	return c
+}
+
+// SetTLSMinVersion specifies the minimum TLS version that is acceptable when establishing TLS connections. The value
+// must be one of the version constants in the crypto/tls package (e.g. tls.VersionTLS12). This applies both to the
+// tls.Config generated from URI options and to a tls.Config provided through SetTLSConfig. The default for tls.Config
+// instances generated from URI options is tls.VersionTLS12.
+func (c *ClientOptions) SetTLSMinVersion(version uint16) *ClientOptions {
+	c.TLSMinVersion = &version
+
+	return c
+}
+
+// SetTLSRenegotiation specifies what types of TLS renegotiation are supported when establishing TLS connections. This
+// sets the Renegotiation field of the tls.Config generated from URI options and of a tls.Config provided through
+// SetTLSConfig. The default is tls.RenegotiateNever.
+//
+// Renegotiation is disabled by default in Go because it complicates the TLS state machine and has been the source of
+// several security vulnerabilities. Only enable it when connecting to servers or proxies that are known to require it
+// on long-lived connections. Renegotiation is not supported in TLS 1.3, and Go never accepts renegotiation requests
+// during the handshake or certificate changes during renegotiation.
+func (c *ClientOptions) SetTLSRenegotiation(renegotiation tls.RenegotiationSupport) *ClientOptions {
+	c.TLSRenegotiation = &renegotiation
+
+	return c
+}
+
+// SetTLSVerifyPeerCertificate specifies a callback that performs custom verification of the certificate chain presented
+// by the server, e.g. pinning the hash of a specific SubjectPublicKeyInfo. This sets the VerifyPeerCertificate field of
+// the tls.Config generated from URI options or provided through SetTLSConfig. If that tls.Config already has a
+// VerifyPeerCertificate callback, both callbacks are run and the connection fails if either returns an error.
+//
+// The callback runs in addition to the standard certificate verification, after it succeeds, and verifiedChains
+// contains the chains built by the standard verification. If InsecureSkipVerify is set (e.g. through
+// "tlsInsecure=true"), the standard verification is skipped, the callback is the only verification of the server's
+// certificates and verifiedChains is nil. The callback must be safe for concurrent use. The default is nil, meaning
+// only the standard verification is performed.
+func (c *ClientOptions) SetTLSVerifyPeerCertificate(
+	fn func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error,
+) *ClientOptions {
+	c.TLSVerifyPeerCertificate = fn
+
+	return c
+}
+
+// SetTLCPConfig specifies a tlcp.Config instance to use when connecting to servers over TLCP. TLCPConfigFromPEM can be
+// used to build the config from PEM-encoded certificates and keys. The default is nil, meaning TLCP is not used.
 func (c *ClientOptions) SetTLCPConfig(cfg *tlcp.Config) *ClientOptions {
 	c.TLCPConfig = cfg
 	return c
@@ -1043,10 +1939,55 @@ func (c *ClientOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *ClientOp
 	return c
 }
 
+// SetRequireKnownTopology specifies whether server selection should fail immediately while the topology is unknown,
+// i.e. before any server has responded successfully to a hello command, instead of waiting for a suitable server until
+// the server selection timeout expires. The returned error wraps topology.ErrTopologyUnknown. This is useful when an
+// application prefers to fail fast and report unavailability rather than block, e.g. in readiness checks. Note that
+// operations that run immediately after Connect fail until the initial server discovery completes. The default is
+// false.
+func (c *ClientOptions) SetRequireKnownTopology(b bool) *ClientOptions {
+	c.RequireKnownTopology = &b
+
+	return c
+}
+
+// SetNamespaceWriteConcern specifies the default write concern for a database or collection. The namespace parameter
+// is either a database name (e.g. "app") or a database name and collection name separated by a dot (e.g.
+// "app.events"). The write concern is used by Database and Collection handles created for the namespace when
+// options.DatabaseOptions or options.CollectionOptions do not specify one. A write concern set through
+// options.DatabaseOptions also takes precedence over the defaults of the database's collections. A collection default
+// takes precedence over a default for its database, which takes precedence over the client's write concern.
+//
+// If wc is nil, any default for the namespace is removed.
+func (c *ClientOptions) SetNamespaceWriteConcern(namespace string, wc *writeconcern.WriteConcern) *ClientOptions {
+	if wc == nil {
+		delete(c.NamespaceWriteConcerns, namespace)
+
+		return c
+	}
+	if c.NamespaceWriteConcerns == nil {
+		c.NamespaceWriteConcerns = make(map[string]*writeconcern.WriteConcern)
+	}
+	c.NamespaceWriteConcerns[namespace] = wc
+
+	return c
+}
+
+// SetStrictCompressorLevels specifies whether Validate should return an error if a compression level is set for a
+// compressor that is not enabled, i.e. if ZlibLevel is set but "zlib" is not included in Compressors, or if ZstdLevel
+// is set but "zstd" is not included in Compressors. This helps catch misconfigurations where a compression level is
+// silently ignored. The default is false, meaning such compression levels are ignored.
+func (c *ClientOptions) SetStrictCompressorLevels(strict bool) *ClientOptions {
+	c.StrictCompressorLevels = &strict
+
+	return c
+}
+
 // SetZlibLevel specifies the level for the zlib compressor. This option is ignored if zlib is not specified as a
-// compressor through ApplyURI or SetCompressors. Supported values are -1 through 9, inclusive. -1 tells the zlib
-// library to use its default, 0 means no compression, 1 means best speed, and 9 means best compression.
-// This can also be set through the "zlibCompressionLevel" URI option (e.g. "zlibCompressionLevel=-1"). Defaults to -1.
+// compressor through ApplyURI or SetCompressors, unless SetStrictCompressorLevels is used to report an error instead.
+// Supported values are -1 through 9, inclusive. -1 tells the zlib library to use its default, 0 means no compression, 1
+// means best speed, and 9 means best compression. This can also be set through the "zlibCompressionLevel" URI option
+// (e.g. "zlibCompressionLevel=-1"). Defaults to -1.
 func (c *ClientOptions) SetZlibLevel(level int) *ClientOptions {
 	c.ZlibLevel = &level
 
@@ -1054,8 +1995,9 @@ func (c *ClientOptions) SetZlibLevel(level int) *ClientOptions {
 }
 
 // SetZstdLevel sets the level for the zstd compressor. This option is ignored if zstd is not specified as a compressor
-// through ApplyURI or SetCompressors. Supported values are 1 through 20, inclusive. 1 means best speed and 20 means
-// best compression. This can also be set through the "zstdCompressionLevel" URI option. Defaults to 6.
+// through ApplyURI or SetCompressors, unless SetStrictCompressorLevels is used to report an error instead. Supported
+// values are 1 through 20, inclusive. 1 means best speed and 20 means best compression. This can also be set through
+// the "zstdCompressionLevel" URI option. Defaults to 6.
 func (c *ClientOptions) SetZstdLevel(level int) *ClientOptions {
 	c.ZstdLevel = &level
 
@@ -1086,6 +2028,19 @@ func (c *ClientOptions) SetDisableOCSPEndpointCheck(disableCheck bool) *ClientOp
 	return c
 }
 
+// SetOCSPHostPolicies specifies OCSP policies for individual hosts, keyed by host pattern. Patterns are matched against
+// the host name without the port using the syntax of path.Match, e.g. "*.example.com". If several patterns match a
+// host, an exact match takes precedence, followed by the longest pattern. Supported policies are OCSPPolicySoftFail,
+// OCSPPolicyHardFail, and OCSPPolicyDisabled.
+//
+// Hosts that do not match any pattern use the behavior configured by SetDisableOCSPEndpointCheck. This is useful for
+// deployments where only some hosts have OCSP responders. The default is nil.
+func (c *ClientOptions) SetOCSPHostPolicies(policies map[string]string) *ClientOptions {
+	c.OCSPHostPolicies = policies
+
+	return c
+}
+
 // SetServerAPIOptions specifies a ServerAPIOptions instance used to configure the API version sent to the server
 // when running commands. See the options.ServerAPIOptions documentation for more information about the supported
 // options.
@@ -1105,6 +2060,22 @@ func (c *ClientOptions) SetServerMonitoringMode(mode string) *ClientOptions {
 	return c
 }
 
+// SetMaxStreamingMonitors specifies the maximum number of servers that are monitored with the streaming protocol. In
+// stream monitoring mode, each server holds a dedicated monitoring connection on which the server pushes topology
+// changes, so very large deployments use a lot of sockets. Servers are granted streaming in the order in which they
+// become streamable and the remaining servers are polled. A server that stops being streamable, e.g. because it is
+// down, gives up its slot to another server.
+//
+// Topology changes on polled servers, such as a primary stepping down, are only detected at the next heartbeat, so they
+// can take up to the heartbeat interval to be noticed instead of being reported immediately. This option has no effect
+// if the server monitoring mode is "poll". This value must not be negative. The default is 0, meaning every server can
+// use streaming.
+func (c *ClientOptions) SetMaxStreamingMonitors(n int) *ClientOptions {
+	c.MaxStreamingMonitors = &n
+
+	return c
+}
+
 // SetSRVMaxHosts specifies the maximum number of SRV results to randomly select during polling. To limit the number
 // of hosts selected in SRV discovery, this function must be called before ApplyURI. This can also be set through
 // the "srvMaxHosts" URI option.
@@ -1114,6 +2085,53 @@ func (c *ClientOptions) SetSRVMaxHosts(srvMaxHosts int) *ClientOptions {
 	return c
 }
 
+// SetSRVMaxPollingFailures specifies the number of consecutive failed SRV record polls after which the driver reports
+// that the host list discovered through a "mongodb+srv" URI may be stale. Polls fail if the DNS lookup returns an error
+// or no valid hosts. While polls fail, the driver keeps using the last known host list. Once the limit is reached,
+// server selection errors include a SRV polling error describing the DNS failure until a poll succeeds again.
+//
+// This value must not be negative. The default is 0, meaning SRV polling failures are not reported.
+func (c *ClientOptions) SetSRVMaxPollingFailures(n int) *ClientOptions {
+	c.SRVMaxPollingFailures = &n
+
+	return c
+}
+
+// SetSRVPollingRetries specifies the number of times a SRV record poll retries the DNS lookup after a transient DNS
+// error, such as a timeout or a temporary DNS server failure, before the poll is considered failed. Retries happen
+// within a single poll, so a DNS hiccup does not cause the driver to poll more frequently or count towards
+// SRVMaxPollingFailures. Other errors, such as a missing SRV record, are not retried.
+//
+// This value must not be negative. The default is 0, meaning failed lookups are not retried.
+func (c *ClientOptions) SetSRVPollingRetries(n int) *ClientOptions {
+	c.SRVPollingRetries = &n
+
+	return c
+}
+
+// SetSRVPollingRetryBackoff specifies how long a SRV record poll waits before retrying a DNS lookup that failed with
+// a transient error. The wait doubles after each retry. This option has no effect unless SRVPollingRetries is set.
+//
+// This value must not be negative. The default is 100 milliseconds, which is also used if the value is 0.
+func (c *ClientOptions) SetSRVPollingRetryBackoff(d time.Duration) *ClientOptions {
+	c.SRVPollingRetryBackoff = &d
+
+	return c
+}
+
+// SetAllowedTXTOptions restricts which options a "mongodb+srv" URI may take from the DNS TXT record of its host.
+// Regardless of this option, the driver only accepts the "authSource", "replicaSet" and "loadBalanced" options from a
+// TXT record and rejects the URI if the record contains any other option. If this option is set, Validate also
+// returns an error if the TXT record contains an option that is not in opts. Option names are matched
+// case-insensitively. An empty slice rejects all TXT record options.
+//
+// The default is nil, meaning all options allowed by the specification are accepted from the TXT record.
+func (c *ClientOptions) SetAllowedTXTOptions(opts []string) *ClientOptions {
+	c.AllowedTXTOptions = opts
+
+	return c
+}
+
 // SetSRVServiceName specifies a custom SRV service name to use in SRV polling. To use a custom SRV service name
 // in SRV discovery, this function must be called before ApplyURI. This can also be set through the "srvServiceName"
 // URI option.
@@ -1287,6 +2305,15 @@ func stringSliceContains(source []string, target string) bool {
 	return false
 }
 
+func stringSliceContainsFold(source []string, target string) bool {
+	for _, str := range source {
+		if strings.EqualFold(str, target) {
+			return true
+		}
+	}
+	return false
+}
+
 // create a username for x509 authentication from an x509 certificate subject.
 func extractX509UsernameFromSubject(subject string) string {
 	// the Go x509 package gives the subject with the pairs in the reverse order from what we want.
@@ -22,6 +22,28 @@ type CollectionOptions struct {
 	ReadPreference *readpref.ReadPref
 	BSONOptions    *BSONOptions
 	Registry       *bson.Registry
+	UniqueKeyCheck *UniqueKeyCheck
+}
+
+// UniqueKeyFunc extracts the unique key of a document that is about to be inserted. It returns false if the
+// document has no unique key, in which case the document is not checked.
+type UniqueKeyFunc func(doc bson.Raw) (key string, ok bool)
+
+// UniqueKeySet is a local set of unique keys that are known to exist in a collection. It can be an exact set or a
+// probabilistic structure such as a bloom filter. Implementations must be safe for concurrent use.
+type UniqueKeySet interface {
+	// Contains reports whether key is known to exist in the collection.
+	Contains(key string) bool
+
+	// Add records that key exists in the collection.
+	Add(key string)
+}
+
+// UniqueKeyCheck configures a best-effort, client-side check of unique keys before documents are inserted. See
+// CollectionOptionsBuilder.SetUniqueKeyCheck for more information.
+type UniqueKeyCheck struct {
+	KeyFunc UniqueKeyFunc
+	Keys    UniqueKeySet
 }
 
 // CollectionOptionsBuilder contains options to configure a Collection instance.
@@ -103,3 +125,23 @@ func (c *CollectionOptionsBuilder) SetRegistry(r *bson.Registry) *CollectionOpti
 	})
 	return c
 }
+
+// SetUniqueKeyCheck sets the value for the UniqueKeyCheck field. If set, InsertOne and InsertMany extract the unique
+// key of each document with fn before sending it to the server. If any key is contained in keys, no documents are
+// sent and an error wrapping mongo.ErrKnownDuplicateKey is returned. After an insert, the keys of the documents that
+// were inserted and of the documents rejected by the server with a duplicate key error are added to keys. This avoids
+// a round trip for inserts that are known to fail in hot paths.
+//
+// The check is best-effort and is not a substitute for a unique index on the server: keys inserted by other clients
+// or processes are not known locally, and a key that is removed from the collection is not removed from keys. If keys
+// is a probabilistic structure such as a bloom filter, a false positive causes a document to be rejected without
+// being sent to the server. The default value is nil, which means that no check is performed.
+func (c *CollectionOptionsBuilder) SetUniqueKeyCheck(fn UniqueKeyFunc, keys UniqueKeySet) *CollectionOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CollectionOptions) error {
+		opts.UniqueKeyCheck = &UniqueKeyCheck{KeyFunc: fn, Keys: keys}
+
+		return nil
+	})
+
+	return c
+}
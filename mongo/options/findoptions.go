@@ -19,8 +19,11 @@ type FindOptions struct {
 	Collation           *Collation
 	Comment             interface{}
 	Hint                interface{}
+	IgnoreClientTimeout *bool
+	LocalThreshold      *time.Duration
 	Max                 interface{}
 	MaxAwaitTime        *time.Duration
+	MaxTime             *time.Duration
 	Min                 interface{}
 	OplogReplay         *bool
 	Projection          interface{}
@@ -132,6 +135,18 @@ func (f *FindOptionsBuilder) SetHint(hint interface{}) *FindOptionsBuilder {
 	return f
 }
 
+// SetIgnoreClientTimeout sets the value for the IgnoreClientTimeout field. If true, the Timeout
+// set on the Client is not applied to the Find operation, so that MaxTime is honored even if it
+// is longer than the client Timeout. A deadline on the operation Context is still honored and
+// takes precedence over MaxTime. The default value is false.
+func (f *FindOptionsBuilder) SetIgnoreClientTimeout(b bool) *FindOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOptions) error {
+		opts.IgnoreClientTimeout = &b
+		return nil
+	})
+	return f
+}
+
 // SetLet sets the value for the Let field. Let specifies parameters for the find expression.
 // This option is only valid for MongoDB versions >= 5.0. Older servers will report an error
 // for using this option. This must be a document mapping parameter names to values. Values
@@ -157,6 +172,19 @@ func (f *FindOptionsBuilder) SetLimit(i int64) *FindOptionsBuilder {
 	return f
 }
 
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold overrides the latency
+// window used when selecting a server for the Find operation, e.g. a threshold of 0 only allows the
+// suitable servers with the lowest average round trip time. It takes precedence over a threshold set
+// on the operation Context with mongo.WithLocalThreshold. The default value is nil, which means that
+// the window configured with ClientOptions.SetLocalThreshold is used.
+func (f *FindOptionsBuilder) SetLocalThreshold(d time.Duration) *FindOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOptions) error {
+		opts.LocalThreshold = &d
+		return nil
+	})
+	return f
+}
+
 // SetMax sets the value for the Max field. Max is a document specifying the exclusive upper bound
 // for a specific index. The default value is nil, which means that there is no maximum value.
 func (f *FindOptionsBuilder) SetMax(max interface{}) *FindOptionsBuilder {
@@ -180,6 +208,25 @@ func (f *FindOptionsBuilder) SetMaxAwaitTime(d time.Duration) *FindOptionsBuilde
 	return f
 }
 
+// SetMaxTime sets the value for the MaxTime field. MaxTime is the maximum amount of time that the
+// server may spend executing the Find operation, sent as the "maxTimeMS" command option. The
+// precedence of the time limits that apply to the operation is:
+//
+//  1. If the operation Context has a deadline, "maxTimeMS" is calculated from the deadline and
+//     MaxTime is ignored.
+//  2. Otherwise, if a Timeout is set on the Client, "maxTimeMS" is calculated from the Timeout
+//     and MaxTime is ignored, unless IgnoreClientTimeout is set to true.
+//  3. Otherwise, MaxTime is sent.
+//
+// The default value is nil, which means that the server does not limit the execution time.
+func (f *FindOptionsBuilder) SetMaxTime(d time.Duration) *FindOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOptions) error {
+		opts.MaxTime = &d
+		return nil
+	})
+	return f
+}
+
 // SetMin sets the value for the Min field. Min is a document specifying the inclusive lower bound
 // for a specific index. The default value is 0, which means that there is no minimum value.
 func (f *FindOptionsBuilder) SetMin(min interface{}) *FindOptionsBuilder {
@@ -277,7 +324,10 @@ type FindOneOptions struct {
 	Collation           *Collation
 	Comment             interface{}
 	Hint                interface{}
+	IgnoreClientTimeout *bool
+	LocalThreshold      *time.Duration
 	Max                 interface{}
+	MaxTime             *time.Duration
 	Min                 interface{}
 	OplogReplay         *bool
 	Projection          interface{}
@@ -348,6 +398,31 @@ func (f *FindOneOptionsBuilder) SetHint(hint interface{}) *FindOneOptionsBuilder
 	return f
 }
 
+// SetIgnoreClientTimeout sets the value for the IgnoreClientTimeout field. If true, the Timeout
+// set on the Client is not applied to the FindOne operation, so that MaxTime is honored even if it
+// is longer than the client Timeout. A deadline on the operation Context is still honored and
+// takes precedence over MaxTime. The default value is false.
+func (f *FindOneOptionsBuilder) SetIgnoreClientTimeout(b bool) *FindOneOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneOptions) error {
+		opts.IgnoreClientTimeout = &b
+		return nil
+	})
+	return f
+}
+
+// SetLocalThreshold sets the value for the LocalThreshold field. LocalThreshold overrides the latency
+// window used when selecting a server for the FindOne operation, e.g. a threshold of 0 only allows the
+// suitable servers with the lowest average round trip time. It takes precedence over a threshold set
+// on the operation Context with mongo.WithLocalThreshold. The default value is nil, which means that
+// the window configured with ClientOptions.SetLocalThreshold is used.
+func (f *FindOneOptionsBuilder) SetLocalThreshold(d time.Duration) *FindOneOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneOptions) error {
+		opts.LocalThreshold = &d
+		return nil
+	})
+	return f
+}
+
 // SetMax sets the value for the Max field. Sets a document specifying the exclusive upper bound
 // for a specific index. The default value is nil, which means that there is no maximum value.
 func (f *FindOneOptionsBuilder) SetMax(max interface{}) *FindOneOptionsBuilder {
@@ -358,6 +433,25 @@ func (f *FindOneOptionsBuilder) SetMax(max interface{}) *FindOneOptionsBuilder {
 	return f
 }
 
+// SetMaxTime sets the value for the MaxTime field. MaxTime is the maximum amount of time that the
+// server may spend executing the FindOne operation, sent as the "maxTimeMS" command option. The
+// precedence of the time limits that apply to the operation is:
+//
+//  1. If the operation Context has a deadline, "maxTimeMS" is calculated from the deadline and
+//     MaxTime is ignored.
+//  2. Otherwise, if a Timeout is set on the Client, "maxTimeMS" is calculated from the Timeout
+//     and MaxTime is ignored, unless IgnoreClientTimeout is set to true.
+//  3. Otherwise, MaxTime is sent.
+//
+// The default value is nil, which means that the server does not limit the execution time.
+func (f *FindOneOptionsBuilder) SetMaxTime(d time.Duration) *FindOneOptionsBuilder {
+	f.Opts = append(f.Opts, func(opts *FindOneOptions) error {
+		opts.MaxTime = &d
+		return nil
+	})
+	return f
+}
+
 // SetMin sets the value for the Min field. Sets a document specifying the inclusive lower bound
 // for a specific index. The default value is 0, which means that there is no minimum value.
 func (f *FindOneOptionsBuilder) SetMin(min interface{}) *FindOneOptionsBuilder {
@@ -12,6 +12,7 @@ package options
 // See corresponding setter methods for documentation.
 type CreateIndexesOptions struct {
 	CommitQuorum interface{}
+	Verbatim     *bool
 }
 
 // CreateIndexesOptionsBuilder contains options to create indexes. Each option
@@ -119,6 +120,21 @@ func (c *CreateIndexesOptionsBuilder) SetCommitQuorumVotingMembers() *CreateInde
 	return c
 }
 
+// SetVerbatim sets the value for the Verbatim field. If true, each IndexModel is sent to
+// the server exactly as specified: the driver does not generate an index name from the
+// keys when the model does not specify one, and does not set the generated name on the
+// model's options. The server then applies its own defaults, which allows index
+// specifications to match existing indexes exactly. The default value is false.
+func (c *CreateIndexesOptionsBuilder) SetVerbatim(b bool) *CreateIndexesOptionsBuilder {
+	c.Opts = append(c.Opts, func(opts *CreateIndexesOptions) error {
+		opts.Verbatim = &b
+
+		return nil
+	})
+
+	return c
+}
+
 // DropIndexesOptions represents arguments that can be used to configure
 // IndexView.DropOne and IndexView.DropAll operations.
 type DropIndexesOptions struct{}
@@ -36,6 +36,8 @@ func ServerAPI(serverAPIVersion ServerAPIVersion) *ServerAPIOptions {
 }
 
 // SetStrict specifies whether the server should return errors for features that are not part of the API version.
+// When strict is true, the driver also rejects commands that use certain deprecated options known not to be part of
+// the API version before sending them to the server.
 func (s *ServerAPIOptions) SetStrict(strict bool) *ServerAPIOptions {
 	s.Strict = &strict
 
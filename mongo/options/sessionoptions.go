@@ -16,6 +16,7 @@ type SessionOptions struct {
 	CausalConsistency         *bool
 	DefaultTransactionOptions *TransactionOptionsBuilder
 	Snapshot                  *bool
+	ServerAffinity            *bool
 }
 
 // SessionOptionsBuilder represents functional options that configure a Sessionopts.
@@ -69,3 +70,18 @@ func (s *SessionOptionsBuilder) SetSnapshot(b bool) *SessionOptionsBuilder {
 	})
 	return s
 }
+
+// SetServerAffinity sets the value for the ServerAffinity field. If true, server selection for
+// operations in the session prefers the server used by the previous operation, as long as it
+// still satisfies the operation's read preference and is within the latency window. Reading
+// from the same secondary reduces how long causally consistent reads wait for a secondary to
+// catch up to the session's operation time. The tradeoff is that load is no longer spread
+// evenly across eligible servers for the lifetime of the session, so long-lived sessions can
+// concentrate reads on a single server. The default value is false.
+func (s *SessionOptionsBuilder) SetServerAffinity(b bool) *SessionOptionsBuilder {
+	s.Opts = append(s.Opts, func(opts *SessionOptions) error {
+		opts.ServerAffinity = &b
+		return nil
+	})
+	return s
+}
@@ -0,0 +1,81 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"errors"
+	"fmt"
+
+	"gitee.com/Trisia/gotlcp/tlcp"
+	"github.com/emmansun/gmsm/smx509"
+)
+
+// DefaultTLCPCipherSuites are the cipher suites set on the tlcp.Config returned by TLCPConfigFromPEM, in order of
+// preference.
+var DefaultTLCPCipherSuites = []uint16{
+	tlcp.ECDHE_SM4_GCM_SM3,
+	tlcp.ECDHE_SM4_CBC_SM3,
+}
+
+// TLCPConfigFromPEM builds a tlcp.Config from PEM-encoded material held in memory, e.g. fetched from a secrets
+// manager, for use with SetTLCPConfig.
+//
+// caPEM contains one or more CA certificates used to verify the server. If it is empty, the system roots are used.
+// authCertPEM and authKeyPEM are the client's signing certificate and private key, and encCertPEM and encKeyPEM are
+// the client's encryption certificate and private key. The client certificates are only needed if the server
+// requires client authentication, in which case both pairs must be provided; a pair is omitted by passing nil for
+// both the certificate and the key. The CipherSuites of the returned config are set to DefaultTLCPCipherSuites.
+func TLCPConfigFromPEM(caPEM, authCertPEM, authKeyPEM, encCertPEM, encKeyPEM []byte) (*tlcp.Config, error) {
+	cfg := &tlcp.Config{
+		CipherSuites: append([]uint16(nil), DefaultTLCPCipherSuites...),
+	}
+
+	if len(caPEM) > 0 {
+		pool := smx509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("the CA PEM does not contain any valid certificates")
+		}
+		cfg.RootCAs = pool
+	}
+
+	authCert, hasAuth, err := tlcpKeyPairFromPEM("authentication", authCertPEM, authKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	encCert, hasEnc, err := tlcpKeyPairFromPEM("encryption", encCertPEM, encKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case hasAuth && hasEnc:
+		cfg.Certificates = []tlcp.Certificate{authCert, encCert}
+	case hasAuth:
+		return nil, errors.New("an authentication certificate was provided without an encryption certificate")
+	case hasEnc:
+		return nil, errors.New("an encryption certificate was provided without an authentication certificate")
+	}
+
+	return cfg, nil
+}
+
+// tlcpKeyPairFromPEM parses a certificate and private key pair. It returns false if neither is provided.
+func tlcpKeyPairFromPEM(kind string, certPEM, keyPEM []byte) (tlcp.Certificate, bool, error) {
+	switch {
+	case len(certPEM) == 0 && len(keyPEM) == 0:
+		return tlcp.Certificate{}, false, nil
+	case len(certPEM) == 0:
+		return tlcp.Certificate{}, false, fmt.Errorf("the %s key was provided without a certificate", kind)
+	case len(keyPEM) == 0:
+		return tlcp.Certificate{}, false, fmt.Errorf("the %s certificate was provided without a key", kind)
+	}
+
+	cert, err := tlcp.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tlcp.Certificate{}, false, fmt.Errorf("error parsing the %s certificate and key: %w", kind, err)
+	}
+	return cert, true, nil
+}
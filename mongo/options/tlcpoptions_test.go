@@ -0,0 +1,154 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"gitee.com/Trisia/gotlcp/tlcp"
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/smx509"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+// sm2CertPEM creates an SM2 key and a certificate for it, signed by the parent
+// certificate and key or self-signed if parent is nil, and returns them PEM
+// encoded along with the parsed certificate and key.
+func sm2CertPEM(
+	t *testing.T,
+	template *x509.Certificate,
+	parent *x509.Certificate,
+	parentKey *sm2.PrivateKey,
+) ([]byte, []byte, *x509.Certificate, *sm2.PrivateKey) {
+	t.Helper()
+
+	key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err, "error generating SM2 key")
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := smx509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err, "error creating certificate")
+	cert, err := smx509.ParseCertificate(der)
+	require.NoError(t, err, "error parsing certificate")
+
+	keyDER, err := smx509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err, "error marshaling SM2 key")
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, cert.ToX509(), key
+}
+
+func TestTLCPConfigFromPEM(t *testing.T) {
+	t.Parallel()
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(time.Hour)
+
+	caPEM, _, caCert, caKey := sm2CertPEM(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	authCertPEM, authKeyPEM, _, _ := sm2CertPEM(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "client sign"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}, caCert, caKey)
+	encCertPEM, encKeyPEM, _, _ := sm2CertPEM(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "client enc"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment,
+	}, caCert, caKey)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := TLCPConfigFromPEM(caPEM, authCertPEM, authKeyPEM, encCertPEM, encKeyPEM)
+		require.NoError(t, err, "TLCPConfigFromPEM error")
+
+		assert.Equal(t, []uint16{tlcp.ECDHE_SM4_GCM_SM3, tlcp.ECDHE_SM4_CBC_SM3}, cfg.CipherSuites,
+			"unexpected cipher suites")
+		require.NotNil(t, cfg.RootCAs, "expected RootCAs to be set")
+		assert.Len(t, cfg.Certificates, 2, "expected the authentication and encryption certificates")
+	})
+	t.Run("CA only", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := TLCPConfigFromPEM(caPEM, nil, nil, nil, nil)
+		require.NoError(t, err, "TLCPConfigFromPEM error")
+
+		assert.NotNil(t, cfg.RootCAs, "expected RootCAs to be set")
+		assert.Len(t, cfg.Certificates, 0, "expected no client certificates")
+	})
+
+	malformed := []byte("-----BEGIN CERTIFICATE-----\nbm90IGEgY2VydGlmaWNhdGU=\n-----END CERTIFICATE-----\n")
+	testCases := []struct {
+		name    string
+		pems    [5][]byte
+		wantErr string
+	}{
+		{
+			name:    "malformed CA",
+			pems:    [5][]byte{malformed, authCertPEM, authKeyPEM, encCertPEM, encKeyPEM},
+			wantErr: "the CA PEM does not contain any valid certificates",
+		},
+		{
+			name:    "malformed authentication certificate",
+			pems:    [5][]byte{caPEM, malformed, authKeyPEM, encCertPEM, encKeyPEM},
+			wantErr: "error parsing the authentication certificate and key",
+		},
+		{
+			name:    "mismatched encryption key",
+			pems:    [5][]byte{caPEM, authCertPEM, authKeyPEM, encCertPEM, authKeyPEM},
+			wantErr: "error parsing the encryption certificate and key",
+		},
+		{
+			name:    "authentication certificate without key",
+			pems:    [5][]byte{caPEM, authCertPEM, nil, encCertPEM, encKeyPEM},
+			wantErr: "the authentication certificate was provided without a key",
+		},
+		{
+			name:    "encryption key without certificate",
+			pems:    [5][]byte{caPEM, authCertPEM, authKeyPEM, nil, encKeyPEM},
+			wantErr: "the encryption key was provided without a certificate",
+		},
+		{
+			name:    "authentication certificate only",
+			pems:    [5][]byte{caPEM, authCertPEM, authKeyPEM, nil, nil},
+			wantErr: "an authentication certificate was provided without an encryption certificate",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture the range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := TLCPConfigFromPEM(tc.pems[0], tc.pems[1], tc.pems[2], tc.pems[3], tc.pems[4])
+			require.Error(t, err, "expected TLCPConfigFromPEM to fail")
+			assert.Contains(t, err.Error(), tc.wantErr, "unexpected error")
+		})
+	}
+}
@@ -90,6 +90,30 @@ type BulkWriteResult struct {
 	Acknowledged bool
 }
 
+// BulkWriteBatchResult is the result of a single batch of a BulkWriteStream
+// operation. It is passed to the callback as soon as the server acknowledges
+// the batch.
+type BulkWriteBatchResult struct {
+	// The indexes of the write models in the batch, in the order in which they
+	// were sent to the server. Each index refers to the models slice passed to
+	// BulkWriteStream. For unordered writes, models that are not referenced by
+	// an entry in WriteErrors succeeded. For ordered writes, the server stops at
+	// the first write error, so the models after it were not executed.
+	Indexes []int
+
+	// The counts and upserted _id values for the batch. Keys of
+	// Result.UpsertedIDs are indexes in the models slice.
+	Result BulkWriteResult
+
+	// The write errors that occurred in the batch. The Index of each error
+	// refers to the models slice.
+	WriteErrors []BulkWriteError
+
+	// The write concern error that occurred in the batch, or nil if there was
+	// none.
+	WriteConcernError *WriteConcernError
+}
+
 // InsertOneResult is the result type returned by an InsertOne operation.
 type InsertOneResult struct {
 	// The _id of the inserted document. A value generated by the driver will be of type bson.ObjectID.
@@ -0,0 +1,30 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driver
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type afterClusterTimeContextKey struct{}
+
+// WithAfterClusterTime returns a context that sets the read concern
+// afterClusterTime sent with commands for operations run with it. It is only
+// sent with commands that include a read concern and is ignored for snapshot
+// sessions. If the operation runs in a causally consistent session, the later
+// of ts and the session's operation time is sent.
+func WithAfterClusterTime(ctx context.Context, ts bson.Timestamp) context.Context {
+	return context.WithValue(ctx, afterClusterTimeContextKey{}, ts)
+}
+
+// afterClusterTimeFromContext returns the afterClusterTime set on the context.
+func afterClusterTimeFromContext(ctx context.Context) (bson.Timestamp, bool) {
+	ts, ok := ctx.Value(afterClusterTimeContextKey{}).(bson.Timestamp)
+	return ts, ok
+}
@@ -53,12 +53,24 @@ func RegisterAuthenticatorFactory(name string, factory AuthenticatorFactory) {
 	authFactories[name] = factory
 }
 
+// CredentialProvider returns the authentication mechanism and credential to use
+// for a single authentication attempt.
+type CredentialProvider func(ctx context.Context) (mechanism string, cred *Cred, err error)
+
 // HandshakeOptions packages options that can be passed to the Handshaker()
 // function.  DBUser is optional but must be of the form <dbname.username>;
 // if non-empty, then the connection will do SASL mechanism negotiation.
+//
+// If CredentialProvider is set, it is called at the start of every handshake
+// and the authenticator created from its result is used in place of
+// Authenticator and DBUser for that connection. HTTPClient is passed to the
+// authenticator factory in that case and when the credential is replaced by a
+// credential injector during connection establishment.
 type HandshakeOptions struct {
 	AppName               string
 	Authenticator         Authenticator
+	CredentialProvider    CredentialProvider
+	HTTPClient            *http.Client
 	Compressors           []string
 	DBUser                string
 	PerformAuthentication func(description.Server) bool
@@ -66,6 +78,11 @@ type HandshakeOptions struct {
 	ServerAPI             *driver.ServerAPIOptions
 	LoadBalanced          bool
 
+	// SpeculativeAuthentication controls whether the initial handshake includes a speculativeAuthenticate
+	// document. If nil, speculative authentication is attempted whenever the authenticator supports it. If
+	// false, it is never attempted. If true, the handshake fails if the authenticator does not support it.
+	SpeculativeAuthentication *bool
+
 	// Fields provided by a library that wraps the Go Driver.
 	OuterLibraryName     string
 	OuterLibraryVersion  string
@@ -76,6 +93,7 @@ type authHandshaker struct {
 	wrapped driver.Handshaker
 	options *HandshakeOptions
 
+	authenticator Authenticator
 	handshakeInfo driver.HandshakeInformation
 	conversation  SpeculativeConversation
 }
@@ -89,6 +107,15 @@ func (ah *authHandshaker) GetHandshakeInformation(
 	addr address.Address,
 	conn *mnet.Connection,
 ) (driver.HandshakeInformation, error) {
+	dbUser := ah.options.DBUser
+	if ah.options.CredentialProvider != nil {
+		var err error
+		ah.authenticator, dbUser, err = ah.provideAuthenticator(ctx)
+		if err != nil {
+			return driver.HandshakeInformation{}, err
+		}
+	}
+
 	if ah.wrapped != nil {
 		return ah.wrapped.GetHandshakeInformation(ctx, addr, conn)
 	}
@@ -96,7 +123,7 @@ func (ah *authHandshaker) GetHandshakeInformation(
 	op := operation.NewHello().
 		AppName(ah.options.AppName).
 		Compressors(ah.options.Compressors).
-		SASLSupportedMechs(ah.options.DBUser).
+		SASLSupportedMechs(dbUser).
 		ClusterClock(ah.options.ClusterClock).
 		ServerAPI(ah.options.ServerAPI).
 		LoadBalanced(ah.options.LoadBalanced).
@@ -104,8 +131,13 @@ func (ah *authHandshaker) GetHandshakeInformation(
 		OuterLibraryVersion(ah.options.OuterLibraryVersion).
 		OuterLibraryPlatform(ah.options.OuterLibraryPlatform)
 
-	if ah.options.Authenticator != nil {
-		if speculativeAuth, ok := ah.options.Authenticator.(SpeculativeAuthenticator); ok {
+	speculative := ah.options.SpeculativeAuthentication
+	if ah.authenticator != nil && (speculative == nil || *speculative) {
+		speculativeAuth, ok := ah.authenticator.(SpeculativeAuthenticator)
+		if !ok && speculative != nil {
+			return driver.HandshakeInformation{}, newAuthError("speculative authentication is not supported by the authentication mechanism", nil)
+		}
+		if ok {
 			var err error
 			ah.conversation, err = speculativeAuth.CreateSpeculativeConversation()
 			if err != nil {
@@ -134,6 +166,35 @@ func (ah *authHandshaker) GetHandshakeInformation(
 	return ah.handshakeInfo, nil
 }
 
+// provideAuthenticator fetches a credential from the configured
+// CredentialProvider and creates the authenticator and SASL negotiation user
+// to use for the current handshake.
+func (ah *authHandshaker) provideAuthenticator(ctx context.Context) (Authenticator, string, error) {
+	mechanism, cred, err := ah.options.CredentialProvider(ctx)
+	if err != nil {
+		return nil, "", newAuthError("failed to get credential from provider", err)
+	}
+	if cred == nil {
+		return nil, "", newAuthError("credential provider returned a nil credential", nil)
+	}
+
+	authenticator, err := CreateAuthenticator(mechanism, cred, ah.options.HTTPClient)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var dbUser string
+	if mechanism == "" {
+		// Required for SASL mechanism negotiation during handshake
+		source := cred.Source
+		if source == "" {
+			source = "admin"
+		}
+		dbUser = source + "." + cred.Username
+	}
+	return authenticator, dbUser, nil
+}
+
 // FinishHandshake performs authentication for conn if necessary.
 func (ah *authHandshaker) FinishHandshake(ctx context.Context, conn *mnet.Connection) error {
 	performAuth := ah.options.PerformAuthentication
@@ -144,7 +205,7 @@ func (ah *authHandshaker) FinishHandshake(ctx context.Context, conn *mnet.Connec
 		}
 	}
 
-	if performAuth(conn.Description()) && ah.options.Authenticator != nil {
+	if performAuth(conn.Description()) && ah.authenticator != nil {
 		cfg := &driver.AuthConfig{
 			Connection:    conn,
 			ClusterClock:  ah.options.ClusterClock,
@@ -163,6 +224,23 @@ func (ah *authHandshaker) FinishHandshake(ctx context.Context, conn *mnet.Connec
 	return ah.wrapped.FinishHandshake(ctx, conn)
 }
 
+// SetCredential replaces the authenticator used by FinishHandshake with one
+// created from mechanism and cred. It is called between
+// GetHandshakeInformation and FinishHandshake, so any speculative conversation
+// started in the initial handshake is abandoned and FinishHandshake
+// authenticates from scratch.
+func (ah *authHandshaker) SetCredential(mechanism string, cred *Cred) error {
+	authenticator, err := CreateAuthenticator(mechanism, cred, ah.options.HTTPClient)
+	if err != nil {
+		return err
+	}
+
+	ah.authenticator = authenticator
+	ah.conversation = nil
+	ah.handshakeInfo.SpeculativeAuthenticate = nil
+	return nil
+}
+
 func (ah *authHandshaker) authenticate(ctx context.Context, cfg *driver.AuthConfig) error {
 	// If the initial hello reply included a response to the speculative authentication attempt, we only need to
 	// conduct the remainder of the conversation.
@@ -176,14 +254,15 @@ func (ah *authHandshaker) authenticate(ctx context.Context, cfg *driver.AuthConf
 
 	// If the server does not support speculative authentication or the first attempt was not successful, we need to
 	// perform authentication from scratch.
-	return ah.options.Authenticator.Auth(ctx, cfg)
+	return ah.authenticator.Auth(ctx, cfg)
 }
 
 // Handshaker creates a connection handshaker for the given authenticator.
 func Handshaker(h driver.Handshaker, options *HandshakeOptions) driver.Handshaker {
 	return &authHandshaker{
-		wrapped: h,
-		options: options,
+		wrapped:       h,
+		options:       options,
+		authenticator: options.Authenticator,
 	}
 }
 
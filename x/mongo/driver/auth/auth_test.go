@@ -160,3 +160,79 @@ func TestPerformAuthentication(t *testing.T) {
 		})
 	}
 }
+
+func TestHandshakerCredentialProvider(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	provider := func(context.Context) (string, *auth.Cred, error) {
+		calls++
+		return auth.PLAIN, &auth.Cred{
+			Username: fmt.Sprintf("user%d", calls),
+			Password: fmt.Sprintf("pencil%d", calls),
+		}, nil
+	}
+	handshaker := func() driver.Handshaker {
+		return auth.Handshaker(nil, &auth.HandshakeOptions{
+			CredentialProvider: provider,
+			HTTPClient:         &http.Client{},
+		})
+	}
+
+	hello := bsoncore.BuildDocumentFromElements(nil,
+		bsoncore.AppendInt32Element(nil, "ok", 1),
+		bsoncore.AppendBooleanElement(nil, "isWritablePrimary", true),
+		bsoncore.AppendInt32Element(nil, "maxWireVersion", 6),
+	)
+	saslReply := bsoncore.BuildDocumentFromElements(nil,
+		bsoncore.AppendInt32Element(nil, "ok", 1),
+		bsoncore.AppendInt32Element(nil, "conversationId", 1),
+		bsoncore.AppendBinaryElement(nil, "payload", 0x00, []byte{}),
+		bsoncore.AppendBooleanElement(nil, "done", true),
+	)
+
+	// Each new connection should authenticate with the credential returned by
+	// the provider at the time of its handshake.
+	for i := 1; i <= 2; i++ {
+		resps := make(chan []byte, 2)
+		writeReplies(resps, hello, saslReply)
+
+		c := &drivertest.ChannelConn{
+			Written:  make(chan []byte, 2),
+			ReadResp: resps,
+		}
+		mnetconn := mnet.NewConnection(c)
+
+		h := handshaker()
+		info, err := h.GetHandshakeInformation(context.Background(), "localhost:27017", mnetconn)
+		require.NoError(t, err)
+		c.Desc = info.Description
+
+		err = h.FinishHandshake(context.Background(), mnetconn)
+		require.NoError(t, err)
+		require.Len(t, c.Written, 2)
+
+		<-c.Written // hello
+		saslStart, err := drivertest.GetCommandFromMsgWireMessage(<-c.Written)
+		require.NoError(t, err)
+
+		_, payload := saslStart.Lookup("payload").Binary()
+		want := fmt.Sprintf("\x00user%d\x00pencil%d", i, i)
+		require.Equal(t, want, string(payload))
+	}
+	require.Equal(t, 2, calls)
+}
+
+func TestHandshakerCredentialProviderError(t *testing.T) {
+	t.Parallel()
+
+	handshaker := auth.Handshaker(nil, &auth.HandshakeOptions{
+		CredentialProvider: func(context.Context) (string, *auth.Cred, error) {
+			return "", nil, fmt.Errorf("vault unavailable")
+		},
+	})
+
+	mnetconn := mnet.NewConnection(&drivertest.ChannelConn{})
+	_, err := handshaker.GetHandshakeInformation(context.Background(), "localhost:27017", mnetconn)
+	require.EqualError(t, err, "failed to get credential from provider: vault unavailable")
+}
@@ -10,6 +10,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 )
@@ -28,6 +30,7 @@ func newDefaultAuthenticator(cred *Cred, httpClient *http.Client) (Authenticator
 	return &DefaultAuthenticator{
 		Cred:                     cred,
 		speculativeAuthenticator: speculative,
+		scramSHA256:              scram,
 		httpClient:               httpClient,
 	}, nil
 }
@@ -38,9 +41,23 @@ type DefaultAuthenticator struct {
 	Cred *Cred
 
 	// The authenticator to use for speculative authentication. Because the correct auth mechanism is unknown when doing
-	// the initial hello, SCRAM-SHA-256 is used for the speculative attempt.
+	// the initial hello, SCRAM-SHA-256 is used for the speculative attempt unless a previous handshake negotiated
+	// SCRAM-SHA-1.
 	speculativeAuthenticator SpeculativeAuthenticator
 
+	// negotiated holds the mechanism chosen from the saslSupportedMechs the server advertised for the user in the most
+	// recent handshake that needed a full authentication. It is used for the speculative attempts of later handshakes
+	// so that a user that only supports SCRAM-SHA-1 is not sent a SCRAM-SHA-256 attempt that is bound to fail.
+	negotiated atomic.Value // string
+
+	// The SCRAM authenticators are shared by every handshake so that the salted password derived from the credential
+	// is computed once per server salt and iteration count instead of once per connection. scramSHA1 is created by
+	// the first handshake that needs it.
+	scramSHA256   Authenticator
+	scramSHA1Once sync.Once
+	scramSHA1     Authenticator
+	scramSHA1Err  error
+
 	httpClient *http.Client
 }
 
@@ -48,19 +65,38 @@ var _ SpeculativeAuthenticator = (*DefaultAuthenticator)(nil)
 
 // CreateSpeculativeConversation creates a speculative conversation for SCRAM authentication.
 func (a *DefaultAuthenticator) CreateSpeculativeConversation() (SpeculativeConversation, error) {
+	if mech, _ := a.negotiated.Load().(string); mech == SCRAMSHA1 {
+		scram, err := a.getScramSHA1Authenticator()
+		if err != nil {
+			return nil, err
+		}
+		return scram.(SpeculativeAuthenticator).CreateSpeculativeConversation()
+	}
 	return a.speculativeAuthenticator.CreateSpeculativeConversation()
 }
 
+func (a *DefaultAuthenticator) getScramSHA1Authenticator() (Authenticator, error) {
+	a.scramSHA1Once.Do(func() {
+		a.scramSHA1, a.scramSHA1Err = newScramSHA1Authenticator(a.Cred, a.httpClient)
+	})
+	return a.scramSHA1, a.scramSHA1Err
+}
+
 // Auth authenticates the connection.
 func (a *DefaultAuthenticator) Auth(ctx context.Context, cfg *driver.AuthConfig) error {
 	var actual Authenticator
 	var err error
 
-	switch chooseAuthMechanism(cfg) {
+	mech := chooseAuthMechanism(cfg)
+	if cfg.HandshakeInfo.SaslSupportedMechs != nil {
+		a.negotiated.Store(mech)
+	}
+
+	switch mech {
 	case SCRAMSHA256:
-		actual, err = newScramSHA256Authenticator(a.Cred, a.httpClient)
+		actual = a.scramSHA256
 	case SCRAMSHA1:
-		actual, err = newScramSHA1Authenticator(a.Cred, a.httpClient)
+		actual, err = a.getScramSHA1Authenticator()
 	default:
 		actual, err = newMongoDBCRAuthenticator(a.Cred, a.httpClient)
 	}
@@ -77,14 +113,24 @@ func (a *DefaultAuthenticator) Reauth(_ context.Context, _ *driver.AuthConfig) e
 	return newAuthError("DefaultAuthenticator does not support reauthentication", nil)
 }
 
+// defaultMechanisms are the mechanisms that can be negotiated when no mechanism is specified, from strongest to
+// weakest.
+var defaultMechanisms = []string{SCRAMSHA256, SCRAMSHA1}
+
 // If a server provides a list of supported mechanisms, we choose
 // SCRAM-SHA-256 if it exists or else MUST use SCRAM-SHA-1.
 // Otherwise, we decide based on what is supported.
 func chooseAuthMechanism(cfg *driver.AuthConfig) string {
-	if saslSupportedMechs := cfg.HandshakeInfo.SaslSupportedMechs; saslSupportedMechs != nil {
+	return strongestMechanism(cfg.HandshakeInfo.SaslSupportedMechs)
+}
+
+// strongestMechanism returns the strongest of defaultMechanisms that is included in saslSupportedMechs. If none of
+// them is included, SCRAM-SHA-1 is returned.
+func strongestMechanism(saslSupportedMechs []string) string {
+	for _, mech := range defaultMechanisms {
 		for _, v := range saslSupportedMechs {
-			if v == SCRAMSHA256 {
-				return v
+			if v == mech {
+				return mech
 			}
 		}
 	}
@@ -0,0 +1,194 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
+)
+
+// helloWithMechs returns a hello response that advertises mechs as the SASL
+// mechanisms supported for the user.
+func helloWithMechs(mechs ...string) bsoncore.Document {
+	arr := bsoncore.NewArrayBuilder()
+	for _, mech := range mechs {
+		arr.AppendString(mech)
+	}
+	elems := append(handshakeHelloElements[:len(handshakeHelloElements):len(handshakeHelloElements)],
+		bsoncore.AppendArrayElement(nil, "saslSupportedMechs", arr.Build()))
+	return bsoncore.BuildDocumentFromElements(nil, elems...)
+}
+
+func TestChooseAuthMechanism(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		hello bsoncore.Document
+		want  string
+	}{
+		{
+			name:  "both SCRAM mechanisms",
+			hello: helloWithMechs(SCRAMSHA1, SCRAMSHA256),
+			want:  SCRAMSHA256,
+		},
+		{
+			name:  "strongest mechanism listed last",
+			hello: helloWithMechs(PLAIN, SCRAMSHA1, SCRAMSHA256),
+			want:  SCRAMSHA256,
+		},
+		{
+			name:  "only SCRAM-SHA-1",
+			hello: helloWithMechs(SCRAMSHA1),
+			want:  SCRAMSHA1,
+		},
+		{
+			name:  "no SCRAM mechanisms",
+			hello: helloWithMechs(PLAIN),
+			want:  SCRAMSHA1,
+		},
+		{
+			name:  "saslSupportedMechs not advertised",
+			hello: bsoncore.BuildDocumentFromElements(nil, handshakeHelloElements...),
+			want:  SCRAMSHA1,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture the range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			responses := make(chan []byte, 1)
+			writeReplies(responses, tc.hello)
+			conn := &drivertest.ChannelConn{
+				Written:  make(chan []byte, 1),
+				ReadResp: responses,
+			}
+
+			handshaker := Handshaker(nil, &HandshakeOptions{DBUser: "admin.user"})
+			info, err := handshaker.GetHandshakeInformation(
+				context.Background(),
+				address.Address("localhost:27017"),
+				mnet.NewConnection(conn))
+			require.NoError(t, err, "GetHandshakeInformation error")
+
+			// The driver must ask for the mechanisms supported for the user.
+			helloCmd, err := drivertest.GetCommandFromQueryWireMessage(<-conn.Written)
+			require.NoError(t, err, "error parsing hello command")
+			user, err := helloCmd.LookupErr("saslSupportedMechs")
+			require.NoError(t, err, "expected hello to contain saslSupportedMechs")
+			assert.Equal(t, "admin.user", user.StringValue())
+
+			got := chooseAuthMechanism(&driver.AuthConfig{HandshakeInfo: info})
+			assert.Equal(t, tc.want, got, "unexpected mechanism")
+		})
+	}
+}
+
+func TestDefaultAuthenticator_negotiation(t *testing.T) {
+	t.Parallel()
+
+	cred := &Cred{
+		Username:    "user",
+		Password:    "pencil",
+		PasswordSet: true,
+		Source:      "admin",
+	}
+	saslStartErr := bsoncore.BuildDocumentFromElements(nil,
+		bsoncore.AppendInt32Element(nil, "ok", 0),
+		bsoncore.AppendStringElement(nil, "errmsg", "authentication failed"),
+	)
+
+	// handshake runs both parts of a handshake against a server that replies to
+	// hello with the given document and fails the saslStart, returning the hello
+	// and saslStart commands the driver sent.
+	handshake := func(t *testing.T, authenticator Authenticator, hello bsoncore.Document) (bsoncore.Document, bsoncore.Document) {
+		t.Helper()
+
+		responses := make(chan []byte, 2)
+		writeReplies(responses, hello, saslStartErr)
+		conn := &drivertest.ChannelConn{
+			Written:  make(chan []byte, 2),
+			ReadResp: responses,
+		}
+		mnetconn := mnet.NewConnection(conn)
+
+		handshaker := Handshaker(nil, &HandshakeOptions{
+			Authenticator: authenticator,
+			DBUser:        "admin.user",
+		})
+		info, err := handshaker.GetHandshakeInformation(context.Background(), address.Address("localhost:27017"), mnetconn)
+		require.NoError(t, err, "GetHandshakeInformation error")
+		conn.Desc = info.Description
+
+		err = handshaker.FinishHandshake(context.Background(), mnetconn)
+		require.Error(t, err, "expected FinishHandshake to return the saslStart error")
+
+		helloCmd, err := drivertest.GetCommandFromQueryWireMessage(<-conn.Written)
+		require.NoError(t, err, "error parsing hello command")
+		saslStartCmd, err := drivertest.GetCommandFromMsgWireMessage(<-conn.Written)
+		require.NoError(t, err, "error parsing saslStart command")
+		return helloCmd, saslStartCmd
+	}
+
+	mechanism := func(t *testing.T, doc bsoncore.Document) string {
+		t.Helper()
+
+		val, err := doc.LookupErr("mechanism")
+		require.NoError(t, err, "expected %s to contain a mechanism", doc)
+		return val.StringValue()
+	}
+
+	testCases := []struct {
+		name  string
+		hello bsoncore.Document
+		want  string
+	}{
+		{
+			name:  "both SCRAM mechanisms",
+			hello: helloWithMechs(SCRAMSHA1, SCRAMSHA256),
+			want:  SCRAMSHA256,
+		},
+		{
+			name:  "only SCRAM-SHA-1",
+			hello: helloWithMechs(SCRAMSHA1),
+			want:  SCRAMSHA1,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture the range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			authenticator, err := CreateAuthenticator("", cred, &http.Client{})
+			require.NoError(t, err, "CreateAuthenticator error")
+
+			// The first handshake speculates with SCRAM-SHA-256 and then
+			// authenticates with the mechanism the server advertised.
+			helloCmd, saslStartCmd := handshake(t, authenticator, tc.hello)
+			assert.Equal(t, SCRAMSHA256, mechanism(t, helloCmd.Lookup("speculativeAuthenticate").Document()),
+				"unexpected speculative mechanism in first handshake")
+			assert.Equal(t, tc.want, mechanism(t, saslStartCmd), "unexpected saslStart mechanism")
+
+			// Later handshakes speculate with the negotiated mechanism.
+			helloCmd, _ = handshake(t, authenticator, tc.hello)
+			assert.Equal(t, tc.want, mechanism(t, helloCmd.Lookup("speculativeAuthenticate").Document()),
+				"unexpected speculative mechanism in second handshake")
+		})
+	}
+}
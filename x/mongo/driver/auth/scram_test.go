@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
@@ -60,28 +61,8 @@ func TestSCRAM(t *testing.T) {
 					},
 					&http.Client{})
 				assert.Nil(t, err, "error creating authenticator: %v", err)
-				sa, _ := authenticator.(*ScramAuthenticator)
-				sa.client = sa.client.WithNonceGenerator(func() string {
-					return tc.nonce
-				})
-
-				responses := make(chan []byte, len(tc.payloads))
-				writeReplies(responses, createSCRAMConversation(tc.payloads)...)
-
-				desc := description.Server{
-					WireVersion: &description.VersionRange{
-						Max: 21,
-					},
-				}
-				chanconn := &drivertest.ChannelConn{
-					Written:  make(chan []byte, len(tc.payloads)),
-					ReadResp: responses,
-					Desc:     desc,
-				}
-
-				conn := mnet.NewConnection(chanconn)
-
-				err = authenticator.Auth(context.Background(), &driver.AuthConfig{Connection: conn})
+				setNonce(t, authenticator, tc.nonce)
+				chanconn, err := runSCRAMConversation(authenticator, tc.payloads)
 				assert.Nil(t, err, "Auth error: %v\n", err)
 
 				// Verify that the first command sent is saslStart.
@@ -101,6 +82,101 @@ func TestSCRAM(t *testing.T) {
 	})
 }
 
+// runSCRAMConversation authenticates a connection with authenticator against a
+// server that replies with payloads.
+func runSCRAMConversation(authenticator Authenticator, payloads [][]byte) (*drivertest.ChannelConn, error) {
+	responses := make(chan []byte, len(payloads))
+	writeReplies(responses, createSCRAMConversation(payloads)...)
+
+	desc := description.Server{
+		WireVersion: &description.VersionRange{
+			Max: 21,
+		},
+	}
+	chanconn := &drivertest.ChannelConn{
+		Written:  make(chan []byte, len(payloads)),
+		ReadResp: responses,
+		Desc:     desc,
+	}
+
+	conn := mnet.NewConnection(chanconn)
+	return chanconn, authenticator.Auth(context.Background(), &driver.AuthConfig{Connection: conn})
+}
+
+func TestSCRAMAuthenticatorReuse(t *testing.T) {
+	t.Parallel()
+
+	cred := &Cred{Username: "user", Password: "pencil", Source: "admin"}
+
+	t.Run("conversations reuse the salted password", func(t *testing.T) {
+		t.Parallel()
+
+		authenticator, err := newScramSHA256Authenticator(cred, &http.Client{})
+		require.NoError(t, err, "error creating authenticator")
+		setNonce(t, authenticator, scramSha256Nonce)
+
+		// The second conversation uses the salted password derived by the first
+		// one and must produce the same proof for the server to accept it.
+		for i := 0; i < 2; i++ {
+			_, err = runSCRAMConversation(authenticator, scramSha256ShortPayloads)
+			require.NoError(t, err, "Auth error in conversation %d", i)
+		}
+	})
+	t.Run("default authenticator reuses SCRAM authenticators", func(t *testing.T) {
+		t.Parallel()
+
+		authenticator, err := newDefaultAuthenticator(cred, &http.Client{})
+		require.NoError(t, err, "error creating authenticator")
+		da := authenticator.(*DefaultAuthenticator)
+
+		first, err := da.getScramSHA1Authenticator()
+		require.NoError(t, err, "error creating SCRAM-SHA-1 authenticator")
+		second, err := da.getScramSHA1Authenticator()
+		require.NoError(t, err, "error creating SCRAM-SHA-1 authenticator")
+		assert.True(t, first == second, "expected the SCRAM-SHA-1 authenticator to be reused")
+		assert.True(t, da.scramSHA256 == da.speculativeAuthenticator.(Authenticator),
+			"expected the SCRAM-SHA-256 authenticator to be the speculative authenticator")
+
+		other, err := newDefaultAuthenticator(cred, &http.Client{})
+		require.NoError(t, err, "error creating authenticator")
+		assert.True(t, other.(*DefaultAuthenticator).scramSHA256 != da.scramSHA256,
+			"expected authenticators not to be shared between clients")
+	})
+}
+
+func BenchmarkSCRAMAuthenticator(b *testing.B) {
+	cred := &Cred{Username: "user", Password: "pencil", Source: "admin"}
+	newAuthenticator := func(b *testing.B) Authenticator {
+		authenticator, err := newScramSHA256Authenticator(cred, &http.Client{})
+		if err != nil {
+			b.Fatalf("error creating authenticator: %v", err)
+		}
+		authenticator.(*ScramAuthenticator).client.WithNonceGenerator(func() string {
+			return scramSha256Nonce
+		})
+		return authenticator
+	}
+
+	b.Run("shared authenticator", func(b *testing.B) {
+		authenticator := newAuthenticator(b)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := runSCRAMConversation(authenticator, scramSha256ShortPayloads); err != nil {
+				b.Fatalf("Auth error: %v", err)
+			}
+		}
+	})
+	b.Run("authenticator per connection", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := runSCRAMConversation(newAuthenticator(b), scramSha256ShortPayloads); err != nil {
+				b.Fatalf("Auth error: %v", err)
+			}
+		}
+	})
+}
+
 func createSCRAMConversation(payloads [][]byte) []bsoncore.Document {
 	responses := make([]bsoncore.Document, len(payloads))
 	for idx, payload := range payloads {
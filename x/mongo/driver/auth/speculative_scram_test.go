@@ -201,6 +201,74 @@ func TestSpeculativeSCRAM(t *testing.T) {
 	})
 }
 
+func TestSpeculativeAuthenticationOption(t *testing.T) {
+	cred := &Cred{
+		Username:    "user",
+		Password:    "pencil",
+		PasswordSet: true,
+		Source:      "admin",
+	}
+	enabled, disabled := true, false
+
+	testCases := []struct {
+		name        string
+		speculative *bool
+		expected    bool
+	}{
+		{"default", nil, true},
+		{"enabled", &enabled, true},
+		{"disabled", &disabled, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authenticator, err := CreateAuthenticator("SCRAM-SHA-256", cred, &http.Client{})
+			assert.Nil(t, err, "CreateAuthenticator error: %v", err)
+			setNonce(t, authenticator, scramSha256Nonce)
+
+			handshaker := Handshaker(nil, &HandshakeOptions{
+				Authenticator:             authenticator,
+				DBUser:                    "admin.user",
+				SpeculativeAuthentication: tc.speculative,
+			})
+			responses := make(chan []byte, 1)
+			writeReplies(responses, bsoncore.BuildDocumentFromElements(nil, handshakeHelloElements...))
+
+			conn := &drivertest.ChannelConn{
+				Written:  make(chan []byte, 1),
+				ReadResp: responses,
+			}
+
+			_, err = handshaker.GetHandshakeInformation(context.Background(), address.Address("localhost:27017"), mnet.NewConnection(conn))
+			assert.Nil(t, err, "GetHandshakeInformation error: %v", err)
+
+			helloCmd, err := drivertest.GetCommandFromQueryWireMessage(<-conn.Written)
+			assert.Nil(t, err, "error parsing hello command: %v", err)
+			assertCommandName(t, helloCmd, handshake.LegacyHello)
+
+			_, err = helloCmd.LookupErr("speculativeAuthenticate")
+			assert.Equal(t, tc.expected, err == nil,
+				"expected 'speculativeAuthenticate' presence to be %v in command %s", tc.expected, bson.Raw(helloCmd))
+		})
+	}
+	t.Run("enabled with unsupported mechanism", func(t *testing.T) {
+		authenticator, err := CreateAuthenticator("PLAIN", cred, &http.Client{})
+		assert.Nil(t, err, "CreateAuthenticator error: %v", err)
+
+		handshaker := Handshaker(nil, &HandshakeOptions{
+			Authenticator:             authenticator,
+			SpeculativeAuthentication: &enabled,
+		})
+		conn := &drivertest.ChannelConn{
+			Written:  make(chan []byte, 1),
+			ReadResp: make(chan []byte, 1),
+		}
+
+		_, err = handshaker.GetHandshakeInformation(context.Background(), address.Address("localhost:27017"), mnet.NewConnection(conn))
+		assert.NotNil(t, err, "expected GetHandshakeInformation error, got nil")
+		assert.Equal(t, 0, len(conn.Written), "expected no wire messages to be sent, got %d", len(conn.Written))
+	})
+}
+
 func setNonce(t *testing.T, authenticator Authenticator, nonce string) {
 	t.Helper()
 	nonceGenerator := func() string {
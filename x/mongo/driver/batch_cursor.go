@@ -56,6 +56,12 @@ type BatchCursor struct {
 	// is set, it will be used as the "maxTimeMS" field on getMore commands.
 	maxAwaitTime *time.Duration
 
+	// targetBatchBytes, if positive, is used with the average size of the documents returned so
+	// far to calculate the batchSize for getMore commands.
+	targetBatchBytes int32
+	observedBytes    int64
+	observedDocs     int64
+
 	// legacy server (< 3.2) fields
 	limit       int32
 	numReturned int32 // number of docs returned by server
@@ -170,6 +176,7 @@ type CursorOptions struct {
 	Crypt                 Crypt
 	ServerAPI             *ServerAPIOptions
 	MarshalValueEncoderFn func(io.Writer) *bson.Encoder
+	TargetBatchBytes      int32
 
 	// MaxAwaitTime is only valid for tailable awaitData cursors. If this option
 	// is set, it will be used as the "maxTimeMS" field on getMore commands.
@@ -210,10 +217,14 @@ func NewBatchCursor(
 		serverAPI:            opts.ServerAPI,
 		serverDescription:    cr.Desc,
 		encoderFn:            opts.MarshalValueEncoderFn,
+		targetBatchBytes:     opts.TargetBatchBytes,
 	}
 
 	if firstBatch != nil {
 		bc.numReturned = int32(firstBatch.Count())
+		if bc.targetBatchBytes > 0 {
+			bc.observeBatch(firstBatch.List)
+		}
 	}
 
 	bc.currentBatch = firstBatch
@@ -346,15 +357,49 @@ func (bc *BatchCursor) KillCursor(ctx context.Context) error {
 	}.Execute(ctx)
 }
 
+// observeBatch records the number and total size of the documents in batch so
+// the average document size can be used to calculate future batch sizes. The
+// elements are read in place so that observing a batch does not allocate.
+func (bc *BatchCursor) observeBatch(batch bsoncore.Array) {
+	length, rem, ok := bsoncore.ReadLength(batch)
+	if !ok || length < 5 || int(length) > len(batch) {
+		return
+	}
+	rem = rem[:length-5] // Exclude the length and the trailing null byte.
+
+	for len(rem) > 0 {
+		var elem bsoncore.Element
+		elem, rem, ok = bsoncore.ReadElement(rem)
+		if !ok {
+			return
+		}
+		bc.observedBytes += int64(len(elem.Value().Data))
+		bc.observedDocs++
+	}
+}
+
 // calcGetMoreBatchSize calculates the number of documents to return in the
 // response of a "getMore" operation based on the given limit, batchSize, and
-// number of documents already returned. Returns false if a non-trivial limit is
-// lower than or equal to the number of documents already returned.
+// number of documents already returned. If a target batch size in bytes is
+// set and documents have been observed, the batchSize is derived from the
+// average observed document size instead. Returns false if a non-trivial limit
+// is lower than or equal to the number of documents already returned.
 func calcGetMoreBatchSize(bc BatchCursor) (int32, bool) {
 	gmBatchSize := bc.batchSize
 
+	if bc.targetBatchBytes > 0 && bc.observedDocs > 0 {
+		avgDocSize := bc.observedBytes / bc.observedDocs
+		if avgDocSize < 1 {
+			avgDocSize = 1
+		}
+		gmBatchSize = int32(int64(bc.targetBatchBytes) / avgDocSize)
+		if gmBatchSize < 1 {
+			gmBatchSize = 1
+		}
+	}
+
 	// Account for legacy operations that don't support setting a limit.
-	if bc.limit != 0 && bc.numReturned+bc.batchSize >= bc.limit {
+	if bc.limit != 0 && bc.numReturned+gmBatchSize >= bc.limit {
 		gmBatchSize = bc.limit - bc.numReturned
 		if gmBatchSize <= 0 {
 			return gmBatchSize, false
@@ -448,6 +493,9 @@ func (bc *BatchCursor) getMore(ctx context.Context) {
 
 			// Required for legacy operations which don't support limit.
 			bc.numReturned += int32(bc.currentBatch.Count())
+			if bc.targetBatchBytes > 0 {
+				bc.observeBatch(batch)
+			}
 
 			pbrt, err := response.LookupErr("cursor", "postBatchResumeToken")
 			if err != nil {
@@ -520,6 +568,14 @@ func (bc *BatchCursor) SetBatchSize(size int32) {
 	bc.batchSize = size
 }
 
+// SetTargetBatchBytes sets the approximate number of bytes to fetch with future
+// getMore operations. If size is positive, the batchSize sent with each getMore
+// is calculated from the average size of the documents returned so far and
+// takes precedence over the value set with SetBatchSize.
+func (bc *BatchCursor) SetTargetBatchBytes(size int32) {
+	bc.targetBatchBytes = size
+}
+
 // SetMaxAwaitTime will set the maximum amount of time the server will allow the
 // operations to execute. The server will error if this field is set but the
 // cursor is not configured with awaitData=true.
@@ -7,9 +7,12 @@
 package driver
 
 import (
+	"strings"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 )
 
 func TestBatchCursor(t *testing.T) {
@@ -89,4 +92,56 @@ func TestBatchCursor(t *testing.T) {
 			})
 		}
 	})
+	t.Run("calcGetMoreBatchSize with targetBatchBytes", func(t *testing.T) {
+		t.Parallel()
+
+		// makeBatch returns an array of n documents that are each size bytes long.
+		makeBatch := func(n, size int) bsoncore.Array {
+			doc := bsoncore.NewDocumentBuilder().
+				AppendString("s", strings.Repeat("x", size-13)).
+				Build()
+			require.Len(t, doc, size, "unexpected document size")
+
+			ab := bsoncore.NewArrayBuilder()
+			for i := 0; i < n; i++ {
+				ab.AppendDocument(doc)
+			}
+			return ab.Build()
+		}
+
+		bc := &BatchCursor{batchSize: 5}
+		bc.SetTargetBatchBytes(1000)
+
+		size, ok := calcGetMoreBatchSize(*bc)
+		assert.True(t, ok, "expected ok")
+		assert.Equal(t, int32(5), size, "expected batchSize to be used before any documents are observed")
+
+		bc.observeBatch(makeBatch(10, 100))
+		size, _ = calcGetMoreBatchSize(*bc)
+		assert.Equal(t, int32(10), size, "expected batchSize for 100 byte documents")
+
+		bc.observeBatch(makeBatch(10, 300))
+		size, _ = calcGetMoreBatchSize(*bc)
+		assert.Equal(t, int32(5), size, "expected batchSize for an average of 200 byte documents")
+
+		bc.observeBatch(makeBatch(1, 50000))
+		size, _ = calcGetMoreBatchSize(*bc)
+		assert.Equal(t, int32(1), size, "expected batchSize to be at least 1")
+
+		bc.SetTargetBatchBytes(0)
+		size, _ = calcGetMoreBatchSize(*bc)
+		assert.Equal(t, int32(5), size, "expected batchSize to be used when targetBatchBytes is unset")
+	})
+	t.Run("observeBatch", func(t *testing.T) {
+		t.Parallel()
+
+		doc := bsoncore.NewDocumentBuilder().AppendInt32("x", 1).Build()
+		batch := bsoncore.NewArrayBuilder().AppendDocument(doc).AppendDocument(doc).Build()
+
+		bc := &BatchCursor{}
+		bc.observeBatch(batch)
+		bc.observeBatch(bsoncore.NewArrayBuilder().Build())
+		assert.Equal(t, int64(2), bc.observedDocs, "unexpected number of observed documents")
+		assert.Equal(t, int64(2*len(doc)), bc.observedBytes, "unexpected number of observed bytes")
+	})
 }
@@ -7,6 +7,7 @@
 package driver
 
 import (
+	"fmt"
 	"io"
 	"strconv"
 
@@ -14,6 +15,26 @@ import (
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
 )
 
+// DocumentTooLargeError is returned when a document to be inserted is larger than the maxBsonObjectSize advertised by
+// the server. It matches ErrDocumentTooLarge with errors.Is.
+type DocumentTooLargeError struct {
+	// Size is the size of the document in bytes.
+	Size int
+
+	// MaxSize is the server's maxBsonObjectSize in bytes.
+	MaxSize int
+}
+
+// Error implements the error interface.
+func (e DocumentTooLargeError) Error() string {
+	return fmt.Sprintf("document exceeds maxBsonObjectSize: document is %d bytes, limit is %d bytes", e.Size, e.MaxSize)
+}
+
+// Is reports whether target is ErrDocumentTooLarge.
+func (e DocumentTooLargeError) Is(target error) bool {
+	return target == ErrDocumentTooLarge
+}
+
 // Batches contains the necessary information to batch split an operation. This is only used for write
 // operations.
 type Batches struct {
@@ -22,6 +43,10 @@ type Batches struct {
 	Ordered    *bool
 
 	offset int
+
+	// maxDocumentSize is the server's maxBsonObjectSize, which inserted documents are checked
+	// against as they are appended to a batch. It is not checked if it is 0.
+	maxDocumentSize int
 }
 
 var _ OperationBatches = &Batches{}
@@ -46,6 +71,14 @@ func (b *Batches) AppendBatchSequence(dst []byte, maxCount, totalSize int) (int,
 			break
 		}
 		doc := b.Documents[i]
+		if err := b.checkDocumentSize(doc); err != nil {
+			// End the batch before the oversized document so the documents preceding it are
+			// still sent.
+			if n == 0 {
+				return 0, dst[:l], err
+			}
+			break
+		}
 		size += len(doc)
 		if size > totalSize {
 			break
@@ -60,6 +93,13 @@ func (b *Batches) AppendBatchSequence(dst []byte, maxCount, totalSize int) (int,
 	return n, dst, nil
 }
 
+// sequenceOverhead returns the number of bytes, in addition to the documents, that a document
+// sequence section appended by AppendBatchSequence occupies in a message.
+func (b *Batches) sequenceOverhead() int {
+	// section kind, section size, and null-terminated identifier
+	return 1 + 4 + len(b.Identifier) + 1
+}
+
 // AppendBatchArray appends dst with array of batches as long as the limits of max count, max document size, or
 // total size allows. It returns the number of batches appended, the new appended slice, and any error raised. It
 // returns the origenal input slice if nothing can be appends within the limits.
@@ -76,6 +116,12 @@ func (b *Batches) AppendBatchArray(dst []byte, maxCount, totalSize int) (int, []
 			break
 		}
 		doc := b.Documents[i]
+		if err := b.checkDocumentSize(doc); err != nil {
+			if n == 0 {
+				return 0, dst[:l], err
+			}
+			break
+		}
 		size += len(doc)
 		if size > totalSize {
 			break
@@ -94,6 +140,19 @@ func (b *Batches) AppendBatchArray(dst []byte, maxCount, totalSize int) (int, []
 	return n, dst, nil
 }
 
+// checkDocumentSize returns a DocumentTooLargeError if doc is larger than maxDocumentSize. Only the documents of an
+// insert command are checked because the documents of other commands, such as update statements, wrap the user's
+// document and can be slightly larger than maxBsonObjectSize.
+func (b *Batches) checkDocumentSize(doc bsoncore.Document) error {
+	if b.Identifier != "documents" || b.maxDocumentSize <= 0 {
+		return nil
+	}
+	if len(doc) > b.maxDocumentSize {
+		return DocumentTooLargeError{Size: len(doc), MaxSize: b.maxDocumentSize}
+	}
+	return nil
+}
+
 // IsOrdered indicates if the batches are ordered.
 func (b *Batches) IsOrdered() *bool {
 	return b.Ordered
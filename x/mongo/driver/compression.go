@@ -9,8 +9,10 @@ package driver
 import (
 	"bytes"
 	"compress/zlib"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 
 	"github.com/golang/snappy"
@@ -18,12 +20,136 @@ import (
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
 )
 
+// ErrUncompressedSizeTooLarge is returned when an OP_COMPRESSED message claims
+// an uncompressed size greater than the maximum allowed size.
+var ErrUncompressedSizeTooLarge = errors.New("uncompressed message size too large")
+
+// Custom compressors must use an ID in the range [MinCustomCompressorID,
+// MaxCustomCompressorID]. IDs below that range are reserved for the
+// compressors defined by the MongoDB wire protocol.
+const (
+	MinCustomCompressorID wiremessage.CompressorID = 128
+	MaxCustomCompressorID wiremessage.CompressorID = 255
+)
+
+// Compressor is a wire message compression codec that can be registered with
+// RegisterCompressor.
+type Compressor interface {
+	// Compress returns the compressed form of src.
+	Compress(src []byte) ([]byte, error)
+
+	// Decompress returns the decompressed form of src. uncompressedSize is the
+	// size of the original payload, as reported in the OP_COMPRESSED header.
+	Decompress(src []byte, uncompressedSize int32) ([]byte, error)
+}
+
+type customCompressor struct {
+	name       string
+	compressor Compressor
+}
+
+var (
+	customCompressorsMu sync.RWMutex
+	customCompressors   = make(map[wiremessage.CompressorID]customCompressor)
+)
+
+// RegisterCompressor registers a custom compressor with the given ID and name.
+// The name is used in the "compressors" client option and in the compressor
+// negotiation with the server, and the ID is written to the header of every
+// OP_COMPRESSED message compressed with c.
+//
+// Custom compressors are intended for experimenting with new codecs. Both ends
+// of the connection must agree on the ID, the name and the encoding: the server
+// (or proxy) must advertise name in the "compression" field of its hello
+// response and decompress messages with the same codec, otherwise the
+// compressor is never negotiated or messages cannot be read.
+//
+// RegisterCompressor returns an error if id is outside the range
+// [MinCustomCompressorID, MaxCustomCompressorID], or if the ID or the name is
+// already registered. It should be called before any client is connected.
+func RegisterCompressor(id wiremessage.CompressorID, name string, c Compressor) error {
+	if id < MinCustomCompressorID {
+		return fmt.Errorf("compressor ID %d is outside the range reserved for custom compressors [%d, %d]",
+			id, MinCustomCompressorID, MaxCustomCompressorID)
+	}
+	if name == "" {
+		return errors.New("compressor name must not be empty")
+	}
+	if c == nil {
+		return errors.New("compressor must not be nil")
+	}
+	switch strings.ToLower(name) {
+	case "snappy", "zlib", "zstd", "noop":
+		return fmt.Errorf("compressor name %q is reserved", name)
+	}
+
+	customCompressorsMu.Lock()
+	defer customCompressorsMu.Unlock()
+
+	if existing, ok := customCompressors[id]; ok {
+		return fmt.Errorf("compressor ID %d is already registered for %q", id, existing.name)
+	}
+	for _, existing := range customCompressors {
+		if strings.EqualFold(existing.name, name) {
+			return fmt.Errorf("compressor name %q is already registered", name)
+		}
+	}
+	customCompressors[id] = customCompressor{name: name, compressor: c}
+	return nil
+}
+
+// unregisterCompressor removes the custom compressor registered with the given
+// ID. It is used by tests to undo RegisterCompressor.
+func unregisterCompressor(id wiremessage.CompressorID) {
+	customCompressorsMu.Lock()
+	defer customCompressorsMu.Unlock()
+
+	delete(customCompressors, id)
+}
+
+// LookupCompressor returns the ID of the custom compressor registered with the
+// given name, compared case-insensitively.
+func LookupCompressor(name string) (wiremessage.CompressorID, bool) {
+	customCompressorsMu.RLock()
+	defer customCompressorsMu.RUnlock()
+
+	for id, cc := range customCompressors {
+		if strings.EqualFold(cc.name, name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// CompressorName returns the name of the custom compressor registered with the
+// given ID.
+func CompressorName(id wiremessage.CompressorID) (string, bool) {
+	customCompressorsMu.RLock()
+	defer customCompressorsMu.RUnlock()
+
+	cc, ok := customCompressors[id]
+	return cc.name, ok
+}
+
+func getCustomCompressor(id wiremessage.CompressorID) (Compressor, bool) {
+	customCompressorsMu.RLock()
+	defer customCompressorsMu.RUnlock()
+
+	cc, ok := customCompressors[id]
+	return cc.compressor, ok
+}
+
 // CompressionOpts holds settings for how to compress a payload
 type CompressionOpts struct {
 	Compressor       wiremessage.CompressorID
 	ZlibLevel        int
 	ZstdLevel        int
 	UncompressedSize int32
+
+	// MaxUncompressedSize is the maximum UncompressedSize accepted by
+	// DecompressPayload. It is checked before the buffer for the decompressed
+	// payload is allocated. If it is 0, the size is not limited.
+	MaxUncompressedSize int32
 }
 
 // mustZstdNewWriter creates a zstd.Encoder with the given level and a nil
@@ -142,6 +268,9 @@ func CompressPayload(in []byte, opts CompressionOpts) ([]byte, error) {
 		zstdBufPool.Put(ptr)
 		return dst, nil
 	default:
+		if c, ok := getCustomCompressor(opts.Compressor); ok {
+			return c.Compress(in)
+		}
 		return nil, fmt.Errorf("unknown compressor ID %v", opts.Compressor)
 	}
 }
@@ -155,6 +284,14 @@ var zstdReaderPool = sync.Pool{
 
 // DecompressPayload takes a byte slice that has been compressed and undoes it according to the options passed
 func DecompressPayload(in []byte, opts CompressionOpts) ([]byte, error) {
+	if opts.UncompressedSize < 0 {
+		return nil, fmt.Errorf("invalid uncompressed size %d", opts.UncompressedSize)
+	}
+	if max := opts.MaxUncompressedSize; max > 0 && opts.UncompressedSize > max {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the maximum of %d bytes",
+			ErrUncompressedSizeTooLarge, opts.UncompressedSize, max)
+	}
+
 	switch opts.Compressor {
 	case wiremessage.CompressorNoOp:
 		return in, nil
@@ -189,6 +326,9 @@ func DecompressPayload(in []byte, opts CompressionOpts) ([]byte, error) {
 		zstdReaderPool.Put(r)
 		return out, err
 	default:
+		if c, ok := getCustomCompressor(opts.Compressor); ok {
+			return c.Decompress(in, opts.UncompressedSize)
+		}
 		return nil, fmt.Errorf("unknown compressor ID %v", opts.Compressor)
 	}
 }
@@ -9,6 +9,7 @@ package driver
 import (
 	"bytes"
 	"compress/zlib"
+	"fmt"
 	"os"
 	"testing"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/klauspost/compress/zstd"
 
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
 )
 
@@ -102,6 +104,115 @@ func TestDecompressFailures(t *testing.T) {
 		_, err = DecompressPayload(compressedData, opts)
 		assert.Error(t, err)
 	})
+	t.Run("uncompressed size above maximum", func(t *testing.T) {
+		t.Parallel()
+
+		for _, compressor := range []wiremessage.CompressorID{
+			wiremessage.CompressorSnappy,
+			wiremessage.CompressorZLib,
+			wiremessage.CompressorZstd,
+		} {
+			opts := CompressionOpts{
+				Compressor:          compressor,
+				UncompressedSize:    1<<31 - 1,
+				MaxUncompressedSize: 1024,
+			}
+			_, err := DecompressPayload([]byte{0x01, 0x02, 0x03}, opts)
+			assert.ErrorIs(t, err, ErrUncompressedSizeTooLarge, "unexpected error for compressor %v", compressor)
+		}
+	})
+	t.Run("negative uncompressed size", func(t *testing.T) {
+		t.Parallel()
+
+		opts := CompressionOpts{
+			Compressor:       wiremessage.CompressorZLib,
+			UncompressedSize: -1,
+		}
+		_, err := DecompressPayload([]byte{0x01, 0x02, 0x03}, opts)
+		assert.EqualError(t, err, "invalid uncompressed size -1")
+	})
+}
+
+// identityCompressor is a Compressor that does not change the payload.
+type identityCompressor struct{}
+
+func (identityCompressor) Compress(src []byte) ([]byte, error) {
+	return append([]byte(nil), src...), nil
+}
+
+func (identityCompressor) Decompress(src []byte, uncompressedSize int32) ([]byte, error) {
+	if int32(len(src)) != uncompressedSize {
+		return nil, fmt.Errorf("expected %d bytes, got %d", uncompressedSize, len(src))
+	}
+	return append([]byte(nil), src...), nil
+}
+
+func TestRegisterCompressor(t *testing.T) {
+	const id wiremessage.CompressorID = 200
+
+	err := RegisterCompressor(id, "identity", identityCompressor{})
+	require.NoError(t, err, "RegisterCompressor error")
+	t.Cleanup(func() { unregisterCompressor(id) })
+
+	gotID, ok := LookupCompressor("IDENTITY")
+	assert.True(t, ok, "expected the compressor to be found by name")
+	assert.Equal(t, id, gotID, "unexpected compressor ID")
+	name, ok := CompressorName(id)
+	assert.True(t, ok, "expected the compressor to be found by ID")
+	assert.Equal(t, "identity", name, "unexpected compressor name")
+
+	payload := []byte("Lorem ipsum dolor sit amet, consectetur adipiscing elit")
+	opts := CompressionOpts{
+		Compressor:       id,
+		UncompressedSize: int32(len(payload)),
+	}
+	compressed, err := CompressPayload(payload, opts)
+	require.NoError(t, err, "CompressPayload error")
+	assert.Equal(t, payload, compressed, "expected the identity compressor to not change the payload")
+	decompressed, err := DecompressPayload(compressed, opts)
+	require.NoError(t, err, "DecompressPayload error")
+	assert.Equal(t, payload, decompressed, "unexpected decompressed payload")
+
+	testCases := []struct {
+		name    string
+		id      wiremessage.CompressorID
+		cname   string
+		wantErr string
+	}{
+		{
+			name:    "ID below reserved range",
+			id:      wiremessage.CompressorZstd,
+			cname:   "lz4",
+			wantErr: "compressor ID 3 is outside the range reserved for custom compressors [128, 255]",
+		},
+		{
+			name:    "ID already registered",
+			id:      id,
+			cname:   "lz4",
+			wantErr: `compressor ID 200 is already registered for "identity"`,
+		},
+		{
+			name:    "name already registered",
+			id:      id + 1,
+			cname:   "Identity",
+			wantErr: `compressor name "Identity" is already registered`,
+		},
+		{
+			name:    "built-in name",
+			id:      id + 1,
+			cname:   "zstd",
+			wantErr: `compressor name "zstd" is reserved`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := RegisterCompressor(tc.id, tc.cname, identityCompressor{})
+			if err == nil {
+				unregisterCompressor(tc.id)
+			}
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
 }
 
 var (
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/internal/randutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/dns"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
@@ -158,6 +159,7 @@ type ConnString struct {
 	SocketTimeout                      time.Duration
 	SocketTimeoutSet                   bool
 	SRVMaxHosts                        int
+	SRVRecordsDiscarded                int
 	SRVServiceName                     string
 	SSL                                bool
 	SSLSet                             bool
@@ -177,6 +179,7 @@ type ConnString struct {
 	SSLDisableOCSPEndpointCheckSet     bool
 	Timeout                            time.Duration
 	TimeoutSet                         bool
+	TXTOptions                         []string
 	WString                            string
 	WNumber                            int
 	WNumberSet                         bool
@@ -223,7 +226,7 @@ func (u *ConnString) Validate() error {
 	// Check for invalid use of direct connections.
 	if (u.ConnectSet && u.Connect == SingleConnect) ||
 		(u.DirectConnectionSet && u.DirectConnection) {
-		if len(u.Hosts) > 1 {
+		if hosts, _ := DeduplicateHosts(u.Hosts); len(hosts) > 1 {
 			return errors.New("a direct connection cannot be made if multiple hosts are specified")
 		}
 		if u.Scheme == SchemeMongoDBSRV {
@@ -236,7 +239,7 @@ func (u *ConnString) Validate() error {
 
 	// Validation for load-balanced mode.
 	if u.LoadBalancedSet && u.LoadBalanced {
-		if len(u.Hosts) > 1 {
+		if hosts, _ := DeduplicateHosts(u.Hosts); len(hosts) > 1 {
 			return ErrLoadBalancedWithMultipleHosts
 		}
 		if u.ReplicaSet != "" {
@@ -839,6 +842,25 @@ func sanitizeHost(host string) (string, error) {
 	return unescaped, nil
 }
 
+// DeduplicateHosts returns hosts with duplicate entries removed, keeping the first occurrence of each, and the
+// entries that were removed. Hosts are compared in their canonical form, so "LOCALHOST" and "localhost:27017" are
+// duplicates.
+func DeduplicateHosts(hosts []string) ([]string, []string) {
+	seen := make(map[address.Address]struct{}, len(hosts))
+	unique := make([]string, 0, len(hosts))
+	var duplicates []string
+	for _, host := range hosts {
+		addr := address.Address(host).Canonicalize()
+		if _, ok := seen[addr]; ok {
+			duplicates = append(duplicates, host)
+			continue
+		}
+		seen[addr] = struct{}{}
+		unique = append(unique, host)
+	}
+	return unique, duplicates
+}
+
 // ConnectMode informs the driver on how to connect
 // to the server.
 type ConnectMode uint8
@@ -972,6 +994,10 @@ func (p *parser) parse(original string) (*ConnString, error) {
 		if err != nil {
 			return nil, err
 		}
+		for _, pair := range connectionArgsFromTXT {
+			key, _, _ := strings.Cut(pair, "=")
+			connStr.TXTOptions = append(connStr.TXTOptions, strings.ToLower(key))
+		}
 
 		// SSL is enabled by default for SRV, but can be manually disabled with "ssl=false".
 		connStr.SSL = true
@@ -990,10 +1016,11 @@ func (p *parser) parse(original string) (*ConnString, error) {
 
 	// do SRV lookup if "mongodb+srv://"
 	if connStr.Scheme == SchemeMongoDBSRV && p.dnsResolver != nil {
-		parsedHosts, err := p.dnsResolver.ParseHosts(hosts, connStr.SRVServiceName, true)
+		parsedHosts, discarded, err := p.dnsResolver.LookupHosts(hosts, connStr.SRVServiceName, true)
 		if err != nil {
 			return connStr, err
 		}
+		connStr.SRVRecordsDiscarded = discarded
 
 		// If p.SRVMaxHosts is non-zero and is less than the number of hosts, randomly
 		// select SRVMaxHosts hosts from parsedHosts.
@@ -181,6 +181,33 @@ func TestDirectConnection(t *testing.T) {
 	}
 }
 
+func TestDeduplicateHosts(t *testing.T) {
+	testCases := []struct {
+		name       string
+		hosts      []string
+		unique     []string
+		duplicates []string
+	}{
+		{"no duplicates", []string{"a:27017", "b:27017"}, []string{"a:27017", "b:27017"}, nil},
+		{"same host twice", []string{"a:27017", "a:27017"}, []string{"a:27017"}, []string{"a:27017"}},
+		{"default port", []string{"a", "a:27017"}, []string{"a"}, []string{"a:27017"}},
+		{"case-insensitive", []string{"A:27017", "b", "a:27017"}, []string{"A:27017", "b"}, []string{"a:27017"}},
+		{"different ports", []string{"a:27017", "a:27018"}, []string{"a:27017", "a:27018"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unique, duplicates := connstring.DeduplicateHosts(tc.hosts)
+			assert.Equal(t, tc.unique, unique, "unexpected unique hosts")
+			assert.Equal(t, tc.duplicates, duplicates, "unexpected duplicate hosts")
+		})
+	}
+
+	t.Run("direct connection with the same host twice", func(t *testing.T) {
+		_, err := connstring.ParseAndValidate("mongodb://localhost,localhost:27017/?directConnection=true")
+		assert.Nil(t, err, "expected no error, got %v", err)
+	})
+}
+
 func TestConnectTimeout(t *testing.T) {
 	tests := []struct {
 		s        string
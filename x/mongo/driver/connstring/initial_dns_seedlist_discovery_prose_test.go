@@ -120,3 +120,56 @@ func TestInitialDNSSeedlistDiscoveryProse(t *testing.T) {
 		}
 	})
 }
+
+func TestSRVRecordsTruncated(t *testing.T) {
+	t.Parallel()
+
+	records := make([]*net.SRV, dns.MaxSRVRecords+10)
+	for i := range records {
+		records[i] = &net.SRV{Target: fmt.Sprintf("host%d.test.build.10gen.cc", i), Port: 27017}
+	}
+	p := &parser{&dns.Resolver{
+		LookupSRV: func(_, _, _ string) (string, []*net.SRV, error) {
+			return "", records, nil
+		},
+		LookupTXT: func(string) ([]string, error) {
+			return nil, nil
+		},
+	}}
+
+	t.Run("without srvMaxHosts", func(t *testing.T) {
+		t.Parallel()
+
+		cs, err := p.parse("mongodb+srv://test.build.10gen.cc")
+		assert.NoError(t, err, "expected no URI parsing error, got %v", err)
+		assert.Len(t, cs.Hosts, dns.MaxSRVRecords, "expected hosts to be truncated")
+		assert.Equal(t, 10, cs.SRVRecordsDiscarded, "unexpected number of discarded records")
+	})
+	t.Run("with srvMaxHosts", func(t *testing.T) {
+		t.Parallel()
+
+		cs, err := p.parse("mongodb+srv://test.build.10gen.cc/?srvMaxHosts=3")
+		assert.NoError(t, err, "expected no URI parsing error, got %v", err)
+		assert.Len(t, cs.Hosts, 3, "expected hosts to be sampled down to srvMaxHosts")
+		assert.Equal(t, 10, cs.SRVRecordsDiscarded, "unexpected number of discarded records")
+	})
+}
+
+func TestTXTOptions(t *testing.T) {
+	t.Parallel()
+
+	p := &parser{&dns.Resolver{
+		LookupSRV: func(_, _, _ string) (string, []*net.SRV, error) {
+			return "", []*net.SRV{{Target: "host1.test.build.10gen.cc", Port: 27017}}, nil
+		},
+		LookupTXT: func(string) ([]string, error) {
+			return []string{"authSource=thisDB&replicaSet=repl0"}, nil
+		},
+	}}
+
+	cs, err := p.parse("mongodb+srv://test.build.10gen.cc/?authSource=otherDB")
+	assert.NoError(t, err, "expected no URI parsing error, got %v", err)
+	assert.Equal(t, []string{"authsource", "replicaset"}, cs.TXTOptions)
+	assert.Equal(t, "otherDB", cs.AuthSource, "expected the URI to override the TXT record")
+	assert.Equal(t, "repl0", cs.ReplicaSet)
+}
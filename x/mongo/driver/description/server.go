@@ -83,6 +83,8 @@ type Server struct {
 	Arbiters              []string
 	AverageRTT            time.Duration
 	AverageRTTSet         bool
+	ClockSkew             time.Duration // estimated difference between the server's clock and the local clock
+	ClockSkewSet          bool
 	Compression           []string // compression methods returned by server
 	CanonicalAddr         address.Address
 	ElectionID            bson.ObjectID
@@ -93,6 +95,7 @@ type Server struct {
 	LastError             error
 	LastUpdateTime        time.Time
 	LastWriteTime         time.Time
+	LocalTime             time.Time // the server's clock when it replied to the hello command
 	MaxBatchCount         uint32
 	MaxDocumentSize       uint32
 	MaxMessageSize        uint32
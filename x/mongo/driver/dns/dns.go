@@ -19,8 +19,18 @@ import (
 	"net"
 	"runtime"
 	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/internal/randutil"
 )
 
+// MaxSRVRecords is the maximum number of SRV records processed from a single
+// lookup, regardless of srvMaxHosts. If a lookup returns more records, a random
+// sample of MaxSRVRecords records is processed and the rest are discarded.
+const MaxSRVRecords = 1000
+
+// random is a package-global pseudo-random number generator.
+var random = randutil.NewLockedRand()
+
 // Resolver resolves DNS records.
 type Resolver struct {
 	// Holds the functions to use for DNS lookups
@@ -33,10 +43,18 @@ var DefaultResolver = &Resolver{net.LookupSRV, net.LookupTXT}
 
 // ParseHosts uses the srv string and service name to get the hosts.
 func (r *Resolver) ParseHosts(host string, srvName string, stopOnErr bool) ([]string, error) {
+	hosts, _, err := r.LookupHosts(host, srvName, stopOnErr)
+	return hosts, err
+}
+
+// LookupHosts is like ParseHosts, but also returns the number of SRV records
+// that were discarded without being processed because the lookup returned more
+// than MaxSRVRecords records.
+func (r *Resolver) LookupHosts(host string, srvName string, stopOnErr bool) ([]string, int, error) {
 	parsedHosts := strings.Split(host, ",")
 
 	if len(parsedHosts) != 1 {
-		return nil, fmt.Errorf("URI with SRV must include one and only one hostname")
+		return nil, 0, fmt.Errorf("URI with SRV must include one and only one hostname")
 	}
 	return r.fetchSeedlistFromSRV(parsedHosts[0], srvName, stopOnErr)
 }
@@ -71,7 +89,7 @@ func (r *Resolver) GetConnectionArgsFromTXT(host string) ([]string, error) {
 	return connectionArgsFromTXT, nil
 }
 
-func (r *Resolver) fetchSeedlistFromSRV(host string, srvName string, stopOnErr bool) ([]string, error) {
+func (r *Resolver) fetchSeedlistFromSRV(host string, srvName string, stopOnErr bool) ([]string, int, error) {
 	var err error
 
 	_, _, err = net.SplitHostPort(host)
@@ -79,7 +97,7 @@ func (r *Resolver) fetchSeedlistFromSRV(host string, srvName string, stopOnErr b
 	if err == nil {
 		// we were able to successfully extract a port from the host,
 		// but should not be able to when using SRV
-		return nil, fmt.Errorf("URI with srv must not include a port number")
+		return nil, 0, fmt.Errorf("URI with srv must not include a port number")
 	}
 
 	// default to "mongodb" as service name if not supplied
@@ -88,9 +106,22 @@ func (r *Resolver) fetchSeedlistFromSRV(host string, srvName string, stopOnErr b
 	}
 	_, addresses, err := r.LookupSRV(srvName, "tcp", host)
 	if err != nil && strings.Contains(err.Error(), "cannot unmarshal DNS message") {
-		return nil, fmt.Errorf("see https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#hdr-Potential_DNS_Issues: %w", err)
+		return nil, 0, fmt.Errorf("see https://pkg.go.dev/go.mongodb.org/mongo-driver/mongo#hdr-Potential_DNS_Issues: %w", err)
 	} else if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	// Randomly sample the records down to MaxSRVRecords so that an oversized
+	// record set cannot produce an unbounded host list. Copy the records first
+	// so the slice returned by LookupSRV is not reordered.
+	var discarded int
+	if len(addresses) > MaxSRVRecords {
+		discarded = len(addresses) - MaxSRVRecords
+		addresses = append([]*net.SRV(nil), addresses...)
+		random.Shuffle(len(addresses), func(i, j int) {
+			addresses[i], addresses[j] = addresses[j], addresses[i]
+		})
+		addresses = addresses[:MaxSRVRecords]
 	}
 
 	trimmedHost := strings.TrimSuffix(host, ".")
@@ -101,13 +132,13 @@ func (r *Resolver) fetchSeedlistFromSRV(host string, srvName string, stopOnErr b
 		err := validateSRVResult(trimmedAddressTarget, trimmedHost)
 		if err != nil {
 			if stopOnErr {
-				return nil, err
+				return nil, 0, err
 			}
 			continue
 		}
 		parsedHosts = append(parsedHosts, fmt.Sprintf("%s:%d", trimmedAddressTarget, address.Port))
 	}
-	return parsedHosts, nil
+	return parsedHosts, discarded, nil
 }
 
 func validateSRVResult(recordFromSRV, inputHostName string) error {
@@ -0,0 +1,145 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package dns
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+// newSRVResolver returns a Resolver whose SRV lookups return n records for
+// hosts under test.build.10gen.cc.
+func newSRVResolver(n int) (*Resolver, []*net.SRV) {
+	records := make([]*net.SRV, n)
+	for i := range records {
+		records[i] = &net.SRV{Target: fmt.Sprintf("host%d.test.build.10gen.cc.", i), Port: 27017}
+	}
+	return &Resolver{
+		LookupSRV: func(string, string, string) (string, []*net.SRV, error) {
+			return "", records, nil
+		},
+		LookupTXT: func(string) ([]string, error) {
+			return nil, nil
+		},
+	}, records
+}
+
+func TestResolver_LookupHosts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("within the limit", func(t *testing.T) {
+		t.Parallel()
+
+		resolver, _ := newSRVResolver(MaxSRVRecords)
+		hosts, discarded, err := resolver.LookupHosts("test.build.10gen.cc", "", true)
+		require.NoError(t, err, "LookupHosts error")
+
+		assert.Len(t, hosts, MaxSRVRecords, "expected all records to be processed")
+		assert.Equal(t, 0, discarded, "expected no records to be discarded")
+		assert.Equal(t, "host0.test.build.10gen.cc:27017", hosts[0], "expected records to keep their order")
+	})
+
+	t.Run("oversized record set is truncated", func(t *testing.T) {
+		t.Parallel()
+
+		resolver, records := newSRVResolver(MaxSRVRecords + 500)
+
+		sample := func() map[string]bool {
+			hosts, discarded, err := resolver.LookupHosts("test.build.10gen.cc", "", true)
+			require.NoError(t, err, "LookupHosts error")
+			assert.Len(t, hosts, MaxSRVRecords, "expected records to be truncated")
+			assert.Equal(t, 500, discarded, "unexpected number of discarded records")
+
+			set := make(map[string]bool, len(hosts))
+			for _, host := range hosts {
+				set[host] = true
+			}
+			assert.Len(t, set, MaxSRVRecords, "expected sampled hosts to be unique")
+			return set
+		}
+
+		// The records kept should be a random sample, so two lookups are
+		// expected to keep different records.
+		first, second := sample(), sample()
+		assert.NotEqual(t, first, second, "expected truncation to randomly sample records")
+
+		// The records returned by LookupSRV must not be reordered.
+		for i, record := range records {
+			assert.Equal(t, fmt.Sprintf("host%d.test.build.10gen.cc.", i), record.Target)
+		}
+	})
+
+	t.Run("ParseHosts truncates", func(t *testing.T) {
+		t.Parallel()
+
+		resolver, _ := newSRVResolver(MaxSRVRecords * 2)
+		hosts, err := resolver.ParseHosts("test.build.10gen.cc", "", true)
+		require.NoError(t, err, "ParseHosts error")
+		assert.Len(t, hosts, MaxSRVRecords, "expected records to be truncated")
+	})
+}
+
+func TestResolver_GetConnectionArgsFromTXT(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		record string
+		want   []string
+		err    string
+	}{
+		{
+			name:   "allowed options",
+			record: "authSource=admin&replicaSet=rs0",
+			want:   []string{"authSource=admin", "replicaSet=rs0"},
+		},
+		{
+			name:   "allowed options are case insensitive",
+			record: "AUTHSOURCE=admin;loadbalanced=true",
+			want:   []string{"AUTHSOURCE=admin", "loadbalanced=true"},
+		},
+		{
+			name:   "disallowed option",
+			record: "authSource=admin&tls=false",
+			err:    "Cannot specify option 'tls' in TXT record",
+		},
+		{
+			name:   "disallowed credential option",
+			record: "authMechanism=PLAIN",
+			err:    "Cannot specify option 'authMechanism' in TXT record",
+		},
+		{
+			name:   "option without value",
+			record: "replicaSet",
+			err:    "Invalid TXT record",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // Capture the range variable.
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := &Resolver{
+				LookupTXT: func(string) ([]string, error) {
+					return []string{tc.record}, nil
+				},
+			}
+			args, err := resolver.GetConnectionArgsFromTXT("test.build.10gen.cc")
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err, "GetConnectionArgsFromTXT error")
+			assert.Equal(t, tc.want, args)
+		})
+	}
+}
@@ -94,6 +94,27 @@ type Deployment interface {
 	GetServerSelectionTimeout() time.Duration
 }
 
+// OperationTimeoutDeployment is implemented by deployments that define separate default timeouts for read and write
+// operations. A non-nil timeout returned for an operation's Type takes precedence over the operation's Timeout.
+type OperationTimeoutDeployment interface {
+	ReadTimeout() *time.Duration
+	WriteTimeout() *time.Duration
+}
+
+// HedgeDeployment is implemented by deployments that define a default for hedged reads. The default only applies to
+// non-primary reads sent to a mongos when the operation's read preference does not specify whether hedged reads are
+// enabled.
+type HedgeDeployment interface {
+	HedgeEnabled() *bool
+}
+
+// OperationLimiter is implemented by deployments that limit the number of operations that can execute concurrently.
+// AcquireOperation returns when the operation may execute, or an error if it may not execute before ctx is done. The
+// returned release function must be called once the operation completes.
+type OperationLimiter interface {
+	AcquireOperation(ctx context.Context) (release func(), err error)
+}
+
 // Connector represents a type that can connect to a server.
 type Connector interface {
 	Connect() error
@@ -0,0 +1,33 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package drivertest
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ReadWireMessage reads a single wire message from r, which is useful for fake
+// servers that read the messages sent by the driver from a net.Conn.
+func ReadWireMessage(r io.Reader) ([]byte, error) {
+	var sizeBuf [4]byte
+	if _, err := io.ReadFull(r, sizeBuf[:]); err != nil {
+		return nil, err
+	}
+	size := int32(binary.LittleEndian.Uint32(sizeBuf[:]))
+	if size < 16 {
+		return nil, errors.New("wire message is too short")
+	}
+
+	wm := make([]byte, size)
+	copy(wm, sizeBuf[:])
+	if _, err := io.ReadFull(r, wm[4:]); err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
@@ -47,6 +47,7 @@ var (
 
 	unknownReplWriteConcernCode   = int32(79)
 	unsatisfiableWriteConcernCode = int32(100)
+	bsonObjectTooLargeCode        = int64(10334)
 )
 
 var (
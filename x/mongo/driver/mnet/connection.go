@@ -76,6 +76,14 @@ type Pinner interface {
 	UnpinFromTransaction() error
 }
 
+// RequestIDGenerator represents a Connection that supplies the request IDs
+// used when building wire messages to send over it. If a Connection does not
+// implement this interface, the global wire message request ID counter is
+// used instead.
+type RequestIDGenerator interface {
+	NextRequestID() int32
+}
+
 // Connection represents a connection to a MongoDB server.
 type Connection struct {
 	ReadWriteCloser
@@ -83,6 +91,7 @@ type Connection struct {
 	Streamer
 	Compressor
 	Pinner
+	RequestIDGenerator
 }
 
 // NewConnection creates a new Connection with the provided component. This
@@ -116,5 +125,9 @@ func NewConnection(component interface {
 		conn.Pinner = pinner
 	}
 
+	if generator, ok := component.(RequestIDGenerator); ok {
+		conn.RequestIDGenerator = generator
+	}
+
 	return conn
 }
@@ -20,6 +20,7 @@ type config struct {
 	serverCert, issuer      *x509.Certificate
 	cache                   Cache
 	disableEndpointChecking bool
+	hardFail                bool
 	ocspRequest             *ocsp.Request
 	ocspRequestBytes        []byte
 	httpClient              *http.Client
@@ -29,6 +30,7 @@ func newConfig(certChain []*x509.Certificate, opts *VerifyOptions) (config, erro
 	cfg := config{
 		cache:                   opts.Cache,
 		disableEndpointChecking: opts.DisableEndpointChecking,
+		hardFail:                opts.HardFail,
 		httpClient:              opts.HTTPClient,
 	}
 
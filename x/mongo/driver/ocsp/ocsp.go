@@ -131,7 +131,10 @@ func getParsedResponse(ctx context.Context, cfg config, connState tls.Connection
 	}
 	externalResponse := contactResponders(ctx, cfg)
 	if externalResponse == nil {
-		// None of the responders were available.
+		// None of the responders were available. This is only an error if the hard-fail policy is in effect.
+		if cfg.hardFail {
+			return nil, newOCSPError(errors.New("unable to determine certificate status from OCSP responders"))
+		}
 		return nil, nil
 	}
 
@@ -6,11 +6,80 @@
 
 package ocsp
 
-import "net/http"
+import (
+	"net/http"
+	"path"
+)
+
+const (
+	// PolicySoftFail indicates that OCSP responders are contacted when there is no stapled or cached response, and
+	// the connection continues if none of them return a conclusive certificate status.
+	PolicySoftFail = "soft-fail"
+
+	// PolicyHardFail indicates that OCSP responders are contacted when there is no stapled or cached response, and
+	// verification fails if the certificate status cannot be determined.
+	PolicyHardFail = "hard-fail"
+
+	// PolicyDisabled indicates that OCSP responders are never contacted. Stapled responses are still verified.
+	PolicyDisabled = "disabled"
+)
 
 // VerifyOptions specifies options to configure OCSP verification.
 type VerifyOptions struct {
 	Cache                   Cache
 	DisableEndpointChecking bool
 	HTTPClient              *http.Client
+
+	// HardFail specifies whether verification should fail if the certificate status cannot be determined from a
+	// stapled, cached, or responder-provided response. It has no effect if DisableEndpointChecking is true.
+	HardFail bool
+}
+
+// IsValidPolicy returns true if the given string is a supported OCSP policy.
+func IsValidPolicy(policy string) bool {
+	switch policy {
+	case PolicySoftFail, PolicyHardFail, PolicyDisabled:
+		return true
+	}
+	return false
+}
+
+// PolicyForHost returns the policy in policies whose host pattern matches host. Patterns use the syntax of
+// path.Match, e.g. "*.example.com". If multiple patterns match, an exact match takes precedence, followed by the
+// longest pattern. The second return value is false if no pattern matches.
+func PolicyForHost(policies map[string]string, host string) (string, bool) {
+	if policy, ok := policies[host]; ok {
+		return policy, true
+	}
+
+	var best string
+	var found bool
+	for pattern := range policies {
+		if matched, err := path.Match(pattern, host); err != nil || !matched {
+			continue
+		}
+		if !found || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best = pattern
+			found = true
+		}
+	}
+	if !found {
+		return "", false
+	}
+	return policies[best], true
+}
+
+// Apply configures opts according to the given policy.
+func (opts *VerifyOptions) Apply(policy string) {
+	switch policy {
+	case PolicySoftFail:
+		opts.DisableEndpointChecking = false
+		opts.HardFail = false
+	case PolicyHardFail:
+		opts.DisableEndpointChecking = false
+		opts.HardFail = true
+	case PolicyDisabled:
+		opts.DisableEndpointChecking = true
+		opts.HardFail = false
+	}
 }
@@ -0,0 +1,61 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package ocsp
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+)
+
+func TestPolicyForHost(t *testing.T) {
+	policies := map[string]string{
+		"*.example.com":      PolicyDisabled,
+		"*.prod.example.com": PolicyHardFail,
+		"db1.example.com":    PolicySoftFail,
+	}
+
+	testCases := []struct {
+		name   string
+		host   string
+		want   string
+		wantOK bool
+	}{
+		{"exact match", "db1.example.com", PolicySoftFail, true},
+		{"wildcard match", "db2.example.com", PolicyDisabled, true},
+		{"longest pattern wins", "db1.prod.example.com", PolicyHardFail, true},
+		{"no match", "db1.example.org", "", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := PolicyForHost(policies, tc.host)
+			assert.Equal(t, tc.wantOK, ok, "expected match %v, got %v", tc.wantOK, ok)
+			assert.Equal(t, tc.want, got, "expected policy %q, got %q", tc.want, got)
+		})
+	}
+}
+
+func TestVerifyOptionsApply(t *testing.T) {
+	testCases := []struct {
+		policy      string
+		wantDisable bool
+		wantHard    bool
+	}{
+		{PolicySoftFail, false, false},
+		{PolicyHardFail, false, true},
+		{PolicyDisabled, true, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.policy, func(t *testing.T) {
+			opts := &VerifyOptions{DisableEndpointChecking: !tc.wantDisable, HardFail: !tc.wantHard}
+			opts.Apply(tc.policy)
+
+			assert.Equal(t, tc.wantDisable, opts.DisableEndpointChecking, "unexpected DisableEndpointChecking")
+			assert.Equal(t, tc.wantHard, opts.HardFail, "unexpected HardFail")
+		})
+	}
+}
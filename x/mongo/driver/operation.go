@@ -52,7 +52,11 @@ var (
 	// ErrEmptyWriteConcern indicates that a write concern has no fields set.
 	ErrEmptyWriteConcern = errors.New("a write concern must have at least one field set")
 	// ErrDocumentTooLarge occurs when a document that is larger than the maximum size accepted by a
-	// server is passed to an insert command.
+	// server is passed to an insert command. Documents larger than the server's maxBsonObjectSize are
+	// reported with a DocumentTooLargeError, which matches ErrDocumentTooLarge with errors.Is. The
+	// documents preceding an oversized document in an ordered insert are still inserted, and an
+	// unordered insert inserts all other documents and reports each oversized document as a write
+	// error with the code BSONObjectTooLarge.
 	ErrDocumentTooLarge = errors.New("an inserted document is too large")
 	// errDatabaseNameEmpty occurs when a database name is not provided.
 	errDatabaseNameEmpty = errors.New("database name cannot be empty")
@@ -320,6 +324,23 @@ type Operation struct {
 	// of the operation do not contain a maxTimeMS field.
 	OmitMaxTimeMS bool
 
+	// MaxTime is the maximum amount of time the server may spend executing the operation, sent as the maxTimeMS
+	// field. MaxTime is only honored if the operation's Context is not a timeout Context, i.e. if it has no deadline
+	// and no client-level timeout has been applied to it. Otherwise maxTimeMS is calculated from the Context deadline
+	// and MaxTime is ignored. MaxTime is sent even if OmitMaxTimeMS is true.
+	MaxTime *time.Duration
+
+	// IgnoreClientTimeout prevents Timeout, and any read or write timeout of the Deployment, from being applied to
+	// the operation's Context, so that MaxTime is honored. A deadline on the Context passed to Execute is still
+	// honored and takes precedence over MaxTime.
+	IgnoreClientTimeout bool
+
+	// CompressHello allows hello commands to be compressed if the connection has negotiated a
+	// compressor. Hello commands are never compressed otherwise because compression is negotiated
+	// during the initial handshake. This is used for streaming heartbeats on monitoring
+	// connections.
+	CompressHello bool
+
 	// Authenticator is the authenticator to use for this operation when a reauthentication is
 	// required.
 	Authenticator Authenticator
@@ -379,6 +400,10 @@ func filterDeprioritizedServers(candidates, deprioritized []description.Server)
 type opServerSelector struct {
 	selector             description.ServerSelector
 	deprioritizedServers []description.Server
+
+	// localThreshold, if set, narrows the servers returned by selector to the
+	// given latency window.
+	localThreshold *time.Duration
 }
 
 // SelectServer will filter candidates with operation-specific logic before
@@ -392,6 +417,14 @@ func (oss *opServerSelector) SelectServer(
 		return nil, err
 	}
 
+	if oss.localThreshold != nil {
+		latency := &serverselector.Latency{Latency: *oss.localThreshold}
+		selectedServers, err = latency.SelectServer(topo, selectedServers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	filteredServers := filterDeprioritizedServers(selectedServers, oss.deprioritizedServers)
 
 	return filteredServers, nil
@@ -407,6 +440,8 @@ func (op Operation) selectServer(
 		return nil, err
 	}
 
+	localThreshold, hasLocalThreshold := driverutil.LocalThreshold(ctx)
+
 	selector := op.Selector
 	if selector == nil {
 		rp := op.ReadPreference
@@ -414,10 +449,15 @@ func (op Operation) selectServer(
 			rp = readpref.Primary()
 		}
 
+		latency := defaultLocalThreshold
+		if hasLocalThreshold {
+			latency = localThreshold
+		}
+
 		selector = &serverselector.Composite{
 			Selectors: []description.ServerSelector{
 				&serverselector.ReadPref{ReadPref: rp},
-				&serverselector.Latency{Latency: defaultLocalThreshold},
+				&serverselector.Latency{Latency: latency},
 			},
 		}
 	}
@@ -426,6 +466,9 @@ func (op Operation) selectServer(
 		selector:             selector,
 		deprioritizedServers: deprioritized,
 	}
+	if hasLocalThreshold && op.Selector != nil {
+		oss.localThreshold = &localThreshold
+	}
 
 	ctx = logger.WithOperationName(ctx, op.Name)
 	ctx = logger.WithOperationID(ctx, requestID)
@@ -512,6 +555,32 @@ var memoryPool = sync.Pool{
 	},
 }
 
+// operationTimeout returns the timeout for this operation. If the deployment implements OperationTimeoutDeployment, its
+// read timeout is used for Read operations and its write timeout is used for Write operations and aggregations with
+// an output stage. Otherwise, or if the corresponding timeout is nil, op.Timeout is used.
+func (op Operation) operationTimeout() *time.Duration {
+	otd, ok := op.Deployment.(OperationTimeoutDeployment)
+	if !ok {
+		return op.Timeout
+	}
+
+	var timeout *time.Duration
+	switch {
+	case op.Type == Write || op.IsOutputAggregate:
+		timeout = otd.WriteTimeout()
+	case op.Type == Read:
+		timeout = otd.ReadTimeout()
+	}
+	if timeout == nil {
+		return op.Timeout
+	}
+	return timeout
+}
+
+// operationPermitKey is the context key that marks a context as belonging to an operation that holds an
+// OperationLimiter permit.
+type operationPermitKey struct{}
+
 // Execute runs this operation.
 func (op Operation) Execute(ctx context.Context) error {
 	err := op.Validate()
@@ -519,8 +588,23 @@ func (op Operation) Execute(ctx context.Context) error {
 		return err
 	}
 
-	ctx, cancel := csot.WithTimeout(ctx, op.Timeout)
-	defer cancel()
+	if !op.IgnoreClientTimeout {
+		var cancel context.CancelFunc
+		ctx, cancel = csot.WithTimeout(ctx, op.operationTimeout())
+		defer cancel()
+	}
+
+	// Operations executed while this one holds a permit, such as key vault queries for automatic encryption, reuse
+	// the permit so that they cannot deadlock waiting for one.
+	if limiter, ok := op.Deployment.(OperationLimiter); ok && ctx.Value(operationPermitKey{}) == nil {
+		release, err := limiter.AcquireOperation(ctx)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		ctx = context.WithValue(ctx, operationPermitKey{}, true)
+	}
 
 	if op.Client != nil {
 		if err := op.Client.StartCommand(); err != nil {
@@ -714,10 +798,34 @@ func (op Operation) Execute(ctx context.Context) error {
 			Kind:   op.Deployment.Kind(),
 		}
 
+		// If the connection supplies its own request IDs, use one of those for the wire message
+		// instead of the ID taken from the global counter.
+		if generator := conn.RequestIDGenerator; generator != nil {
+			requestID = generator.NextRequestID()
+		}
+
 		var moreToCome bool
 		var startedInfo startedInformation
 		*wm, moreToCome, startedInfo, err = op.createWireMessage(ctx, maxTimeMS, (*wm)[:0], desc, conn, requestID)
 
+		// An oversized document is always the first document of a batch. Like the server would for
+		// a document it rejects, report it as a write error and skip it if the writes are unordered.
+		var tooLarge DocumentTooLargeError
+		if errors.As(err, &tooLarge) && op.Batches != nil {
+			if isOrdered := op.Batches.IsOrdered(); isOrdered != nil && !*isOrdered {
+				operationErr.WriteErrors = append(operationErr.WriteErrors, WriteError{
+					Index:   int64(currIndex),
+					Code:    bsonObjectTooLargeCode,
+					Message: tooLarge.Error(),
+				})
+				currIndex++
+				op.Batches.AdvanceBatches(1)
+				if op.Batches.Size() > 0 {
+					continue
+				}
+				break
+			}
+		}
 		if err != nil {
 			return err
 		}
@@ -744,7 +852,8 @@ func (op Operation) Execute(ctx context.Context) error {
 		op.publishStartedEvent(ctx, startedInfo)
 
 		// compress wiremessage if allowed
-		if compressor := conn.Compressor; compressor != nil && op.canCompress(startedInfo.cmdName) {
+		if compressor := conn.Compressor; compressor != nil &&
+			(op.canCompress(startedInfo.cmdName) || op.CompressHello && isHelloCommand(startedInfo.cmdName)) {
 			b := memoryPool.Get().(*[]byte)
 			*b, err = compressor.CompressWireMessage(*wm, (*b)[:0])
 			memoryPool.Put(wm)
@@ -1082,25 +1191,35 @@ func (op Operation) readWireMessage(ctx context.Context, conn *mnet.Connection)
 		return nil, op.networkError(err)
 	}
 
-	// If we're using a streamable connection, we set its streaming state based on the moreToCome flag in the server
-	// response.
-	if streamer := conn.Streamer; streamer != nil {
-		streamer.SetStreaming(wiremessage.IsMsgMoreToCome(wm))
-	}
-
-	length, _, _, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	length, reqid, respto, opcode, rem, ok := wiremessage.ReadHeader(wm)
 	if !ok || len(wm) < int(length) {
+		if streamer := conn.Streamer; streamer != nil {
+			streamer.SetStreaming(false)
+		}
 		return nil, errors.New("malformed wire message: insufficient bytes")
 	}
 	if opcode == wiremessage.OpCompressed {
 		rawsize := length - 16 // remove header size
 		// decompress wiremessage
 		opcode, rem, err = op.decompressWireMessage(rem[:rawsize])
+		if err == nil {
+			// A checksum of the original OP_MSG is part of the compressed payload.
+			rem, err = wiremessage.RemoveMsgBodyChecksum(reqid, respto, opcode, rem)
+		}
 		if err != nil {
+			if streamer := conn.Streamer; streamer != nil {
+				streamer.SetStreaming(false)
+			}
 			return nil, err
 		}
 	}
 
+	// If we're using a streamable connection, we set its streaming state based on the moreToCome flag in the server
+	// response. The flag is read after decompressing because streamed responses may be compressed.
+	if streamer := conn.Streamer; streamer != nil {
+		streamer.SetStreaming(opcode == wiremessage.OpMsg && isMsgMoreToCome(rem))
+	}
+
 	// decode
 	res, err := op.decodeResult(opcode, rem)
 	// Update cluster/operation time and recovery tokens before handling the error to ensure we're properly updating
@@ -1232,7 +1351,7 @@ func (op Operation) createLegacyHandshakeWireMessage(
 		}
 	}
 
-	dst, err = op.addReadConcern(dst, desc)
+	dst, err = op.addReadConcern(ctx, dst, desc)
 	if err != nil {
 		return 0, dst, nil, err
 	}
@@ -1248,7 +1367,11 @@ func (op Operation) createLegacyHandshakeWireMessage(
 	}
 
 	dst = op.addClusterTime(dst, desc)
-	dst = op.addServerAPI(dst)
+	serverAPI, err := op.serverAPI(ctx)
+	if err != nil {
+		return 0, dst, nil, err
+	}
+	dst = op.addServerAPI(dst, serverAPI)
 	// If maxTimeMS is greater than 0 append it to wire message. A maxTimeMS value of 0 only explicitly
 	// specifies the default behavior of no timeout server-side.
 	if maxTimeMS > 0 {
@@ -1295,7 +1418,7 @@ func (op Operation) createMsgWireMessage(
 	if err != nil {
 		return dst, nil, err
 	}
-	dst, err = op.addReadConcern(dst, desc)
+	dst, err = op.addReadConcern(ctx, dst, desc)
 	if err != nil {
 		return dst, nil, err
 	}
@@ -1313,7 +1436,11 @@ func (op Operation) createMsgWireMessage(
 	}
 
 	dst = op.addClusterTime(dst, desc)
-	dst = op.addServerAPI(dst)
+	serverAPI, err := op.serverAPI(ctx)
+	if err != nil {
+		return dst, nil, err
+	}
+	dst = op.addServerAPI(dst, serverAPI)
 	// If maxTimeMS is greater than 0 append it to wire message. A maxTimeMS value of 0 only explicitly
 	// specifies the default behavior of no timeout server-side.
 	if maxTimeMS > 0 {
@@ -1331,6 +1458,10 @@ func (op Operation) createMsgWireMessage(
 
 	dst, _ = bsoncore.AppendDocumentEnd(dst, idx)
 
+	if err := validateStrictAPI(serverAPI, dst[idx:]); err != nil {
+		return dst, nil, err
+	}
+
 	return dst, dst[idx:], nil
 }
 
@@ -1354,13 +1485,18 @@ func (op Operation) createWireMessage(
 	var wmindex int32
 	var err error
 
+	// Reject documents that exceed the server's maxBsonObjectSize as they are added to the batch to get a clearer error
+	// than the server would return.
+	if b, ok := op.Batches.(*Batches); ok && b != nil {
+		b.maxDocumentSize = int(desc.MaxDocumentSize)
+	}
+
 	unacknowledged := op.WriteConcern != nil && !op.WriteConcern.Acknowledged()
 
 	fIdx := -1
 	isLegacy := isLegacyHandshake(op, desc)
 	switch {
 	case isLegacy:
-		requestID := wiremessage.NextRequestID()
 		wmindex, dst = wiremessage.AppendHeaderStart(dst, requestID, 0, wiremessage.OpQuery)
 		info.processedBatches, dst, info.cmd, err = op.createLegacyHandshakeWireMessage(ctx, maxTimeMS, dst, desc)
 	case op.shouldEncrypt():
@@ -1379,13 +1515,16 @@ func (op Operation) createWireMessage(
 		fIdx = len(dst)
 
 		batchOffset := -1
-		switch op.Batches.(type) {
+		switch b := op.Batches.(type) {
 		case *Batches:
 			dst, info.cmd, err = op.createMsgWireMessage(ctx, maxTimeMS, dst, desc, conn, op.CommandFn)
-			if err == nil && op.Batches != nil {
+			if err == nil && b != nil {
 				batchOffset = len(dst)
-				info.processedBatches, dst, err = op.Batches.AppendBatchSequence(dst,
-					int(desc.MaxBatchCount), int(desc.MaxMessageSize),
+				// Limit the documents in the sequence to the space left in the message after the
+				// header, the command document, and the document sequence section header.
+				maxDocsSize := int(desc.MaxMessageSize) - len(dst[wmindex:]) - b.sequenceOverhead()
+				info.processedBatches, dst, err = b.AppendBatchSequence(dst,
+					int(desc.MaxBatchCount), maxDocsSize,
 				)
 				if err != nil {
 					break
@@ -1489,9 +1628,28 @@ func (op Operation) addEncryptCommandFields(ctx context.Context, dst []byte, des
 	return n, dst, nil
 }
 
-// addServerAPI adds the relevant fields for server API specification to the wire message in dst.
-func (op Operation) addServerAPI(dst []byte) []byte {
+// serverAPI returns the server API options for the operation. A server API set on ctx with
+// WithServerAPI takes precedence over op.ServerAPI, and an apiStrict set on ctx with
+// WithServerAPIStrict takes precedence over the Strict field of either.
+func (op Operation) serverAPI(ctx context.Context) (*ServerAPIOptions, error) {
 	sa := op.ServerAPI
+	if override, ok := ServerAPIFromContext(ctx); ok {
+		sa = override
+	}
+	if strict, ok := serverAPIStrictFromContext(ctx); ok {
+		if sa == nil || sa.ServerAPIVersion == "" {
+			return nil, ErrServerAPIStrictWithoutVersion
+		}
+		// Copy the options so the client-wide options are not modified.
+		override := *sa
+		override.Strict = &strict
+		sa = &override
+	}
+	return sa, nil
+}
+
+// addServerAPI adds the relevant fields for server API specification to the wire message in dst.
+func (op Operation) addServerAPI(dst []byte, sa *ServerAPIOptions) []byte {
 	if sa == nil {
 		return dst
 	}
@@ -1520,7 +1678,7 @@ func MarshalBSONReadConcern(rc *readconcern.ReadConcern) (bson.Type, []byte, err
 	return bson.TypeEmbeddedDocument, bsoncore.BuildDocument(nil, elems), nil
 }
 
-func (op Operation) addReadConcern(dst []byte, desc description.SelectedServer) ([]byte, error) {
+func (op Operation) addReadConcern(ctx context.Context, dst []byte, desc description.SelectedServer) ([]byte, error) {
 	if op.MinimumReadConcernWireVersion > 0 && (desc.WireVersion == nil ||
 		!driverutil.VersionRangeIncludes(*desc.WireVersion, op.MinimumReadConcernWireVersion)) {
 
@@ -1554,13 +1712,23 @@ func (op Operation) addReadConcern(dst []byte, desc description.SelectedServer)
 		return dst, err
 	}
 
-	if sessionsSupported(desc.WireVersion) && client != nil {
-		if client.Consistent && client.OperationTime != nil {
+	if sessionsSupported(desc.WireVersion) {
+		var afterClusterTime *bson.Timestamp
+		if client != nil && client.Consistent && client.OperationTime != nil {
+			afterClusterTime = client.OperationTime
+		}
+		// An explicit afterClusterTime can only strengthen the guarantee of a causally consistent
+		// session, so the later of the two is sent.
+		if ts, ok := afterClusterTimeFromContext(ctx); ok && (client == nil || !client.Snapshot) &&
+			(afterClusterTime == nil || afterClusterTime.Before(ts)) {
+			afterClusterTime = &ts
+		}
+		if afterClusterTime != nil {
 			data = data[:len(data)-1] // remove the null byte
-			data = bsoncore.AppendTimestampElement(data, "afterClusterTime", client.OperationTime.T, client.OperationTime.I)
+			data = bsoncore.AppendTimestampElement(data, "afterClusterTime", afterClusterTime.T, afterClusterTime.I)
 			data, _ = bsoncore.AppendDocumentEnd(data, 0)
 		}
-		if client.Snapshot && client.SnapshotTime != nil {
+		if client != nil && client.Snapshot && client.SnapshotTime != nil {
 			data = data[:len(data)-1] // remove the null byte
 			data = bsoncore.AppendTimestampElement(data, "atClusterTime", client.SnapshotTime.T, client.SnapshotTime.I)
 			data, _ = bsoncore.AppendDocumentEnd(data, 0)
@@ -1643,7 +1811,7 @@ func (op Operation) addWriteConcern(ctx context.Context, dst []byte, desc descri
 	// > waiting forever (or until a socket timeout) if the majority write concern
 	// > cannot be satisfied.
 	var wtimeout time.Duration
-	if _, ok := ctx.Deadline(); op.Client != nil && op.Timeout == nil && !ok {
+	if _, ok := ctx.Deadline(); op.Client != nil && op.operationTimeout() == nil && !ok {
 		wtimeout = op.Client.CurrentWTimeout
 	}
 
@@ -1720,6 +1888,10 @@ func (op Operation) addClusterTime(dst []byte, desc description.SelectedServer)
 // operation's MaxTimeMS if set. If no MaxTimeMS is set on the operation, and context is
 // not a Timeout context, calculateMaxTimeMS returns 0.
 func (op Operation) calculateMaxTimeMS(ctx context.Context, rttMin time.Duration, rttStats string) (int64, error) {
+	if op.MaxTime != nil && !csot.IsTimeoutContext(ctx) {
+		// Round up so that a MaxTime of less than a millisecond is not sent as 0, which means no limit.
+		return int64((*op.MaxTime + time.Millisecond - 1) / time.Millisecond), nil
+	}
 	if op.OmitMaxTimeMS {
 		return 0, nil
 	}
@@ -1834,6 +2006,8 @@ func (op Operation) createReadPref(desc description.SelectedServer, isOpQuery bo
 		return nil, nil
 	}
 
+	hedgeEnabled := op.hedgeEnabled(rp, desc)
+
 	switch rp.Mode() {
 	case readpref.PrimaryMode:
 		if desc.Server.Kind == description.ServerKindMongos {
@@ -1857,7 +2031,7 @@ func (op Operation) createReadPref(desc description.SelectedServer, isOpQuery bo
 	case readpref.SecondaryPreferredMode:
 		_, ok := rp.MaxStaleness()
 		if desc.Server.Kind == description.ServerKindMongos && isOpQuery && !ok && len(rp.TagSets()) == 0 &&
-			rp.HedgeEnabled() == nil {
+			hedgeEnabled == nil {
 
 			return nil, nil
 		}
@@ -1890,7 +2064,7 @@ func (op Operation) createReadPref(desc description.SelectedServer, isOpQuery bo
 		doc = bsoncore.AppendInt32Element(doc, "maxStalenessSeconds", int32(d.Seconds()))
 	}
 
-	if hedgeEnabled := rp.HedgeEnabled(); hedgeEnabled != nil {
+	if hedgeEnabled != nil {
 		var hedgeIdx int32
 		hedgeIdx, doc = bsoncore.AppendDocumentElementStart(doc, "hedge")
 		doc = bsoncore.AppendBooleanElement(doc, "enabled", *hedgeEnabled)
@@ -1904,6 +2078,21 @@ func (op Operation) createReadPref(desc description.SelectedServer, isOpQuery bo
 	return doc, nil
 }
 
+// hedgeEnabled returns whether hedged reads should be requested for rp. A hedge setting on the read preference always
+// takes precedence. Otherwise, the deployment's default is used if the selected server is a mongos.
+func (op Operation) hedgeEnabled(rp *readpref.ReadPref, desc description.SelectedServer) *bool {
+	if hedgeEnabled := rp.HedgeEnabled(); hedgeEnabled != nil {
+		return hedgeEnabled
+	}
+	if desc.Server.Kind != description.ServerKindMongos {
+		return nil
+	}
+	if hd, ok := op.Deployment.(HedgeDeployment); ok {
+		return hd.HedgeEnabled()
+	}
+	return nil
+}
+
 func (op Operation) secondaryOK(desc description.SelectedServer) wiremessage.QueryFlag {
 	if desc.Kind == description.TopologyKindSingle && desc.Server.Kind != description.ServerKindMongos {
 		return wiremessage.SecondaryOK
@@ -1916,7 +2105,25 @@ func (op Operation) secondaryOK(desc description.SelectedServer) wiremessage.Que
 	return 0
 }
 
+// isMsgMoreToCome returns whether the moreToCome flag is set in the OP_MSG wm, which must not
+// include the message header.
+func isMsgMoreToCome(wm []byte) bool {
+	flags, _, ok := wiremessage.ReadMsgFlags(wm)
+	return ok && flags&wiremessage.MoreToCome == wiremessage.MoreToCome
+}
+
+// isHelloCommand returns whether cmd is the name of a hello command.
+func isHelloCommand(cmd string) bool {
+	return cmd == "hello" || cmd == handshake.LegacyHello
+}
+
 func (Operation) canCompress(cmd string) bool {
+	return CanCompress(cmd)
+}
+
+// CanCompress returns whether a command with the given name may be sent in a compressed wire
+// message. Handshake and authentication commands must never be compressed.
+func CanCompress(cmd string) bool {
 	if cmd == handshake.LegacyHello || cmd == "hello" || cmd == "saslStart" || cmd == "saslContinue" || cmd == "getnonce" || cmd == "authenticate" ||
 		cmd == "createUser" || cmd == "updateUser" || cmd == "copydbSaslStart" || cmd == "copydbgetnonce" || cmd == "copydb" {
 		return false
@@ -2102,6 +2309,7 @@ func (op Operation) publishStartedEvent(ctx context.Context, info startedInforma
 			CommandName:        info.cmdName,
 			RequestID:          int64(info.requestID),
 			ConnectionID:       info.connID,
+			Address:            info.serverAddress,
 			ServerConnectionID: info.serverConnID,
 			ServiceID:          info.serviceID,
 		}
@@ -2181,6 +2389,7 @@ func (op Operation) publishFinishedEvent(ctx context.Context, info finishedInfor
 		DatabaseName:       op.Database,
 		RequestID:          int64(info.requestID),
 		ConnectionID:       info.connID,
+		Address:            info.serverAddress,
 		Duration:           info.duration,
 		ServerConnectionID: info.serverConnID,
 		ServiceID:          info.serviceID,
@@ -50,6 +50,8 @@ type Aggregate struct {
 	customOptions            map[string]bsoncore.Value
 	timeout                  *time.Duration
 	omitMaxTimeMS            bool
+	maxTime                  *time.Duration
+	ignoreClientTimeout      bool
 
 	result driver.CursorResponse
 }
@@ -116,6 +118,8 @@ func (a *Aggregate) Execute(ctx context.Context) error {
 		Name:                           driverutil.AggregateOp,
 		Authenticator:                  a.authenticator,
 		OmitMaxTimeMS:                  a.omitMaxTimeMS,
+		MaxTime:                        a.maxTime,
+		IgnoreClientTimeout:            a.ignoreClientTimeout,
 	}.Execute(ctx)
 
 }
@@ -431,3 +435,25 @@ func (a *Aggregate) OmitMaxTimeMS(omit bool) *Aggregate {
 	a.omitMaxTimeMS = omit
 	return a
 }
+
+// MaxTime sets the maximum amount of time the server may spend executing the command. It is
+// ignored if the operation is subject to a timeout, unless IgnoreClientTimeout is set.
+func (a *Aggregate) MaxTime(maxTime *time.Duration) *Aggregate {
+	if a == nil {
+		a = new(Aggregate)
+	}
+
+	a.maxTime = maxTime
+	return a
+}
+
+// IgnoreClientTimeout prevents the timeout set by Timeout from being applied to the operation,
+// so that the value set by MaxTime is honored.
+func (a *Aggregate) IgnoreClientTimeout(ignore bool) *Aggregate {
+	if a == nil {
+		a = new(Aggregate)
+	}
+
+	a.ignoreClientTimeout = ignore
+	return a
+}
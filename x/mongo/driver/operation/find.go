@@ -63,6 +63,8 @@ type Find struct {
 	timeout             *time.Duration
 	logger              *logger.Logger
 	omitMaxTimeMS       bool
+	maxTime             *time.Duration
+	ignoreClientTimeout bool
 }
 
 // NewFind constructs and returns a new Find.
@@ -94,26 +96,28 @@ func (f *Find) Execute(ctx context.Context) error {
 	}
 
 	return driver.Operation{
-		CommandFn:         f.command,
-		ProcessResponseFn: f.processResponse,
-		RetryMode:         f.retry,
-		Type:              driver.Read,
-		Client:            f.session,
-		Clock:             f.clock,
-		CommandMonitor:    f.monitor,
-		Crypt:             f.crypt,
-		Database:          f.database,
-		Deployment:        f.deployment,
-		ReadConcern:       f.readConcern,
-		ReadPreference:    f.readPreference,
-		Selector:          f.selector,
-		Legacy:            driver.LegacyFind,
-		ServerAPI:         f.serverAPI,
-		Timeout:           f.timeout,
-		Logger:            f.logger,
-		Name:              driverutil.FindOp,
-		Authenticator:     f.authenticator,
-		OmitMaxTimeMS:     f.omitMaxTimeMS,
+		CommandFn:           f.command,
+		ProcessResponseFn:   f.processResponse,
+		RetryMode:           f.retry,
+		Type:                driver.Read,
+		Client:              f.session,
+		Clock:               f.clock,
+		CommandMonitor:      f.monitor,
+		Crypt:               f.crypt,
+		Database:            f.database,
+		Deployment:          f.deployment,
+		ReadConcern:         f.readConcern,
+		ReadPreference:      f.readPreference,
+		Selector:            f.selector,
+		Legacy:              driver.LegacyFind,
+		ServerAPI:           f.serverAPI,
+		Timeout:             f.timeout,
+		Logger:              f.logger,
+		Name:                driverutil.FindOp,
+		Authenticator:       f.authenticator,
+		OmitMaxTimeMS:       f.omitMaxTimeMS,
+		MaxTime:             f.maxTime,
+		IgnoreClientTimeout: f.ignoreClientTimeout,
 	}.Execute(ctx)
 }
 
@@ -575,3 +579,25 @@ func (f *Find) OmitMaxTimeMS(omit bool) *Find {
 	f.omitMaxTimeMS = omit
 	return f
 }
+
+// MaxTime sets the maximum amount of time the server may spend executing the command. It is
+// ignored if the operation is subject to a timeout, unless IgnoreClientTimeout is set.
+func (f *Find) MaxTime(maxTime *time.Duration) *Find {
+	if f == nil {
+		f = new(Find)
+	}
+
+	f.maxTime = maxTime
+	return f
+}
+
+// IgnoreClientTimeout prevents the timeout set by Timeout from being applied to the operation,
+// so that the value set by MaxTime is honored.
+func (f *Find) IgnoreClientTimeout(ignore bool) *Find {
+	if f == nil {
+		f = new(Find)
+	}
+
+	f.ignoreClientTimeout = ignore
+	return f
+}
@@ -49,6 +49,7 @@ type Hello struct {
 	serverAPI          *driver.ServerAPIOptions
 	loadBalanced       bool
 	omitMaxTimeMS      bool
+	compress           bool
 
 	// Fields provided by a library that wraps the Go Driver.
 	outerLibraryName     string
@@ -637,6 +638,7 @@ func (h *Hello) createOperation() driver.Operation {
 		},
 		ServerAPI:     h.serverAPI,
 		OmitMaxTimeMS: h.omitMaxTimeMS,
+		CompressHello: h.compress,
 	}
 
 	if isLegacyHandshake(h.serverAPI, h.loadBalanced) {
@@ -707,6 +709,16 @@ func (h *Hello) OmitMaxTimeMS(val bool) *Hello {
 	return h
 }
 
+// Compress allows the hello command to be compressed if the connection it is sent on has
+// negotiated a compressor. This must not be used for the initial handshake on a connection.
+func (h *Hello) Compress(val bool) *Hello {
+	if h == nil {
+		h = new(Hello)
+	}
+	h.compress = val
+	return h
+}
+
 // Authenticator sets the authenticator to use for this operation.
 func (h *Hello) Authenticator(authenticator driver.Authenticator) *Hello {
 	if h == nil {
@@ -10,15 +10,19 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/csot"
+	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
 	"go.mongodb.org/mongo-driver/v2/internal/handshake"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
 	"go.mongodb.org/mongo-driver/v2/internal/uuid"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
@@ -100,6 +104,68 @@ func TestOperation(t *testing.T) {
 				t.Error("The selectServer method should use a default selector when not specified on Operation, but it passed <nil>.")
 			}
 		})
+		t.Run("local threshold from context", func(t *testing.T) {
+			topo := description.Topology{Kind: description.TopologyKindReplicaSetNoPrimary}
+			candidates := []description.Server{
+				{Addr: "fast:27017", Kind: description.ServerKindRSSecondary, AverageRTT: 5 * time.Millisecond, AverageRTTSet: true},
+				{Addr: "medium:27017", Kind: description.ServerKindRSSecondary, AverageRTT: 10 * time.Millisecond, AverageRTTSet: true},
+				{Addr: "slow:27017", Kind: description.ServerKindRSSecondary, AverageRTT: 18 * time.Millisecond, AverageRTTSet: true},
+			}
+
+			testCases := []struct {
+				name     string
+				selector description.ServerSelector
+				ctx      context.Context
+				want     []address.Address
+			}{
+				{
+					name:     "default threshold",
+					selector: &serverselector.Latency{Latency: 15 * time.Millisecond},
+					ctx:      context.Background(),
+					want:     []address.Address{"fast:27017", "medium:27017", "slow:27017"},
+				},
+				{
+					name:     "tighter threshold narrows candidates",
+					selector: &serverselector.Latency{Latency: 15 * time.Millisecond},
+					ctx:      driverutil.WithLocalThreshold(context.Background(), 5*time.Millisecond),
+					want:     []address.Address{"fast:27017", "medium:27017"},
+				},
+				{
+					name:     "zero threshold selects fastest",
+					selector: &serverselector.Latency{Latency: 15 * time.Millisecond},
+					ctx:      driverutil.WithLocalThreshold(context.Background(), 0),
+					want:     []address.Address{"fast:27017"},
+				},
+				{
+					name: "default selector uses threshold",
+					ctx:  driverutil.WithLocalThreshold(context.Background(), 0),
+					want: []address.Address{"fast:27017"},
+				},
+			}
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					d := new(mockDeployment)
+					op := &Operation{
+						CommandFn:      func([]byte, description.SelectedServer) ([]byte, error) { return nil, nil },
+						Deployment:     d,
+						Database:       "testing",
+						Selector:       tc.selector,
+						ReadPreference: readpref.Nearest(),
+					}
+					_, err := op.selectServer(tc.ctx, 1, nil)
+					require.NoError(t, err)
+
+					selected, err := d.params.selector.SelectServer(topo, candidates)
+					require.NoError(t, err)
+
+					got := make([]address.Address, 0, len(selected))
+					for _, s := range selected {
+						got = append(got, s.Addr)
+					}
+					assert.Equal(t, tc.want, got)
+				})
+			}
+		})
 	})
 	t.Run("Validate", func(t *testing.T) {
 		cmdFn := func([]byte, description.SelectedServer) ([]byte, error) { return nil, nil }
@@ -219,13 +285,73 @@ func TestOperation(t *testing.T) {
 		}
 
 		for _, tc := range testCases {
-			got, err := Operation{ReadConcern: tc.rc}.addReadConcern(nil, description.SelectedServer{})
+			got, err := Operation{ReadConcern: tc.rc}.addReadConcern(context.Background(), nil, description.SelectedServer{})
 			noerr(t, err)
 			if !bytes.Equal(got, tc.want) {
 				t.Errorf("ReadConcern elements do not match. got %v; want %v", got, tc.want)
 			}
 		}
 	})
+	t.Run("addReadConcern afterClusterTime", func(t *testing.T) {
+		desc := description.SelectedServer{Server: description.Server{WireVersion: &description.VersionRange{Max: 21}}}
+		earlier := bson.Timestamp{T: 1, I: 1}
+		later := bson.Timestamp{T: 2, I: 1}
+		readConcern := func(ts bson.Timestamp) bsoncore.Document {
+			return bsoncore.AppendDocumentElement(nil, "readConcern", bsoncore.BuildDocument(nil,
+				bsoncore.AppendTimestampElement(nil, "afterClusterTime", ts.T, ts.I),
+			))
+		}
+		newSession := func(operationTime bson.Timestamp) *session.Client {
+			return &session.Client{Consistent: true, OperationTime: &operationTime}
+		}
+
+		testCases := []struct {
+			name string
+			ctx  context.Context
+			op   Operation
+			want bsoncore.Document
+		}{
+			{
+				name: "context",
+				ctx:  WithAfterClusterTime(context.Background(), earlier),
+				op:   Operation{ReadConcern: &readconcern.ReadConcern{}},
+				want: readConcern(earlier),
+			},
+			{
+				name: "not set",
+				ctx:  context.Background(),
+				op:   Operation{ReadConcern: &readconcern.ReadConcern{}},
+				want: nil,
+			},
+			{
+				name: "no read concern",
+				ctx:  WithAfterClusterTime(context.Background(), later),
+				op:   Operation{},
+				want: nil,
+			},
+			{
+				name: "later than session operation time",
+				ctx:  WithAfterClusterTime(context.Background(), later),
+				op:   Operation{ReadConcern: &readconcern.ReadConcern{}, Client: newSession(earlier)},
+				want: readConcern(later),
+			},
+			{
+				name: "earlier than session operation time",
+				ctx:  WithAfterClusterTime(context.Background(), earlier),
+				op:   Operation{ReadConcern: &readconcern.ReadConcern{}, Client: newSession(later)},
+				want: readConcern(later),
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				got, err := tc.op.addReadConcern(tc.ctx, nil, desc)
+				noerr(t, err)
+				if !bytes.Equal(got, tc.want) {
+					t.Errorf("ReadConcern elements do not match. got %v; want %v", got, tc.want)
+				}
+			})
+		}
+	})
 	t.Run("addWriteConcern", func(t *testing.T) {
 		want := bsoncore.AppendDocumentElement(nil, "writeConcern", bsoncore.BuildDocumentFromElements(
 			nil, bsoncore.AppendStringElement(nil, "w", "majority"),
@@ -269,6 +395,124 @@ func TestOperation(t *testing.T) {
 			}
 		})
 	})
+	t.Run("addServerAPI", func(t *testing.T) {
+		clientAPI := NewServerAPIOptions("1").SetStrict(true)
+		overrideAPI := NewServerAPIOptions("2").SetDeprecationErrors(true)
+
+		testCases := []struct {
+			name      string
+			ctx       context.Context
+			serverAPI *ServerAPIOptions
+			want      []byte
+			wantErr   error
+		}{
+			{
+				name:      "operation server API",
+				ctx:       context.Background(),
+				serverAPI: clientAPI,
+				want: bsoncore.AppendBooleanElement(
+					bsoncore.AppendStringElement(nil, "apiVersion", "1"),
+					"apiStrict", true),
+			},
+			{
+				name:      "context override",
+				ctx:       WithServerAPI(context.Background(), overrideAPI),
+				serverAPI: clientAPI,
+				want: bsoncore.AppendBooleanElement(
+					bsoncore.AppendStringElement(nil, "apiVersion", "2"),
+					"apiDeprecationErrors", true),
+			},
+			{
+				name:      "context override with no server API",
+				ctx:       WithServerAPI(context.Background(), nil),
+				serverAPI: clientAPI,
+				want:      nil,
+			},
+			{
+				name:      "strict disabled for the operation",
+				ctx:       WithServerAPIStrict(context.Background(), false),
+				serverAPI: clientAPI,
+				want: bsoncore.AppendBooleanElement(
+					bsoncore.AppendStringElement(nil, "apiVersion", "1"),
+					"apiStrict", false),
+			},
+			{
+				name:      "strict enabled for the operation",
+				ctx:       WithServerAPIStrict(context.Background(), true),
+				serverAPI: NewServerAPIOptions("1"),
+				want: bsoncore.AppendBooleanElement(
+					bsoncore.AppendStringElement(nil, "apiVersion", "1"),
+					"apiStrict", true),
+			},
+			{
+				name:      "strict override applied to context server API",
+				ctx:       WithServerAPIStrict(WithServerAPI(context.Background(), overrideAPI), true),
+				serverAPI: clientAPI,
+				want: bsoncore.AppendBooleanElement(
+					bsoncore.AppendBooleanElement(
+						bsoncore.AppendStringElement(nil, "apiVersion", "2"),
+						"apiStrict", true),
+					"apiDeprecationErrors", true),
+			},
+			{
+				name:      "strict override without server API",
+				ctx:       WithServerAPIStrict(context.Background(), true),
+				serverAPI: nil,
+				wantErr:   ErrServerAPIStrictWithoutVersion,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				op := Operation{ServerAPI: tc.serverAPI}
+				sa, err := op.serverAPI(tc.ctx)
+				if tc.wantErr != nil {
+					assert.ErrorIs(t, err, tc.wantErr)
+					return
+				}
+				require.NoError(t, err, "serverAPI error")
+				assert.Equal(t, tc.want, op.addServerAPI(nil, sa))
+			})
+		}
+
+		// The override applies to a single operation and does not modify the
+		// client-wide options.
+		assert.True(t, *clientAPI.Strict, "expected the client server API to be unchanged")
+		assert.Nil(t, overrideAPI.Strict, "expected the context server API to be unchanged")
+	})
+	t.Run("validateStrictAPI", func(t *testing.T) {
+		cmd := bsoncore.NewDocumentBuilder().
+			AppendString("find", "coll").
+			AppendBoolean("oplogReplay", true).
+			Build()
+
+		testCases := []struct {
+			name      string
+			serverAPI *ServerAPIOptions
+			want      error
+		}{
+			{
+				name:      "strict",
+				serverAPI: NewServerAPIOptions("1").SetStrict(true),
+				want:      StrictAPIError{Command: "find", Option: "oplogReplay"},
+			},
+			{
+				name:      "non-strict",
+				serverAPI: NewServerAPIOptions("1").SetStrict(false),
+				want:      nil,
+			},
+			{
+				name:      "no server API",
+				serverAPI: nil,
+				want:      nil,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				err := validateStrictAPI(tc.serverAPI, cmd)
+				assert.Equal(t, tc.want, err)
+			})
+		}
+	})
 	t.Run("calculateMaxTimeMS", func(t *testing.T) {
 		var (
 			timeout  = 5 * time.Second
@@ -326,6 +570,106 @@ func TestOperation(t *testing.T) {
 			})
 		}
 	})
+	t.Run("calculateMaxTimeMS with MaxTime", func(t *testing.T) {
+		var (
+			maxTime     = 90 * time.Second
+			subMillis   = 400 * time.Microsecond
+			zeroTimeout = time.Duration(0)
+		)
+
+		// A zero client-level timeout marks the Context as a timeout Context without setting a deadline.
+		clientLevelCtx, cancel := csot.WithTimeout(context.Background(), &zeroTimeout)
+		defer cancel()
+
+		deadlineCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		testCases := []struct {
+			name string
+			op   Operation
+			ctx  context.Context
+			want int64
+		}{
+			{
+				name: "MaxTime is sent without timeout",
+				op:   Operation{MaxTime: &maxTime},
+				ctx:  context.Background(),
+				want: 90000,
+			},
+			{
+				name: "sub millisecond MaxTime should round up",
+				op:   Operation{MaxTime: &subMillis},
+				ctx:  context.Background(),
+				want: 1,
+			},
+			{
+				name: "MaxTime is sent when OmitMaxTimeMS is set",
+				op:   Operation{MaxTime: &maxTime, OmitMaxTimeMS: true},
+				ctx:  context.Background(),
+				want: 90000,
+			},
+			{
+				name: "MaxTime is ignored with client-level timeout",
+				op:   Operation{MaxTime: &maxTime},
+				ctx:  clientLevelCtx,
+				want: 0,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := tc.op.calculateMaxTimeMS(tc.ctx, 0, "")
+				require.NoError(t, err, "calculateMaxTimeMS error")
+				assert.Equal(t, tc.want, got, "unexpected maxTimeMS")
+			})
+		}
+		t.Run("context deadline takes precedence", func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Operation{MaxTime: &maxTime}.calculateMaxTimeMS(deadlineCtx, 0, "")
+			require.NoError(t, err, "calculateMaxTimeMS error")
+			assert.LessOrEqual(t, got, int64(5000), "expected maxTimeMS to be calculated from the deadline")
+		})
+	})
+	t.Run("IgnoreClientTimeout", func(t *testing.T) {
+		timeout := time.Second
+		maxTime := time.Minute
+
+		d := new(mockDeployment)
+		d.returns.server = mockServer{
+			conn:       mnet.NewConnection(&mockConnection{}),
+			rttMonitor: mockRTTMonitor{},
+		}
+
+		var cmd bsoncore.Document
+		op := Operation{
+			CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+				return bsoncore.AppendInt32Element(dst, "ping", 1), nil
+			},
+			Database:   "admin",
+			Deployment: d,
+			Timeout:    &timeout,
+			MaxTime:    &maxTime,
+			CommandMonitor: &event.CommandMonitor{
+				Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+					cmd = bsoncore.Document(evt.Command)
+				},
+			},
+		}
+
+		_ = op.Execute(context.Background())
+		val, err := cmd.LookupErr("maxTimeMS")
+		require.NoError(t, err, "expected maxTimeMS to be sent")
+		assert.LessOrEqual(t, val.Int64(), int64(1000), "expected maxTimeMS to be calculated from Timeout")
+
+		op.IgnoreClientTimeout = true
+		_ = op.Execute(context.Background())
+		val, err = cmd.LookupErr("maxTimeMS")
+		require.NoError(t, err, "expected maxTimeMS to be sent")
+		assert.Equal(t, int64(60000), val.Int64(), "expected maxTimeMS to be MaxTime")
+	})
 	t.Run("updateClusterTimes", func(t *testing.T) {
 		clustertime := bsoncore.BuildDocumentFromElements(nil,
 			bsoncore.AppendDocumentElement(nil, "$clusterTime", bsoncore.BuildDocumentFromElements(nil,
@@ -509,6 +853,56 @@ func TestOperation(t *testing.T) {
 			})
 		}
 	})
+	t.Run("createReadPref with deployment hedge default", func(t *testing.T) {
+		hedge := func(enabled bool) bsoncore.Document {
+			return bsoncore.BuildDocumentFromElements(nil,
+				bsoncore.AppendStringElement(nil, "mode", "secondaryPreferred"),
+				bsoncore.AppendDocumentElement(nil, "hedge", bsoncore.BuildDocumentFromElements(nil,
+					bsoncore.AppendBooleanElement(nil, "enabled", enabled),
+				)),
+			)
+		}
+		rpSecondaryPreferred := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "mode", "secondaryPreferred"))
+
+		enabled := true
+		deployment := &hedgeDeployment{hedgeEnabled: &enabled}
+
+		testCases := []struct {
+			name       string
+			rp         *readpref.ReadPref
+			serverKind description.ServerKind
+			topoKind   description.TopologyKind
+			opQuery    bool
+			want       bsoncore.Document
+		}{
+			{"secondaryPreferred/mongos", readpref.SecondaryPreferred(), description.ServerKindMongos, description.TopologyKindSharded, false, hedge(true)},
+			{"secondaryPreferred/mongos/opquery", readpref.SecondaryPreferred(), description.ServerKindMongos, description.TopologyKindSharded, true, hedge(true)},
+			{"primary/mongos", readpref.Primary(), description.ServerKindMongos, description.TopologyKindSharded, false, nil},
+			{"secondaryPreferred/secondary", readpref.SecondaryPreferred(), description.ServerKindRSSecondary, description.TopologyKindReplicaSet, false, rpSecondaryPreferred},
+			{
+				"read preference overrides deployment",
+				readpref.SecondaryPreferred(readpref.WithHedgeEnabled(false)),
+				description.ServerKindMongos,
+				description.TopologyKindSharded,
+				false,
+				hedge(false),
+			},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				desc := description.SelectedServer{Kind: tc.topoKind, Server: description.Server{Kind: tc.serverKind}}
+				got, err := Operation{ReadPreference: tc.rp, Deployment: deployment}.createReadPref(desc, tc.opQuery)
+				if err != nil {
+					t.Fatalf("error creating read pref: %v", err)
+				}
+				if !bytes.Equal(got, tc.want) {
+					t.Errorf("Returned documents do not match. got %v; want %v", got, tc.want)
+				}
+			})
+		}
+	})
 	t.Run("secondaryOK", func(t *testing.T) {
 		t.Run("description.SelectedServer", func(t *testing.T) {
 			want := wiremessage.SecondaryOK
@@ -600,6 +994,64 @@ func TestOperation(t *testing.T) {
 		assert.Nil(t, err, "ExecuteExhaust error: %v", err)
 		assert.True(t, conn.CurrentlyStreaming(), "expected CurrentlyStreaming to be true")
 	})
+	t.Run("compressed streaming hello", func(t *testing.T) {
+		serverResponseDoc := bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 1).
+			AppendBoolean("isWritablePrimary", true).
+			Build()
+		streamingResponse := compressSnappyWireMessage(t, createExhaustServerResponse(serverResponseDoc, true))
+
+		conn := &compressorMockConnection{
+			t: t,
+			mockConnection: &mockConnection{
+				rDesc: description.Server{
+					WireVersion: &description.VersionRange{
+						Max: 21,
+					},
+				},
+				rReadWM:    streamingResponse,
+				rCanStream: true,
+			},
+		}
+		mnetconn := mnet.NewConnection(conn)
+
+		var res bsoncore.Document
+		op := Operation{
+			CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+				return bsoncore.AppendInt32Element(dst, "hello", 1), nil
+			},
+			Database:   "admin",
+			Deployment: SingleConnectionDeployment{C: mnetconn},
+			ProcessResponseFn: func(_ context.Context, resp bsoncore.Document, _ ResponseInfo) error {
+				res = resp
+				return nil
+			},
+		}
+
+		// Hello commands are not compressed unless CompressHello is set.
+		err := op.Execute(context.TODO())
+		require.NoError(t, err, "Execute error")
+		_, _, _, opcode, _, _ := wiremessage.ReadHeader(conn.pWriteWM)
+		assert.Equal(t, wiremessage.OpMsg, opcode, "expected hello not to be compressed")
+
+		op.CompressHello = true
+		err = op.Execute(context.TODO())
+		require.NoError(t, err, "Execute error")
+		_, _, _, opcode, _, _ = wiremessage.ReadHeader(conn.pWriteWM)
+		assert.Equal(t, wiremessage.OpCompressed, opcode, "expected hello to be compressed")
+
+		// The moreToCome flag is read from the decompressed response, so the connection should be streaming.
+		assert.True(t, conn.CurrentlyStreaming(), "expected CurrentlyStreaming to be true")
+		assert.Equal(t, serverResponseDoc, res)
+
+		// Read the next compressed streamed response.
+		res = nil
+		conn.rReadWM = streamingResponse
+		err = op.ExecuteExhaust(context.TODO(), mnetconn)
+		require.NoError(t, err, "ExecuteExhaust error")
+		assert.True(t, conn.CurrentlyStreaming(), "expected CurrentlyStreaming to be true")
+		assert.Equal(t, serverResponseDoc, res)
+	})
 	t.Run("context deadline exceeded not marked as TransientTransactionError", func(t *testing.T) {
 		conn := mnet.NewConnection(&mockConnection{})
 
@@ -673,6 +1125,74 @@ func TestOperation(t *testing.T) {
 		assert.ErrorIs(t, err, ErrDeadlineWouldBeExceeded)
 		assert.ErrorIs(t, err, context.DeadlineExceeded)
 	})
+	t.Run("OperationLimiter", func(t *testing.T) {
+		selectErr := errors.New("select error")
+		newOp := func(d Deployment) Operation {
+			return Operation{
+				Database:   "foobar",
+				Deployment: d,
+				CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+					return dst, nil
+				},
+			}
+		}
+
+		t.Run("holds a permit while executing", func(t *testing.T) {
+			d := &mockLimitedDeployment{mockDeployment: new(mockDeployment)}
+			d.returns.err = selectErr
+
+			err := newOp(d).Execute(context.Background())
+			assert.ErrorIs(t, err, selectErr)
+			assert.Equal(t, 1, d.acquired, "expected one permit to be acquired")
+			assert.Equal(t, 1, d.released, "expected the permit to be released")
+		})
+		t.Run("acquire error", func(t *testing.T) {
+			acquireErr := errors.New("acquire error")
+			d := &mockLimitedDeployment{mockDeployment: new(mockDeployment), err: acquireErr}
+			d.returns.err = selectErr
+
+			err := newOp(d).Execute(context.Background())
+			assert.ErrorIs(t, err, acquireErr)
+		})
+		t.Run("nested operations reuse the permit", func(t *testing.T) {
+			d := &mockLimitedDeployment{mockDeployment: new(mockDeployment)}
+			d.returns.err = selectErr
+
+			ctx := context.WithValue(context.Background(), operationPermitKey{}, true)
+			err := newOp(d).Execute(ctx)
+			assert.ErrorIs(t, err, selectErr)
+			assert.Equal(t, 0, d.acquired, "expected no permit to be acquired")
+		})
+	})
+	t.Run("uses connection request ID generator", func(t *testing.T) {
+		serverResponseDoc := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 1),
+		)
+		conn := &mockRequestIDConnection{
+			mockConnection: &mockConnection{
+				rDesc:   description.Server{WireVersion: &description.VersionRange{Max: 6}},
+				rReadWM: createExhaustServerResponse(serverResponseDoc, false),
+			},
+			nextID: 41,
+		}
+
+		op := Operation{
+			Database:   "foobar",
+			Deployment: SingleConnectionDeployment{C: mnet.NewConnection(conn)},
+			CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+				return bsoncore.AppendInt32Element(dst, "ping", 1), nil
+			},
+		}
+
+		for _, want := range []int32{42, 43} {
+			err := op.Execute(context.Background())
+			assert.Nil(t, err, "Execute error: %v", err)
+
+			_, requestID, _, _, _, ok := wiremessage.ReadHeader(conn.pWriteWM)
+			assert.True(t, ok, "could not read wm header")
+			assert.Equal(t, want, requestID, "expected request ID %d, got %d", want, requestID)
+		}
+	})
 }
 
 func createExhaustServerResponse(response bsoncore.Document, moreToCome bool) []byte {
@@ -688,6 +1208,24 @@ func createExhaustServerResponse(response bsoncore.Document, moreToCome bool) []
 	return bsoncore.UpdateLength(wm, idx, int32(len(wm)))
 }
 
+// compressSnappyWireMessage compresses wm into an OP_COMPRESSED wire message using snappy.
+func compressSnappyWireMessage(t *testing.T, wm []byte) []byte {
+	t.Helper()
+
+	_, reqid, respto, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	require.True(t, ok, "could not read wm header")
+
+	compressed, err := CompressPayload(rem, CompressionOpts{Compressor: wiremessage.CompressorSnappy})
+	require.NoError(t, err, "CompressPayload error")
+
+	idx, dst := wiremessage.AppendHeaderStart(nil, reqid, respto, wiremessage.OpCompressed)
+	dst = wiremessage.AppendCompressedOriginalOpCode(dst, opcode)
+	dst = wiremessage.AppendCompressedUncompressedSize(dst, int32(len(rem)))
+	dst = wiremessage.AppendCompressedCompressorID(dst, wiremessage.CompressorSnappy)
+	dst = wiremessage.AppendCompressedCompressedMessage(dst, compressed)
+	return bsoncore.UpdateLength(dst, idx, int32(len(dst[idx:])))
+}
+
 func assertExhaustAllowedSet(t *testing.T, wm []byte, expected bool) {
 	t.Helper()
 	_, _, _, _, wm, ok := wiremessage.ReadHeader(wm)
@@ -728,6 +1266,21 @@ func (m *mockDeployment) GetServerSelectionTimeout() time.Duration {
 
 func (m *mockDeployment) Kind() description.TopologyKind { return m.returns.kind }
 
+type mockLimitedDeployment struct {
+	*mockDeployment
+	err      error
+	acquired int
+	released int
+}
+
+func (m *mockLimitedDeployment) AcquireOperation(context.Context) (func(), error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.acquired++
+	return func() { m.released++ }, nil
+}
+
 type mockServerSelector struct{}
 
 func (m *mockServerSelector) SelectServer(description.Topology, []description.Server) ([]description.Server, error) {
@@ -757,6 +1310,16 @@ func (mrm mockRTTMonitor) EWMA() time.Duration { return mrm.ewma }
 func (mrm mockRTTMonitor) Min() time.Duration  { return mrm.min }
 func (mrm mockRTTMonitor) Stats() string       { return mrm.stats }
 
+// compressorMockConnection is a mockConnection that compresses wire messages with snappy.
+type compressorMockConnection struct {
+	*mockConnection
+	t *testing.T
+}
+
+func (c *compressorMockConnection) CompressWireMessage(src, dst []byte) ([]byte, error) {
+	return append(dst, compressSnappyWireMessage(c.t, src)...), nil
+}
+
 type mockConnection struct {
 	// parameters
 	pWriteWM []byte
@@ -797,6 +1360,28 @@ func (m *mockConnection) Read(_ context.Context) ([]byte, error) {
 	return m.rReadWM, m.rReadErr
 }
 
+// recordingConnection is a mockConnection that records every wire message written to it.
+type recordingConnection struct {
+	*mockConnection
+	written [][]byte
+}
+
+func (c *recordingConnection) Write(_ context.Context, wm []byte) error {
+	c.written = append(c.written, append([]byte(nil), wm...))
+	return nil
+}
+
+// mockRequestIDConnection is a mockConnection that generates request IDs from a deterministic counter.
+type mockRequestIDConnection struct {
+	*mockConnection
+	nextID int32
+}
+
+func (m *mockRequestIDConnection) NextRequestID() int32 {
+	m.nextID++
+	return m.nextID
+}
+
 type retryableError struct {
 	error
 }
@@ -1063,3 +1648,366 @@ func TestMarshalBSONWriteConcern(t *testing.T) {
 		})
 	}
 }
+
+// timeoutDeployment is a mockDeployment that provides read and write timeouts and records the deadline of the Context
+// passed to SelectServer.
+type timeoutDeployment struct {
+	mockDeployment
+	readTimeout  *time.Duration
+	writeTimeout *time.Duration
+	deadline     time.Time
+	hasDeadline  bool
+}
+
+func (d *timeoutDeployment) SelectServer(ctx context.Context, _ description.ServerSelector) (Server, error) {
+	d.deadline, d.hasDeadline = ctx.Deadline()
+	return nil, errors.New("no server")
+}
+
+func (d *timeoutDeployment) ReadTimeout() *time.Duration  { return d.readTimeout }
+func (d *timeoutDeployment) WriteTimeout() *time.Duration { return d.writeTimeout }
+
+// hedgeDeployment is a mockDeployment that provides a default for hedged reads.
+type hedgeDeployment struct {
+	mockDeployment
+	hedgeEnabled *bool
+}
+
+func (d *hedgeDeployment) HedgeEnabled() *bool { return d.hedgeEnabled }
+
+func TestOperationTimeout(t *testing.T) {
+	t.Parallel()
+
+	readTimeout := time.Minute
+	writeTimeout := time.Hour
+	timeout := 24 * time.Hour
+
+	testCases := []struct {
+		name              string
+		typ               Type
+		isOutputAggregate bool
+		readTimeout       *time.Duration
+		writeTimeout      *time.Duration
+		timeout           *time.Duration
+		want              *time.Duration
+	}{
+		{
+			name:         "read uses read timeout",
+			typ:          Read,
+			readTimeout:  &readTimeout,
+			writeTimeout: &writeTimeout,
+			timeout:      &timeout,
+			want:         &readTimeout,
+		},
+		{
+			name:         "write uses write timeout",
+			typ:          Write,
+			readTimeout:  &readTimeout,
+			writeTimeout: &writeTimeout,
+			timeout:      &timeout,
+			want:         &writeTimeout,
+		},
+		{
+			name:              "output aggregate uses write timeout",
+			typ:               Read,
+			isOutputAggregate: true,
+			readTimeout:       &readTimeout,
+			writeTimeout:      &writeTimeout,
+			timeout:           &timeout,
+			want:              &writeTimeout,
+		},
+		{
+			name:         "untyped operation uses timeout",
+			readTimeout:  &readTimeout,
+			writeTimeout: &writeTimeout,
+			timeout:      &timeout,
+			want:         &timeout,
+		},
+		{
+			name:         "read falls back to timeout",
+			typ:          Read,
+			writeTimeout: &writeTimeout,
+			timeout:      &timeout,
+			want:         &timeout,
+		},
+		{
+			name:        "write falls back to timeout",
+			typ:         Write,
+			readTimeout: &readTimeout,
+			timeout:     &timeout,
+			want:        &timeout,
+		},
+		{
+			name: "no timeouts",
+			typ:  Read,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &timeoutDeployment{readTimeout: tc.readTimeout, writeTimeout: tc.writeTimeout}
+			op := Operation{
+				CommandFn:         func([]byte, description.SelectedServer) ([]byte, error) { return nil, nil },
+				Database:          "testing",
+				Deployment:        d,
+				Type:              tc.typ,
+				IsOutputAggregate: tc.isOutputAggregate,
+				Timeout:           tc.timeout,
+			}
+
+			assert.Equal(t, tc.want, op.operationTimeout(), "unexpected operation timeout")
+
+			start := time.Now()
+			_ = op.Execute(context.Background())
+
+			if tc.want == nil {
+				assert.False(t, d.hasDeadline, "expected no deadline")
+				return
+			}
+			require.True(t, d.hasDeadline, "expected a deadline")
+			remaining := d.deadline.Sub(start)
+			assert.True(t, remaining >= *tc.want && remaining < *tc.want+time.Second,
+				"expected deadline about %v after start, got %v", *tc.want, remaining)
+		})
+	}
+	t.Run("context deadline takes precedence", func(t *testing.T) {
+		t.Parallel()
+
+		d := &timeoutDeployment{readTimeout: &readTimeout}
+		op := Operation{
+			CommandFn:  func([]byte, description.SelectedServer) ([]byte, error) { return nil, nil },
+			Database:   "testing",
+			Deployment: d,
+			Type:       Read,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		want, _ := ctx.Deadline()
+
+		_ = op.Execute(ctx)
+		assert.Equal(t, want, d.deadline, "expected the Context deadline to be used")
+	})
+}
+
+func TestCreateWireMessageBatches(t *testing.T) {
+	t.Parallel()
+
+	docs := make([]bsoncore.Document, 10)
+	for i := range docs {
+		docs[i] = bsoncore.NewDocumentBuilder().
+			AppendInt32("_id", int32(i)).
+			AppendString("payload", strings.Repeat("x", 100)).
+			Build()
+	}
+
+	newInsert := func() Operation {
+		return Operation{
+			Database: "db",
+			CommandFn: func(dst []byte, _ description.SelectedServer) ([]byte, error) {
+				return bsoncore.AppendStringElement(dst, "insert", "coll"), nil
+			},
+			Batches: &Batches{Identifier: "documents", Documents: docs},
+		}
+	}
+
+	// readSections returns the command document and the documents in the "documents" document
+	// sequence of an OP_MSG wire message.
+	readSections := func(t *testing.T, wm []byte) (bsoncore.Document, []bsoncore.Document) {
+		t.Helper()
+
+		length, _, _, opcode, rem, ok := wiremessage.ReadHeader(wm)
+		require.True(t, ok, "could not read header")
+		assert.Equal(t, wiremessage.OpMsg, opcode, "expected OP_MSG")
+		assert.Equal(t, int32(len(wm)), length, "unexpected message length")
+		_, rem, ok = wiremessage.ReadMsgFlags(rem)
+		require.True(t, ok, "could not read flags")
+
+		var cmd bsoncore.Document
+		var seq []bsoncore.Document
+		for len(rem) > 0 {
+			var stype wiremessage.SectionType
+			stype, rem, ok = wiremessage.ReadMsgSectionType(rem)
+			require.True(t, ok, "could not read section type")
+			switch stype {
+			case wiremessage.SingleDocument:
+				cmd, rem, ok = wiremessage.ReadMsgSectionSingleDocument(rem)
+				require.True(t, ok, "could not read command document")
+			case wiremessage.DocumentSequence:
+				var identifier string
+				identifier, seq, rem, ok = wiremessage.ReadMsgSectionDocumentSequence(rem)
+				require.True(t, ok, "could not read document sequence")
+				assert.Equal(t, "documents", identifier, "unexpected document sequence identifier")
+			}
+		}
+		return cmd, seq
+	}
+
+	t.Run("uses a document sequence", func(t *testing.T) {
+		t.Parallel()
+
+		desc := description.SelectedServer{Server: description.Server{
+			MaxBatchCount:  100,
+			MaxMessageSize: 48000000,
+			WireVersion:    &description.VersionRange{Max: 21},
+		}}
+		conn := mnet.NewConnection(&mockConnection{})
+
+		wm, _, info, err := newInsert().createWireMessage(context.Background(), 0, nil, desc, conn, 1)
+		require.NoError(t, err, "createWireMessage error")
+		assert.Equal(t, len(docs), info.processedBatches, "expected all documents in one batch")
+
+		cmd, seq := readSections(t, wm)
+		_, err = cmd.LookupErr("documents")
+		assert.Error(t, err, "expected documents not to be embedded in the command document")
+		assert.Equal(t, docs, seq, "unexpected documents in document sequence")
+	})
+	t.Run("respects maxWriteBatchSize", func(t *testing.T) {
+		t.Parallel()
+
+		desc := description.SelectedServer{Server: description.Server{
+			MaxBatchCount:  4,
+			MaxMessageSize: 48000000,
+			WireVersion:    &description.VersionRange{Max: 21},
+		}}
+		conn := mnet.NewConnection(&mockConnection{})
+
+		wm, _, info, err := newInsert().createWireMessage(context.Background(), 0, nil, desc, conn, 1)
+		require.NoError(t, err, "createWireMessage error")
+		assert.Equal(t, 4, info.processedBatches, "unexpected number of documents in batch")
+
+		_, seq := readSections(t, wm)
+		assert.Equal(t, docs[:4], seq, "unexpected documents in document sequence")
+	})
+	t.Run("respects maxMessageSize", func(t *testing.T) {
+		t.Parallel()
+
+		// Allow room for exactly three documents after the header, the command document, and the
+		// document sequence section header.
+		op := newInsert()
+		unlimited := description.SelectedServer{Server: description.Server{
+			MaxBatchCount:  100,
+			MaxMessageSize: 48000000,
+			WireVersion:    &description.VersionRange{Max: 21},
+		}}
+		conn := mnet.NewConnection(&mockConnection{})
+		wm, _, _, err := op.createWireMessage(context.Background(), 0, nil, unlimited, conn, 1)
+		require.NoError(t, err, "createWireMessage error")
+		overhead := len(wm)
+		for _, doc := range docs {
+			overhead -= len(doc)
+		}
+		maxMessageSize := overhead + 3*len(docs[0])
+
+		desc := unlimited
+		desc.MaxMessageSize = uint32(maxMessageSize)
+		wm, _, info, err := op.createWireMessage(context.Background(), 0, nil, desc, conn, 1)
+		require.NoError(t, err, "createWireMessage error")
+		assert.Equal(t, 3, info.processedBatches, "unexpected number of documents in batch")
+		assert.Equal(t, maxMessageSize, len(wm), "expected message to fill maxMessageSize")
+
+		desc.MaxMessageSize = uint32(maxMessageSize - 1)
+		wm, _, info, err = op.createWireMessage(context.Background(), 0, nil, desc, conn, 1)
+		require.NoError(t, err, "createWireMessage error")
+		assert.Equal(t, 2, info.processedBatches, "unexpected number of documents in batch")
+		assert.LessOrEqual(t, len(wm), maxMessageSize-1, "expected message to be within maxMessageSize")
+	})
+	t.Run("rejects documents larger than maxBsonObjectSize", func(t *testing.T) {
+		t.Parallel()
+
+		large := bsoncore.NewDocumentBuilder().
+			AppendString("payload", strings.Repeat("x", 200)).
+			Build()
+		op := newInsert()
+		op.Batches = &Batches{Identifier: "documents", Documents: []bsoncore.Document{docs[0], large, docs[1]}}
+
+		desc := description.SelectedServer{Server: description.Server{
+			MaxBatchCount:   100,
+			MaxDocumentSize: uint32(len(large) - 1),
+			MaxMessageSize:  48000000,
+			WireVersion:     &description.VersionRange{Max: 21},
+		}}
+		conn := mnet.NewConnection(&mockConnection{})
+
+		// The batch ends before the oversized document.
+		wm, _, info, err := op.createWireMessage(context.Background(), 0, nil, desc, conn, 1)
+		require.NoError(t, err, "createWireMessage error")
+		assert.Equal(t, 1, info.processedBatches, "unexpected number of documents in batch")
+		_, seq := readSections(t, wm)
+		assert.Equal(t, docs[:1], seq, "unexpected documents in document sequence")
+
+		op.Batches.AdvanceBatches(info.processedBatches)
+		_, _, _, err = op.createWireMessage(context.Background(), 0, nil, desc, conn, 1)
+		require.Error(t, err, "expected createWireMessage to reject the oversized document")
+		assert.ErrorIs(t, err, ErrDocumentTooLarge, "expected error to match ErrDocumentTooLarge")
+
+		var tooLarge DocumentTooLargeError
+		require.True(t, errors.As(err, &tooLarge), "expected a DocumentTooLargeError, got %T", err)
+		assert.Equal(t, len(large), tooLarge.Size, "unexpected document size")
+		assert.Equal(t, len(large)-1, tooLarge.MaxSize, "unexpected size limit")
+		assert.Contains(t, err.Error(), "document exceeds maxBsonObjectSize", "unexpected error message")
+	})
+	t.Run("oversized documents", func(t *testing.T) {
+		t.Parallel()
+
+		large := bsoncore.NewDocumentBuilder().
+			AppendString("payload", strings.Repeat("x", 200)).
+			Build()
+		reply := createExhaustServerResponse(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "ok", 1),
+		), false)
+
+		execute := func(t *testing.T, ordered bool) ([]bsoncore.Document, error) {
+			t.Helper()
+
+			conn := &recordingConnection{mockConnection: &mockConnection{
+				rDesc: description.Server{
+					MaxBatchCount:   100,
+					MaxDocumentSize: uint32(len(large) - 1),
+					MaxMessageSize:  48000000,
+					WireVersion:     &description.VersionRange{Max: 21},
+				},
+				rReadWM: reply,
+			}}
+			op := newInsert()
+			op.Deployment = SingleConnectionDeployment{C: mnet.NewConnection(conn)}
+			op.Batches = &Batches{
+				Identifier: "documents",
+				Documents:  []bsoncore.Document{docs[0], large, docs[1]},
+				Ordered:    &ordered,
+			}
+
+			err := op.Execute(context.Background())
+
+			var sent []bsoncore.Document
+			for _, wm := range conn.written {
+				_, seq := readSections(t, wm)
+				sent = append(sent, seq...)
+			}
+			return sent, err
+		}
+
+		t.Run("ordered", func(t *testing.T) {
+			t.Parallel()
+
+			sent, err := execute(t, true)
+			assert.ErrorIs(t, err, ErrDocumentTooLarge, "expected error to match ErrDocumentTooLarge")
+			assert.Equal(t, docs[:1], sent, "expected the documents preceding the oversized one to be sent")
+		})
+		t.Run("unordered", func(t *testing.T) {
+			t.Parallel()
+
+			sent, err := execute(t, false)
+			var wce WriteCommandError
+			require.True(t, errors.As(err, &wce), "expected a WriteCommandError, got %T", err)
+			require.Len(t, wce.WriteErrors, 1, "expected one write error")
+			assert.Equal(t, int64(1), wce.WriteErrors[0].Index, "unexpected write error index")
+			assert.Equal(t, bsonObjectTooLargeCode, wce.WriteErrors[0].Code, "unexpected write error code")
+			assert.Equal(t, docs[:2], sent, "expected all other documents to be sent")
+		})
+	})
+}
@@ -6,6 +6,14 @@
 
 package driver
 
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
 // TestServerAPIVersion is the most recent, stable variant of options.ServerAPIVersion.
 // Only to be used in testing.
 const TestServerAPIVersion = "1"
@@ -34,3 +42,87 @@ func (s *ServerAPIOptions) SetDeprecationErrors(deprecationErrors bool) *ServerA
 	s.DeprecationErrors = &deprecationErrors
 	return s
 }
+
+type serverAPIContextKey struct{}
+
+// WithServerAPI returns a context that overrides the server API options sent
+// with commands for operations run with it. If serverAPI is nil, no server API
+// parameters are sent.
+func WithServerAPI(ctx context.Context, serverAPI *ServerAPIOptions) context.Context {
+	return context.WithValue(ctx, serverAPIContextKey{}, serverAPI)
+}
+
+// ServerAPIFromContext returns the server API options set on the context with
+// WithServerAPI. The returned options are nil if the context overrides the
+// server API options to send no server API parameters.
+func ServerAPIFromContext(ctx context.Context) (*ServerAPIOptions, bool) {
+	serverAPI, ok := ctx.Value(serverAPIContextKey{}).(*ServerAPIOptions)
+	return serverAPI, ok
+}
+
+type serverAPIStrictContextKey struct{}
+
+// WithServerAPIStrict returns a context that overrides the apiStrict field sent
+// with commands for operations run with it, e.g. to run a command that is not
+// part of the Stable API from a client that enables strict mode. It applies on
+// top of the server API options of the operation, including any set with
+// WithServerAPI. The server rejects apiStrict without an API version, so
+// operations run with it fail with ErrServerAPIStrictWithoutVersion if no
+// server API version is configured.
+func WithServerAPIStrict(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, serverAPIStrictContextKey{}, strict)
+}
+
+// serverAPIStrictFromContext returns the apiStrict override from the context.
+func serverAPIStrictFromContext(ctx context.Context) (bool, bool) {
+	strict, ok := ctx.Value(serverAPIStrictContextKey{}).(bool)
+	return strict, ok
+}
+
+// ErrServerAPIStrictWithoutVersion is returned when apiStrict is set on the
+// context with WithServerAPIStrict but no server API version is configured.
+var ErrServerAPIStrictWithoutVersion = errors.New("apiStrict cannot be set without a server API version")
+
+// strictAPIUnsupportedOptions lists, by command name, deprecated command
+// options that are known not to be part of version 1 of the Stable API. This is
+// not an exhaustive list: the server remains the authority on which options are
+// allowed in strict mode, but commands using these options are rejected before
+// they are sent.
+var strictAPIUnsupportedOptions = map[string][]string{
+	// oplogReplay was deprecated in MongoDB 4.4. maxScan and snapshot were
+	// removed in MongoDB 4.2.
+	"find": {"oplogReplay", "maxScan", "snapshot"},
+}
+
+// StrictAPIError is returned when a command includes an option that is not part
+// of the Stable API while strict mode is enabled.
+type StrictAPIError struct {
+	Command string
+	Option  string
+}
+
+// Error implements the error interface.
+func (e StrictAPIError) Error() string {
+	return fmt.Sprintf("option %q of the %q command is not part of the Stable API and cannot be used when apiStrict is true",
+		e.Option, e.Command)
+}
+
+// validateStrictAPI returns a StrictAPIError if serverAPI enables strict mode
+// and cmd includes an option that is known not to be part of the Stable API.
+func validateStrictAPI(serverAPI *ServerAPIOptions, cmd bsoncore.Document) error {
+	if serverAPI == nil || serverAPI.Strict == nil || !*serverAPI.Strict {
+		return nil
+	}
+
+	elem, err := cmd.IndexErr(0)
+	if err != nil {
+		return nil
+	}
+	name := elem.Key()
+	for _, option := range strictAPIUnsupportedOptions[name] {
+		if _, err := cmd.LookupErr(option); err == nil {
+			return StrictAPIError{Command: name, Option: option}
+		}
+	}
+	return nil
+}
@@ -102,6 +102,11 @@ type Client struct {
 	Aborting       bool
 	Snapshot       bool
 
+	// ServerAffinity causes server selection to prefer the server used by the previous operation
+	// in the session, which is recorded in LastServerAddr.
+	ServerAffinity bool
+	LastServerAddr *address.Address
+
 	// options for the current transaction
 	// most recently set by transactionopt
 	CurrentRc       *readconcern.ReadConcern
@@ -192,6 +197,9 @@ func NewClientSession(pool *Pool, clientID uuid.UUID, opts ...*ClientOptions) (*
 	if mergedOpts.Snapshot != nil {
 		c.Snapshot = *mergedOpts.Snapshot
 	}
+	if mergedOpts.ServerAffinity != nil {
+		c.ServerAffinity = *mergedOpts.ServerAffinity
+	}
 
 	// For explicit sessions, the default for causalConsistency is true, unless Snapshot is
 	// enabled, then it's false. Set the default and then allow any explicit causalConsistency
@@ -502,6 +510,10 @@ func (c *Client) StartCommand() error {
 // ApplyCommand advances the state machine upon command execution. This must be called after server selection is
 // complete.
 func (c *Client) ApplyCommand(desc description.Server) error {
+	if c.ServerAffinity {
+		addr := desc.Addr
+		c.LastServerAddr = &addr
+	}
 	if c.Committing {
 		// Do not change state if committing after already committed
 		return nil
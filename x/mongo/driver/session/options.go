@@ -19,6 +19,7 @@ type ClientOptions struct {
 	DefaultWriteConcern   *writeconcern.WriteConcern
 	DefaultReadPreference *readpref.ReadPref
 	Snapshot              *bool
+	ServerAffinity        *bool
 }
 
 // TransactionOptions represents all possible options for starting a transaction in a session.
@@ -49,6 +50,9 @@ func mergeClientOptions(opts ...*ClientOptions) *ClientOptions {
 		if opt.Snapshot != nil {
 			c.Snapshot = opt.Snapshot
 		}
+		if opt.ServerAffinity != nil {
+			c.ServerAffinity = opt.ServerAffinity
+		}
 	}
 
 	return c
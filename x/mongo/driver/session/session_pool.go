@@ -186,6 +186,17 @@ func (p *Pool) String() string {
 	return s
 }
 
+// TimeoutMinutes returns the logicalSessionTimeoutMinutes value of the most recent topology description observed by
+// the pool. It returns nil if the deployment has not reported a session timeout, which indicates that sessions are not
+// supported.
+func (p *Pool) TimeoutMinutes() *int64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.updateTimeout()
+	return p.latestTopology.timeoutMinutes
+}
+
 // CheckedOut returns number of sessions checked out from pool.
 func (p *Pool) CheckedOut() int64 {
 	return atomic.LoadInt64(&p.checkedOut)
@@ -69,4 +69,17 @@ func TestSessionPool(t *testing.T) {
 		assert.False(t, bytes.Equal(sess.SessionID, firstID), "first expired session was not removed")
 		assert.False(t, bytes.Equal(sess.SessionID, secondID), "second expired session was not removed")
 	})
+
+	t.Run("TimeoutMinutes", func(t *testing.T) {
+		descChan := make(chan description.Topology, 1)
+		p := NewPool(descChan)
+
+		got := p.TimeoutMinutes()
+		assert.Nil(t, got, "expected nil timeout before a topology description is received, got %v", got)
+
+		descChan <- description.Topology{SessionTimeoutMinutes: int64ToPtr(30)}
+		got = p.TimeoutMinutes()
+		assert.NotNil(t, got, "expected timeout to be set")
+		assert.Equal(t, int64(30), *got, "expected timeout of 30 minutes, got %v", *got)
+	})
 }
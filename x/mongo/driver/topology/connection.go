@@ -9,17 +9,20 @@ package topology
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"gitee.com/Trisia/gotlcp/tlcp"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
@@ -43,8 +46,15 @@ var (
 	defaultMaxMessageSize        uint32 = 48000000
 	errResponseTooLarge                 = errors.New("length of read message too large")
 	errLoadBalancedStateMismatch        = errors.New("driver attempted to initialize in load balancing mode, but the server does not support this mode")
+	errNoCommonCompressor               = errors.New("compression is required but the server does not support any of the requested compressors")
 )
 
+// credentialSetter is implemented by handshakers that can replace the credential used to authenticate the connection
+// after the initial handshake.
+type credentialSetter interface {
+	SetCredential(mechanism string, cred *driver.Cred) error
+}
+
 func nextConnectionID() uint64 { return atomic.AddUint64(&globalConnectionID, 1) }
 
 type connection struct {
@@ -58,6 +68,7 @@ type connection struct {
 	addr                 address.Address
 	idleTimeout          time.Duration
 	idleStart            atomic.Value // Stores a time.Time
+	lastUsed             atomic.Value // Stores a time.Time
 	desc                 description.Server
 	helloRTT             time.Duration
 	compressor           wiremessage.CompressorID
@@ -71,6 +82,7 @@ type connection struct {
 	cancellationListener contextListener
 	connectListener      contextListener // Cancels blocking ops during connect
 	serverConnectionID   *int64          // the server's ID for this client's connection
+	saslSupportedMechs   []string        // the SASL mechanisms the server supports for the user
 	prevCanceled         atomic.Value
 
 	// pool related fields
@@ -85,13 +97,22 @@ type connection struct {
 	// awaitRemainingBytes indicates the size of server response that was not completely
 	// read before returning the connection to the pool.
 	awaitRemainingBytes *int32
+
+	// deadline is an absolute I/O deadline set via Connection.SetDeadline. It is combined with the
+	// context deadline of every read and write, and the earlier of the two is used.
+	deadline time.Time
 }
 
 // newConnection handles the creation of a connection. It does not connect the connection.
 func newConnection(addr address.Address, opts ...ConnectionOption) *connection {
 	cfg := newConnectionConfig(opts...)
 
-	id := fmt.Sprintf("%s[-%d]", addr, nextConnectionID())
+	var id string
+	if cfg.idFn != nil {
+		id = cfg.idFn(addr, cfg.idSeqFn())
+	} else {
+		id = fmt.Sprintf("%s[-%d]", addr, cfg.idSeqFn())
+	}
 
 	c := &connection{
 		id:                   id,
@@ -113,6 +134,31 @@ func newConnection(addr address.Address, opts ...ConnectionOption) *connection {
 	return c
 }
 
+// NewConnectionFromNetConn creates a Connection that communicates over nc, which must already be connected to a
+// MongoDB server or to a proxy that speaks the wire protocol, and runs the handshake configured by opts over it. No
+// dialing takes place, so any Dialer set in opts is ignored, but TLS or TLCP is still negotiated over nc if it is
+// configured. It is intended for proxies and for tests that run a fake server over one end of a net.Pipe.
+//
+// The returned Connection does not belong to a connection pool: it cannot be pinned and Close closes nc. If the
+// handshake fails, nc is closed and the error is returned.
+func NewConnectionFromNetConn(ctx context.Context, nc net.Conn, opts ...ConnectionOption) (*Connection, error) {
+	var addr address.Address
+	if remote := nc.RemoteAddr(); remote != nil {
+		addr = address.Address(remote.String())
+	}
+
+	opts = append(opts[:len(opts):len(opts)], WithDialer(func(Dialer) Dialer {
+		return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+			return nc, nil
+		})
+	}))
+	c := newConnection(addr, opts...)
+	if err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+	return &Connection{connection: c}, nil
+}
+
 // setGenerationNumber sets the connection's generation number if a callback has been provided to do so in connection
 // configuration.
 func (c *connection) setGenerationNumber() {
@@ -200,6 +246,25 @@ func configureTLCP(ctx context.Context,
 	return client, nil
 }
 
+// newOCSPVerifyOptions creates the OCSP verification options for a connection to addr. A host policy matching addr
+// takes precedence over the global disableOCSPEndpointCheck setting.
+func newOCSPVerifyOptions(cfg *connectionConfig, addr address.Address) *ocsp.VerifyOptions {
+	opts := &ocsp.VerifyOptions{
+		Cache:                   cfg.ocspCache,
+		DisableEndpointChecking: cfg.disableOCSPEndpointCheck,
+		HTTPClient:              cfg.httpClient,
+	}
+
+	host := addr.String()
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if policy, ok := ocsp.PolicyForHost(cfg.ocspHostPolicies, host); ok {
+		opts.Apply(policy)
+	}
+	return opts
+}
+
 // connect handles the I/O for a connection. It will dial, configure TLS, and perform initialization
 // handshakes. All errors returned by connect are considered "before the handshake completes" and
 // must be handled by calling the appropriate SDAM handshake error handler.
@@ -242,43 +307,106 @@ func (c *connection) connect(ctx context.Context) (err error) {
 		c.connectListener.Listen(ctx, func() {})
 	}()
 
+	for attempt := 0; ; attempt++ {
+		err = c.establish(ctx)
+		if err == nil || attempt >= connLimitMaxRetries || !c.retryableConnLimitError(err) {
+			return err
+		}
+
+		// The server rejected the connection because it has too many open connections. Close the socket and back off
+		// before trying again so that many clients starting at once don't keep hammering the server.
+		if c.nc != nil {
+			_ = c.nc.Close()
+			c.nc = nil
+		}
+		if !sleepWithContext(ctx, connLimitBackoff(attempt)) {
+			return err
+		}
+	}
+}
+
+// dial dials the server. If the connection is configured with a dial limiter, it
+// first waits for a slot in the limiter or for ctx to be done.
+func (c *connection) dial(ctx context.Context) (net.Conn, error) {
+	if limiter := c.config.dialLimiter; limiter != nil {
+		select {
+		case limiter <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-limiter }()
+	}
+
+	return c.config.dialer.DialContext(ctx, c.addr.Network(), c.addr.String())
+}
+
+// establish dials the server, configures TLS or TLCP if necessary, and performs the initial handshakes.
+func (c *connection) establish(ctx context.Context) error {
 	// Assign the result of DialContext to a temporary net.Conn to ensure that c.nc is not set in an error case.
-	tempNc, err := c.config.dialer.DialContext(ctx, c.addr.Network(), c.addr.String())
+	tempNc, err := c.dial(ctx)
 	if err != nil {
-		return ConnectionError{Wrapped: err, init: true, message: fmt.Sprintf("failed to connect to %s", c.addr)}
+		phase := event.PhaseDial
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			phase = event.PhaseDNS
+		}
+		return ConnectionError{Wrapped: err, init: true, message: fmt.Sprintf("failed to connect to %s", c.addr), phase: phase}
 	}
 	c.nc = tempNc
 
+	if c.config.keepAlive != nil {
+		if err := setKeepAlive(c.nc, *c.config.keepAlive); err != nil {
+			return ConnectionError{Wrapped: err, init: true, message: fmt.Sprintf("failed to set keep-alive parameters for %s", c.addr), phase: event.PhaseDial}
+		}
+	}
+
+	if c.config.dialedConnFn != nil {
+		if err := c.config.dialedConnFn(c.addr, c.nc); err != nil {
+			return ConnectionError{Wrapped: err, init: true, message: fmt.Sprintf("dialed connection callback failed for %s", c.addr), phase: event.PhaseDial}
+		}
+	}
+
 	if c.config.tlsConfig != nil {
 		tlsConfig := c.config.tlsConfig.Clone()
+		if c.config.tlsKeyLogWriter != nil {
+			tlsConfig.KeyLogWriter = c.config.tlsKeyLogWriter
+		}
+		if verifyFn := c.config.tlsVerifyPeerCertFn; verifyFn != nil {
+			if cfgFn := tlsConfig.VerifyPeerCertificate; cfgFn != nil {
+				tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+					if err := cfgFn(rawCerts, verifiedChains); err != nil {
+						return err
+					}
+					return verifyFn(rawCerts, verifiedChains)
+				}
+			} else {
+				tlsConfig.VerifyPeerCertificate = verifyFn
+			}
+		}
 
 		// store the result of configureTLS in a separate variable than c.nc to avoid overwriting c.nc with nil in
 		// error cases.
-		ocspOpts := &ocsp.VerifyOptions{
-			Cache:                   c.config.ocspCache,
-			DisableEndpointChecking: c.config.disableOCSPEndpointCheck,
-			HTTPClient:              c.config.httpClient,
-		}
+		ocspOpts := newOCSPVerifyOptions(c.config, c.addr)
 		tlsNc, err := configureTLS(ctx, c.config.tlsConnectionSource, c.nc, c.addr, tlsConfig, ocspOpts)
 
 		if err != nil {
-			return ConnectionError{Wrapped: err, init: true, message: fmt.Sprintf("failed to configure TLS for %s", c.addr)}
+			return ConnectionError{Wrapped: err, init: true, message: fmt.Sprintf("failed to configure TLS for %s", c.addr), phase: event.PhaseTLS}
 		}
 		c.nc = tlsNc
+
+		if client, ok := tlsNc.(tlsConn); ok && c.config.certExpiryFn != nil {
+			c.checkCertificateExpiry(client.ConnectionState(), tlsConfig.Certificates)
+		}
 	}
 
 	//添加tlcp连接方式
 	if c.config.tlcpConfig != nil {
 		tlcpConfig := c.config.tlcpConfig.Clone()
-		ocspOpts := &ocsp.VerifyOptions{
-			Cache:                   c.config.ocspCache,
-			DisableEndpointChecking: c.config.disableOCSPEndpointCheck,
-			HTTPClient:              c.config.httpClient,
-		}
+		ocspOpts := newOCSPVerifyOptions(c.config, c.addr)
 		tlcpNc, err := configureTLCP(ctx, c.config.tlcpConnectionSource, c.nc, c.addr, tlcpConfig, ocspOpts)
 
 		if err != nil {
-			return ConnectionError{Wrapped: err, init: true, message: fmt.Sprintf("failed to configure TLCP for %s", c.addr)}
+			return ConnectionError{Wrapped: err, init: true, message: fmt.Sprintf("failed to configure TLCP for %s", c.addr), phase: event.PhaseTLS}
 		}
 		c.nc = tlcpNc
 	}
@@ -295,12 +423,24 @@ func (c *connection) connect(ctx context.Context) (err error) {
 	iconn := initConnection{c}
 	handshakeConn := mnet.NewConnection(iconn)
 
+	var recorder *handshakeRecorder
+	if c.config.handshakeObserver != nil {
+		recorder = &handshakeRecorder{initConnection: iconn}
+		handshakeConn = mnet.NewConnection(recorder)
+	}
+
+	phase := event.PhaseHandshake
 	handshakeInfo, err = handshaker.GetHandshakeInformation(ctx, c.addr, handshakeConn)
+	if recorder != nil {
+		recorder.notify(c.config.handshakeObserver, c.addr)
+	}
 	if err == nil {
 		// We only need to retain the Description field as the connection's description. The authentication-related
-		// fields in handshakeInfo are tracked by the handshaker if necessary.
+		// fields in handshakeInfo are tracked by the handshaker if necessary, except for the supported SASL
+		// mechanisms, which are only mechanism names and are exposed to applications.
 		c.desc = handshakeInfo.Description
 		c.serverConnectionID = handshakeInfo.ServerConnectionID
+		c.saslSupportedMechs = handshakeInfo.SaslSupportedMechs
 		c.helloRTT = time.Since(handshakeStartTime)
 
 		// If the application has indicated that the cluster is load balanced, ensure the server has included serviceId
@@ -318,13 +458,19 @@ func (c *connection) connect(ctx context.Context) (err error) {
 		}
 
 		// If we successfully finished the first part of the handshake and verified LB state, continue with the rest of
-		// the handshake.
-		err = handshaker.FinishHandshake(ctx, handshakeConn)
+		// the handshake, which authenticates the connection if necessary.
+		phase = event.PhaseAuth
+		if c.config.credentialInjector != nil {
+			err = c.injectCredential(ctx, handshaker)
+		}
+		if err == nil {
+			err = handshaker.FinishHandshake(ctx, handshakeConn)
+		}
 	}
 
 	// We have a failed handshake here
 	if err != nil {
-		return ConnectionError{Wrapped: err, init: true}
+		return ConnectionError{Wrapped: err, init: true, phase: phase}
 	}
 
 	if len(c.desc.Compression) > 0 {
@@ -350,14 +496,133 @@ func (c *connection) connect(ctx context.Context) (err error) {
 					if c.config.zstdLevel != nil {
 						c.zstdLevel = *c.config.zstdLevel
 					}
+				default:
+					id, ok := driver.LookupCompressor(method)
+					if !ok {
+						continue
+					}
+					c.compressor = id
 				}
 				break clientMethodLoop
 			}
 		}
 	}
+
+	if c.config.requireCompression && len(c.config.compressors) > 0 && c.compressor == wiremessage.CompressorNoOp {
+		err = fmt.Errorf("%w: requested %v, server supports %v",
+			errNoCommonCompressor, c.config.compressors, c.desc.Compression)
+		return ConnectionError{Wrapped: err, init: true, phase: event.PhaseHandshake}
+	}
 	return nil
 }
 
+// injectCredential calls the credential injector and, if it returns a credential, makes the handshaker authenticate
+// the connection with it. The injector is not called if the handshaker does not authenticate the connection, such as
+// for monitoring connections.
+func (c *connection) injectCredential(ctx context.Context, handshaker Handshaker) error {
+	setter, ok := handshaker.(credentialSetter)
+	if !ok {
+		return nil
+	}
+
+	mechanism, cred, err := c.config.credentialInjector(ctx, c.addr, c.desc)
+	if err != nil {
+		return fmt.Errorf("credential injector error: %w", err)
+	}
+	if cred == nil {
+		return nil
+	}
+	return setter.SetCredential(mechanism, cred)
+}
+
+// Backoff parameters used when the server rejects a new connection because it has reached its connection limit.
+const (
+	connLimitMaxRetries     = 5
+	connLimitInitialBackoff = 50 * time.Millisecond
+	connLimitMaxBackoff     = 2 * time.Second
+)
+
+// isConnectionLimitError returns true if err indicates that the server rejected the connection because it has too
+// many open connections.
+func isConnectionLimitError(err error) bool {
+	var driverErr driver.Error
+	if !errors.As(err, &driverErr) {
+		return false
+	}
+	return strings.Contains(strings.ToLower(driverErr.Message), "too many open connections")
+}
+
+// retryableConnLimitError returns true if err is a connection limit error and connection establishment can be
+// retried. Load-balanced connections are not retried once their generation number has been set because retrying
+// would count the connection against the generation more than once.
+func (c *connection) retryableConnLimitError(err error) bool {
+	if c.config.loadBalanced && c.hasGenerationNumber() {
+		return false
+	}
+	return isConnectionLimitError(err)
+}
+
+// connLimitBackoff returns the jittered exponential backoff to wait before the given connection establishment retry
+// attempt, starting from 0.
+func connLimitBackoff(attempt int) time.Duration {
+	backoff := connLimitInitialBackoff << uint(attempt)
+	if backoff <= 0 || backoff > connLimitMaxBackoff {
+		backoff = connLimitMaxBackoff
+	}
+
+	// Use "equal jitter" so concurrent clients spread out their retries while still backing off.
+	half := backoff / 2
+	return half + time.Duration(random.Int63n(int64(half)+1))
+}
+
+// sleepWithContext waits for d to elapse. It returns false without waiting if ctx would expire before d elapses, or
+// if ctx is done while waiting.
+func sleepWithContext(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// checkCertificateExpiry invokes the configured certificate expiry callback for each certificate in the server's
+// certificate chain and each client certificate that expires within the configured certificate expiry window.
+func (c *connection) checkCertificateExpiry(state tls.ConnectionState, clientCerts []tls.Certificate) {
+	cutoff := time.Now().Add(c.config.certExpiryWindow)
+
+	for _, cert := range state.PeerCertificates {
+		if cert.NotAfter.Before(cutoff) {
+			c.config.certExpiryFn(c.addr, cert, false)
+		}
+	}
+
+	for _, clientCert := range clientCerts {
+		leaf := clientCert.Leaf
+		if leaf == nil {
+			if len(clientCert.Certificate) == 0 {
+				continue
+			}
+
+			var err error
+			if leaf, err = x509.ParseCertificate(clientCert.Certificate[0]); err != nil {
+				continue
+			}
+		}
+
+		if leaf.NotAfter.Before(cutoff) {
+			c.config.certExpiryFn(c.addr, leaf, true)
+		}
+	}
+}
+
 func (c *connection) wait() {
 	if c.connectDone != nil {
 		<-c.connectDone
@@ -399,6 +664,17 @@ func transformNetworkError(ctx context.Context, originalError error, contextDead
 	return originalError
 }
 
+// ioDeadline returns the deadline to use for a single read or write: the earlier of the context
+// deadline and the deadline set via Connection.SetDeadline. The returned bool reports whether the
+// context deadline was chosen.
+func (c *connection) ioDeadline(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Deadline()
+	if c.deadline.IsZero() || (ok && !deadline.After(c.deadline)) {
+		return deadline, ok
+	}
+	return c.deadline, false
+}
+
 func (c *connection) writeWireMessage(ctx context.Context, wm []byte) error {
 	var err error
 	if atomic.LoadInt64(&c.state) != connConnected {
@@ -408,11 +684,15 @@ func (c *connection) writeWireMessage(ctx context.Context, wm []byte) error {
 		}
 	}
 
-	deadline, contextDeadlineUsed := ctx.Deadline()
+	deadline, contextDeadlineUsed := c.ioDeadline(ctx)
 	if err := c.nc.SetWriteDeadline(deadline); err != nil {
 		return ConnectionError{ConnectionID: c.id, Wrapped: err, message: "failed to set write deadline"}
 	}
 
+	if c.config != nil && c.config.msgChecksum {
+		wm = wiremessage.AppendMsgChecksum(make([]byte, 0, len(wm)+4), wm)
+	}
+
 	err = c.write(ctx, wm)
 	if err != nil {
 		c.close()
@@ -439,6 +719,12 @@ func (c *connection) write(ctx context.Context, wm []byte) (err error) {
 		}
 	}()
 
+	if c.config != nil && c.config.faultInjector != nil {
+		if err := c.config.faultInjector.inject(ctx, FaultOpWrite); err != nil {
+			return err
+		}
+	}
+
 	_, err = c.nc.Write(wm)
 	return err
 }
@@ -452,7 +738,7 @@ func (c *connection) readWireMessage(ctx context.Context) ([]byte, error) {
 		}
 	}
 
-	deadline, contextDeadlineUsed := ctx.Deadline()
+	deadline, contextDeadlineUsed := c.ioDeadline(ctx)
 	if err := c.nc.SetReadDeadline(deadline); err != nil {
 		return nil, ConnectionError{ConnectionID: c.id, Wrapped: err, message: "failed to set read deadline"}
 	}
@@ -472,6 +758,9 @@ func (c *connection) readWireMessage(ctx context.Context) ([]byte, error) {
 		}
 	}
 
+	// A successful read completes a round trip, so it marks the connection as used.
+	c.lastUsed.Store(time.Now())
+
 	return dst, nil
 }
 
@@ -520,6 +809,15 @@ func (c *connection) read(ctx context.Context) (bytesRead []byte, errMsg string,
 	// reslice dst once instead of twice.
 	var sizeBuf [4]byte
 
+	if c.config != nil && c.config.faultInjector != nil {
+		if err := c.config.faultInjector.inject(ctx, FaultOpRead); err != nil {
+			if isCSOTTimeout(err) {
+				c.awaitRemainingBytes = new(int32)
+			}
+			return nil, "incomplete read of message header", err
+		}
+	}
+
 	// We do a ReadFull into an array here instead of doing an opportunistic ReadAtLeast into dst
 	// because there might be more than one wire message waiting to be read, for example when
 	// reading messages from an exhaust cursor.
@@ -547,9 +845,53 @@ func (c *connection) read(ctx context.Context) (bytesRead []byte, errMsg string,
 		return dst, "incomplete read of full message", err
 	}
 
+	if err := c.checkUncompressedSize(dst); err != nil {
+		return nil, err.Error(), err
+	}
+
+	dst, err = wiremessage.RemoveMsgChecksum(dst)
+	if err != nil {
+		return nil, err.Error(), err
+	}
+
 	return dst, "", nil
 }
 
+// checkUncompressedSize returns an error if wm is an OP_COMPRESSED message that
+// claims an uncompressed size greater than the connection's maximum. It is
+// checked when the message is read so that a server claiming an enormous size
+// cannot make the driver allocate the buffer for the decompressed message.
+func (c *connection) checkUncompressedSize(wm []byte) error {
+	_, _, _, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	if !ok || opcode != wiremessage.OpCompressed {
+		return nil
+	}
+	_, rem, ok = wiremessage.ReadCompressedOriginalOpCode(rem)
+	if !ok {
+		return nil
+	}
+	size, _, ok := wiremessage.ReadCompressedUncompressedSize(rem)
+	if !ok {
+		return nil
+	}
+
+	var maxSize int32
+	if c.config != nil {
+		maxSize = c.config.maxUncompressedSize
+	}
+	if maxSize <= 0 {
+		maxSize = int32(defaultMaxMessageSize)
+	}
+	if size < 0 {
+		return fmt.Errorf("malformed OP_COMPRESSED: invalid uncompressed size %d", size)
+	}
+	if size > maxSize {
+		return fmt.Errorf("%w: OP_COMPRESSED message claims %d bytes, which exceeds the maximum of %d bytes",
+			driver.ErrUncompressedSizeTooLarge, size, maxSize)
+	}
+	return nil
+}
+
 func (c *connection) close() error {
 	// Stop any blocking operations occurring in connect(), but await closing the
 	// connections directly before closing the connection context. This ensures
@@ -602,6 +944,23 @@ func (c *connection) getCurrentlyStreaming() bool {
 	return c.currentlyStreaming
 }
 
+// trackStream updates the streaming state of a connection that supports streaming from the
+// moreToCome flag of an OP_MSG reply read from it. Only the flag bits are inspected. The flags of
+// an OP_COMPRESSED reply are compressed, so such a reply leaves the streaming state unchanged; the
+// driver sets it with SetStreaming once the reply is decompressed.
+func (c *connection) trackStream(wm []byte) {
+	if !c.canStream {
+		return
+	}
+
+	_, _, _, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	if !ok || opcode == wiremessage.OpCompressed {
+		return
+	}
+	flags, _, ok := wiremessage.ReadMsgFlags(rem)
+	c.setStreaming(opcode == wiremessage.OpMsg && ok && flags&wiremessage.MoreToCome != 0)
+}
+
 func (c *connection) previousCanceled() bool {
 	if val := c.prevCanceled.Load(); val != nil {
 		return val.(bool)
@@ -623,6 +982,43 @@ func (c *connection) DriverConnectionID() int64 {
 	return c.driverConnectionID
 }
 
+// compressWireMessage compresses the wire message src using the connection's negotiated
+// compressor and appends the result to dst. If no compressor was negotiated, src is appended to
+// dst unchanged.
+func (c *connection) compressWireMessage(src, dst []byte) ([]byte, error) {
+	if c.compressor == wiremessage.CompressorNoOp {
+		return append(dst, src...), nil
+	}
+	if c.config != nil && c.config.msgChecksum {
+		// The checksum covers the uncompressed message, so it is added before compressing.
+		src = wiremessage.AppendMsgChecksum(make([]byte, 0, len(src)+4), src)
+	}
+	_, reqid, respto, origcode, rem, ok := wiremessage.ReadHeader(src)
+	if !ok {
+		return dst, errors.New("wiremessage is too short to compress, less than 16 bytes")
+	}
+	idx, dst := wiremessage.AppendHeaderStart(dst, reqid, respto, wiremessage.OpCompressed)
+	dst = wiremessage.AppendCompressedOriginalOpCode(dst, origcode)
+	dst = wiremessage.AppendCompressedUncompressedSize(dst, int32(len(rem)))
+	dst = wiremessage.AppendCompressedCompressorID(dst, c.compressor)
+	opts := driver.CompressionOpts{
+		Compressor: c.compressor,
+		ZlibLevel:  c.zliblevel,
+		ZstdLevel:  c.zstdLevel,
+	}
+	compressed, err := driver.CompressPayload(rem, opts)
+	if err != nil {
+		return nil, err
+	}
+	dst = wiremessage.AppendCompressedCompressedMessage(dst, compressed)
+	return bsoncore.UpdateLength(dst, idx, int32(len(dst[idx:]))), nil
+}
+
+// NextRequestID returns a request ID for the next wire message sent over the connection.
+func (c *connection) NextRequestID() int32 {
+	return c.config.requestIDFn()
+}
+
 func (c *connection) OIDCTokenGenID() uint64 {
 	return c.oidcTokenGenID
 }
@@ -639,6 +1035,8 @@ type initConnection struct{ *connection }
 var _ mnet.ReadWriteCloser = initConnection{}
 var _ mnet.Describer = initConnection{}
 var _ mnet.Streamer = initConnection{}
+var _ mnet.RequestIDGenerator = initConnection{}
+var _ mnet.Compressor = initConnection{}
 
 func (c initConnection) Description() description.Server {
 	if c.connection == nil {
@@ -672,16 +1070,118 @@ func (c initConnection) SupportsStreaming() bool {
 	return c.canStream
 }
 
+// CompressWireMessage compresses the wire message src using the connection's negotiated
+// compressor. It is only used for heartbeats after compression has been negotiated.
+func (c initConnection) CompressWireMessage(src, dst []byte) ([]byte, error) {
+	return c.compressWireMessage(src, dst)
+}
+
+// handshakeRecorder wraps an initConnection to capture the first command written and the first
+// reply read during the initial handshake so they can be passed to a HandshakeObserverFunc.
+type handshakeRecorder struct {
+	initConnection
+	cmd, reply []byte
+}
+
+func (r *handshakeRecorder) Write(ctx context.Context, wm []byte) error {
+	if r.cmd == nil {
+		r.cmd = append([]byte(nil), wm...)
+	}
+	return r.initConnection.Write(ctx, wm)
+}
+
+func (r *handshakeRecorder) Read(ctx context.Context) ([]byte, error) {
+	wm, err := r.initConnection.Read(ctx)
+	if err == nil && r.cmd != nil && r.reply == nil {
+		r.reply = append([]byte(nil), wm...)
+	}
+	return wm, err
+}
+
+// notify invokes fn with the recorded command and reply. It does nothing if either message
+// was not recorded or cannot be decoded.
+func (r *handshakeRecorder) notify(fn HandshakeObserverFunc, addr address.Address) {
+	if fn == nil || r.cmd == nil || r.reply == nil {
+		return
+	}
+	cmd, err := decodeHandshakeDocument(r.cmd)
+	if err != nil {
+		return
+	}
+	reply, err := decodeHandshakeDocument(r.reply)
+	if err != nil {
+		return
+	}
+	fn(addr, cmd, reply)
+}
+
+// decodeHandshakeDocument extracts the command or reply document from a handshake wire message.
+// The legacy hello is sent as OP_QUERY and answered with OP_REPLY; all other messages are
+// decoded as OP_MSG.
+func decodeHandshakeDocument(wm []byte) (bsoncore.Document, error) {
+	_, _, _, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	if !ok {
+		return nil, errors.New("malformed wire message: insufficient bytes")
+	}
+
+	var doc bsoncore.Document
+	switch opcode {
+	case wiremessage.OpQuery:
+		_, rem, ok = wiremessage.ReadQueryFlags(rem)
+		if ok {
+			_, rem, ok = wiremessage.ReadQueryFullCollectionName(rem)
+		}
+		if ok {
+			_, rem, ok = wiremessage.ReadQueryNumberToSkip(rem)
+		}
+		if ok {
+			_, rem, ok = wiremessage.ReadQueryNumberToReturn(rem)
+		}
+		if ok {
+			doc, _, ok = wiremessage.ReadQueryQuery(rem)
+		}
+		if !ok {
+			return nil, errors.New("malformed OP_QUERY: insufficient bytes")
+		}
+	case wiremessage.OpReply:
+		_, rem, ok = wiremessage.ReadReplyFlags(rem)
+		if ok {
+			_, rem, ok = wiremessage.ReadReplyCursorID(rem)
+		}
+		if ok {
+			_, rem, ok = wiremessage.ReadReplyStartingFrom(rem)
+		}
+		if ok {
+			_, rem, ok = wiremessage.ReadReplyNumberReturned(rem)
+		}
+		if ok {
+			doc, _, ok = wiremessage.ReadReplyDocument(rem)
+		}
+		if !ok {
+			return nil, errors.New("malformed OP_REPLY: insufficient bytes")
+		}
+	default:
+		return decodeRawCommandReply(wm)
+	}
+	return doc, doc.Validate()
+}
+
 // Connection implements the driver.Connection interface to allow reading and writing wire
 // messages and the driver.Expirable interface to allow expiring. It wraps an underlying
 // topology.connection to make it more goroutine-safe and nil-safe.
 type Connection struct {
 	connection    *connection
 	refCount      int
+	pinReason     string // pinReason is the reason the connection was first pinned, e.g. "cursor".
+	pinLeakTimer  *time.Timer
 	cleanupPoolFn func()
 
 	oidcTokenGenID uint64
 
+	// serverAPI is the server API configured for the server this connection is to. It is used by
+	// RunRawCommand.
+	serverAPI *driver.ServerAPIOptions
+
 	// cleanupServerFn resets the server state when a connection is returned to the connection pool
 	// via Close() or expired via Expire().
 	cleanupServerFn func()
@@ -693,6 +1193,7 @@ var _ mnet.ReadWriteCloser = (*Connection)(nil)
 var _ mnet.Describer = (*Connection)(nil)
 var _ mnet.Compressor = (*Connection)(nil)
 var _ mnet.Pinner = (*Connection)(nil)
+var _ mnet.RequestIDGenerator = (*Connection)(nil)
 var _ driver.Expirable = (*Connection)(nil)
 
 // WriteWireMessage handles writing a wire message to the underlying connection.
@@ -713,7 +1214,53 @@ func (c *Connection) Read(ctx context.Context) ([]byte, error) {
 	if c.connection == nil {
 		return nil, ErrConnectionClosed
 	}
-	return c.connection.readWireMessage(ctx)
+	wm, err := c.connection.readWireMessage(ctx)
+	if err == nil {
+		c.connection.trackStream(wm)
+	}
+	return wm, err
+}
+
+// AbortStream stops an exhaust stream in progress on this connection by closing the connection.
+// The server keeps sending streamed replies until the stream ends and only reads further commands
+// afterwards, so closing the connection is the only way to stop the stream without waiting for it.
+// The server releases the cursor being streamed when the connection is closed. AbortStream does
+// nothing if the connection is not streaming.
+func (c *Connection) AbortStream(context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.connection == nil {
+		return ErrConnectionClosed
+	}
+	if !c.connection.getCurrentlyStreaming() {
+		return nil
+	}
+
+	c.connection.setStreaming(false)
+	return c.connection.close()
+}
+
+// SetDeadline sets an absolute deadline for all subsequent reads and writes on the underlying
+// net.Conn. It is intended for callers that cannot conveniently thread a context deadline through
+// every operation. A zero value removes the deadline.
+//
+// Read and Write still derive a per-I/O deadline from their context; when both are set, the earlier
+// of the two applies. A timeout caused by this deadline is not converted to
+// context.DeadlineExceeded. The deadline is cleared when the connection is returned to the pool.
+func (c *Connection) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.connection == nil {
+		return ErrConnectionClosed
+	}
+	if c.connection.nc == nil {
+		return ConnectionError{ConnectionID: c.connection.id, message: "connection is closed"}
+	}
+	if err := c.connection.nc.SetDeadline(t); err != nil {
+		return ConnectionError{ConnectionID: c.connection.id, Wrapped: err, message: "failed to set deadline"}
+	}
+	c.connection.deadline = t
+	return nil
 }
 
 // CompressWireMessage handles compressing the provided wire message using the underlying
@@ -725,28 +1272,7 @@ func (c *Connection) CompressWireMessage(src, dst []byte) ([]byte, error) {
 	if c.connection == nil {
 		return dst, ErrConnectionClosed
 	}
-	if c.connection.compressor == wiremessage.CompressorNoOp {
-		return append(dst, src...), nil
-	}
-	_, reqid, respto, origcode, rem, ok := wiremessage.ReadHeader(src)
-	if !ok {
-		return dst, errors.New("wiremessage is too short to compress, less than 16 bytes")
-	}
-	idx, dst := wiremessage.AppendHeaderStart(dst, reqid, respto, wiremessage.OpCompressed)
-	dst = wiremessage.AppendCompressedOriginalOpCode(dst, origcode)
-	dst = wiremessage.AppendCompressedUncompressedSize(dst, int32(len(rem)))
-	dst = wiremessage.AppendCompressedCompressorID(dst, c.connection.compressor)
-	opts := driver.CompressionOpts{
-		Compressor: c.connection.compressor,
-		ZlibLevel:  c.connection.zliblevel,
-		ZstdLevel:  c.connection.zstdLevel,
-	}
-	compressed, err := driver.CompressPayload(rem, opts)
-	if err != nil {
-		return nil, err
-	}
-	dst = wiremessage.AppendCompressedCompressedMessage(dst, compressed)
-	return bsoncore.UpdateLength(dst, idx, int32(len(dst[idx:]))), nil
+	return c.connection.compressWireMessage(src, dst)
 }
 
 // Description returns the server description of the server this connection is connected to.
@@ -784,7 +1310,14 @@ func (c *Connection) Expire() error {
 }
 
 func (c *Connection) cleanupReferences() error {
-	err := c.connection.pool.checkIn(c.connection)
+	c.connection.deadline = time.Time{}
+	var err error
+	if c.connection.pool != nil {
+		err = c.connection.pool.checkIn(c.connection)
+	} else {
+		// Connections created by NewConnectionFromNetConn have no pool to return to.
+		err = c.connection.close()
+	}
 	if c.cleanupPoolFn != nil {
 		c.cleanupPoolFn()
 		c.cleanupPoolFn = nil
@@ -793,6 +1326,9 @@ func (c *Connection) cleanupReferences() error {
 		c.cleanupServerFn()
 		c.cleanupServerFn = nil
 	}
+	c.refCount = 0
+	c.pinReason = ""
+	c.stopPinLeakDetection()
 	c.connection = nil
 	return err
 }
@@ -820,13 +1356,43 @@ func (c *Connection) ServerConnectionID() *int64 {
 	return c.connection.serverConnectionID
 }
 
+// SaslSupportedMechs returns the SASL mechanisms that the server advertised in the handshake for the user of the
+// configured credential, e.g. "SCRAM-SHA-1" and "SCRAM-SHA-256". The server only advertises them if the credential
+// does not specify an authentication mechanism. It returns nil if the server did not advertise any mechanisms or the
+// connection has been returned to the pool.
+func (c *Connection) SaslSupportedMechs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.connection == nil {
+		return nil
+	}
+	return c.connection.saslSupportedMechs
+}
+
 // Stale returns if the connection is stale.
 func (c *Connection) Stale() bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	if c.connection != nil && c.connection.pool == nil {
+		return false
+	}
 	return c.connection.pool.stale(c.connection)
 }
 
+// LastUsed returns the time at which the connection last completed an operation, i.e. when it last read a reply from
+// the server. Unlike the idle time reported by Diagnostics, which starts when the connection is returned to the pool,
+// it reveals connections that are checked out but not being used. It returns the zero time if the connection has not
+// read a reply or has been returned to the pool.
+func (c *Connection) LastUsed() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.connection == nil {
+		return time.Time{}
+	}
+	lastUsed, _ := c.connection.lastUsed.Load().(time.Time)
+	return lastUsed
+}
+
 // Address returns the address of this connection.
 func (c *Connection) Address() address.Address {
 	c.mu.RLock()
@@ -863,17 +1429,142 @@ func (c *Connection) pin(reason string, updatePoolFn, cleanupPoolFn func()) erro
 	if c.connection == nil {
 		return fmt.Errorf("attempted to pin a connection for a %s, but the connection has already been returned to the pool", reason)
 	}
+	if c.connection.pool == nil {
+		return fmt.Errorf("attempted to pin a connection for a %s, but the connection does not belong to a pool", reason)
+	}
 
 	// Only use the provided callbacks for the first reference to avoid double-counting pinned connection statistics
 	// in the pool.
 	if c.refCount == 0 {
 		updatePoolFn()
 		c.cleanupPoolFn = cleanupPoolFn
+		c.pinReason = reason
+		c.startPinLeakDetection(reason)
 	}
 	c.refCount++
 	return nil
 }
 
+// startPinLeakDetection starts a timer that logs a warning with the stack trace of the caller if
+// the connection is still pinned after the pool's pin leak threshold. The stack trace is only
+// captured if pin leak detection is enabled. The caller must hold c.mu.
+func (c *Connection) startPinLeakDetection(reason string) {
+	p := c.connection.pool
+	if p == nil || p.pinLeakThreshold <= 0 {
+		return
+	}
+
+	conn := c.connection
+	threshold := p.pinLeakThreshold
+	stack := debug.Stack()
+	c.pinLeakTimer = time.AfterFunc(threshold, func() {
+		p.logPinLeak(conn, reason, threshold, stack)
+	})
+}
+
+// stopPinLeakDetection stops the pin leak timer, if any. The caller must hold c.mu.
+func (c *Connection) stopPinLeakDetection() {
+	if c.pinLeakTimer != nil {
+		c.pinLeakTimer.Stop()
+		c.pinLeakTimer = nil
+	}
+}
+
+// PinState reports whether the connection is pinned, the reason it was first pinned ("cursor" or
+// "transaction"), and the number of resources currently holding it. Pinned connections that are
+// never unpinned indicate leaked cursors or transactions.
+func (c *Connection) PinState() (pinned bool, reason string, refCount int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.refCount > 0, c.pinReason, c.refCount
+}
+
+// Diagnostics returns a snapshot of the connection's state that is suitable for
+// attaching to a support ticket. It only contains identifiers, negotiated
+// settings, and state, never credentials, certificates, or wire messages. If
+// the connection has been returned to the pool, only the "closed" key is set.
+//
+// The snapshot contains the following keys:
+//   - "id", "driverConnectionID", "serverConnectionID", "address", and "generation"
+//   - "serverType" and "maxWireVersion" from the server description
+//   - "compressor": "snappy", "zlib", "zstd", or "none"
+//   - "transport": "tls", "tlcp", or "tcp"
+//   - "idleDuration": the time since the connection was last returned to the
+//     pool, or 0 if it has not been or MaxConnIdleTime is not set
+//   - "lastUsed": the time returned by LastUsed
+//   - "pinned", "pinReason", and "pinRefCount"
+//   - "closed"
+func (c *Connection) Diagnostics() map[string]any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.connection == nil {
+		return map[string]any{"closed": true}
+	}
+	conn := c.connection
+
+	var serverConnectionID any
+	if conn.serverConnectionID != nil {
+		serverConnectionID = *conn.serverConnectionID
+	}
+
+	var maxWireVersion int32
+	if conn.desc.WireVersion != nil {
+		maxWireVersion = conn.desc.WireVersion.Max
+	}
+
+	transport := "tcp"
+	switch {
+	case conn.config.tlcpConfig != nil:
+		transport = "tlcp"
+	case conn.config.tlsConfig != nil:
+		transport = "tls"
+	}
+
+	var idleDuration time.Duration
+	if idleStart, ok := conn.idleStart.Load().(time.Time); ok {
+		idleDuration = time.Since(idleStart)
+	}
+	lastUsed, _ := conn.lastUsed.Load().(time.Time)
+
+	return map[string]any{
+		"id":                 conn.id,
+		"driverConnectionID": conn.driverConnectionID,
+		"serverConnectionID": serverConnectionID,
+		"address":            conn.addr.String(),
+		"generation":         conn.generation,
+		"serverType":         conn.desc.Kind.String(),
+		"maxWireVersion":     maxWireVersion,
+		"compressor":         compressorName(conn.compressor),
+		"transport":          transport,
+		"idleDuration":       idleDuration,
+		"lastUsed":           lastUsed,
+		"pinned":             c.refCount > 0,
+		"pinReason":          c.pinReason,
+		"pinRefCount":        c.refCount,
+		"closed":             conn.closed(),
+	}
+}
+
+// compressorName returns the name of the compressor used in the "compressors"
+// URI option, or "none" if no compressor was negotiated.
+func compressorName(id wiremessage.CompressorID) string {
+	switch id {
+	case wiremessage.CompressorSnappy:
+		return "snappy"
+	case wiremessage.CompressorZLib:
+		return "zlib"
+	case wiremessage.CompressorZstd:
+		return "zstd"
+	default:
+		if name, ok := driver.CompressorName(id); ok {
+			return name
+		}
+		return "none"
+	}
+}
+
 // UnpinFromCursor updates this connection to reflect that it is no longer pinned to a cursor.
 func (c *Connection) UnpinFromCursor() error {
 	return c.unpin("cursor")
@@ -896,9 +1587,162 @@ func (c *Connection) unpin(reason string) error {
 	}
 
 	c.refCount--
+	if c.refCount == 0 {
+		c.pinReason = ""
+		c.stopPinLeakDetection()
+	}
 	return nil
 }
 
+// NextRequestID returns a request ID for the next wire message sent over this connection using the connection's
+// configured request ID generator. If the connection has been returned to the pool, the global request ID counter is
+// used instead.
+func (c *Connection) NextRequestID() int32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.connection == nil {
+		return wiremessage.NextRequestID()
+	}
+	return c.connection.NextRequestID()
+}
+
+// RunRawCommand runs cmd against the db database on this connection and returns the server's
+// response, bypassing the higher-level operation builders. The command is sent in an OP_MSG wire
+// message with "$db" and, if a server API is configured, the server API fields appended. The
+// message is compressed if the connection negotiated a compressor and the command may be
+// compressed. If the server returns an error response, both the response and the error are
+// returned.
+//
+// RunRawCommand does not retry, select a server, or apply read preferences, session or
+// transaction fields.
+func (c *Connection) RunRawCommand(ctx context.Context, db string, cmd bsoncore.Document) (bsoncore.Document, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.connection == nil {
+		return nil, ErrConnectionClosed
+	}
+
+	elem, err := cmd.IndexErr(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command document: %w", err)
+	}
+	cmdName := elem.Key()
+
+	wm := c.buildRawCommandWireMessage(db, cmd)
+	if c.connection.compressor != wiremessage.CompressorNoOp && driver.CanCompress(cmdName) {
+		wm, err = c.connection.compressWireMessage(wm, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.connection.writeWireMessage(ctx, wm); err != nil {
+		return nil, err
+	}
+	wm, err = c.connection.readWireMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := decodeRawCommandReply(wm)
+	if err != nil {
+		return nil, err
+	}
+	return reply, driver.ExtractErrorFromServerResponse(reply)
+}
+
+// buildRawCommandWireMessage builds an OP_MSG wire message for RunRawCommand.
+func (c *Connection) buildRawCommandWireMessage(db string, cmd bsoncore.Document) []byte {
+	wmIdx, dst := wiremessage.AppendHeaderStart(nil, c.connection.NextRequestID(), 0, wiremessage.OpMsg)
+	dst = wiremessage.AppendMsgFlags(dst, 0)
+	dst = wiremessage.AppendMsgSectionType(dst, wiremessage.SingleDocument)
+
+	idx, dst := bsoncore.AppendDocumentStart(dst)
+	// Copy the command's elements without its length prefix and terminating null byte.
+	dst = append(dst, cmd[4:len(cmd)-1]...)
+	dst = bsoncore.AppendStringElement(dst, "$db", db)
+	if sa := c.serverAPI; sa != nil {
+		dst = bsoncore.AppendStringElement(dst, "apiVersion", sa.ServerAPIVersion)
+		if sa.Strict != nil {
+			dst = bsoncore.AppendBooleanElement(dst, "apiStrict", *sa.Strict)
+		}
+		if sa.DeprecationErrors != nil {
+			dst = bsoncore.AppendBooleanElement(dst, "apiDeprecationErrors", *sa.DeprecationErrors)
+		}
+	}
+	dst, _ = bsoncore.AppendDocumentEnd(dst, idx)
+
+	return bsoncore.UpdateLength(dst, wmIdx, int32(len(dst[wmIdx:])))
+}
+
+// decodeRawCommandReply decompresses wm if necessary and returns the single document section of
+// the OP_MSG reply.
+func decodeRawCommandReply(wm []byte) (bsoncore.Document, error) {
+	_, reqid, respto, opcode, rem, ok := wiremessage.ReadHeader(wm)
+	if !ok {
+		return nil, errors.New("malformed wire message: insufficient bytes")
+	}
+
+	if opcode == wiremessage.OpCompressed {
+		var uncompressedSize int32
+		var compressorID wiremessage.CompressorID
+		opcode, rem, ok = wiremessage.ReadCompressedOriginalOpCode(rem)
+		if ok {
+			uncompressedSize, rem, ok = wiremessage.ReadCompressedUncompressedSize(rem)
+		}
+		if ok {
+			compressorID, rem, ok = wiremessage.ReadCompressedCompressorID(rem)
+		}
+		if !ok {
+			return nil, errors.New("malformed OP_COMPRESSED: insufficient bytes")
+		}
+
+		var err error
+		rem, err = driver.DecompressPayload(rem, driver.CompressionOpts{
+			Compressor:       compressorID,
+			UncompressedSize: uncompressedSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rem, err = wiremessage.RemoveMsgBodyChecksum(reqid, respto, opcode, rem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opcode != wiremessage.OpMsg {
+		return nil, fmt.Errorf("cannot decode reply with opcode %v, expected OP_MSG", opcode)
+	}
+
+	_, rem, ok = wiremessage.ReadMsgFlags(rem)
+	if !ok {
+		return nil, errors.New("malformed OP_MSG: missing flags")
+	}
+	for len(rem) > 0 {
+		var stype wiremessage.SectionType
+		stype, rem, ok = wiremessage.ReadMsgSectionType(rem)
+		if !ok {
+			return nil, errors.New("malformed OP_MSG: missing section type")
+		}
+		switch stype {
+		case wiremessage.SingleDocument:
+			doc, _, ok := wiremessage.ReadMsgSectionSingleDocument(rem)
+			if !ok {
+				return nil, errors.New("malformed OP_MSG: insufficient bytes to read single document")
+			}
+			return doc, doc.Validate()
+		case wiremessage.DocumentSequence:
+			_, _, rem, ok = wiremessage.ReadMsgSectionDocumentSequence(rem)
+			if !ok {
+				return nil, errors.New("malformed OP_MSG: insufficient bytes to read document sequence")
+			}
+		default:
+			return nil, fmt.Errorf("malformed OP_MSG: unknown section type: %v", stype)
+		}
+	}
+	return nil, errors.New("malformed OP_MSG: missing single document section")
+}
+
 // DriverConnectionID returns the driver connection ID.
 func (c *Connection) DriverConnectionID() int64 {
 	return c.connection.DriverConnectionID()
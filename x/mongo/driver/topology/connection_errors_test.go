@@ -12,12 +12,20 @@ package topology
 import (
 	"context"
 	"errors"
+	"io"
 	"net"
+	"syscall"
 	"testing"
+	"time"
 
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
 )
 
 func TestConnectionErrors(t *testing.T) {
@@ -51,4 +59,128 @@ func TestConnectionErrors(t *testing.T) {
 			assert.True(t, errors.Is(err, context.Canceled), "expected error %v, got %v", context.Canceled, err)
 		})
 	})
+	t.Run("connection limit errors", func(t *testing.T) {
+		limitReply := bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 0).
+			AppendString("errmsg", "connection refused because too many open connections: 100").
+			Build()
+		helloReply := bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 1).
+			AppendBoolean("isWritablePrimary", true).
+			AppendInt32("maxWireVersion", 21).
+			Build()
+
+		// newLimitedServerDialer returns a dialer for a fake server that rejects the first rejections handshakes
+		// with a connection limit error and accepts all later ones.
+		newLimitedServerDialer := func(t *testing.T, rejections int, dials *int) Dialer {
+			return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+				reply := helloReply
+				if *dials < rejections {
+					reply = limitReply
+				}
+				*dials++
+
+				nc := &drivertest.ChannelNetConn{
+					Written:  make(chan []byte, 1),
+					ReadResp: make(chan []byte, 2),
+				}
+				err := nc.AddResponse(drivertest.MakeReply(reply))
+				require.NoError(t, err, "AddResponse error")
+				return nc, nil
+			})
+		}
+
+		t.Run("retries with backoff", func(t *testing.T) {
+			var dials int
+			conn := newConnection(address.Address("localhost:27017"),
+				WithHandshaker(func(Handshaker) Handshaker {
+					return auth.Handshaker(nil, &auth.HandshakeOptions{})
+				}),
+				WithDialer(func(Dialer) Dialer {
+					return newLimitedServerDialer(t, 3, &dials)
+				}),
+			)
+			defer conn.close()
+
+			start := time.Now()
+			err := conn.connect(context.Background())
+			require.NoError(t, err, "connect error")
+
+			assert.Equal(t, 4, dials, "expected 3 rejected dials and 1 accepted dial")
+
+			// The minimum backoff before each retry is half of 50ms, 100ms, and 200ms.
+			minElapsed := (connLimitInitialBackoff + 2*connLimitInitialBackoff + 4*connLimitInitialBackoff) / 2
+			assert.True(t, time.Since(start) >= minElapsed, "expected connect to back off for at least %v", minElapsed)
+		})
+		t.Run("does not retry when the server closes the connection during the handshake", func(t *testing.T) {
+			var dials int
+			conn := newConnection(address.Address("localhost:27017"),
+				WithHandshaker(func(Handshaker) Handshaker {
+					return auth.Handshaker(nil, &auth.HandshakeOptions{})
+				}),
+				WithDialer(func(Dialer) Dialer {
+					return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+						dials++
+						nc := &drivertest.ChannelNetConn{
+							Written: make(chan []byte, 1),
+							ReadErr: make(chan error, 1),
+						}
+						nc.ReadErr <- io.EOF
+						return nc, nil
+					})
+				}),
+			)
+			defer conn.close()
+
+			err := conn.connect(context.Background())
+			assert.ErrorIs(t, err, io.EOF)
+			assert.Equal(t, 1, dials, "expected no retries")
+		})
+		t.Run("only server connection limit errors are connection limit errors", func(t *testing.T) {
+			testCases := []struct {
+				name string
+				err  error
+				want bool
+			}{
+				{
+					"connection limit error",
+					ConnectionError{Wrapped: driver.Error{Message: "too many open connections"}, init: true},
+					true,
+				},
+				{"handshake EOF", ConnectionError{Wrapped: io.EOF, init: true, phase: event.PhaseHandshake}, false},
+				{
+					"handshake connection reset",
+					ConnectionError{Wrapped: syscall.ECONNRESET, init: true, phase: event.PhaseHandshake},
+					false,
+				},
+				{"other error", driver.Error{Message: "foo"}, false},
+			}
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					assert.Equal(t, tc.want, isConnectionLimitError(tc.err), "unexpected result for %v", tc.err)
+				})
+			}
+		})
+		t.Run("respects connect timeout", func(t *testing.T) {
+			var dials int
+			conn := newConnection(address.Address("localhost:27017"),
+				WithHandshaker(func(Handshaker) Handshaker {
+					return auth.Handshaker(nil, &auth.HandshakeOptions{})
+				}),
+				WithDialer(func(Dialer) Dialer {
+					return newLimitedServerDialer(t, 100, &dials)
+				}),
+			)
+			defer conn.close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+
+			err := conn.connect(ctx)
+			var driverErr driver.Error
+			require.True(t, errors.As(err, &driverErr), "expected driver.Error, got %v", err)
+			assert.True(t, isConnectionLimitError(err), "expected connection limit error, got %v", err)
+			assert.Equal(t, 1, dials, "expected no retries when the backoff exceeds the connect timeout")
+		})
+	})
 }
@@ -9,6 +9,8 @@ package topology
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"io"
 	"net"
 	"net/http"
 	"time"
@@ -17,8 +19,12 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/httputil"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/ocsp"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
 )
 
 // Dialer is used to make network connections.
@@ -45,11 +51,51 @@ var DefaultDialer Dialer = &net.Dialer{}
 // initialization. Implementations must be goroutine safe.
 type Handshaker = driver.Handshaker
 
+// defaultCertificateExpiryWindow is the default window before a certificate's expiration time in which the
+// certificate expiry callback is invoked.
+const defaultCertificateExpiryWindow = 30 * 24 * time.Hour
+
+// CertificateExpiryFunc is a callback invoked when a certificate used to establish a TLS connection expires within
+// the configured warning window. The isClientCert parameter is true for certificates configured on the client and false
+// for certificates presented by the server.
+type CertificateExpiryFunc func(addr address.Address, cert *x509.Certificate, isClientCert bool)
+
+// VerifyPeerCertificateFunc is a callback that performs custom verification of the certificates presented by the
+// server during a TLS handshake. It has the signature of the VerifyPeerCertificate field of tls.Config.
+type VerifyPeerCertificateFunc func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+
+// HandshakeObserverFunc is a callback invoked with the raw hello command sent and the raw reply received during the
+// initial handshake of a connection, before the reply is parsed into a server description. It is intended for
+// protocol-level debugging and must not modify or retain either document.
+type HandshakeObserverFunc func(addr address.Address, cmd, reply bsoncore.Document)
+
+// DialedConnFunc is a callback invoked with the net.Conn returned by the dialer, before any TLS or TLCP wrapping and
+// before the connection handshake. It is intended for setting socket options and must not read from, write to, close,
+// or set deadlines on the connection. Returning an error aborts connection establishment.
+type DialedConnFunc func(addr address.Address, nc net.Conn) error
+
+// CredentialInjectorFunc is a callback that supplies the credential used to authenticate a new connection to addr. It
+// is called once per connection, after the initial handshake and before authentication, with the server description
+// from the handshake. It returns the authentication mechanism and credential to use, or a nil credential to use the
+// credential configured on the client. Returning an error aborts connection establishment.
+//
+// Speculative authentication is part of the initial handshake, so it is attempted with the credential configured on
+// the client before the callback is called. If the callback returns a credential, any speculative conversation is
+// abandoned and the connection authenticates from scratch with the returned credential, which costs an extra round
+// trip; applications that always supply a credential should disable speculative authentication. The mechanism should
+// be set explicitly, as SASL mechanism negotiation in the initial handshake is done for the configured user.
+type CredentialInjectorFunc func(ctx context.Context, addr address.Address, desc description.Server) (mechanism string, cred *driver.Cred, err error)
+
+// ConnectionIDFunc returns the identifier of a new connection to addr. The seq parameter is a process-wide sequence
+// number that is unique for every connection. Implementations must be goroutine safe.
+type ConnectionIDFunc func(addr address.Address, seq uint64) string
+
 // generationNumberFn is a callback type used by a connection to fetch its generation number given its service ID.
 type generationNumberFn func(serviceID *bson.ObjectID) uint64
 
 type connectionConfig struct {
 	dialer                   Dialer
+	dialLimiter              chan struct{}
 	handshaker               Handshaker
 	idleTimeout              time.Duration
 	cmdMonitor               *event.CommandMonitor
@@ -57,14 +103,30 @@ type connectionConfig struct {
 	tlcpConfig               *tlcp.Config
 	httpClient               *http.Client
 	compressors              []string
+	requireCompression       bool
 	zlibLevel                *int
 	zstdLevel                *int
+	maxUncompressedSize      int32
+	msgChecksum              bool
 	ocspCache                ocsp.Cache
 	disableOCSPEndpointCheck bool
+	ocspHostPolicies         map[string]string
 	tlsConnectionSource      tlsConnectionSource
 	tlcpConnectionSource     tlcpConnectionSource
 	loadBalanced             bool
 	getGenerationFn          generationNumberFn
+	requestIDFn              func() int32
+	certExpiryFn             CertificateExpiryFunc
+	certExpiryWindow         time.Duration
+	handshakeObserver        HandshakeObserverFunc
+	credentialInjector       CredentialInjectorFunc
+	faultInjector            *FaultInjector
+	dialedConnFn             DialedConnFunc
+	keepAlive                *KeepAliveConfig
+	idFn                     ConnectionIDFunc
+	idSeqFn                  func() uint64
+	tlsKeyLogWriter          io.Writer
+	tlsVerifyPeerCertFn      VerifyPeerCertificateFunc
 }
 
 func newConnectionConfig(opts ...ConnectionOption) *connectionConfig {
@@ -73,6 +135,10 @@ func newConnectionConfig(opts ...ConnectionOption) *connectionConfig {
 		tlsConnectionSource:  defaultTLSConnectionSource,
 		tlcpConnectionSource: defaultTLCPConnectionSource,
 		httpClient:           httputil.DefaultHTTPClient,
+		requestIDFn:          wiremessage.NextRequestID,
+		idSeqFn:              nextConnectionID,
+		certExpiryWindow:     defaultCertificateExpiryWindow,
+		maxUncompressedSize:  int32(defaultMaxMessageSize),
 	}
 
 	for _, opt := range opts {
@@ -88,6 +154,14 @@ func newConnectionConfig(opts ...ConnectionOption) *connectionConfig {
 		cfg.dialer = &net.Dialer{}
 	}
 
+	if cfg.requestIDFn == nil {
+		cfg.requestIDFn = wiremessage.NextRequestID
+	}
+
+	if cfg.idSeqFn == nil {
+		cfg.idSeqFn = nextConnectionID
+	}
+
 	return cfg
 }
 
@@ -107,6 +181,14 @@ func WithCompressors(fn func([]string) []string) ConnectionOption {
 	}
 }
 
+// WithRequireCompression configures whether establishing a connection fails if
+// none of the compressors set with WithCompressors is supported by the server.
+func WithRequireCompression(fn func(bool) bool) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.requireCompression = fn(c.requireCompression)
+	}
+}
+
 // WithDialer configures the Dialer to use when making a new connection to MongoDB.
 func WithDialer(fn func(Dialer) Dialer) ConnectionOption {
 	return func(c *connectionConfig) {
@@ -114,6 +196,17 @@ func WithDialer(fn func(Dialer) Dialer) ConnectionOption {
 	}
 }
 
+// WithDialLimiter configures a semaphore that bounds the number of concurrent
+// dials. Each dial waits until it can send to the channel and receives from it
+// once the dial completes, so the channel's capacity is the maximum number of
+// concurrent dials. The same channel can be shared by the connections to every
+// server. If it is nil, dials are not limited.
+func WithDialLimiter(fn func(chan struct{}) chan struct{}) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.dialLimiter = fn(c.dialLimiter)
+	}
+}
+
 // WithHandshaker configures the Handshaker that wll be used to initialize newly
 // dialed connections.
 func WithHandshaker(fn func(Handshaker) Handshaker) ConnectionOption {
@@ -136,6 +229,23 @@ func WithTLSConfig(fn func(*tls.Config) *tls.Config) ConnectionOption {
 	}
 }
 
+// WithTLSKeyLogWriter configures a destination for TLS master secrets in NSS key log format, which can be used to
+// decrypt TLS connections with external programs such as Wireshark. It compromises the security of the connections
+// and must only be used for debugging.
+func WithTLSKeyLogWriter(fn func(io.Writer) io.Writer) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.tlsKeyLogWriter = fn(c.tlsKeyLogWriter)
+	}
+}
+
+// WithTLSVerifyPeerCertificate configures a callback that is set as the VerifyPeerCertificate field of the tls.Config
+// used for each connection. If the tls.Config already has a VerifyPeerCertificate callback, both are run.
+func WithTLSVerifyPeerCertificate(fn func(VerifyPeerCertificateFunc) VerifyPeerCertificateFunc) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.tlsVerifyPeerCertFn = fn(c.tlsVerifyPeerCertFn)
+	}
+}
+
 // WithTLCPConfig configures the TLCP options for a connection.
 func WithTLCPConfig(fn func(*tlcp.Config) *tlcp.Config) ConnectionOption {
 	return func(c *connectionConfig) {
@@ -143,6 +253,77 @@ func WithTLCPConfig(fn func(*tlcp.Config) *tlcp.Config) ConnectionOption {
 	}
 }
 
+// WithCertificateExpiryFunc configures a callback that is invoked after a TLS handshake for every server or client
+// certificate that expires within the certificate expiry window.
+func WithCertificateExpiryFunc(fn func(CertificateExpiryFunc) CertificateExpiryFunc) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.certExpiryFn = fn(c.certExpiryFn)
+	}
+}
+
+// WithDialedConnFunc configures a callback that is invoked with the raw net.Conn of every new connection immediately
+// after it is dialed. See DialedConnFunc for restrictions on what the callback may do.
+func WithDialedConnFunc(fn func(DialedConnFunc) DialedConnFunc) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.dialedConnFn = fn(c.dialedConnFn)
+	}
+}
+
+// KeepAliveConfig specifies the TCP keep-alive parameters applied to the socket of every new connection. A zero value
+// leaves the corresponding setting unchanged.
+type KeepAliveConfig struct {
+	// Idle is how long the connection must be idle before keep-alive probes are sent (TCP_KEEPIDLE).
+	Idle time.Duration
+
+	// Interval is the interval between keep-alive probes (TCP_KEEPINTVL).
+	Interval time.Duration
+
+	// Count is the number of unacknowledged probes after which the connection is considered dead (TCP_KEEPCNT).
+	Count int
+}
+
+// WithKeepAlive configures the TCP keep-alive parameters that are applied to the socket of every new connection
+// immediately after it is dialed. The parameters are only applied on platforms that support them and if the dialed
+// net.Conn exposes its socket through a SyscallConn method. Otherwise, they are ignored.
+func WithKeepAlive(fn func(*KeepAliveConfig) *KeepAliveConfig) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.keepAlive = fn(c.keepAlive)
+	}
+}
+
+// WithCertificateExpiryWindow configures how long before a certificate's expiration time the certificate expiry
+// callback starts being invoked. The default is 30 days.
+func WithCertificateExpiryWindow(fn func(time.Duration) time.Duration) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.certExpiryWindow = fn(c.certExpiryWindow)
+	}
+}
+
+// WithHandshakeObserver configures a callback that is invoked with the raw hello command and reply exchanged during
+// the initial handshake of every connection.
+func WithHandshakeObserver(fn func(HandshakeObserverFunc) HandshakeObserverFunc) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.handshakeObserver = fn(c.handshakeObserver)
+	}
+}
+
+// WithCredentialInjector configures a callback that supplies the credential used to authenticate every new
+// connection. The callback is only called for connections whose handshaker is created by auth.Handshaker, so it is not
+// called for monitoring connections, which are not authenticated.
+func WithCredentialInjector(fn func(CredentialInjectorFunc) CredentialInjectorFunc) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.credentialInjector = fn(c.credentialInjector)
+	}
+}
+
+// WithFaultInjector configures a FaultInjector that injects latency and errors into the reads and writes of every
+// connection. It is intended for testing only.
+func WithFaultInjector(fn func(*FaultInjector) *FaultInjector) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.faultInjector = fn(c.faultInjector)
+	}
+}
+
 // WithHTTPClient configures the HTTP client for a connection.
 func WithHTTPClient(fn func(*http.Client) *http.Client) ConnectionOption {
 	return func(c *connectionConfig) {
@@ -171,6 +352,25 @@ func WithZstdLevel(fn func(*int) *int) ConnectionOption {
 	}
 }
 
+// WithMaxUncompressedSize sets the maximum uncompressed size, in bytes, that an
+// OP_COMPRESSED message read from the server may claim. Messages claiming a
+// larger size are rejected before any memory is allocated to decompress them.
+// If it is not positive, the default maximum message size of 48MB is used.
+func WithMaxUncompressedSize(fn func(int32) int32) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.maxUncompressedSize = fn(c.maxUncompressedSize)
+	}
+}
+
+// WithMessageChecksum configures whether the connection sets the
+// checksumPresent flag and appends a CRC-32C checksum to every OP_MSG it
+// sends. Checksums on OP_MSG replies are verified regardless of this option.
+func WithMessageChecksum(fn func(bool) bool) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.msgChecksum = fn(c.msgChecksum)
+	}
+}
+
 // WithOCSPCache specifies a cache to use for OCSP verification.
 func WithOCSPCache(fn func(ocsp.Cache) ocsp.Cache) ConnectionOption {
 	return func(c *connectionConfig) {
@@ -187,6 +387,15 @@ func WithDisableOCSPEndpointCheck(fn func(bool) bool) ConnectionOption {
 	}
 }
 
+// WithOCSPHostPolicies specifies OCSP policies keyed by host pattern. The policy for the most specific pattern matching
+// a connection's host overrides the setting from WithDisableOCSPEndpointCheck. See ocsp.PolicyForHost for the pattern
+// syntax.
+func WithOCSPHostPolicies(fn func(map[string]string) map[string]string) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.ocspHostPolicies = fn(c.ocspHostPolicies)
+	}
+}
+
 // WithConnectionLoadBalanced specifies whether or not the connection is to a server behind a load balancer.
 func WithConnectionLoadBalanced(fn func(bool) bool) ConnectionOption {
 	return func(c *connectionConfig) {
@@ -194,6 +403,30 @@ func WithConnectionLoadBalanced(fn func(bool) bool) ConnectionOption {
 	}
 }
 
+// WithRequestIDGenerator configures the function used to generate the request IDs of wire messages sent over a
+// connection. The default uses a process-wide atomic counter.
+func WithRequestIDGenerator(fn func(func() int32) func() int32) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.requestIDFn = fn(c.requestIDFn)
+	}
+}
+
+// WithConnectionIDFunc configures the function used to generate connection identifiers. The default formats
+// identifiers as "<address>[-<seq>]".
+func WithConnectionIDFunc(fn func(ConnectionIDFunc) ConnectionIDFunc) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.idFn = fn(c.idFn)
+	}
+}
+
+// WithConnectionIDSequence configures the function used to generate the sequence numbers of connection identifiers.
+// The default uses a process-wide atomic counter.
+func WithConnectionIDSequence(fn func(func() uint64) func() uint64) ConnectionOption {
+	return func(c *connectionConfig) {
+		c.idSeqFn = fn(c.idSeqFn)
+	}
+}
+
 func withGenerationNumberFn(fn func(generationNumberFn) generationNumberFn) ConnectionOption {
 	return func(c *connectionConfig) {
 		c.getGenerationFn = fn(c.getGenerationFn)
@@ -7,23 +7,43 @@
 package topology
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
+	"fmt"
+	"io"
+	"math/big"
 	"math/rand"
 	"net"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/handshake"
+	"go.mongodb.org/mongo-driver/v2/internal/logger"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/ocsp"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/operation"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/wiremessage"
 )
 
@@ -59,6 +79,41 @@ func TestConnection(t *testing.T) {
 				assert.Equal(t, wantTimeout, conn.idleTimeout, "expected idle timeout %v, got %v", wantTimeout,
 					conn.idleTimeout)
 			})
+			t.Run("request ID generator", func(t *testing.T) {
+				var next int32 = 100
+				conn := newConnection(address.Address(""), WithRequestIDGenerator(func(func() int32) func() int32 {
+					return func() int32 {
+						next++
+						return next
+					}
+				}))
+
+				// Request IDs should be taken from the configured generator both on the connection itself and on
+				// the mnet.Connection used during the handshake.
+				assert.Equal(t, int32(101), conn.NextRequestID(), "expected request ID from generator")
+				handshakeConn := mnet.NewConnection(initConnection{conn})
+				assert.NotNil(t, handshakeConn.RequestIDGenerator, "expected RequestIDGenerator to be set")
+				assert.Equal(t, int32(102), handshakeConn.NextRequestID(), "expected request ID from generator")
+			})
+			t.Run("connection ID func", func(t *testing.T) {
+				idFn := WithConnectionIDFunc(func(ConnectionIDFunc) ConnectionIDFunc {
+					return func(addr address.Address, seq uint64) string {
+						return fmt.Sprintf("pod-1/%s/%d", addr, seq)
+					}
+				})
+				conn := newConnection(address.Address("testaddr:27017"), idFn)
+
+				var seq uint64
+				_, err := fmt.Sscanf(conn.ID(), "pod-1/testaddr:27017/%d", &seq)
+				require.NoError(t, err, "unexpected connection ID %q", conn.ID())
+
+				next := newConnection(address.Address("testaddr:27017"), idFn)
+				assert.NotEqual(t, conn.ID(), next.ID(), "expected unique connection IDs")
+			})
+			t.Run("default connection ID", func(t *testing.T) {
+				conn := newConnection(address.Address("testaddr:27017"))
+				assert.True(t, strings.HasPrefix(conn.ID(), "testaddr:27017[-"), "unexpected connection ID %q", conn.ID())
+			})
 		})
 		t.Run("connect", func(t *testing.T) {
 			t.Run("dialer error", func(t *testing.T) {
@@ -98,6 +153,173 @@ func TestConnection(t *testing.T) {
 				connState := atomic.LoadInt64(&conn.state)
 				assert.Equal(t, connDisconnected, connState, "expected connection state %v, got %v", connDisconnected, connState)
 			})
+			t.Run("retains SASL supported mechanisms", func(t *testing.T) {
+				mechs := []string{"SCRAM-SHA-1", "SCRAM-SHA-256"}
+				conn := newConnection(address.Address(""),
+					WithHandshaker(func(Handshaker) Handshaker {
+						return &testHandshaker{
+							getHandshakeInformation: func(context.Context, address.Address, *mnet.Connection) (driver.HandshakeInformation, error) {
+								return driver.HandshakeInformation{SaslSupportedMechs: mechs}, nil
+							},
+						}
+					}),
+					WithDialer(func(Dialer) Dialer {
+						return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+							return &net.TCPConn{}, nil
+						})
+					}),
+				)
+				err := conn.connect(context.Background())
+				require.NoError(t, err, "connect error")
+
+				c := &Connection{connection: conn}
+				assert.Equal(t, mechs, c.SaslSupportedMechs(), "unexpected SASL supported mechanisms")
+			})
+			t.Run("require compression", func(t *testing.T) {
+				newConn := func(serverCompressors []string) *connection {
+					return newConnection(address.Address(""),
+						WithCompressors(func([]string) []string { return []string{"snappy", "zstd"} }),
+						WithRequireCompression(func(bool) bool { return true }),
+						WithHandshaker(func(Handshaker) Handshaker {
+							return &testHandshaker{
+								getHandshakeInformation: func(context.Context, address.Address, *mnet.Connection) (driver.HandshakeInformation, error) {
+									desc := description.Server{Compression: serverCompressors}
+									return driver.HandshakeInformation{Description: desc}, nil
+								},
+							}
+						}),
+						WithDialer(func(Dialer) Dialer {
+							return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+								return &net.TCPConn{}, nil
+							})
+						}),
+					)
+				}
+
+				t.Run("disjoint compressors", func(t *testing.T) {
+					conn := newConn([]string{"zlib"})
+					err := conn.connect(context.Background())
+
+					var connErr ConnectionError
+					require.True(t, errors.As(err, &connErr), "expected a ConnectionError, got %v", err)
+					assert.True(t, connErr.init, "expected the error to occur during initialization")
+					assert.ErrorIs(t, err, errNoCommonCompressor)
+					assert.Equal(t, connDisconnected, atomic.LoadInt64(&conn.state), "expected the connection to be disconnected")
+				})
+				t.Run("common compressor", func(t *testing.T) {
+					conn := newConn([]string{"zlib", "zstd"})
+					err := conn.connect(context.Background())
+					require.NoError(t, err, "connect error")
+					assert.Equal(t, wiremessage.CompressorZstd, conn.compressor, "expected zstd to be negotiated")
+				})
+			})
+			t.Run("dialed conn callback", func(t *testing.T) {
+				addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+					_ = nc.Close()
+				})
+
+				var gotAddr address.Address
+				conn := newConnection(address.Address(addr.String()),
+					WithDialedConnFunc(func(DialedConnFunc) DialedConnFunc {
+						return func(addr address.Address, nc net.Conn) error {
+							gotAddr = addr
+
+							tcpConn, ok := nc.(*net.TCPConn)
+							require.True(t, ok, "expected *net.TCPConn, got %T", nc)
+							if err := tcpConn.SetLinger(0); err != nil {
+								return err
+							}
+							return tcpConn.SetReadBuffer(64 * 1024)
+						}
+					}),
+				)
+				err := conn.connect(context.Background())
+				require.NoError(t, err, "connect error")
+				defer func() { _ = conn.close() }()
+
+				assert.Equal(t, address.Address(addr.String()), gotAddr, "expected callback to receive the connection address")
+			})
+			t.Run("dialed conn callback error", func(t *testing.T) {
+				err := errors.New("callback error")
+				var want error = ConnectionError{
+					Wrapped: err,
+					init:    true,
+					message: "dialed connection callback failed for testaddr:27017",
+				}
+				conn := newConnection(address.Address("testaddr"),
+					WithDialer(func(Dialer) Dialer {
+						return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+							return &net.TCPConn{}, nil
+						})
+					}),
+					WithDialedConnFunc(func(DialedConnFunc) DialedConnFunc {
+						return func(address.Address, net.Conn) error { return err }
+					}),
+				)
+				got := conn.connect(context.Background())
+				if !cmp.Equal(got, want, cmp.Comparer(compareErrors)) {
+					t.Errorf("errors do not match. got %v; want %v", got, want)
+				}
+				connState := atomic.LoadInt64(&conn.state)
+				assert.Equal(t, connDisconnected, connState, "expected connection state %v, got %v", connDisconnected, connState)
+			})
+			t.Run("handshake observer", func(t *testing.T) {
+				hello := bsoncore.NewDocumentBuilder().
+					AppendInt32(handshake.LegacyHello, 1).
+					AppendBoolean("helloOk", true).
+					Build()
+				reply := bsoncore.NewDocumentBuilder().
+					AppendInt32("ok", 1).
+					AppendBoolean("isWritablePrimary", true).
+					AppendInt32("maxWireVersion", 21).
+					Build()
+
+				nc := &drivertest.ChannelNetConn{
+					Written:  make(chan []byte, 1),
+					ReadResp: make(chan []byte, 2),
+				}
+				err := nc.AddResponse(drivertest.MakeReply(reply))
+				require.NoError(t, err, "AddResponse error")
+
+				var gotAddr address.Address
+				var gotCmd, gotReply bsoncore.Document
+				conn := newConnection(address.Address("localhost:27017"),
+					WithDialer(func(Dialer) Dialer {
+						return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+							return nc, nil
+						})
+					}),
+					WithHandshaker(func(Handshaker) Handshaker {
+						return &testHandshaker{
+							getHandshakeInformation: func(ctx context.Context, _ address.Address, conn *mnet.Connection) (driver.HandshakeInformation, error) {
+								idx, wm := wiremessage.AppendHeaderStart(nil, 1, 0, wiremessage.OpQuery)
+								wm = wiremessage.AppendQueryFlags(wm, 0)
+								wm = wiremessage.AppendQueryFullCollectionName(wm, "admin.$cmd")
+								wm = wiremessage.AppendQueryNumberToSkip(wm, 0)
+								wm = wiremessage.AppendQueryNumberToReturn(wm, -1)
+								wm = append(wm, hello...)
+								wm = bsoncore.UpdateLength(wm, idx, int32(len(wm[idx:])))
+								if err := conn.Write(ctx, wm); err != nil {
+									return driver.HandshakeInformation{}, err
+								}
+								_, err := conn.Read(ctx)
+								return driver.HandshakeInformation{}, err
+							},
+						}
+					}),
+					WithHandshakeObserver(func(HandshakeObserverFunc) HandshakeObserverFunc {
+						return func(addr address.Address, cmd, reply bsoncore.Document) {
+							gotAddr, gotCmd, gotReply = addr, cmd, reply
+						}
+					}),
+				)
+
+				err = conn.connect(context.Background())
+				require.NoError(t, err, "error establishing connection")
+				assert.Equal(t, address.Address("localhost:27017"), gotAddr)
+				assert.Equal(t, hello, gotCmd)
+				assert.Equal(t, reply, gotReply)
+			})
 			t.Run("context is not pinned by connect", func(t *testing.T) {
 				// connect creates a cancel-able version of the context passed to it and stores the CancelFunc on the
 				// connection. The CancelFunc must be set to nil once the connection has been established so the driver
@@ -205,6 +427,201 @@ func TestConnection(t *testing.T) {
 						})
 					}
 				})
+				t.Run("key log writer", func(t *testing.T) {
+					serverCert := newTestKeyPair(t)
+					clientNc, serverNc := net.Pipe()
+					defer serverNc.Close()
+					go func() {
+						server := tls.Server(serverNc, &tls.Config{Certificates: []tls.Certificate{serverCert}})
+						_, _ = io.Copy(io.Discard, server)
+					}()
+
+					var sentCfg *tls.Config
+					var keyLog bytes.Buffer
+					connOpts := []ConnectionOption{
+						WithDialer(func(Dialer) Dialer {
+							return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+								return clientNc, nil
+							})
+						}),
+						WithHandshaker(func(Handshaker) Handshaker {
+							return &testHandshaker{}
+						}),
+						WithTLSConfig(func(*tls.Config) *tls.Config {
+							// Skip verification so the handshake does not require OCSP checks.
+							return &tls.Config{InsecureSkipVerify: true}
+						}),
+						WithTLSKeyLogWriter(func(io.Writer) io.Writer {
+							return &keyLog
+						}),
+						withTLSConnectionSource(func(tlsConnectionSource) tlsConnectionSource {
+							return tlsConnectionSourceFn(func(nc net.Conn, cfg *tls.Config) tlsConn {
+								sentCfg = cfg
+								return tls.Client(nc, cfg)
+							})
+						}),
+					}
+					conn := newConnection(address.Address("localhost:27017"), connOpts...)
+
+					err := conn.connect(context.Background())
+					require.NoError(t, err)
+					defer conn.close()
+
+					require.NotNil(t, sentCfg, "expected TLS config to be set, but was not")
+					assert.Equal(t, &keyLog, sentCfg.KeyLogWriter, "expected KeyLogWriter to be set")
+					assert.Contains(t, keyLog.String(), "CLIENT_HANDSHAKE_TRAFFIC_SECRET ",
+						"expected key log lines to be written during the handshake")
+				})
+				t.Run("verify peer certificate callback", func(t *testing.T) {
+					serverCert := newTestKeyPair(t)
+					leaf, err := x509.ParseCertificate(serverCert.Certificate[0])
+					require.NoError(t, err)
+					pinned := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+					errPinMismatch := errors.New("SPKI hash does not match the pinned hash")
+
+					// pinSPKI returns a callback that accepts the server certificate
+					// only if the hash of its SubjectPublicKeyInfo is pin.
+					pinSPKI := func(pin [sha256.Size]byte) VerifyPeerCertificateFunc {
+						return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+							cert, err := x509.ParseCertificate(rawCerts[0])
+							if err != nil {
+								return err
+							}
+							if sha256.Sum256(cert.RawSubjectPublicKeyInfo) != pin {
+								return errPinMismatch
+							}
+							return nil
+						}
+					}
+
+					testCases := []struct {
+						name    string
+						pin     [sha256.Size]byte
+						wantErr error
+					}{
+						{"accepts pinned certificate", pinned, nil},
+						{"rejects other certificate", sha256.Sum256([]byte("other")), errPinMismatch},
+					}
+					for _, tc := range testCases {
+						t.Run(tc.name, func(t *testing.T) {
+							// Use a TCP listener instead of net.Pipe so that the client can send an alert while
+							// the server is still writing its handshake messages.
+							addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+								server := tls.Server(nc, &tls.Config{Certificates: []tls.Certificate{serverCert}})
+								_, _ = io.Copy(io.Discard, server)
+								_ = nc.Close()
+							})
+
+							var called bool
+							connOpts := []ConnectionOption{
+								WithHandshaker(func(Handshaker) Handshaker {
+									return &testHandshaker{}
+								}),
+								WithTLSConfig(func(*tls.Config) *tls.Config {
+									// Skip the standard verification of the self-signed certificate so the
+									// callback is the only verification.
+									return &tls.Config{
+										InsecureSkipVerify: true,
+										VerifyPeerCertificate: func([][]byte, [][]*x509.Certificate) error {
+											called = true
+											return nil
+										},
+									}
+								}),
+								WithTLSVerifyPeerCertificate(func(VerifyPeerCertificateFunc) VerifyPeerCertificateFunc {
+									return pinSPKI(tc.pin)
+								}),
+							}
+							conn := newConnection(address.Address(addr.String()), connOpts...)
+
+							err := conn.connect(context.Background())
+							defer conn.close()
+
+							assert.True(t, called, "expected the tls.Config callback to be run")
+							if tc.wantErr == nil {
+								require.NoError(t, err)
+								return
+							}
+							assert.ErrorIs(t, err, tc.wantErr)
+						})
+					}
+				})
+				t.Run("certificate expiry callback", func(t *testing.T) {
+					nearExpiry := newTestCertificate(t, time.Now().Add(48*time.Hour))
+					farExpiry := newTestCertificate(t, time.Now().Add(365*24*time.Hour))
+
+					type expiryCall struct {
+						cert         *x509.Certificate
+						isClientCert bool
+					}
+
+					testCases := []struct {
+						name        string
+						peerCerts   []*x509.Certificate
+						clientCerts []tls.Certificate
+						want        []expiryCall
+					}{
+						{
+							name:      "near expiry server certificate",
+							peerCerts: []*x509.Certificate{nearExpiry},
+							want:      []expiryCall{{cert: nearExpiry, isClientCert: false}},
+						},
+						{
+							name:        "near expiry client certificate",
+							peerCerts:   []*x509.Certificate{farExpiry},
+							clientCerts: []tls.Certificate{{Certificate: [][]byte{nearExpiry.Raw}}},
+							want:        []expiryCall{{cert: nearExpiry, isClientCert: true}},
+						},
+						{
+							name:        "no certificates near expiry",
+							peerCerts:   []*x509.Certificate{farExpiry},
+							clientCerts: []tls.Certificate{{Certificate: [][]byte{farExpiry.Raw}}},
+							want:        nil,
+						},
+					}
+					for _, tc := range testCases {
+						t.Run(tc.name, func(t *testing.T) {
+							var got []expiryCall
+							connOpts := []ConnectionOption{
+								WithDialer(func(Dialer) Dialer {
+									return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+										return &net.TCPConn{}, nil
+									})
+								}),
+								WithHandshaker(func(Handshaker) Handshaker {
+									return &testHandshaker{}
+								}),
+								WithTLSConfig(func(*tls.Config) *tls.Config {
+									// Skip verification so the fake handshake does not require OCSP checks.
+									return &tls.Config{Certificates: tc.clientCerts, InsecureSkipVerify: true}
+								}),
+								withTLSConnectionSource(func(tlsConnectionSource) tlsConnectionSource {
+									return tlsConnectionSourceFn(func(nc net.Conn, _ *tls.Config) tlsConn {
+										return &testTLSConn{Conn: nc, state: tls.ConnectionState{PeerCertificates: tc.peerCerts}}
+									})
+								}),
+								WithCertificateExpiryWindow(func(time.Duration) time.Duration {
+									return 7 * 24 * time.Hour
+								}),
+								WithCertificateExpiryFunc(func(CertificateExpiryFunc) CertificateExpiryFunc {
+									return func(_ address.Address, cert *x509.Certificate, isClientCert bool) {
+										got = append(got, expiryCall{cert: cert, isClientCert: isClientCert})
+									}
+								}),
+							}
+							conn := newConnection(address.Address("localhost:27017"), connOpts...)
+
+							err := conn.connect(context.Background())
+							require.NoError(t, err)
+							require.Len(t, got, len(tc.want), "expected %d callback invocations, got %d", len(tc.want), len(got))
+							for i, want := range tc.want {
+								assert.True(t, want.cert.Equal(got[i].cert), "expected certificate %d to match", i)
+								assert.Equal(t, want.isClientCert, got[i].isClientCert,
+									"expected isClientCert %v, got %v", want.isClientCert, got[i].isClientCert)
+							}
+						})
+					}
+				})
 			})
 		})
 		t.Run("writeWireMessage", func(t *testing.T) {
@@ -463,6 +880,53 @@ func TestConnection(t *testing.T) {
 						})
 					}
 				})
+				t.Run("uncompressed size too large errors", func(t *testing.T) {
+					// compressedMessage returns an OP_COMPRESSED message that claims an
+					// uncompressed size of size bytes but only carries a few bytes.
+					compressedMessage := func(size int32) []byte {
+						idx, wm := wiremessage.AppendHeaderStart(nil, 1, 0, wiremessage.OpCompressed)
+						wm = wiremessage.AppendCompressedOriginalOpCode(wm, wiremessage.OpMsg)
+						wm = wiremessage.AppendCompressedUncompressedSize(wm, size)
+						wm = wiremessage.AppendCompressedCompressorID(wm, wiremessage.CompressorZLib)
+						wm = append(wm, 0x01, 0x02, 0x03)
+						return bsoncore.UpdateLength(wm, idx, int32(len(wm[idx:])))
+					}
+
+					testCases := []struct {
+						name    string
+						size    int32
+						maxSize int32
+						wantErr bool
+					}{
+						{"enormous size with default maximum", 1<<31 - 1, 0, true},
+						{"size above configured maximum", 2048, 1024, true},
+						{"size at configured maximum", 1024, 1024, false},
+						{"size above default maximum with larger configured maximum", 64000000, 1 << 30, false},
+					}
+					for _, tc := range testCases {
+						t.Run(tc.name, func(t *testing.T) {
+							wm := compressedMessage(tc.size)
+							tnc := &testNetConn{buf: wm}
+							conn := &connection{
+								id:     "foobar",
+								nc:     tnc,
+								state:  connConnected,
+								config: newConnectionConfig(WithMaxUncompressedSize(func(int32) int32 { return tc.maxSize })),
+							}
+							conn.cancellationListener = newTestCancellationListener(false)
+
+							got, err := conn.readWireMessage(context.Background())
+							if !tc.wantErr {
+								require.NoError(t, err)
+								assert.Equal(t, wm, got, "expected the wire message to be read")
+								return
+							}
+							assert.ErrorIs(t, err, driver.ErrUncompressedSizeTooLarge)
+							assert.Nil(t, got, "expected no wire message")
+							assert.True(t, tnc.closed, "expected the connection to be closed")
+						})
+					}
+				})
 				t.Run("success", func(t *testing.T) {
 					want := []byte{0x0A, 0x00, 0x00, 0x00, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A}
 					tnc := &testNetConn{buf: make([]byte, len(want))}
@@ -644,6 +1108,284 @@ func TestConnection(t *testing.T) {
 			}
 		})
 
+		t.Run("RunRawCommand", func(t *testing.T) {
+			makeReply := func(doc bsoncore.Document) []byte {
+				idx, wm := wiremessage.AppendHeaderStart(nil, 1, 0, wiremessage.OpMsg)
+				wm = wiremessage.AppendMsgFlags(wm, 0)
+				wm = wiremessage.AppendMsgSectionType(wm, wiremessage.SingleDocument)
+				wm = append(wm, doc...)
+				return bsoncore.UpdateLength(wm, idx, int32(len(wm[idx:])))
+			}
+			newConn := func(t *testing.T, reply bsoncore.Document) (*Connection, *drivertest.ChannelNetConn) {
+				t.Helper()
+
+				nc := &drivertest.ChannelNetConn{
+					Written:  make(chan []byte, 1),
+					ReadResp: make(chan []byte, 2),
+				}
+				err := nc.AddResponse(makeReply(reply))
+				require.NoError(t, err, "AddResponse error")
+
+				c := newConnection(address.Address(""))
+				c.nc = nc
+				c.state = connConnected
+
+				conn := &Connection{
+					connection: c,
+					serverAPI:  driver.NewServerAPIOptions("1").SetStrict(true),
+				}
+				return conn, nc
+			}
+
+			t.Run("success", func(t *testing.T) {
+				reply := bsoncore.NewDocumentBuilder().
+					AppendInt32("ok", 1).
+					AppendString("msg", "pong").
+					Build()
+				conn, nc := newConn(t, reply)
+
+				cmd := bsoncore.NewDocumentBuilder().AppendInt32("ping", 1).Build()
+				got, err := conn.RunRawCommand(context.Background(), "admin", cmd)
+				require.NoError(t, err, "RunRawCommand error")
+				assert.Equal(t, reply, got)
+
+				sent, err := drivertest.GetCommandFromMsgWireMessage(nc.GetWrittenMessage())
+				require.NoError(t, err, "GetCommandFromMsgWireMessage error")
+				want := bsoncore.NewDocumentBuilder().
+					AppendInt32("ping", 1).
+					AppendString("$db", "admin").
+					AppendString("apiVersion", "1").
+					AppendBoolean("apiStrict", true).
+					Build()
+				assert.Equal(t, want, sent)
+			})
+			t.Run("error reply", func(t *testing.T) {
+				reply := bsoncore.NewDocumentBuilder().
+					AppendInt32("ok", 0).
+					AppendInt32("code", 59).
+					AppendString("errmsg", "no such command: 'foo'").
+					Build()
+				conn, _ := newConn(t, reply)
+
+				cmd := bsoncore.NewDocumentBuilder().AppendInt32("foo", 1).Build()
+				got, err := conn.RunRawCommand(context.Background(), "admin", cmd)
+				assert.Equal(t, reply, got)
+
+				var cmdErr driver.Error
+				require.True(t, errors.As(err, &cmdErr), "expected a driver.Error, got %v", err)
+				assert.Equal(t, int32(59), cmdErr.Code)
+			})
+			t.Run("closed connection", func(t *testing.T) {
+				cmd := bsoncore.NewDocumentBuilder().AppendInt32("ping", 1).Build()
+				_, err := (&Connection{}).RunRawCommand(context.Background(), "admin", cmd)
+				assert.Equal(t, ErrConnectionClosed, err)
+			})
+		})
+		t.Run("AbortStream", func(t *testing.T) {
+			makeReply := func(flags wiremessage.MsgFlag, doc bsoncore.Document) []byte {
+				idx, wm := wiremessage.AppendHeaderStart(nil, 1, 0, wiremessage.OpMsg)
+				wm = wiremessage.AppendMsgFlags(wm, flags)
+				wm = wiremessage.AppendMsgSectionType(wm, wiremessage.SingleDocument)
+				wm = append(wm, doc...)
+				return bsoncore.UpdateLength(wm, idx, int32(len(wm[idx:])))
+			}
+			batch := func(cursorID int64) bsoncore.Document {
+				return bsoncore.NewDocumentBuilder().
+					AppendDocument("cursor", bsoncore.NewDocumentBuilder().
+						AppendInt64("id", cursorID).
+						AppendString("ns", "db.coll").
+						AppendArray("nextBatch", bsoncore.NewArrayBuilder().Build()).
+						Build()).
+					AppendInt32("ok", 1).
+					Build()
+			}
+			// newStreamingConn returns a connection to a fake server that streams the given
+			// replies.
+			newStreamingConn := func(t *testing.T, replies ...[]byte) (*Connection, *drivertest.ChannelNetConn) {
+				t.Helper()
+
+				nc := &drivertest.ChannelNetConn{
+					Written:  make(chan []byte, 2),
+					ReadResp: make(chan []byte, 2*len(replies)),
+				}
+				for _, reply := range replies {
+					err := nc.AddResponse(reply)
+					require.NoError(t, err, "AddResponse error")
+				}
+
+				c := newConnection(address.Address(""))
+				c.nc = nc
+				c.state = connConnected
+				c.canStream = true
+				return &Connection{connection: c}, nc
+			}
+
+			t.Run("closes a streaming connection", func(t *testing.T) {
+				conn, nc := newStreamingConn(t,
+					makeReply(wiremessage.MoreToCome, batch(42)),
+					makeReply(wiremessage.MoreToCome, batch(42)),
+				)
+
+				_, err := conn.Read(context.Background())
+				require.NoError(t, err, "Read error")
+				assert.True(t, conn.connection.getCurrentlyStreaming(), "expected connection to be streaming")
+
+				err = conn.AbortStream(context.Background())
+				require.NoError(t, err, "AbortStream error")
+				assert.False(t, conn.connection.getCurrentlyStreaming(), "expected streaming state to be reset")
+				assert.True(t, conn.connection.closed(), "expected connection to be closed")
+				assert.Nil(t, nc.GetWrittenMessage(), "expected nothing to be written")
+			})
+			t.Run("not streaming", func(t *testing.T) {
+				conn, nc := newStreamingConn(t, makeReply(0, batch(0)))
+
+				_, err := conn.Read(context.Background())
+				require.NoError(t, err, "Read error")
+				assert.False(t, conn.connection.getCurrentlyStreaming(), "expected connection not to be streaming")
+
+				err = conn.AbortStream(context.Background())
+				require.NoError(t, err, "AbortStream error")
+				assert.False(t, conn.connection.closed(), "expected connection to remain open")
+				assert.Nil(t, nc.GetWrittenMessage(), "expected nothing to be written")
+			})
+			t.Run("closed connection", func(t *testing.T) {
+				err := (&Connection{}).AbortStream(context.Background())
+				assert.Equal(t, ErrConnectionClosed, err)
+			})
+		})
+		t.Run("SetDeadline", func(t *testing.T) {
+			t.Run("read times out", func(t *testing.T) {
+				done := make(chan struct{})
+				addr := bootstrapConnections(t, 1, func(net.Conn) { <-done })
+				defer close(done)
+
+				pool := newPool(poolConfig{
+					Address:        address.Address(addr.String()),
+					ConnectTimeout: defaultConnectionTimeout,
+				})
+				err := pool.ready()
+				require.NoError(t, err, "pool.ready error")
+				defer pool.close(context.Background())
+
+				c, err := pool.checkOut(context.Background())
+				require.NoError(t, err, "checkOut error")
+				conn := &Connection{connection: c}
+
+				err = conn.SetDeadline(time.Now().Add(10 * time.Millisecond))
+				require.NoError(t, err, "SetDeadline error")
+
+				_, err = conn.Read(context.Background())
+				var netErr net.Error
+				require.True(t, errors.As(err, &netErr), "expected a net.Error, got %v", err)
+				assert.True(t, netErr.Timeout(), "expected a timeout error, got %v", err)
+				assert.False(t, errors.Is(err, context.DeadlineExceeded),
+					"expected error not to be context.DeadlineExceeded")
+			})
+			t.Run("earlier context deadline wins", func(t *testing.T) {
+				conn := &connection{deadline: time.Now().Add(time.Hour)}
+
+				ctxDeadline := time.Now().Add(time.Minute)
+				ctx, cancel := context.WithDeadline(context.Background(), ctxDeadline)
+				defer cancel()
+
+				got, contextDeadlineUsed := conn.ioDeadline(ctx)
+				assert.Equal(t, ctxDeadline, got, "expected the context deadline")
+				assert.True(t, contextDeadlineUsed, "expected the context deadline to be used")
+
+				conn.deadline = time.Now()
+				got, contextDeadlineUsed = conn.ioDeadline(ctx)
+				assert.Equal(t, conn.deadline, got, "expected the connection deadline")
+				assert.False(t, contextDeadlineUsed, "expected the context deadline not to be used")
+			})
+			t.Run("closed connection", func(t *testing.T) {
+				err := (&Connection{}).SetDeadline(time.Now())
+				assert.Equal(t, ErrConnectionClosed, err)
+			})
+		})
+		t.Run("Diagnostics", func(t *testing.T) {
+			addr := bootstrapConnections(t, 1, func(net.Conn) {})
+			pool := newPool(poolConfig{
+				Address:        address.Address(addr.String()),
+				ConnectTimeout: defaultConnectionTimeout,
+			})
+			err := pool.ready()
+			require.NoError(t, err, "pool.ready error")
+			defer pool.close(context.Background())
+
+			c, err := pool.checkOut(context.Background())
+			require.NoError(t, err, "checkOut error")
+			serverConnectionID := int64(42)
+			c.serverConnectionID = &serverConnectionID
+			c.desc = description.Server{
+				Kind:        description.ServerKindRSPrimary,
+				WireVersion: &description.VersionRange{Max: 21},
+			}
+			c.compressor = wiremessage.CompressorZstd
+			// Report a TLS transport without requiring the fake server to complete a TLS handshake.
+			c.config.tlsConfig = &tls.Config{}
+
+			conn := &Connection{connection: c}
+			err = conn.PinToCursor()
+			require.NoError(t, err, "PinToCursor error")
+
+			diag := conn.Diagnostics()
+			want := map[string]any{
+				"id":                 c.id,
+				"driverConnectionID": c.driverConnectionID,
+				"serverConnectionID": int64(42),
+				"address":            addr.String(),
+				"generation":         c.generation,
+				"serverType":         "RSPrimary",
+				"maxWireVersion":     int32(21),
+				"compressor":         "zstd",
+				"transport":          "tls",
+				"idleDuration":       time.Duration(0),
+				"lastUsed":           time.Time{},
+				"pinned":             true,
+				"pinReason":          "cursor",
+				"pinRefCount":        1,
+				"closed":             false,
+			}
+			assert.Equal(t, want, diag, "unexpected diagnostics")
+
+			err = conn.UnpinFromCursor()
+			require.NoError(t, err, "UnpinFromCursor error")
+			err = conn.Close()
+			require.NoError(t, err, "Close error")
+			assert.Equal(t, map[string]any{"closed": true}, conn.Diagnostics(), "unexpected diagnostics after Close")
+		})
+		t.Run("LastUsed", func(t *testing.T) {
+			reply := []byte{0x0A, 0x00, 0x00, 0x00, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A}
+			tnc := &testNetConn{}
+			c := &connection{id: "foobar", nc: tnc, state: connConnected}
+			c.cancellationListener = newTestCancellationListener(false)
+			conn := &Connection{connection: c}
+
+			// roundTrip writes a message and reads a reply and returns the times
+			// before and after the round trip.
+			roundTrip := func() (time.Time, time.Time) {
+				before := time.Now()
+				err := conn.Write(context.Background(), []byte{0x04, 0x00, 0x00, 0x00})
+				require.NoError(t, err, "Write error")
+				tnc.buf = append(tnc.buf[:0], reply...)
+				_, err = conn.Read(context.Background())
+				require.NoError(t, err, "Read error")
+				return before, time.Now()
+			}
+
+			assert.True(t, conn.LastUsed().IsZero(), "expected no last used time before a round trip")
+
+			before, after := roundTrip()
+			first := conn.LastUsed()
+			assert.False(t, first.Before(before), "expected last used time %v to be after %v", first, before)
+			assert.False(t, first.After(after), "expected last used time %v to be before %v", first, after)
+
+			time.Sleep(time.Millisecond)
+			roundTrip()
+			assert.True(t, conn.LastUsed().After(first), "expected the last used time to advance")
+
+			assert.True(t, (&Connection{}).LastUsed().IsZero(), "expected no last used time for a closed connection")
+		})
 		t.Run("pinning", func(t *testing.T) {
 			makeMultipleConnections := func(t *testing.T, numConns int) (*pool, []*Connection, func()) {
 				t.Helper()
@@ -691,13 +1433,63 @@ func TestConnection(t *testing.T) {
 				assert.Nil(t, err, "PinToCursor error: %v", err)
 				assertPoolPinnedStats(t, pool, 1, 0)
 
+				pinned, reason, refCount := conn.PinState()
+				assert.True(t, pinned, "expected connection to be pinned")
+				assert.Equal(t, "cursor", reason, "expected pin reason %q, got %q", "cursor", reason)
+				assert.Equal(t, 1, refCount, "expected refCount 1, got %d", refCount)
+
 				err = conn.UnpinFromCursor()
 				assert.Nil(t, err, "UnpinFromCursor error: %v", err)
 
+				pinned, reason, refCount = conn.PinState()
+				assert.False(t, pinned, "expected connection not to be pinned")
+				assert.Equal(t, "", reason, "expected empty pin reason, got %q", reason)
+				assert.Equal(t, 0, refCount, "expected refCount 0, got %d", refCount)
+
 				err = conn.Close()
 				assert.Nil(t, err, "Close error: %v", err)
 				assertPoolPinnedStats(t, pool, 0, 0)
 			})
+			t.Run("leak detection", func(t *testing.T) {
+				addr := bootstrapConnections(t, 1, func(net.Conn) {})
+				sink := &pinLeakLogSink{msgs: make(chan pinLeakLogMessage, 1)}
+				lgr, err := logger.New(sink, 0, map[logger.Component]logger.Level{
+					logger.ComponentConnection: logger.LevelInfo,
+				})
+				require.NoError(t, err, "logger.New error")
+
+				pool := newPool(poolConfig{
+					Address:          address.Address(addr.String()),
+					ConnectTimeout:   defaultConnectionTimeout,
+					Logger:           lgr,
+					PinLeakThreshold: 50 * time.Millisecond,
+				})
+				err = pool.ready()
+				require.NoError(t, err, "pool.ready error")
+				defer pool.close(context.Background())
+
+				c, err := pool.checkOut(context.Background())
+				require.NoError(t, err, "checkOut error")
+				conn := &Connection{connection: c}
+
+				err = conn.PinToCursor()
+				require.NoError(t, err, "PinToCursor error")
+
+				select {
+				case msg := <-sink.msgs:
+					assert.Equal(t, logger.ConnectionPinLeaked, msg.msg)
+					assert.Equal(t, "cursor", msg.kv[logger.KeyReason])
+					assert.Equal(t, int64(50), msg.kv[logger.KeyDurationMS])
+					assert.Contains(t, msg.kv[logger.KeyStackTrace], "PinToCursor")
+				case <-time.After(5 * time.Second):
+					t.Fatal("timed out waiting for the pin leak warning")
+				}
+
+				err = conn.UnpinFromCursor()
+				require.NoError(t, err, "UnpinFromCursor error")
+				err = conn.Close()
+				require.NoError(t, err, "Close error")
+			})
 			t.Run("transactions", func(t *testing.T) {
 				pool, conn, disconnect := makeOneConnection(t)
 				defer disconnect()
@@ -807,6 +1599,30 @@ func BenchmarkConnection(b *testing.B) {
 	})
 }
 
+type pinLeakLogMessage struct {
+	msg string
+	kv  map[string]interface{}
+}
+
+// pinLeakLogSink is a logger.LogSink that sends pin leak warnings to a channel.
+type pinLeakLogSink struct {
+	msgs chan pinLeakLogMessage
+}
+
+func (s *pinLeakLogSink) Info(_ int, msg string, keysAndValues ...interface{}) {
+	if msg != logger.ConnectionPinLeaked {
+		return
+	}
+
+	kv := make(map[string]interface{}, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		kv[keysAndValues[i].(string)] = keysAndValues[i+1]
+	}
+	s.msgs <- pinLeakLogMessage{msg: msg, kv: kv}
+}
+
+func (*pinLeakLogSink) Error(error, string, ...interface{}) {}
+
 // cancellationTestNetConn is a net.Conn implementation that is used to test context.Cancellation during an in-progress
 // network read or write. This type has two unbuffered channels: operationStartedChan and continueChan. When Read/Write
 // starts, the type will write to operationStartedChan, which will block until the test reads from it. This signals to
@@ -881,6 +1697,55 @@ func (c *cancellationTestNetConn) Write(b []byte) (n int, err error) {
 	return 0, errors.New("cancelled write")
 }
 
+// testTLSConn is a tlsConn that skips the TLS handshake and reports a fixed connection state.
+type testTLSConn struct {
+	net.Conn
+	state tls.ConnectionState
+}
+
+func (c *testTLSConn) HandshakeContext(context.Context) error { return nil }
+func (c *testTLSConn) ConnectionState() tls.ConnectionState   { return c.state }
+
+// newTestCertificate creates a self-signed certificate that expires at the given time.
+func newTestCertificate(t *testing.T, notAfter time.Time) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// newTestKeyPair creates a self-signed certificate and private key that can be used by a TLS server.
+func newTestKeyPair(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
 type testNetConn struct {
 	nc  net.Conn
 	buf []byte
@@ -1110,6 +1975,346 @@ func (tc *testContext) Deadline() (time.Time, bool) {
 	return tc.deadline, false
 }
 
+func TestConnection_messageChecksum(t *testing.T) {
+	t.Parallel()
+
+	reply := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "ok", 1))
+	msg := func() []byte {
+		idx, wm := wiremessage.AppendHeaderStart(nil, 1, 0, wiremessage.OpMsg)
+		wm = wiremessage.AppendMsgFlags(wm, 0)
+		wm = wiremessage.AppendMsgSectionType(wm, wiremessage.SingleDocument)
+		wm = append(wm, reply...)
+		return bsoncore.UpdateLength(wm, idx, int32(len(wm[idx:])))
+	}
+	newConn := func(tnc *testNetConn) *connection {
+		conn := &connection{
+			id:     "foobar",
+			nc:     tnc,
+			state:  connConnected,
+			config: newConnectionConfig(WithMessageChecksum(func(bool) bool { return true })),
+		}
+		conn.cancellationListener = newTestCancellationListener(false)
+		return conn
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		tnc := &testNetConn{}
+		conn := newConn(tnc)
+		want := msg()
+		err := conn.writeWireMessage(context.Background(), want)
+		require.NoError(t, err, "writeWireMessage error")
+
+		written := tnc.buf
+		assert.Equal(t, len(want)+4, len(written), "expected a checksum to be written")
+		flags, _, _ := wiremessage.ReadMsgFlags(written[16:])
+		assert.Equal(t, wiremessage.ChecksumPresent, flags, "expected checksumPresent to be set")
+		assert.Equal(t, msg(), want, "expected the caller's message to be unchanged")
+
+		got, err := newConn(&testNetConn{buf: written}).readWireMessage(context.Background())
+		require.NoError(t, err, "readWireMessage error")
+		assert.Equal(t, want, got, "expected the checksum to be removed")
+	})
+	t.Run("compressed round trip", func(t *testing.T) {
+		t.Parallel()
+
+		conn := newConn(&testNetConn{})
+		conn.compressor = wiremessage.CompressorSnappy
+		wm, err := conn.compressWireMessage(msg(), nil)
+		require.NoError(t, err, "compressWireMessage error")
+
+		got, err := decodeRawCommandReply(wm)
+		require.NoError(t, err, "decodeRawCommandReply error")
+		assert.Equal(t, reply, got, "unexpected reply")
+	})
+	t.Run("corrupted", func(t *testing.T) {
+		t.Parallel()
+
+		wm := wiremessage.AppendMsgChecksum(nil, msg())
+		wm[len(wm)-6] ^= 0xFF
+		tnc := &testNetConn{buf: wm}
+
+		got, err := newConn(tnc).readWireMessage(context.Background())
+		assert.ErrorIs(t, err, wiremessage.ErrMsgChecksumMismatch, "expected a checksum mismatch")
+		assert.Nil(t, got, "expected no wire message")
+		assert.True(t, tnc.closed, "expected the connection to be closed")
+	})
+}
+
+func TestNewConnectionFromNetConn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("handshake and round trip", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := net.Pipe()
+		defer func() { _ = server.Close() }()
+
+		pong := bsoncore.NewDocumentBuilder().AppendInt32("ok", 1).AppendString("msg", "pong").Build()
+		serverErr := make(chan error, 1)
+		go func() {
+			// Reply to the hello sent by the handshake.
+			if _, err := drivertest.ReadWireMessage(server); err != nil {
+				serverErr <- err
+				return
+			}
+			hello := bsoncore.NewDocumentBuilder().
+				AppendInt32("ok", 1).
+				AppendBoolean("isWritablePrimary", true).
+				AppendInt32("maxWireVersion", 21).
+				AppendInt32("connectionId", 7).
+				Build()
+			if _, err := server.Write(drivertest.MakeReply(hello)); err != nil {
+				serverErr <- err
+				return
+			}
+
+			// Reply to the ping.
+			if _, err := drivertest.ReadWireMessage(server); err != nil {
+				serverErr <- err
+				return
+			}
+			idx, wm := wiremessage.AppendHeaderStart(nil, 1, 0, wiremessage.OpMsg)
+			wm = wiremessage.AppendMsgFlags(wm, 0)
+			wm = wiremessage.AppendMsgSectionType(wm, wiremessage.SingleDocument)
+			wm = append(wm, pong...)
+			_, err := server.Write(bsoncore.UpdateLength(wm, idx, int32(len(wm[idx:]))))
+			serverErr <- err
+		}()
+
+		conn, err := NewConnectionFromNetConn(context.Background(), client,
+			WithHandshaker(func(Handshaker) Handshaker { return operation.NewHello() }),
+		)
+		require.NoError(t, err, "NewConnectionFromNetConn error")
+
+		assert.Equal(t, address.Address("pipe"), conn.Address(), "unexpected address")
+		assert.Equal(t, description.ServerKindStandalone, conn.Description().Kind, "unexpected server kind")
+		require.NotNil(t, conn.ServerConnectionID(), "expected a server connection ID")
+		assert.Equal(t, int64(7), *conn.ServerConnectionID(), "unexpected server connection ID")
+		assert.False(t, conn.Stale(), "expected the connection not to be stale")
+		assert.Error(t, conn.PinToCursor(), "expected pinning to fail")
+
+		cmd := bsoncore.NewDocumentBuilder().AppendInt32("ping", 1).Build()
+		got, err := conn.RunRawCommand(context.Background(), "admin", cmd)
+		require.NoError(t, err, "RunRawCommand error")
+		assert.Equal(t, pong, got, "unexpected reply")
+		require.NoError(t, <-serverErr, "fake server error")
+
+		require.NoError(t, conn.Close(), "Close error")
+		_, err = server.Read(make([]byte, 1))
+		assert.ErrorIs(t, err, io.EOF, "expected Close to close the net.Conn")
+	})
+	t.Run("handshake error", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := net.Pipe()
+		_ = server.Close()
+
+		_, err := NewConnectionFromNetConn(context.Background(), client,
+			WithHandshaker(func(Handshaker) Handshaker { return operation.NewHello() }),
+		)
+		var connErr ConnectionError
+		require.True(t, errors.As(err, &connErr), "expected a ConnectionError, got %v", err)
+		assert.True(t, connErr.init, "expected the error to occur during initialization")
+	})
+}
+
+func TestConnection_credentialInjector(t *testing.T) {
+	t.Parallel()
+
+	// serve replies to the hello and saslStart of a single connection and
+	// sends the saslStart command to saslStarts.
+	serve := func(nc net.Conn, saslStarts chan<- bsoncore.Document) error {
+		defer func() { _ = nc.Close() }()
+
+		if _, err := drivertest.ReadWireMessage(nc); err != nil {
+			return err
+		}
+		hello := bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 1).
+			AppendBoolean("isWritablePrimary", true).
+			AppendInt32("maxWireVersion", 21).
+			Build()
+		if _, err := nc.Write(drivertest.MakeReply(hello)); err != nil {
+			return err
+		}
+
+		wm, err := drivertest.ReadWireMessage(nc)
+		if err != nil {
+			return err
+		}
+		cmd, err := drivertest.GetCommandFromMsgWireMessage(wm)
+		if err != nil {
+			return err
+		}
+		saslStarts <- cmd
+		done := bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 1).
+			AppendInt32("conversationId", 1).
+			AppendBoolean("done", true).
+			AppendBinary("payload", 0x00, nil).
+			Build()
+		_, err = nc.Write(drivertest.MakeReply(done))
+		return err
+	}
+
+	var calls int32
+	injector := func(_ context.Context, _ address.Address, desc description.Server) (string, *driver.Cred, error) {
+		n := atomic.AddInt32(&calls, 1)
+		assert.Equal(t, description.ServerKindStandalone, desc.Kind, "expected the handshake description")
+		cred := &driver.Cred{
+			Username:    fmt.Sprintf("user-%d", n),
+			Password:    "token",
+			PasswordSet: true,
+		}
+		return auth.PLAIN, cred, nil
+	}
+
+	saslStarts := make(chan bsoncore.Document, 2)
+	for i := 1; i <= 2; i++ {
+		client, server := net.Pipe()
+		serverErr := make(chan error, 1)
+		go func() { serverErr <- serve(server, saslStarts) }()
+
+		conn := newConnection(address.Address("localhost:27017"),
+			WithHandshaker(func(Handshaker) Handshaker {
+				return auth.Handshaker(nil, &auth.HandshakeOptions{})
+			}),
+			WithCredentialInjector(func(CredentialInjectorFunc) CredentialInjectorFunc { return injector }),
+			WithDialer(func(Dialer) Dialer {
+				return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+					return client, nil
+				})
+			}),
+		)
+		err := conn.connect(context.Background())
+		require.NoError(t, err, "connect error")
+		require.NoError(t, <-serverErr, "fake server error")
+
+		cmd := <-saslStarts
+		assert.Equal(t, auth.PLAIN, cmd.Lookup("mechanism").StringValue(), "unexpected mechanism")
+		_, payload := cmd.Lookup("payload").Binary()
+		assert.Equal(t, fmt.Sprintf("\x00user-%d\x00token", i), string(payload),
+			"expected connection %d to authenticate with a fresh credential", i)
+
+		_ = conn.close()
+	}
+
+	t.Run("injector error", func(t *testing.T) {
+		t.Parallel()
+
+		injectorErr := errors.New("token expired")
+		conn := newConnection(address.Address(""),
+			WithHandshaker(func(Handshaker) Handshaker {
+				return auth.Handshaker(nil, &auth.HandshakeOptions{})
+			}),
+			WithCredentialInjector(func(CredentialInjectorFunc) CredentialInjectorFunc {
+				return func(context.Context, address.Address, description.Server) (string, *driver.Cred, error) {
+					return "", nil, injectorErr
+				}
+			}),
+			WithDialer(func(Dialer) Dialer {
+				return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+					client, server := net.Pipe()
+					go func() {
+						if _, err := drivertest.ReadWireMessage(server); err == nil {
+							_, _ = server.Write(drivertest.MakeReply(bsoncore.NewDocumentBuilder().
+								AppendInt32("ok", 1).
+								AppendInt32("maxWireVersion", 21).
+								Build()))
+						}
+					}()
+					return client, nil
+				})
+			}),
+		)
+		err := conn.connect(context.Background())
+
+		var connErr ConnectionError
+		require.True(t, errors.As(err, &connErr), "expected a ConnectionError, got %v", err)
+		assert.Equal(t, event.PhaseAuth, connErr.phase, "expected the error to occur in the auth phase")
+		assert.ErrorIs(t, err, injectorErr)
+	})
+	t.Run("handshaker without authentication", func(t *testing.T) {
+		t.Parallel()
+
+		var called int32
+		conn := newConnection(address.Address(""),
+			WithHandshaker(func(Handshaker) Handshaker { return &testHandshaker{} }),
+			WithCredentialInjector(func(CredentialInjectorFunc) CredentialInjectorFunc {
+				return func(context.Context, address.Address, description.Server) (string, *driver.Cred, error) {
+					atomic.AddInt32(&called, 1)
+					return auth.PLAIN, &driver.Cred{Username: "user"}, nil
+				}
+			}),
+			WithDialer(func(Dialer) Dialer {
+				return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+					return &net.TCPConn{}, nil
+				})
+			}),
+		)
+		err := conn.connect(context.Background())
+		require.NoError(t, err, "connect error")
+		assert.Equal(t, int32(0), atomic.LoadInt32(&called), "expected the injector not to be called")
+	})
+	t.Run("monitoring connections", func(t *testing.T) {
+		t.Parallel()
+
+		var called int32
+		hello := bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 1).
+			AppendBoolean("isWritablePrimary", true).
+			AppendInt32("maxWireVersion", 21).
+			Build()
+		s := NewServer(
+			address.Address("localhost:27017"),
+			bson.NewObjectID(),
+			defaultConnectionTimeout,
+			WithHeartbeatInterval(func(time.Duration) time.Duration { return time.Hour }),
+			WithConnectionOptions(func(opts ...ConnectionOption) []ConnectionOption {
+				return append(opts,
+					WithHandshaker(func(Handshaker) Handshaker {
+						return auth.Handshaker(nil, &auth.HandshakeOptions{})
+					}),
+					WithCredentialInjector(func(CredentialInjectorFunc) CredentialInjectorFunc {
+						return func(context.Context, address.Address, description.Server) (string, *driver.Cred, error) {
+							atomic.AddInt32(&called, 1)
+							return auth.PLAIN, &driver.Cred{Username: "user"}, nil
+						}
+					}),
+					WithDialer(func(Dialer) Dialer {
+						return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+							client, server := net.Pipe()
+							go func() {
+								defer func() { _ = server.Close() }()
+								for {
+									if _, err := drivertest.ReadWireMessage(server); err != nil {
+										return
+									}
+									if _, err := server.Write(drivertest.MakeReply(hello)); err != nil {
+										return
+									}
+								}
+							}()
+							return client, nil
+						})
+					}),
+				)
+			}),
+		)
+		require.NoError(t, s.Connect(nil), "Connect error")
+		defer func() { _ = s.Disconnect(context.Background()) }()
+
+		assert.Eventually(t,
+			func() bool { return s.Description().Kind == description.ServerKindStandalone },
+			5*time.Second,
+			10*time.Millisecond,
+			"expected the monitor to discover the server")
+		assert.Equal(t, int32(0), atomic.LoadInt32(&called), "expected the injector not to be called by the monitor")
+	})
+}
+
 func TestConnectionError(t *testing.T) {
 	t.Parallel()
 
@@ -1161,3 +2366,66 @@ func TestConnectionError(t *testing.T) {
 		assert.ErrorContains(t, err, "client timed out waiting for server response")
 	})
 }
+
+func TestNewOCSPVerifyOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		opts        []ConnectionOption
+		addr        address.Address
+		wantDisable bool
+		wantHard    bool
+	}{
+		{
+			name:        "global setting without host policies",
+			opts:        []ConnectionOption{WithDisableOCSPEndpointCheck(func(bool) bool { return true })},
+			addr:        "db1.example.com:27017",
+			wantDisable: true,
+		},
+		{
+			name: "host policy overrides global disable",
+			opts: []ConnectionOption{
+				WithDisableOCSPEndpointCheck(func(bool) bool { return true }),
+				WithOCSPHostPolicies(func(map[string]string) map[string]string {
+					return map[string]string{"*.example.com": ocsp.PolicyHardFail}
+				}),
+			},
+			addr:     "db1.example.com:27017",
+			wantHard: true,
+		},
+		{
+			name: "host policy overrides global enable",
+			opts: []ConnectionOption{
+				WithOCSPHostPolicies(func(map[string]string) map[string]string {
+					return map[string]string{"db1.example.com": ocsp.PolicyDisabled}
+				}),
+			},
+			addr:        "db1.example.com:27017",
+			wantDisable: true,
+		},
+		{
+			name: "unmatched host falls back to global setting",
+			opts: []ConnectionOption{
+				WithDisableOCSPEndpointCheck(func(bool) bool { return true }),
+				WithOCSPHostPolicies(func(map[string]string) map[string]string {
+					return map[string]string{"*.example.com": ocsp.PolicyHardFail}
+				}),
+			},
+			addr:        "db1.example.org:27017",
+			wantDisable: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := newOCSPVerifyOptions(newConnectionConfig(tc.opts...), tc.addr)
+			assert.Equal(t, tc.wantDisable, got.DisableEndpointChecking, "unexpected DisableEndpointChecking")
+			assert.Equal(t, tc.wantHard, got.HardFail, "unexpected HardFail")
+		})
+	}
+}
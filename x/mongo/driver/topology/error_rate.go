@@ -0,0 +1,121 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultErrorRateWindow = time.Minute
+	errorRateBuckets       = 10
+)
+
+// OperationStats is a snapshot of the outcomes of the operations run against a
+// server over a rolling window.
+type OperationStats struct {
+	// Window is the period over which the outcomes are counted.
+	Window time.Duration
+
+	// Successes is the number of operations that completed without an error
+	// within the window.
+	Successes int64
+
+	// Failures is the number of operations that completed with an error within
+	// the window.
+	Failures int64
+}
+
+// ErrorRate returns the fraction of operations within the window that failed,
+// in the range [0, 1]. If no operations completed within the window, it
+// returns 0.
+func (s OperationStats) ErrorRate() float64 {
+	total := s.Successes + s.Failures
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(total)
+}
+
+type errorRateBucket struct {
+	start     time.Time
+	successes int64
+	failures  int64
+}
+
+// errorRateTracker counts operation outcomes over a rolling window. The window
+// is split into a fixed number of buckets so that recording an outcome and
+// taking a snapshot are both constant time, at the cost of the window
+// advancing in steps of window/errorRateBuckets.
+type errorRateTracker struct {
+	mu      sync.Mutex // mu guards buckets
+	window  time.Duration
+	width   time.Duration
+	buckets [errorRateBuckets]errorRateBucket
+	now     func() time.Time
+}
+
+func newErrorRateTracker(window time.Duration) *errorRateTracker {
+	if window <= 0 {
+		window = defaultErrorRateWindow
+	}
+	width := window / errorRateBuckets
+	if width <= 0 {
+		width = 1
+	}
+
+	return &errorRateTracker{
+		window: window,
+		width:  width,
+		now:    time.Now,
+	}
+}
+
+// bucket returns the bucket for the time t, resetting it if it was last used
+// for an earlier period. Must be called with mu held.
+func (t *errorRateTracker) bucket(now time.Time) *errorRateBucket {
+	start := now.Truncate(t.width)
+	b := &t.buckets[(start.UnixNano()/int64(t.width))%errorRateBuckets]
+	if !b.start.Equal(start) {
+		*b = errorRateBucket{start: start}
+	}
+	return b
+}
+
+// record counts the outcome of an operation.
+func (t *errorRateTracker) record(failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.bucket(t.now())
+	if failed {
+		b.failures++
+	} else {
+		b.successes++
+	}
+}
+
+// stats returns the outcomes counted within the window.
+func (t *errorRateTracker) stats() OperationStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := OperationStats{Window: t.window}
+
+	// Only count buckets that started within the window ending with the
+	// current bucket.
+	oldest := t.now().Truncate(t.width).Add(-t.width * (errorRateBuckets - 1))
+	for _, b := range t.buckets {
+		if b.start.Before(oldest) {
+			continue
+		}
+		stats.Successes += b.successes
+		stats.Failures += b.failures
+	}
+	return stats
+}
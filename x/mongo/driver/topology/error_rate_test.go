@@ -0,0 +1,83 @@
+// Copyright (C) MongoDB, Inc. 2024-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+)
+
+func TestErrorRateTracker(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newErrorRateTracker(10 * time.Second)
+	tracker.now = func() time.Time { return now }
+
+	assert.Equal(t, OperationStats{Window: 10 * time.Second}, tracker.stats())
+	assert.Equal(t, 0.0, tracker.stats().ErrorRate(), "expected no error rate without operations")
+
+	// Record 3 successes and 1 failure in the first bucket.
+	for _, failed := range []bool{false, true, false, false} {
+		tracker.record(failed)
+	}
+	// Record 2 successes and 2 failures half way through the window.
+	now = now.Add(5 * time.Second)
+	for _, failed := range []bool{true, false, true, false} {
+		tracker.record(failed)
+	}
+
+	stats := tracker.stats()
+	assert.Equal(t, OperationStats{Window: 10 * time.Second, Successes: 5, Failures: 3}, stats)
+	assert.InDelta(t, 0.375, stats.ErrorRate(), 1e-9, "unexpected error rate")
+
+	// Once the first bucket leaves the window, only the later outcomes are
+	// counted.
+	now = now.Add(5 * time.Second)
+	stats = tracker.stats()
+	assert.Equal(t, OperationStats{Window: 10 * time.Second, Successes: 2, Failures: 2}, stats)
+	assert.InDelta(t, 0.5, stats.ErrorRate(), 1e-9, "unexpected error rate")
+
+	// A bucket is reset when it is reused for a later period.
+	now = now.Add(5 * time.Second)
+	tracker.record(true)
+	stats = tracker.stats()
+	assert.Equal(t, OperationStats{Window: 10 * time.Second, Failures: 1}, stats)
+	assert.InDelta(t, 1.0, stats.ErrorRate(), 1e-9, "unexpected error rate")
+}
+
+func TestServer_OperationStats(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(
+		address.Address("localhost:27017"),
+		bson.NewObjectID(),
+		defaultConnectionTimeout,
+		WithOperationErrorRateWindow(func(time.Duration) time.Duration { return time.Hour }),
+	)
+
+	conn := newProcessErrorTestConn(nil, false)
+	for _, err := range []error{
+		nil,
+		errors.New("operation error"),
+		nil,
+		context.Canceled,
+		nil,
+	} {
+		_ = s.ProcessError(err, conn)
+	}
+
+	stats := s.OperationStats()
+	assert.Equal(t, OperationStats{Window: time.Hour, Successes: 3, Failures: 1}, stats)
+	assert.InDelta(t, 0.25, stats.ErrorRate(), 1e-9, "unexpected error rate")
+}
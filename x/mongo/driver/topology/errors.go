@@ -16,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
 )
 
@@ -30,6 +31,10 @@ type ConnectionError struct {
 	// during a connection handshake.
 	init    bool
 	message string
+
+	// phase is the phase of connection establishment that failed, as reported in pool events. It is only set if
+	// init is true.
+	phase string
 }
 
 // Error implements the error interface.
@@ -67,14 +72,35 @@ func (e ConnectionError) Unwrap() error {
 type ServerSelectionError struct {
 	Desc    description.Topology
 	Wrapped error
+
+	// SRVPollingErr is set if polling the SRV records of the deployment has failed
+	// at least the configured maximum number of consecutive times, in which case
+	// Desc may be based on a stale host list.
+	SRVPollingErr *SRVPollingError
+
+	// Exclusions lists, for each server in Desc that was not selected, why it
+	// was excluded. It is only set if server selection timed out.
+	Exclusions []ServerExclusion
 }
 
 // Error implements the error interface.
 func (e ServerSelectionError) Error() string {
+	var suffix string
+	if len(e.Exclusions) > 0 {
+		exclusions := make([]string, 0, len(e.Exclusions))
+		for _, ex := range e.Exclusions {
+			exclusions = append(exclusions, ex.String())
+		}
+		suffix = ", excluded servers: [ " + strings.Join(exclusions, ", ") + " ]"
+	}
+	if e.SRVPollingErr != nil {
+		suffix += ", " + e.SRVPollingErr.Error()
+	}
 	if e.Wrapped != nil {
-		return fmt.Sprintf("server selection error: %s, current topology: { %s }", e.Wrapped.Error(), e.Desc.String())
+		return fmt.Sprintf("server selection error: %s, current topology: { %s }%s",
+			e.Wrapped.Error(), e.Desc.String(), suffix)
 	}
-	return fmt.Sprintf("server selection error: current topology: { %s }", e.Desc.String())
+	return fmt.Sprintf("server selection error: current topology: { %s }%s", e.Desc.String(), suffix)
 }
 
 // Unwrap returns the underlying error.
@@ -82,6 +108,46 @@ func (e ServerSelectionError) Unwrap() error {
 	return e.Wrapped
 }
 
+// ServerExclusion describes why a server was not selected, e.g. because its
+// type does not match the read preference, its tags do not match the read
+// preference tag sets, its staleness exceeds maxStalenessSeconds or it is
+// outside the latency window.
+type ServerExclusion struct {
+	Addr   address.Address
+	Reason string
+}
+
+// String returns the address of the server and the reason it was excluded.
+func (e ServerExclusion) String() string {
+	return fmt.Sprintf("%s: %s", e.Addr, e.Reason)
+}
+
+// SRVPollingError reports that polling the SRV records of a "mongodb+srv"
+// deployment has failed repeatedly, so the host list may be stale.
+type SRVPollingError struct {
+	// Failures is the number of consecutive failed polls.
+	Failures int
+
+	// Wrapped is the error of the last failed poll. It is nil if the last
+	// poll did not return any valid hosts.
+	Wrapped error
+}
+
+// Error implements the error interface.
+func (e *SRVPollingError) Error() string {
+	if e.Wrapped != nil {
+		return fmt.Sprintf("SRV polling failed %d consecutive times, the host list may be stale: %s",
+			e.Failures, e.Wrapped.Error())
+	}
+	return fmt.Sprintf("SRV polling failed %d consecutive times, the host list may be stale: no valid hosts found",
+		e.Failures)
+}
+
+// Unwrap returns the underlying error.
+func (e *SRVPollingError) Unwrap() error {
+	return e.Wrapped
+}
+
 // WaitQueueTimeoutError represents a timeout when requesting a connection from the pool
 type WaitQueueTimeoutError struct {
 	Wrapped              error
@@ -135,3 +201,26 @@ func (w WaitQueueTimeoutError) Error() string {
 func (w WaitQueueTimeoutError) Unwrap() error {
 	return w.Wrapped
 }
+
+// MaxConcurrentOperationsError is returned when an operation gives up waiting
+// to start because the maximum number of concurrent operations are executing
+// and its context is done. It matches both ErrMaxConcurrentOperations and the
+// context's error with errors.Is.
+type MaxConcurrentOperationsError struct {
+	Wrapped error
+}
+
+// Error implements the error interface.
+func (e MaxConcurrentOperationsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrMaxConcurrentOperations, e.Wrapped)
+}
+
+// Is reports whether target is ErrMaxConcurrentOperations.
+func (e MaxConcurrentOperationsError) Is(target error) bool {
+	return target == ErrMaxConcurrentOperations
+}
+
+// Unwrap returns the underlying error.
+func (e MaxConcurrentOperationsError) Unwrap() error {
+	return e.Wrapped
+}
@@ -0,0 +1,103 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FaultOp identifies the connection I/O operation a Fault applies to.
+type FaultOp int
+
+// These constants are the connection I/O operations faults can be injected
+// into.
+const (
+	// FaultOpWrite is the write of a wire message to the network.
+	FaultOpWrite FaultOp = iota
+
+	// FaultOpRead is the read of a wire message from the network.
+	FaultOpRead
+)
+
+// Fault describes latency and an error to inject into a connection I/O
+// operation.
+type Fault struct {
+	// Probability is the chance, in the range [0, 1], that the fault is
+	// injected into an operation. A value of 1 or more injects the fault into
+	// every operation.
+	Probability float64
+
+	// Delay is how long the operation is delayed before it runs or fails. The
+	// delay is cut short if the operation's context is done.
+	Delay time.Duration
+
+	// Err is the error the operation fails with. If Err is nil, the operation
+	// is only delayed and then runs normally.
+	Err error
+}
+
+// FaultInjector injects artificial latency and errors into the reads and
+// writes of connections to exercise retry and timeout logic without a proxy.
+// It is intended for testing only and must not be used in production. Faults
+// can be changed while connections are in use. A FaultInjector is safe for
+// concurrent use.
+//
+// An injected error is handled like a network error, so it closes the
+// connection and is returned wrapped in a ConnectionError.
+type FaultInjector struct {
+	mu     sync.Mutex // mu guards faults
+	faults map[FaultOp]Fault
+}
+
+// NewFaultInjector returns a FaultInjector that does not inject any faults
+// until SetFault is called.
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{faults: make(map[FaultOp]Fault)}
+}
+
+// SetFault configures the fault injected into op, replacing any fault
+// previously set for op.
+func (fi *FaultInjector) SetFault(op FaultOp, f Fault) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	fi.faults[op] = f
+}
+
+// ClearFaults stops injecting faults into all operations.
+func (fi *FaultInjector) ClearFaults() {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	fi.faults = make(map[FaultOp]Fault)
+}
+
+// inject delays and returns the error of the fault set for op, if the fault is
+// chosen to be injected. If ctx is done during the delay, it returns the
+// context's error.
+func (fi *FaultInjector) inject(ctx context.Context, op FaultOp) error {
+	fi.mu.Lock()
+	f, ok := fi.faults[op]
+	ok = ok && f.Probability > 0 && (f.Probability >= 1 || random.Float64() < f.Probability)
+	fi.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	if f.Delay > 0 {
+		timer := time.NewTimer(f.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	return f.Err
+}
@@ -0,0 +1,129 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+)
+
+func TestFaultInjector(t *testing.T) {
+	t.Parallel()
+
+	errInjected := errors.New("injected fault")
+	wm := []byte{0x0A, 0x00, 0x00, 0x00, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A}
+
+	newConn := func(fi *FaultInjector) (*connection, *testNetConn) {
+		tnc := &testNetConn{buf: append([]byte(nil), wm...)}
+		conn := &connection{
+			id:                   "foobar",
+			nc:                   tnc,
+			state:                connConnected,
+			config:               newConnectionConfig(WithFaultInjector(func(*FaultInjector) *FaultInjector { return fi })),
+			cancellationListener: newTestCancellationListener(false),
+		}
+		return conn, tnc
+	}
+
+	t.Run("write error", func(t *testing.T) {
+		t.Parallel()
+
+		fi := NewFaultInjector()
+		fi.SetFault(FaultOpWrite, Fault{Probability: 1, Err: errInjected})
+		conn, tnc := newConn(fi)
+		tnc.buf = nil
+
+		err := conn.writeWireMessage(context.Background(), wm)
+		assert.ErrorIs(t, err, errInjected, "expected the injected error")
+		var connErr ConnectionError
+		require.True(t, errors.As(err, &connErr), "expected a ConnectionError, got %T", err)
+		assert.Equal(t, "unable to write wire message to network", connErr.message, "unexpected error message")
+		assert.Len(t, tnc.buf, 0, "expected nothing to be written")
+		assert.True(t, tnc.closed, "expected the connection to be closed")
+	})
+	t.Run("read error", func(t *testing.T) {
+		t.Parallel()
+
+		fi := NewFaultInjector()
+		fi.SetFault(FaultOpRead, Fault{Probability: 1, Err: errInjected})
+		conn, tnc := newConn(fi)
+
+		got, err := conn.readWireMessage(context.Background())
+		assert.Nil(t, got, "expected no wire message")
+		assert.ErrorIs(t, err, errInjected, "expected the injected error")
+		var connErr ConnectionError
+		require.True(t, errors.As(err, &connErr), "expected a ConnectionError, got %T", err)
+		assert.Equal(t, "incomplete read of message header", connErr.message, "unexpected error message")
+		assert.True(t, tnc.closed, "expected the connection to be closed")
+	})
+	t.Run("delay is cut short by the context deadline", func(t *testing.T) {
+		t.Parallel()
+
+		fi := NewFaultInjector()
+		fi.SetFault(FaultOpRead, Fault{Probability: 1, Delay: time.Hour})
+		conn, _ := newConn(fi)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := conn.readWireMessage(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "expected the delay to time out")
+	})
+	t.Run("delay without an error", func(t *testing.T) {
+		t.Parallel()
+
+		const delay = 20 * time.Millisecond
+		fi := NewFaultInjector()
+		fi.SetFault(FaultOpRead, Fault{Probability: 1, Delay: delay})
+		conn, _ := newConn(fi)
+
+		start := time.Now()
+		got, err := conn.readWireMessage(context.Background())
+		require.NoError(t, err, "readWireMessage error")
+		assert.GreaterOrEqual(t, time.Since(start), delay, "expected the read to be delayed")
+		assert.Equal(t, wm, got, "unexpected wire message")
+	})
+	t.Run("faults are not injected after they are cleared", func(t *testing.T) {
+		t.Parallel()
+
+		fi := NewFaultInjector()
+		fi.SetFault(FaultOpWrite, Fault{Probability: 1, Err: errInjected})
+		fi.SetFault(FaultOpRead, Fault{Probability: 1, Err: errInjected})
+		fi.ClearFaults()
+		conn, tnc := newConn(fi)
+
+		got, err := conn.readWireMessage(context.Background())
+		require.NoError(t, err, "readWireMessage error")
+		assert.Equal(t, wm, got, "unexpected wire message")
+
+		tnc.buf = nil
+		err = conn.writeWireMessage(context.Background(), wm)
+		require.NoError(t, err, "writeWireMessage error")
+		assert.Equal(t, wm, tnc.buf, "unexpected bytes written")
+	})
+	t.Run("probability", func(t *testing.T) {
+		t.Parallel()
+
+		fi := NewFaultInjector()
+		fi.SetFault(FaultOpWrite, Fault{Probability: 0.5, Err: errInjected})
+
+		var injected int
+		for i := 0; i < 1000; i++ {
+			if fi.inject(context.Background(), FaultOpWrite) != nil {
+				injected++
+			}
+		}
+		assert.Greater(t, injected, 300, "expected about half of the writes to fail")
+		assert.Less(t, injected, 700, "expected about half of the writes to fail")
+		assert.NoError(t, fi.inject(context.Background(), FaultOpRead), "expected no fault for reads")
+	})
+}
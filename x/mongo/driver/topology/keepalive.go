@@ -0,0 +1,40 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"net"
+	"syscall"
+	"time"
+)
+
+// setKeepAlive applies the TCP keep-alive parameters in cfg to the socket of nc. It does nothing if nc does not expose
+// its socket, e.g. because it was returned by a custom Dialer, or if the platform does not support keep-alive tuning.
+func setKeepAlive(nc net.Conn, cfg KeepAliveConfig) error {
+	sc, ok := nc.(syscall.Conn)
+	if !ok {
+		return nil
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sockErr error
+	if err := rc.Control(func(fd uintptr) {
+		sockErr = setKeepAliveSockopts(fd, cfg)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// keepAliveSeconds rounds d up to a whole number of seconds, which is the granularity of the keep-alive socket
+// options.
+func keepAliveSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
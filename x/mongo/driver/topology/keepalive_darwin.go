@@ -0,0 +1,38 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build darwin
+// +build darwin
+
+package topology
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func setKeepAliveSockopts(fd uintptr, cfg KeepAliveConfig) error {
+	if err := unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_KEEPALIVE, 1); err != nil {
+		return os.NewSyscallError("setsockopt", err)
+	}
+	if cfg.Idle > 0 {
+		if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_KEEPALIVE, keepAliveSeconds(cfg.Idle)); err != nil {
+			return os.NewSyscallError("setsockopt", err)
+		}
+	}
+	if cfg.Interval > 0 {
+		if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_KEEPINTVL, keepAliveSeconds(cfg.Interval)); err != nil {
+			return os.NewSyscallError("setsockopt", err)
+		}
+	}
+	if cfg.Count > 0 {
+		if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_KEEPCNT, cfg.Count); err != nil {
+			return os.NewSyscallError("setsockopt", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build linux
+// +build linux
+
+package topology
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"golang.org/x/sys/unix"
+)
+
+// keepAliveSockopts returns the SO_KEEPALIVE, TCP_KEEPIDLE, TCP_KEEPINTVL and TCP_KEEPCNT socket options of nc.
+func keepAliveSockopts(t *testing.T, nc net.Conn) [4]int {
+	t.Helper()
+
+	rc, err := nc.(syscall.Conn).SyscallConn()
+	require.NoError(t, err, "SyscallConn error")
+
+	var opts [4]int
+	var sockErr error
+	err = rc.Control(func(fd uintptr) {
+		for i, opt := range []struct{ level, name int }{
+			{unix.SOL_SOCKET, unix.SO_KEEPALIVE},
+			{unix.IPPROTO_TCP, unix.TCP_KEEPIDLE},
+			{unix.IPPROTO_TCP, unix.TCP_KEEPINTVL},
+			{unix.IPPROTO_TCP, unix.TCP_KEEPCNT},
+		} {
+			if opts[i], sockErr = unix.GetsockoptInt(int(fd), opt.level, opt.name); sockErr != nil {
+				return
+			}
+		}
+	})
+	require.NoError(t, err, "Control error")
+	require.NoError(t, sockErr, "GetsockoptInt error")
+	return opts
+}
+
+func TestKeepAlive(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err, "Listen error")
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			nc, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(io.Discard, nc)
+				_ = nc.Close()
+			}()
+		}
+	}()
+
+	t.Run("applied to dialed connections", func(t *testing.T) {
+		t.Parallel()
+
+		errStop := errors.New("stop after dial")
+		var got [4]int
+		conn := newConnection(address.Address(l.Addr().String()),
+			WithKeepAlive(func(*KeepAliveConfig) *KeepAliveConfig {
+				return &KeepAliveConfig{Idle: 30 * time.Second, Interval: 1500 * time.Millisecond, Count: 4}
+			}),
+			WithDialedConnFunc(func(DialedConnFunc) DialedConnFunc {
+				return func(_ address.Address, nc net.Conn) error {
+					got = keepAliveSockopts(t, nc)
+					return errStop
+				}
+			}),
+		)
+
+		err := conn.connect(context.Background())
+		assert.ErrorIs(t, err, errStop, "expected connect to stop after the dialed connection callback")
+		// The interval is rounded up to a whole number of seconds.
+		assert.Equal(t, [4]int{1, 30, 2, 4}, got, "unexpected keep-alive socket options")
+	})
+	t.Run("zero values are unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		nc, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err, "Dial error")
+		defer nc.Close()
+
+		before := keepAliveSockopts(t, nc)
+		require.NoError(t, setKeepAlive(nc, KeepAliveConfig{Count: 7}), "setKeepAlive error")
+		after := keepAliveSockopts(t, nc)
+		assert.Equal(t, [4]int{1, before[1], before[2], 7}, after, "unexpected keep-alive socket options")
+	})
+	t.Run("ignored without socket access", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+
+		err := setKeepAlive(client, KeepAliveConfig{Idle: time.Second, Interval: time.Second, Count: 1})
+		assert.NoError(t, err, "expected setKeepAlive to ignore a connection without a socket")
+	})
+}
@@ -0,0 +1,15 @@
+// Copyright (C) MongoDB, Inc. 2025-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package topology
+
+// setKeepAliveSockopts does nothing because keep-alive tuning is not supported on this platform.
+func setKeepAliveSockopts(uintptr, KeepAliveConfig) error {
+	return nil
+}
@@ -8,6 +8,7 @@ package topology
 
 import (
 	"context"
+	"errors"
 	"net"
 	"sort"
 	"strings"
@@ -422,3 +423,120 @@ func TestPollSRVRecordsServiceName(t *testing.T) {
 		compareHosts(t, actualHosts, expectedHosts)
 	})
 }
+
+func TestPollSRVRecordsMaxPollingFailures(t *testing.T) {
+	const maxFailures = 3
+
+	// Fail the first lookups and block the next one until the test has inspected the error.
+	var lookups int32
+	release := make(chan struct{})
+	lookupSRV := func(string, string, string) (string, []*net.SRV, error) {
+		if atomic.AddInt32(&lookups, 1) <= maxFailures {
+			return "", nil, &net.DNSError{Err: "no such host", Name: "_mongodb._tcp.test.example.com"}
+		}
+		<-release
+		return "", []*net.SRV{{Target: "localhost.test.example.com.", Port: 27017}}, nil
+	}
+	lookupTXT := func(string) ([]string, error) { return nil, nil }
+
+	topo, err := New(&Config{
+		SRVMaxPollingFailures: maxFailures,
+		ServerOpts: []ServerOption{
+			WithHeartbeatInterval(func(time.Duration) time.Duration { return time.Millisecond }),
+		},
+	})
+	require.NoError(t, err, "Could not create the topology: %v", err)
+	topo.dnsResolver = &dns.Resolver{LookupSRV: lookupSRV, LookupTXT: lookupTXT}
+	topo.rescanSRVInterval = time.Millisecond
+
+	topo.pollingwg.Add(1)
+	go topo.pollSRVRecords("test.example.com")
+	defer func() {
+		topo.pollingDone <- struct{}{}
+		topo.pollingwg.Wait()
+	}()
+
+	assert.Eventually(t,
+		func() bool { return topo.SRVPollingError() != nil },
+		5*time.Second,
+		time.Millisecond,
+		"expected the SRV polling error to be set after %d failures", maxFailures)
+
+	srvErr := topo.SRVPollingError()
+	assert.Equal(t, maxFailures, srvErr.Failures, "unexpected number of failures")
+	var dnsErr *net.DNSError
+	assert.True(t, errors.As(srvErr, &dnsErr), "expected the DNS error to be wrapped, got %v", srvErr)
+
+	selErr := ServerSelectionError{SRVPollingErr: srvErr}
+	assert.Contains(t, selErr.Error(), "SRV polling failed 3 consecutive times",
+		"expected the server selection error to include the SRV polling error")
+
+	close(release)
+	assert.Eventually(t,
+		func() bool { return topo.SRVPollingError() == nil },
+		5*time.Second,
+		time.Millisecond,
+		"expected the SRV polling error to be cleared after a successful poll")
+}
+
+func TestPollSRVRecordsRetriesTransientErrors(t *testing.T) {
+	const retries = 2
+
+	hosts := []*net.SRV{
+		{Target: "localhost.test.example.com.", Port: 27017},
+		{Target: "localhost.test.example.com.", Port: 27018},
+	}
+
+	// The first poll succeeds. The second poll fails transiently as many times as it is retried and then succeeds.
+	// The lookup after that blocks until the test has inspected the topology.
+	var lookups int32
+	release := make(chan struct{})
+	lookupSRV := func(string, string, string) (string, []*net.SRV, error) {
+		switch n := atomic.AddInt32(&lookups, 1); {
+		case n == 1:
+			return "", hosts, nil
+		case n <= 1+retries:
+			return "", nil, &net.DNSError{Err: "server misbehaving", Name: "_mongodb._tcp.test.example.com", IsTemporary: true}
+		case n == 2+retries:
+			return "", hosts, nil
+		}
+		<-release
+		return "", hosts, nil
+	}
+	lookupTXT := func(string) ([]string, error) { return nil, nil }
+
+	// Use a long heartbeat interval so that a failed poll, which would switch to polling at the heartbeat interval,
+	// prevents any further lookups.
+	topo, err := New(&Config{
+		SRVMaxPollingFailures:  1,
+		SRVPollingRetries:      retries,
+		SRVPollingRetryBackoff: time.Millisecond,
+		ServerOpts: []ServerOption{
+			WithHeartbeatInterval(func(time.Duration) time.Duration { return time.Hour }),
+		},
+	})
+	require.NoError(t, err, "Could not create the topology: %v", err)
+	topo.dnsResolver = &dns.Resolver{LookupSRV: lookupSRV, LookupTXT: lookupTXT}
+	topo.rescanSRVInterval = time.Millisecond
+
+	topo.pollingwg.Add(1)
+	go topo.pollSRVRecords("test.example.com")
+	defer func() {
+		topo.pollingDone <- struct{}{}
+		topo.pollingwg.Wait()
+	}()
+	defer close(release)
+
+	assert.Eventually(t,
+		func() bool { return atomic.LoadInt32(&lookups) > 2+retries },
+		5*time.Second,
+		time.Millisecond,
+		"expected the poll after the transient failures to run")
+
+	assert.Nil(t, topo.SRVPollingError(), "expected transient failures not to count as a failed poll")
+	assert.False(t, topo.pollHeartbeatTime.Load().(bool), "expected polling to stay at the rescan interval")
+	compareHosts(t, topo.Description().Servers, []string{
+		"localhost.test.example.com:27017",
+		"localhost.test.example.com:27018",
+	})
+}
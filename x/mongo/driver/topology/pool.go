@@ -8,6 +8,7 @@ package topology
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -39,6 +40,18 @@ var ErrPoolClosed = PoolError("attempted to check out a connection from closed c
 // ErrConnectionClosed is returned from an attempt to use an already closed connection.
 var ErrConnectionClosed = ConnectionError{ConnectionID: "<closed>", message: "connection is closed"}
 
+// ErrPoolExhausted is returned when attempting to check out a connection from a pool that has
+// reached its maximum size and is configured to fail fast instead of waiting for a connection.
+var ErrPoolExhausted = PoolError("connection pool is exhausted")
+
+// ErrWaitQueueFull is returned when attempting to check out a connection from a pool whose wait
+// queue has reached its maximum size.
+var ErrWaitQueueFull = PoolError("connection pool wait queue is full")
+
+// ErrPoolCheckOutsPaused is returned when attempting to check out a connection from a pool whose
+// check outs were paused with PauseCheckOuts.
+var ErrPoolCheckOutsPaused = PoolError("connection pool check outs are paused")
+
 // ErrWrongPool is return when a connection is returned to a pool it doesn't belong to.
 var ErrWrongPool = PoolError("connection does not belong to this pool")
 
@@ -80,6 +93,29 @@ type poolConfig struct {
 	Logger           *logger.Logger
 	handshakeErrFn   func(error, uint64, *bson.ObjectID)
 	ConnectTimeout   time.Duration
+
+	// WaitQueueFailFast causes checkOut to return ErrPoolExhausted instead of
+	// waiting when there are no idle connections and the pool is full.
+	WaitQueueFailFast bool
+
+	// MaxWaitQueueSize is the maximum number of checkOut requests that may wait
+	// for a connection. Further requests fail with ErrWaitQueueFull. If
+	// MaxWaitQueueSize is 0, the wait queue is unbounded.
+	MaxWaitQueueSize uint64
+
+	// WarmSpares is the number of idle connections that the background maintenance
+	// routine keeps ready for checkout in addition to the connections required by
+	// MinPoolSize. The pool keeps WarmSpares more connections than the larger of
+	// MinPoolSize and the number of connections in use.
+	WarmSpares uint64
+
+	// GenerationChanged, if set, is called every time the pool generation is incremented.
+	GenerationChanged GenerationChangedFunc
+
+	// PinLeakThreshold is the duration after which a connection that is still pinned to a cursor
+	// or transaction is reported as a likely leak. If PinLeakThreshold is 0, pin leak detection is
+	// disabled.
+	PinLeakThreshold time.Duration
 }
 
 type pool struct {
@@ -92,13 +128,19 @@ type pool struct {
 	pinnedCursorConnections      uint64
 	pinnedTransactionConnections uint64
 
-	address       address.Address
-	minSize       uint64
-	maxSize       uint64
-	maxConnecting uint64
-	loadBalanced  bool
-	monitor       *event.PoolMonitor
-	logger        *logger.Logger
+	address          address.Address
+	minSize          uint64
+	maxSize          uint64
+	maxConnecting    uint64
+	loadBalanced     bool
+	failFast         bool
+	maxWaitQueueSize uint64
+	warmSpares       uint64
+	pinLeakThreshold time.Duration
+	monitor          *event.PoolMonitor
+	logger           *logger.Logger
+
+	generationChangedFn GenerationChangedFunc
 
 	// handshakeErrFn is used to handle any errors that happen during connection establishment and
 	// handshaking.
@@ -111,10 +153,14 @@ type pool struct {
 	maintainReady    chan struct{}   // maintainReady is a signal channel that starts the maintain() loop when ready() is called.
 	backgroundDone   *sync.WaitGroup // backgroundDone waits for all background goroutines to return.
 
-	stateMu      sync.RWMutex // stateMu guards state, lastClearErr
+	stateMu      sync.RWMutex // stateMu guards state, lastClearErr, checkOutsPaused
 	state        int          // state is the current state of the connection pool.
 	lastClearErr error        // lastClearErr is the last error that caused the pool to be cleared.
 
+	// checkOutsPaused is true while check outs are paused by the user. Unlike the "paused" state, it
+	// is not changed by clear() or ready() and does not affect existing connections.
+	checkOutsPaused bool
+
 	// createConnectionsCond is the condition variable that controls when the createConnections()
 	// loop runs or waits. Its lock guards cancelBackgroundCtx, conns, and newConnWait. Any changes
 	// to the state of the guarded values must be made while holding the lock to prevent undefined
@@ -144,13 +190,17 @@ func mustLogPoolMessage(pool *pool) bool {
 }
 
 func logPoolMessage(pool *pool, msg string, keysAndValues ...interface{}) {
+	logPoolMessageAtLevel(pool, logger.LevelDebug, msg, keysAndValues...)
+}
+
+func logPoolMessageAtLevel(pool *pool, level logger.Level, msg string, keysAndValues ...interface{}) {
 	host, port, err := net.SplitHostPort(pool.address.String())
 	if err != nil {
 		host = pool.address.String()
 		port = ""
 	}
 
-	pool.logger.Print(logger.LevelDebug,
+	pool.logger.Print(level,
 		logger.ComponentConnection,
 		msg,
 		logger.SerializeConnection(logger.Connection{
@@ -213,6 +263,11 @@ func newPool(config poolConfig, connOpts ...ConnectionOption) *pool {
 		maxSize:               config.MaxPoolSize,
 		maxConnecting:         maxConnecting,
 		loadBalanced:          config.LoadBalanced,
+		failFast:              config.WaitQueueFailFast,
+		maxWaitQueueSize:      config.MaxWaitQueueSize,
+		warmSpares:            config.WarmSpares,
+		pinLeakThreshold:      config.PinLeakThreshold,
+		generationChangedFn:   config.GenerationChanged,
 		monitor:               config.PoolMonitor,
 		logger:                config.Logger,
 		handshakeErrFn:        config.handshakeErrFn,
@@ -449,6 +504,20 @@ func (p *pool) pinConnectionToTransaction() {
 	atomic.AddUint64(&p.pinnedTransactionConnections, 1)
 }
 
+// logPinLeak logs that conn has been pinned for a reason for longer than threshold. The stack is
+// the stack trace captured when the connection was pinned.
+func (p *pool) logPinLeak(conn *connection, reason string, threshold time.Duration, stack []byte) {
+	if p.logger == nil || !p.logger.LevelComponentEnabled(logger.LevelInfo, logger.ComponentConnection) {
+		return
+	}
+
+	logPoolMessageAtLevel(p, logger.LevelInfo, logger.ConnectionPinLeaked,
+		logger.KeyDriverConnectionID, conn.driverConnectionID,
+		logger.KeyReason, reason,
+		logger.KeyDurationMS, threshold.Milliseconds(),
+		logger.KeyStackTrace, string(stack))
+}
+
 func (p *pool) unpinConnectionFromTransaction() {
 	// See https://golang.org/pkg/sync/atomic/#AddUint64 for an explanation of the ^uint64(0) syntax.
 	atomic.AddUint64(&p.pinnedTransactionConnections, ^uint64(0))
@@ -528,6 +597,31 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 		return nil, err
 	}
 
+	if p.checkOutsPaused {
+		p.stateMu.RUnlock()
+
+		duration := time.Since(start)
+		if mustLogPoolMessage(p) {
+			keysAndValues := logger.KeyValues{
+				logger.KeyDurationMS, duration.Milliseconds(),
+				logger.KeyReason, logger.ReasonConnCheckoutFailedError,
+			}
+
+			logPoolMessage(p, logger.ConnectionCheckoutFailed, keysAndValues...)
+		}
+
+		if p.monitor != nil {
+			p.monitor.Event(&event.PoolEvent{
+				Type:     event.ConnectionCheckOutFailed,
+				Address:  p.address.String(),
+				Reason:   event.ReasonConnectionErrored,
+				Duration: duration,
+				Error:    ErrPoolCheckOutsPaused,
+			})
+		}
+		return nil, ErrPoolCheckOutsPaused
+	}
+
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -596,6 +690,45 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 		return w.conn, nil
 	}
 
+	// If the pool is configured to fail fast and can't create a new connection, or if the wait
+	// queue is already at its maximum size, return an error instead of waiting for a connection.
+	var waitErr error
+	var logReason, eventReason string
+	switch {
+	case p.failFast && p.full():
+		waitErr = ErrPoolExhausted
+		logReason = logger.ReasonConnCheckoutFailedExhausted
+		eventReason = event.ReasonPoolExhausted
+	case p.maxWaitQueueSize > 0 && uint64(p.waitQueueLength()) > p.maxWaitQueueSize:
+		waitErr = ErrWaitQueueFull
+		logReason = logger.ReasonConnCheckoutFailedWaitQueueFull
+		eventReason = event.ReasonWaitQueueFull
+	}
+	if waitErr != nil {
+		p.stateMu.RUnlock()
+
+		duration := time.Since(start)
+		if mustLogPoolMessage(p) {
+			keysAndValues := logger.KeyValues{
+				logger.KeyDurationMS, duration.Milliseconds(),
+				logger.KeyReason, logReason,
+			}
+
+			logPoolMessage(p, logger.ConnectionCheckoutFailed, keysAndValues...)
+		}
+
+		if p.monitor != nil {
+			p.monitor.Event(&event.PoolEvent{
+				Type:     event.ConnectionCheckOutFailed,
+				Address:  p.address.String(),
+				Duration: duration,
+				Reason:   eventReason,
+				Error:    waitErr,
+			})
+		}
+		return nil, waitErr
+	}
+
 	// If we didn't get an immediately available idle connection, also get in the queue for a new
 	// connection while we're waiting for an idle connection.
 	p.queueForNewConn(w)
@@ -605,6 +738,7 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 	waitQueueStart := time.Now()
 	select {
 	case <-w.ready:
+		waitDuration := w.waitDuration(start)
 		if w.err != nil {
 			duration := time.Since(start)
 			if mustLogPoolMessage(p) {
@@ -619,11 +753,12 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 
 			if p.monitor != nil {
 				p.monitor.Event(&event.PoolEvent{
-					Type:     event.ConnectionCheckOutFailed,
-					Address:  p.address.String(),
-					Duration: duration,
-					Reason:   event.ReasonConnectionErrored,
-					Error:    w.err,
+					Type:         event.ConnectionCheckOutFailed,
+					Address:      p.address.String(),
+					Duration:     duration,
+					WaitDuration: waitDuration,
+					Reason:       event.ReasonConnectionErrored,
+					Error:        w.err,
 				})
 			}
 
@@ -646,6 +781,7 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 				Address:      p.address.String(),
 				ConnectionID: w.conn.driverConnectionID,
 				Duration:     duration,
+				WaitDuration: waitDuration,
 			})
 		}
 		return w.conn, nil
@@ -664,11 +800,12 @@ func (p *pool) checkOut(ctx context.Context) (conn *connection, err error) {
 
 		if p.monitor != nil {
 			p.monitor.Event(&event.PoolEvent{
-				Type:     event.ConnectionCheckOutFailed,
-				Address:  p.address.String(),
-				Duration: duration,
-				Reason:   event.ReasonTimedOut,
-				Error:    ctx.Err(),
+				Type:         event.ConnectionCheckOutFailed,
+				Address:      p.address.String(),
+				Duration:     duration,
+				WaitDuration: duration,
+				Reason:       event.ReasonTimedOut,
+				Error:        ctx.Err(),
 			})
 		}
 
@@ -757,11 +894,18 @@ func (p *pool) removeConnection(conn *connection, reason reason, err error) erro
 	}
 
 	if p.monitor != nil {
+		var phase string
+		var connErr ConnectionError
+		if errors.As(err, &connErr) && connErr.init {
+			phase = connErr.phase
+		}
+
 		p.monitor.Event(&event.PoolEvent{
 			Type:         event.ConnectionClosed,
 			Address:      p.address.String(),
 			ConnectionID: conn.driverConnectionID,
 			Reason:       reason.event,
+			Phase:        phase,
 			Error:        err,
 		})
 	}
@@ -985,7 +1129,9 @@ func (p *pool) clearImpl(err error, serviceID *bson.ObjectID, interruptAllConnec
 		return
 	}
 
-	p.generation.clear(serviceID)
+	if generation, ok := p.generation.clear(serviceID); ok && p.generationChangedFn != nil {
+		p.generationChangedFn(p.address, serviceID, generation-1, generation, err)
+	}
 
 	// If serviceID is nil (i.e. not in load balancer mode), transition the pool to a paused state
 	// by stopping all background goroutines, clearing the wait queues, and setting the pool state
@@ -1127,6 +1273,24 @@ func (p *pool) queueForNewConn(w *wantConn) {
 	p.createConnectionsCond.Signal()
 }
 
+// full returns true if the pool has reached its maximum size and can't create any more
+// connections.
+func (p *pool) full() bool {
+	p.createConnectionsCond.L.Lock()
+	defer p.createConnectionsCond.L.Unlock()
+
+	return p.maxSize != 0 && uint64(len(p.conns)) >= p.maxSize
+}
+
+// waitQueueLength returns the number of checkOut requests waiting for a connection. Every waiting
+// request is queued in idleConnWait, whether or not it is also queued for a new connection.
+func (p *pool) waitQueueLength() int {
+	p.idleMu.Lock()
+	defer p.idleMu.Unlock()
+
+	return p.idleConnWait.waitingLen()
+}
+
 func (p *pool) totalConnectionCount() int {
 	p.createConnectionsCond.L.Lock()
 	defer p.createConnectionsCond.L.Unlock()
@@ -1141,6 +1305,68 @@ func (p *pool) availableConnectionCount() int {
 	return len(p.idleConns)
 }
 
+// PoolStats is a point-in-time snapshot of the counters of a connection pool.
+type PoolStats struct {
+	// TotalConnections is the number of connections owned by the pool, including
+	// idle, in-use and pending connections.
+	TotalConnections int
+
+	// InUseConnections is the number of established connections that are
+	// currently checked out of the pool.
+	InUseConnections int
+
+	// IdleConnections is the number of connections available for check out.
+	IdleConnections int
+
+	// PendingConnections is the number of connections being established. It is
+	// bounded by maxConnecting.
+	PendingConnections int
+
+	// WaitQueueLength is the number of check out requests waiting for a
+	// connection.
+	WaitQueueLength int
+
+	// PinnedCursorConnections is the number of connections pinned to a cursor.
+	PinnedCursorConnections uint64
+
+	// PinnedTransactionConnections is the number of connections pinned to a
+	// transaction.
+	PinnedTransactionConnections uint64
+}
+
+// stats returns a snapshot of the pool's counters. The pool's locks are taken
+// one at a time, so the counts are consistent with each other only
+// approximately while connections are being checked in and out.
+func (p *pool) stats() PoolStats {
+	var stats PoolStats
+
+	p.createConnectionsCond.L.Lock()
+	stats.TotalConnections = len(p.conns)
+	for _, conn := range p.conns {
+		select {
+		case <-conn.connectDone:
+		default:
+			stats.PendingConnections++
+		}
+	}
+	p.createConnectionsCond.L.Unlock()
+
+	p.idleMu.Lock()
+	stats.IdleConnections = len(p.idleConns)
+	stats.WaitQueueLength = p.idleConnWait.waitingLen()
+	p.idleMu.Unlock()
+
+	stats.InUseConnections = stats.TotalConnections - stats.IdleConnections - stats.PendingConnections
+	if stats.InUseConnections < 0 {
+		stats.InUseConnections = 0
+	}
+
+	stats.PinnedCursorConnections = atomic.LoadUint64(&p.pinnedCursorConnections)
+	stats.PinnedTransactionConnections = atomic.LoadUint64(&p.pinnedTransactionConnections)
+
+	return stats
+}
+
 // createConnections creates connections for wantConn requests on the newConnWait queue.
 func (p *pool) createConnections(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
@@ -1347,6 +1573,23 @@ func (p *pool) maintain(ctx context.Context, wg *sync.WaitGroup) {
 		// minPoolSize in case other checkOut() calls are requesting new connections, too.
 		total := p.totalConnectionCount()
 		n := int(p.minSize) - total - len(wantConns)
+
+		// Also request enough connections to keep warmSpares idle connections ready for
+		// checkout in addition to the connections required by minPoolSize. That is, keep
+		// warmSpares more connections than the larger of minPoolSize and the number of
+		// connections in use, without exceeding maxPoolSize.
+		if p.warmSpares > 0 {
+			target := total - p.availableConnectionCount()
+			if target < int(p.minSize) {
+				target = int(p.minSize)
+			}
+			if spares := target + int(p.warmSpares) - total - len(wantConns); spares > n {
+				n = spares
+			}
+		}
+		if p.maxSize != 0 && total+len(wantConns)+n > int(p.maxSize) {
+			n = int(p.maxSize) - total - len(wantConns)
+		}
 		if n > 10 {
 			n = 10
 		}
@@ -1368,6 +1611,79 @@ func (p *pool) maintain(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// pauseCheckOuts makes all subsequent checkOut() calls fail with ErrPoolCheckOutsPaused until
+// resumeCheckOuts() is called. Connections that are already checked out or waiting in the wait
+// queue are not affected, and idle connections are kept.
+func (p *pool) pauseCheckOuts() {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	p.checkOutsPaused = true
+}
+
+// resumeCheckOuts allows check outs again after pauseCheckOuts().
+func (p *pool) resumeCheckOuts() {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	p.checkOutsPaused = false
+}
+
+// prime establishes new connections until the pool holds at least n connections or maxPoolSize
+// is reached, whichever is smaller. The connections are created by the createConnections()
+// goroutines, like connections requested by checkOut() or maintain(), and are returned to the idle
+// connections stack without publishing check out or check in events. prime returns the first
+// error encountered while creating connections, or the Context error if ctx is done first.
+func (p *pool) prime(ctx context.Context, n int) error {
+	p.stateMu.RLock()
+	switch p.state {
+	case poolClosed:
+		p.stateMu.RUnlock()
+		return ErrPoolClosed
+	case poolPaused:
+		err := poolClearedError{err: p.lastClearErr, address: p.address}
+		p.stateMu.RUnlock()
+		return err
+	}
+
+	total := p.totalConnectionCount()
+	if p.maxSize != 0 && n > int(p.maxSize) {
+		n = int(p.maxSize)
+	}
+	var wantConns []*wantConn
+	for i := total; i < n; i++ {
+		w := newWantConn()
+		p.queueForNewConn(w)
+		wantConns = append(wantConns, w)
+	}
+	p.stateMu.RUnlock()
+
+	var err error
+	for i, w := range wantConns {
+		select {
+		case <-w.ready:
+		case <-ctx.Done():
+			// Stop waiting for the remaining connections. Any connection that is delivered to a
+			// cancelled wantConn is returned to the pool.
+			for _, w := range wantConns[i:] {
+				w.cancel(p, ctx.Err())
+			}
+			return ctx.Err()
+		}
+
+		w.mu.Lock()
+		conn, connErr := w.conn, w.err
+		w.mu.Unlock()
+		if conn != nil {
+			_ = p.checkInNoEvent(conn)
+		}
+		if connErr != nil && err == nil {
+			err = connErr
+		}
+	}
+	return err
+}
+
 func (p *pool) removePerishedConns() {
 	p.idleMu.Lock()
 	defer p.idleMu.Unlock()
@@ -1413,9 +1729,10 @@ func compact(arr []*connection) []*connection {
 type wantConn struct {
 	ready chan struct{}
 
-	mu   sync.Mutex // Guards conn, err
-	conn *connection
-	err  error
+	mu          sync.Mutex // Guards conn, err, deliveredAt
+	conn        *connection
+	err         error
+	deliveredAt time.Time
 }
 
 func newWantConn() *wantConn {
@@ -1424,6 +1741,17 @@ func newWantConn() *wantConn {
 	}
 }
 
+// waitDuration returns how long w waited between start and the delivery of a connection or error.
+func (w *wantConn) waitDuration(start time.Time) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.deliveredAt.IsZero() {
+		return time.Since(start)
+	}
+	return w.deliveredAt.Sub(start)
+}
+
 // waiting reports whether w is still waiting for an answer (connection or error).
 func (w *wantConn) waiting() bool {
 	select {
@@ -1448,6 +1776,7 @@ func (w *wantConn) tryDeliver(conn *connection, err error) bool {
 	if w.conn == nil && w.err == nil {
 		panic("x/mongo/driver/topology: internal error: misuse of tryDeliver")
 	}
+	w.deliveredAt = time.Now()
 
 	close(w.ready)
 
@@ -1499,6 +1828,22 @@ func (q *wantConnQueue) len() int {
 	return len(q.head) - q.headPos + len(q.tail)
 }
 
+// waitingLen returns the number of items in the queue that are still waiting.
+func (q *wantConnQueue) waitingLen() int {
+	var n int
+	for _, w := range q.head[q.headPos:] {
+		if w.waiting() {
+			n++
+		}
+	}
+	for _, w := range q.tail {
+		if w.waiting() {
+			n++
+		}
+	}
+	return n
+}
+
 // pushBack adds w to the back of the queue.
 func (q *wantConnQueue) pushBack(w *wantConn) {
 	q.tail = append(q.tail, w)
@@ -96,14 +96,19 @@ func (p *poolGenerationMap) removeConnection(serviceIDPtr *bson.ObjectID) {
 	}
 }
 
-func (p *poolGenerationMap) clear(serviceIDPtr *bson.ObjectID) {
+// clear increments the generation number associated with the given service ID and returns the new generation
+// number. The returned bool is false if the service ID is not tracked, in which case no generation was incremented.
+func (p *poolGenerationMap) clear(serviceIDPtr *bson.ObjectID) (uint64, bool) {
 	serviceID := getServiceID(serviceIDPtr)
 	p.Lock()
 	defer p.Unlock()
 
-	if stats, ok := p.generationMap[serviceID]; ok {
-		stats.generation++
+	stats, ok := p.generationMap[serviceID]
+	if !ok {
+		return 0, false
 	}
+	stats.generation++
+	return stats.generation, true
 }
 
 func (p *poolGenerationMap) stale(serviceIDPtr *bson.ObjectID, knownGeneration uint64) bool {
@@ -12,6 +12,7 @@ import (
 	"net"
 	"regexp"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -21,6 +22,8 @@ import (
 	"go.mongodb.org/mongo-driver/v2/internal/eventtest"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/operation"
 )
 
@@ -482,7 +485,7 @@ func TestPool_checkOut(t *testing.T) {
 		require.NoError(t, err)
 
 		_, err = p.checkOut(context.Background())
-		var want error = ConnectionError{Wrapped: dialErr, init: true, message: "failed to connect to testaddr:27017"}
+		var want error = ConnectionError{Wrapped: dialErr, init: true, message: "failed to connect to testaddr:27017", phase: event.PhaseDial}
 		assert.Equalf(t, want, err, "should return error from calling checkOut()")
 		// If a connection initialization error occurs during checkOut, removing and closing the
 		// failed connection both happen asynchronously with the checkOut. Wait for up to 2s for
@@ -680,6 +683,104 @@ func TestPool_checkOut(t *testing.T) {
 
 		p.close(context.Background())
 	})
+	t.Run("wait queue fail fast returns ErrPoolExhausted", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		tpm := eventtest.NewTestPoolMonitor()
+		p := newPool(poolConfig{
+			Address:           address.Address(addr.String()),
+			MaxPoolSize:       1,
+			ConnectTimeout:    defaultConnectionTimeout,
+			PoolMonitor:       tpm.PoolMonitor,
+			WaitQueueFailFast: true,
+		})
+		err := p.ready()
+		require.NoError(t, err)
+
+		// Saturate the pool by checking out the 1 connection that the pool will create.
+		c, err := p.checkOut(context.Background())
+		require.NoError(t, err)
+
+		// Expect the next check out to fail immediately, even with no timeout.
+		_, err = p.checkOut(context.Background())
+		assert.ErrorIs(t, err, ErrPoolExhausted)
+		assert.Equal(t, 0, p.stats().WaitQueueLength, "expected wait queue to be empty")
+
+		failed := tpm.Events(func(e *event.PoolEvent) bool {
+			return e.Type == event.ConnectionCheckOutFailed
+		})
+		require.Len(t, failed, 1)
+		assert.Equal(t, event.ReasonPoolExhausted, failed[0].Reason)
+
+		// Expect check out to succeed again once the connection is checked in.
+		err = p.checkIn(c)
+		require.NoError(t, err)
+		c, err = p.checkOut(context.Background())
+		require.NoError(t, err)
+		err = p.checkIn(c)
+		require.NoError(t, err)
+
+		p.close(context.Background())
+	})
+	t.Run("full wait queue returns ErrWaitQueueFull", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		p := newPool(poolConfig{
+			Address:          address.Address(addr.String()),
+			MaxPoolSize:      1,
+			MaxWaitQueueSize: 1,
+			ConnectTimeout:   defaultConnectionTimeout,
+		})
+		err := p.ready()
+		require.NoError(t, err)
+
+		// Saturate the pool by checking out the 1 connection that the pool will create.
+		c, err := p.checkOut(context.Background())
+		require.NoError(t, err)
+
+		// Saturate the wait queue with a check out that blocks until the connection is checked in.
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			c, err := p.checkOut(context.Background())
+			assert.NoError(t, err)
+			assert.NoError(t, p.checkIn(c))
+		}()
+		assert.Eventually(t,
+			func() bool { return p.waitQueueLength() == 1 },
+			time.Second,
+			time.Millisecond,
+			"expected one check out to be waiting")
+
+		// Expect further check outs to fail immediately, even with no timeout.
+		for i := 0; i < 10; i++ {
+			_, err = p.checkOut(context.Background())
+			assert.ErrorIs(t, err, ErrWaitQueueFull)
+		}
+		assert.Equal(t, 1, p.waitQueueLength(), "expected failed check outs to leave the wait queue")
+
+		err = p.checkIn(c)
+		require.NoError(t, err)
+		wg.Wait()
+
+		p.close(context.Background())
+	})
 	// Test that an indefinitely blocked checkOut() doesn't cause the wait queue to overflow
 	// if there are many other checkOut() calls that time out. This tests a scenario where a
 	// wantConnQueue may grow unbounded while a checkOut() is blocked, even if all subsequent
@@ -1018,6 +1119,225 @@ func TestPool_checkIn(t *testing.T) {
 	})
 }
 
+func TestPool_stats(t *testing.T) {
+	t.Parallel()
+
+	cleanup := make(chan struct{})
+	defer close(cleanup)
+	addr := bootstrapConnections(t, 2, func(nc net.Conn) {
+		<-cleanup
+		_ = nc.Close()
+	})
+
+	p := newPool(poolConfig{
+		Address:        address.Address(addr.String()),
+		MaxPoolSize:    2,
+		ConnectTimeout: defaultConnectionTimeout,
+	})
+	err := p.ready()
+	require.NoError(t, err)
+	defer p.close(context.Background())
+
+	assert.Equal(t, PoolStats{}, p.stats(), "expected empty pool stats")
+
+	c1, err := p.checkOut(context.Background())
+	require.NoError(t, err)
+	c2, err := p.checkOut(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, PoolStats{TotalConnections: 2, InUseConnections: 2}, p.stats())
+
+	// Start a check out that has to wait for a connection to be checked in.
+	done := make(chan struct{})
+	var c3 *connection
+	go func() {
+		defer close(done)
+
+		var err error
+		c3, err = p.checkOut(context.Background())
+		assert.NoError(t, err)
+	}()
+	assert.Eventually(t,
+		func() bool { return p.stats().WaitQueueLength == 1 },
+		time.Second,
+		time.Millisecond,
+		"expected one check out to be waiting")
+
+	err = p.checkIn(c1)
+	require.NoError(t, err)
+	<-done
+
+	assert.Equal(t, PoolStats{TotalConnections: 2, InUseConnections: 2}, p.stats())
+
+	p.pinConnectionToCursor()
+	p.pinConnectionToTransaction()
+	err = p.checkIn(c2)
+	require.NoError(t, err)
+
+	want := PoolStats{
+		TotalConnections:             2,
+		InUseConnections:             1,
+		IdleConnections:              1,
+		PinnedCursorConnections:      1,
+		PinnedTransactionConnections: 1,
+	}
+	assert.Equal(t, want, p.stats())
+
+	p.unpinConnectionFromCursor()
+	p.unpinConnectionFromTransaction()
+	err = p.checkIn(c3)
+	require.NoError(t, err)
+
+	assert.Equal(t, PoolStats{TotalConnections: 2, IdleConnections: 2}, p.stats())
+}
+
+func TestPool_prime(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates connections up to the requested size", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 3, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		var checkOutEvents int32
+		p := newPool(poolConfig{
+			Address:        address.Address(addr.String()),
+			ConnectTimeout: defaultConnectionTimeout,
+			PoolMonitor: &event.PoolMonitor{
+				Event: func(evt *event.PoolEvent) {
+					if evt.Type == event.ConnectionCheckedOut {
+						atomic.AddInt32(&checkOutEvents, 1)
+					}
+				},
+			},
+		})
+		err := p.ready()
+		require.NoError(t, err)
+		defer p.close(context.Background())
+
+		err = p.prime(context.Background(), 3)
+		require.NoError(t, err)
+		assert.Equal(t, PoolStats{TotalConnections: 3, IdleConnections: 3}, p.stats())
+
+		// Priming to a smaller size does not create or close connections.
+		err = p.prime(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, PoolStats{TotalConnections: 3, IdleConnections: 3}, p.stats())
+		assert.Equal(t, int32(0), atomic.LoadInt32(&checkOutEvents), "expected no check out events")
+	})
+	t.Run("limited by MaxPoolSize", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 2, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		p := newPool(poolConfig{
+			Address:        address.Address(addr.String()),
+			MaxPoolSize:    2,
+			ConnectTimeout: defaultConnectionTimeout,
+		})
+		err := p.ready()
+		require.NoError(t, err)
+		defer p.close(context.Background())
+
+		err = p.prime(context.Background(), 5)
+		require.NoError(t, err)
+		assert.Equal(t, PoolStats{TotalConnections: 2, IdleConnections: 2}, p.stats())
+	})
+	t.Run("returns connection errors", func(t *testing.T) {
+		t.Parallel()
+
+		dialErr := errors.New("dial error")
+		p := newPool(poolConfig{
+			Address:        "testaddr",
+			ConnectTimeout: defaultConnectionTimeout,
+		}, WithDialer(func(Dialer) Dialer {
+			return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+				return nil, dialErr
+			})
+		}))
+		err := p.ready()
+		require.NoError(t, err)
+		defer p.close(context.Background())
+
+		err = p.prime(context.Background(), 2)
+		assert.ErrorIs(t, err, dialErr)
+		assert.Eventually(t,
+			func() bool { return p.totalConnectionCount() == 0 },
+			2*time.Second,
+			10*time.Millisecond,
+			"expected failed connections to be removed from the pool")
+	})
+	t.Run("returns an error if the pool is paused", func(t *testing.T) {
+		t.Parallel()
+
+		p := newPool(poolConfig{})
+		defer p.close(context.Background())
+
+		err := p.prime(context.Background(), 1)
+		assert.IsType(t, poolClearedError{}, err)
+	})
+}
+
+func TestPool_pauseCheckOuts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("check out fails while paused and succeeds after resume", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 2, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		p := newPool(poolConfig{
+			Address:        address.Address(addr.String()),
+			ConnectTimeout: defaultConnectionTimeout,
+		})
+		err := p.ready()
+		require.NoError(t, err)
+		defer p.close(context.Background())
+
+		inFlight, err := p.checkOut(context.Background())
+		require.NoError(t, err)
+
+		p.pauseCheckOuts()
+
+		_, err = p.checkOut(context.Background())
+		assert.Equal(t, ErrPoolCheckOutsPaused, err, "expected check out to fail while paused")
+
+		// Connections that were checked out before pausing can still be used and checked in.
+		err = p.checkIn(inFlight)
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.availableConnectionCount(), "expected the idle connection to be kept")
+
+		// Clearing and readying the pool, like the server monitor does, does not resume check outs.
+		p.clear(errors.New("test error"), nil)
+		err = p.ready()
+		require.NoError(t, err)
+		_, err = p.checkOut(context.Background())
+		assert.Equal(t, ErrPoolCheckOutsPaused, err, "expected check out to fail while paused")
+
+		p.resumeCheckOuts()
+
+		conn, err := p.checkOut(context.Background())
+		require.NoError(t, err)
+		err = p.checkIn(conn)
+		require.NoError(t, err)
+	})
+}
+
 func TestPool_maintain(t *testing.T) {
 	t.Parallel()
 
@@ -1046,6 +1366,43 @@ func TestPool_maintain(t *testing.T) {
 
 		p.close(context.Background())
 	})
+	t.Run("authenticates MinPoolSize connections before they are available", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 3, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		var authenticated int32
+		var p *pool
+		handshaker := &testHandshaker{
+			finishHandshake: func(context.Context, *mnet.Connection) error {
+				// The connection being authenticated must not be available for check out yet.
+				assert.LessOrEqual(t, p.availableConnectionCount(), int(atomic.LoadInt32(&authenticated)),
+					"expected only authenticated connections to be available")
+				atomic.AddInt32(&authenticated, 1)
+				return nil
+			},
+		}
+		p = newPool(poolConfig{
+			Address:        address.Address(addr.String()),
+			MinPoolSize:    3,
+			ConnectTimeout: defaultConnectionTimeout,
+		}, WithHandshaker(func(Handshaker) Handshaker { return handshaker }))
+		err := p.ready()
+		require.NoError(t, err)
+		defer p.close(context.Background())
+
+		assert.Eventually(t,
+			func() bool { return p.availableConnectionCount() == 3 },
+			2*time.Second,
+			10*time.Millisecond,
+			"expected 3 idle connections in pool")
+		assert.Equal(t, int32(3), atomic.LoadInt32(&authenticated), "expected all background connections to be authenticated")
+	})
 	t.Run("when MinPoolSize > MaxPoolSize should not exceed MaxPoolSize connections", func(t *testing.T) {
 		t.Parallel()
 
@@ -1072,6 +1429,88 @@ func TestPool_maintain(t *testing.T) {
 
 		p.close(context.Background())
 	})
+	t.Run("keeps WarmSpares idle connections ready for checkout", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 3, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		// Use a slow dialer so that checking out a connection that has to be created is
+		// noticeably slower than checking out a warm spare.
+		const dialDelay = 500 * time.Millisecond
+		d := newdialer(DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+			time.Sleep(dialDelay)
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		}))
+		p := newPool(poolConfig{
+			Address:          address.Address(addr.String()),
+			WarmSpares:       2,
+			MaintainInterval: 10 * time.Millisecond,
+			ConnectTimeout:   defaultConnectionTimeout,
+		}, WithDialer(func(Dialer) Dialer { return d }))
+		err := p.ready()
+		require.NoError(t, err)
+
+		assertConnectionsOpened(t, d, 2)
+		assert.Eventually(t,
+			func() bool { return p.availableConnectionCount() == 2 },
+			3*time.Second,
+			10*time.Millisecond,
+			"expected 2 idle connections in pool")
+
+		start := time.Now()
+		c, err := p.checkOut(context.Background())
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), dialDelay/5, "checkOut of a warm spare should not wait for a dial")
+
+		// Checking out a warm spare should cause a replacement to be created in the background.
+		assertConnectionsOpened(t, d, 3)
+		assert.Eventually(t,
+			func() bool { return p.availableConnectionCount() == 2 },
+			3*time.Second,
+			10*time.Millisecond,
+			"expected 2 idle connections in pool")
+		assert.Equalf(t, 3, p.totalConnectionCount(), "should be 3 total connections in pool")
+
+		err = p.checkIn(c)
+		require.NoError(t, err)
+		p.close(context.Background())
+	})
+	t.Run("keeps WarmSpares connections in addition to MinPoolSize", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 4, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		d := newdialer(&net.Dialer{})
+		p := newPool(poolConfig{
+			Address:          address.Address(addr.String()),
+			MinPoolSize:      2,
+			WarmSpares:       2,
+			MaintainInterval: 10 * time.Millisecond,
+			ConnectTimeout:   defaultConnectionTimeout,
+		}, WithDialer(func(Dialer) Dialer { return d }))
+		err := p.ready()
+		require.NoError(t, err)
+
+		assertConnectionsOpened(t, d, 4)
+		assert.Eventually(t,
+			func() bool { return p.availableConnectionCount() == 4 },
+			3*time.Second,
+			10*time.Millisecond,
+			"expected 4 idle connections in pool")
+		assert.Equalf(t, 4, p.totalConnectionCount(), "should be 4 total connections in pool")
+
+		p.close(context.Background())
+	})
 	t.Run("removes perished connections", func(t *testing.T) {
 		t.Parallel()
 
@@ -1463,6 +1902,115 @@ func TestBackgroundRead(t *testing.T) {
 		require.Len(t, bgErrs, 1, "expected 1 error from bgRead()")
 		assert.EqualError(t, bgErrs[0], "error discarding 3 byte message: EOF")
 	})
+	t.Run("check in drains remaining bytes", func(t *testing.T) {
+		type bgResult struct {
+			errs       []error
+			connClosed bool
+		}
+		resultCh := make(chan bgResult, 1)
+		var originalCallback func(string, time.Time, time.Time, []error, bool)
+		originalCallback, BGReadCallback = BGReadCallback, func(_ string, _, _ time.Time, errs []error, connClosed bool) {
+			resultCh <- bgResult{errs: errs, connClosed: connClosed}
+		}
+		t.Cleanup(func() {
+			BGReadCallback = originalCallback
+		})
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			defer func() {
+				<-cleanup
+				_ = nc.Close()
+			}()
+
+			// Write the remainder of an abandoned reply followed by a full message.
+			_, err := nc.Write([]byte{1, 2, 3, 4, 5, 10, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+			require.NoError(t, err)
+		})
+
+		p := newPool(
+			poolConfig{Address: address.Address(addr.String())},
+		)
+		defer p.close(context.Background())
+		err := p.ready()
+		require.NoError(t, err)
+
+		conn, err := p.checkOut(context.Background())
+		require.NoError(t, err)
+		remaining := int32(5)
+		conn.awaitRemainingBytes = &remaining
+		err = p.checkIn(conn)
+		require.NoError(t, err)
+
+		var res bgResult
+		select {
+		case res = <-resultCh:
+		case <-time.After(3 * time.Second):
+			require.Fail(t, "did not receive background read result after waiting for 3 seconds")
+		}
+		require.Len(t, res.errs, 0, "expected no error from bgRead()")
+		assert.False(t, res.connClosed, "expected connection to remain open")
+		assert.Nil(t, conn.awaitRemainingBytes, "conn.awaitRemainingBytes should be nil")
+		assert.Equal(t, 1, p.availableConnectionCount(), "expected connection to be available")
+
+		// The next read on the reused connection must start at the following message.
+		conn, err = p.checkOut(context.Background())
+		require.NoError(t, err)
+		wm, err := conn.readWireMessage(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []byte{10, 0, 0, 0, 0, 0, 0, 0, 0, 0}, wm, "expected drained connection to read the next message")
+		require.NoError(t, p.checkIn(conn))
+	})
+	t.Run("check in retires connection when draining fails", func(t *testing.T) {
+		type bgResult struct {
+			errs       []error
+			connClosed bool
+		}
+		resultCh := make(chan bgResult, 1)
+		var originalCallback func(string, time.Time, time.Time, []error, bool)
+		originalCallback, BGReadCallback = BGReadCallback, func(_ string, _, _ time.Time, errs []error, connClosed bool) {
+			resultCh <- bgResult{errs: errs, connClosed: connClosed}
+		}
+		t.Cleanup(func() {
+			BGReadCallback = originalCallback
+		})
+
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			defer func() {
+				_ = nc.Close()
+			}()
+
+			// Write only part of the remaining bytes before closing the socket.
+			_, err := nc.Write([]byte{1, 2})
+			require.NoError(t, err)
+		})
+
+		p := newPool(
+			poolConfig{Address: address.Address(addr.String())},
+		)
+		defer p.close(context.Background())
+		err := p.ready()
+		require.NoError(t, err)
+
+		conn, err := p.checkOut(context.Background())
+		require.NoError(t, err)
+		remaining := int32(5)
+		conn.awaitRemainingBytes = &remaining
+		err = p.checkIn(conn)
+		require.NoError(t, err)
+
+		var res bgResult
+		select {
+		case res = <-resultCh:
+		case <-time.After(3 * time.Second):
+			require.Fail(t, "did not receive background read result after waiting for 3 seconds")
+		}
+		require.Len(t, res.errs, 1, "expected 1 error from bgRead()")
+		assert.EqualError(t, res.errs[0], "error discarding 5 byte message: EOF")
+		assert.True(t, res.connClosed, "expected connection to be closed")
+		assert.Equal(t, 0, p.totalConnectionCount(), "expected connection to be removed from the pool")
+	})
 }
 
 func assertConnectionsClosed(t *testing.T, dialer *dialer, count int) {
@@ -1583,4 +2131,169 @@ func TestPool_PoolMonitor(t *testing.T) {
 			events[2].Duration,
 			"expected ConnectionCheckOutFailed Duration to be set")
 	})
+	t.Run("records wait durations", func(t *testing.T) {
+		t.Parallel()
+
+		cleanup := make(chan struct{})
+		defer close(cleanup)
+
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			<-cleanup
+			_ = nc.Close()
+		})
+
+		tpm := eventtest.NewTestPoolMonitor()
+		p := newPool(poolConfig{
+			Address:     address.Address(addr.String()),
+			MaxPoolSize: 1,
+			PoolMonitor: tpm.PoolMonitor,
+		})
+		defer p.close(context.Background())
+
+		err := p.ready()
+		require.NoError(t, err, "ready error")
+
+		// Check out the only connection so the pool is saturated.
+		conn, err := p.checkOut(context.Background())
+		require.NoError(t, err, "checkOut error")
+
+		// Start a second checkOut that has to wait for the first connection to be checked in.
+		const wait = 50 * time.Millisecond
+		checkedOut := make(chan error, 1)
+		go func() {
+			c, err := p.checkOut(context.Background())
+			if err == nil {
+				err = p.checkIn(c)
+			}
+			checkedOut <- err
+		}()
+
+		time.Sleep(wait)
+		err = p.checkIn(conn)
+		require.NoError(t, err, "checkIn error")
+		require.NoError(t, <-checkedOut, "waiting checkOut error")
+
+		// Try a third checkOut with a Context that times out while waiting.
+		conn, err = p.checkOut(context.Background())
+		require.NoError(t, err, "checkOut error")
+		ctx, cancel := context.WithTimeout(context.Background(), wait)
+		defer cancel()
+		_, err = p.checkOut(ctx)
+		require.Error(t, err, "expected a checkOut error")
+		_ = p.checkIn(conn)
+
+		events := tpm.Events(func(evt *event.PoolEvent) bool {
+			switch evt.Type {
+			case event.ConnectionCheckedOut, event.ConnectionCheckOutFailed:
+				return true
+			}
+			return false
+		})
+		require.Lenf(t, events, 4, "expected there to be 4 pool events")
+
+		assert.Equal(t, event.ConnectionCheckedOut, events[1].Type)
+		assert.GreaterOrEqual(t, events[1].WaitDuration, wait,
+			"expected the blocked checkOut to report its wait duration")
+
+		assert.Equal(t, event.ConnectionCheckedOut, events[2].Type)
+		assert.Equal(t, time.Duration(0), events[2].WaitDuration,
+			"expected no wait when an idle connection is available")
+
+		assert.Equal(t, event.ConnectionCheckOutFailed, events[3].Type)
+		assert.GreaterOrEqual(t, events[3].WaitDuration, wait,
+			"expected the timed out checkOut to report its wait duration")
+	})
+	t.Run("classifies connection creation failures", func(t *testing.T) {
+		t.Parallel()
+
+		pipeDialer := DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+			client, server := net.Pipe()
+			_ = server.Close()
+			return client, nil
+		})
+		errDialer := func(err error) Dialer {
+			return DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+				return nil, err
+			})
+		}
+
+		testCases := []struct {
+			name       string
+			dialer     Dialer
+			handshaker Handshaker
+			want       string
+		}{
+			{
+				name:   "dns",
+				dialer: errDialer(&net.DNSError{Err: "no such host", Name: "db.example.invalid", IsNotFound: true}),
+				want:   event.PhaseDNS,
+			},
+			{
+				name:   "dial",
+				dialer: errDialer(errors.New("connection refused")),
+				want:   event.PhaseDial,
+			},
+			{
+				name:   "handshake",
+				dialer: pipeDialer,
+				handshaker: &testHandshaker{
+					getHandshakeInformation: func(context.Context, address.Address, *mnet.Connection) (driver.HandshakeInformation, error) {
+						return driver.HandshakeInformation{}, errors.New("hello failed")
+					},
+				},
+				want: event.PhaseHandshake,
+			},
+			{
+				name:   "auth",
+				dialer: pipeDialer,
+				handshaker: &testHandshaker{
+					finishHandshake: func(context.Context, *mnet.Connection) error {
+						return errors.New("authentication failed")
+					},
+				},
+				want: event.PhaseAuth,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				tpm := eventtest.NewTestPoolMonitor()
+				opts := []ConnectionOption{WithDialer(func(Dialer) Dialer { return tc.dialer })}
+				if tc.handshaker != nil {
+					opts = append(opts, WithHandshaker(func(Handshaker) Handshaker { return tc.handshaker }))
+				}
+				p := newPool(poolConfig{
+					Address:        address.Address("db.example.invalid:27017"),
+					ConnectTimeout: defaultConnectionTimeout,
+					PoolMonitor:    tpm.PoolMonitor,
+				}, opts...)
+				defer p.close(context.Background())
+
+				err := p.ready()
+				require.NoError(t, err, "ready error")
+
+				_, err = p.checkOut(context.Background())
+				require.Error(t, err, "expected a checkOut error")
+
+				closed := func() []*event.PoolEvent {
+					return tpm.Events(func(evt *event.PoolEvent) bool {
+						return evt.Type == event.ConnectionClosed
+					})
+				}
+				assert.Eventually(t,
+					func() bool { return len(closed()) == 1 },
+					100*time.Millisecond,
+					1*time.Millisecond,
+					"expected a ConnectionClosed event within 100ms")
+
+				events := closed()
+				require.Len(t, events, 1, "expected one ConnectionClosed event")
+				assert.Equal(t, event.ReasonError, events[0].Reason, "unexpected reason")
+				assert.Equal(t, tc.want, events[0].Phase, "unexpected phase")
+			})
+		}
+	})
 }
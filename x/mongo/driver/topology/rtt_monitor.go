@@ -33,6 +33,10 @@ type rttConfig struct {
 	createConnectionFn func() *connection
 	connectTimeout     time.Duration
 	createOperationFn  func(*mnet.Connection) *operation.Hello
+
+	// alpha is the smoothing factor of the RTT exponentially weighted moving average, i.e. the weight given to the
+	// newest sample. If it is 0, rttAlphaValue is used.
+	alpha float64
 }
 
 type rttMonitor struct {
@@ -266,7 +270,11 @@ func (r *rttMonitor) addSample(rtt time.Duration) {
 		return
 	}
 
-	r.averageRTT = time.Duration(rttAlphaValue*float64(rtt) + (1-rttAlphaValue)*float64(r.averageRTT))
+	alpha := r.cfg.alpha
+	if alpha == 0 {
+		alpha = rttAlphaValue
+	}
+	r.averageRTT = time.Duration(alpha*float64(rtt) + (1-alpha)*float64(r.averageRTT))
 }
 
 // EWMA returns the exponentially weighted moving average observed round-trip time.
@@ -19,9 +19,11 @@ import (
 
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/mnet"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/operation"
@@ -484,3 +486,84 @@ func TestRTTMonitor_stddev(t *testing.T) {
 		})
 	}
 }
+
+func TestRTTMonitor_EWMA(t *testing.T) {
+	t.Parallel()
+
+	// Server "a" is usually faster than server "b", but its most recent heartbeat was slow.
+	samplesA := []time.Duration{
+		10 * time.Millisecond,
+		10 * time.Millisecond,
+		10 * time.Millisecond,
+		100 * time.Millisecond,
+	}
+	samplesB := []time.Duration{
+		20 * time.Millisecond,
+		20 * time.Millisecond,
+		20 * time.Millisecond,
+		20 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name     string
+		alpha    float64
+		wantEWMA time.Duration
+		wantAddr address.Address
+	}{
+		{
+			name:     "default smoothing",
+			alpha:    0,
+			wantEWMA: 28 * time.Millisecond,
+			wantAddr: "b:27017",
+		},
+		{
+			name:     "heavy smoothing ignores spike",
+			alpha:    0.05,
+			wantEWMA: 14500 * time.Microsecond,
+			wantAddr: "a:27017",
+		},
+		{
+			name:     "no smoothing",
+			alpha:    1,
+			wantEWMA: 100 * time.Millisecond,
+			wantAddr: "b:27017",
+		},
+	}
+
+	for _, test := range tests {
+		test := test // capture the range variable
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			newServerDesc := func(addr address.Address, samples []time.Duration) (description.Server, time.Duration) {
+				rtt := &rttMonitor{
+					cfg:       &rttConfig{alpha: test.alpha},
+					movingMin: list.New(),
+				}
+				for _, sample := range samples {
+					rtt.addSample(sample)
+				}
+				desc := description.Server{
+					Addr:          addr,
+					Kind:          description.ServerKindRSSecondary,
+					AverageRTT:    rtt.EWMA(),
+					AverageRTTSet: true,
+				}
+				return desc, rtt.EWMA()
+			}
+
+			a, ewmaA := newServerDesc("a:27017", samplesA)
+			b, _ := newServerDesc("b:27017", samplesB)
+			assert.InDelta(t, float64(test.wantEWMA), float64(ewmaA), float64(time.Microsecond),
+				"expected EWMA %v, got %v", test.wantEWMA, ewmaA)
+
+			selector := &serverselector.Latency{Latency: 0}
+			topo := description.Topology{Kind: description.TopologyKindReplicaSetWithPrimary}
+			selected, err := selector.SelectServer(topo, []description.Server{a, b})
+			require.NoError(t, err, "SelectServer error")
+			require.Len(t, selected, 1, "expected exactly one server in the latency window")
+			assert.Equal(t, test.wantAddr, selected[0].Addr, "expected server %v to be selected", test.wantAddr)
+		})
+	}
+}
@@ -27,7 +27,8 @@ import (
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/operation"
 )
 
-const minHeartbeatInterval = 500 * time.Millisecond
+const defaultMinHeartbeatInterval = 500 * time.Millisecond
+const defaultClockSkewWarningThreshold = time.Minute
 const wireVersion42 = 8 // Wire version for MongoDB 4.2
 
 // Server state constants.
@@ -142,7 +143,23 @@ type Server struct {
 
 	processErrorLock sync.Mutex
 	rttMonitor       *rttMonitor
+	errorRate        *errorRateTracker
 	monitorOnce      sync.Once
+
+	// singleConnSem is held by the operation using singleConn. Both are only
+	// used if the server is configured with WithSingleConnection.
+	singleConnSem chan struct{}
+	singleConn    *Connection
+
+	// clockSkewed is whether the estimated clock skew of the server exceeded
+	// the warning threshold at the last check. It is only accessed by the
+	// monitoring goroutine.
+	clockSkewed bool
+
+	// hasStreamingSlot is whether the server holds a slot of the streaming
+	// limiter shared by the servers of the topology. It is only accessed by
+	// the monitoring goroutine.
+	hasStreamingSlot bool
 }
 
 // updateTopologyCallback is a callback used to create a server that should be called when the parent Topology instance
@@ -186,6 +203,7 @@ func NewServer(
 		done:          make(chan struct{}),
 		checkNow:      make(chan struct{}, 1),
 		disconnecting: make(chan struct{}),
+		singleConnSem: make(chan struct{}, 1),
 
 		topologyID: topologyID,
 
@@ -195,12 +213,14 @@ func NewServer(
 	s.desc.Store(newDefaultServerDescription(addr))
 	rttCfg := &rttConfig{
 		interval:           cfg.heartbeatInterval,
+		alpha:              cfg.rttSmoothingFactor,
 		minRTTWindow:       5 * time.Minute,
 		createConnectionFn: s.createConnection,
 		createOperationFn:  s.createBaseOperation,
 		connectTimeout:     connectTimeout,
 	}
 	s.rttMonitor = newRTTMonitor(rttCfg)
+	s.errorRate = newErrorRateTracker(cfg.errorRateWindow)
 
 	pc := poolConfig{
 		Address:          addr,
@@ -214,6 +234,12 @@ func NewServer(
 		Logger:           cfg.logger,
 		handshakeErrFn:   s.ProcessHandshakeError,
 		ConnectTimeout:   connectTimeout,
+
+		WaitQueueFailFast: cfg.waitQueueFailFast,
+		MaxWaitQueueSize:  cfg.maxWaitQueueSize,
+		WarmSpares:        cfg.warmSpares,
+		PinLeakThreshold:  cfg.pinLeakThreshold,
+		GenerationChanged: cfg.generationChangedFn,
 	}
 
 	connectionOpts := copyConnectionOpts(cfg.connectionOpts)
@@ -229,6 +255,10 @@ func mustLogServerMessage(srv *Server) bool {
 }
 
 func logServerMessage(srv *Server, msg string, keysAndValues ...interface{}) {
+	logServerMessageAtLevel(srv, logger.LevelDebug, msg, keysAndValues...)
+}
+
+func logServerMessageAtLevel(srv *Server, level logger.Level, msg string, keysAndValues ...interface{}) {
 	serverHost, serverPort, err := net.SplitHostPort(srv.address.String())
 	if err != nil {
 		serverHost = srv.address.String()
@@ -243,7 +273,7 @@ func logServerMessage(srv *Server, msg string, keysAndValues ...interface{}) {
 		serverConnectionID = srv.conn.serverConnectionID
 	}
 
-	srv.cfg.logger.Print(logger.LevelDebug,
+	srv.cfg.logger.Print(level,
 		logger.ComponentTopology,
 		msg,
 		logger.SerializeServer(logger.Server{
@@ -306,6 +336,18 @@ func (s *Server) Disconnect(ctx context.Context) error {
 
 	s.heartbeatListener.StopListening()
 
+	// Close the pinned connection if no operation is using it so a graceful
+	// pool close doesn't wait for it to be checked in.
+	select {
+	case s.singleConnSem <- struct{}{}:
+		if s.singleConn != nil {
+			_ = s.singleConn.Expire()
+			s.singleConn = nil
+		}
+		<-s.singleConnSem
+	default:
+	}
+
 	s.pool.close(ctx)
 
 	s.closewg.Wait()
@@ -321,6 +363,10 @@ func (s *Server) Connection(ctx context.Context) (*mnet.Connection, error) {
 		return nil, ErrServerClosed
 	}
 
+	if s.cfg.singleConnection {
+		return s.singleConnection(ctx)
+	}
+
 	// Increment the operation count before calling checkOut to make sure that all connection
 	// requests are included in the operation count, including those in the wait queue. If we got an
 	// error instead of a connection, immediately decrement the operation count.
@@ -333,6 +379,7 @@ func (s *Server) Connection(ctx context.Context) (*mnet.Connection, error) {
 
 	serverConn := &Connection{
 		connection: conn,
+		serverAPI:  s.cfg.serverAPI,
 		cleanupServerFn: func() {
 			// Decrement the operation count whenever the caller is done with the connection. Note
 			// that cleanupServerFn() is not called while the connection is pinned to a cursor or
@@ -347,6 +394,83 @@ func (s *Server) Connection(ctx context.Context) (*mnet.Connection, error) {
 	return mnet.NewConnection(serverConn), nil
 }
 
+// singleConnection returns the server's pinned connection once the previous
+// operation has closed it. If there is no pinned connection yet, or the pinned
+// connection was closed because of an error or is stale, a connection is checked
+// out of the pool and pinned in its place.
+func (s *Server) singleConnection(ctx context.Context) (*mnet.Connection, error) {
+	select {
+	case s.singleConnSem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	atomic.AddInt64(&s.operationCount, 1)
+	release := func() {
+		atomic.AddInt64(&s.operationCount, -1)
+		<-s.singleConnSem
+	}
+
+	if c := s.singleConn; c != nil && (!c.Alive() || c.connection.closed() || c.Stale()) {
+		_ = c.Expire()
+		s.singleConn = nil
+	}
+
+	if s.singleConn == nil {
+		conn, err := s.pool.checkOut(ctx)
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		c := &Connection{
+			connection: conn,
+			serverAPI:  s.cfg.serverAPI,
+		}
+		// Pin the connection so that closing it at the end of each operation
+		// doesn't return it to the pool. The pool's pinned connection counters
+		// only track cursors and transactions, so they are not updated.
+		if err := c.pin("client", func() {}, nil); err != nil {
+			release()
+			return nil, err
+		}
+		c.mu.Lock()
+		c.stopPinLeakDetection()
+		c.mu.Unlock()
+		s.singleConn = c
+	}
+
+	return mnet.NewConnection(&singleConnection{Connection: s.singleConn, release: release}), nil
+}
+
+// singleConnection is the server's pinned connection as used by a single
+// operation. Closing it makes the connection available to the next operation
+// instead of returning it to the pool.
+type singleConnection struct {
+	*Connection
+
+	once    sync.Once
+	release func()
+}
+
+// Close releases the connection to the next operation.
+func (c *singleConnection) Close() error {
+	c.once.Do(c.release)
+	return nil
+}
+
+// Expire closes the underlying connection and releases the server's pinned
+// connection slot, so the next operation checks out a new connection. It has no
+// effect if the connection was already released.
+func (c *singleConnection) Expire() error {
+	var err error
+	c.once.Do(func() {
+		err = c.Connection.Expire()
+		c.release()
+	})
+	return err
+}
+
 // ProcessHandshakeError implements SDAM error handling for errors that occur before a connection
 // finishes handshaking.
 func (s *Server) ProcessHandshakeError(err error, startingGenerationNumber uint64, serviceID *bson.ObjectID) {
@@ -387,6 +511,40 @@ func (s *Server) Description() description.Server {
 	return s.desc.Load().(description.Server)
 }
 
+// PoolStats returns a snapshot of the counters of the server's connection pool.
+func (s *Server) PoolStats() PoolStats {
+	return s.pool.stats()
+}
+
+// PrimeConnections establishes connections in the server's connection pool
+// until it holds at least n connections, limited by the maximum pool size.
+func (s *Server) PrimeConnections(ctx context.Context, n int) error {
+	if atomic.LoadInt64(&s.state) != serverConnected {
+		return ErrServerClosed
+	}
+
+	return s.pool.prime(ctx, n)
+}
+
+// PauseCheckOuts makes connection check outs from the server's connection
+// pool fail with ErrPoolCheckOutsPaused until ResumeCheckOuts is called.
+// Operations that already hold a connection are not affected.
+func (s *Server) PauseCheckOuts() {
+	s.pool.pauseCheckOuts()
+}
+
+// ResumeCheckOuts allows connection check outs from the server's connection
+// pool again after PauseCheckOuts.
+func (s *Server) ResumeCheckOuts() {
+	s.pool.resumeCheckOuts()
+}
+
+// OperationStats returns a snapshot of the outcomes of the operations run
+// against the server over the configured error rate window.
+func (s *Server) OperationStats() OperationStats {
+	return s.errorRate.stats()
+}
+
 // SelectedDescription returns a description.SelectedServer with a Kind of
 // Single. This can be used when performing tasks like monitoring a batch
 // of servers and you want to run one off commands against those servers.
@@ -453,6 +611,13 @@ func getWriteConcernErrorForProcessing(err error) (*driver.WriteConcernError, bo
 
 // ProcessError handles SDAM error handling and implements driver.ErrorProcessor.
 func (s *Server) ProcessError(err error, describer mnet.Describer) driver.ProcessErrorResult {
+	// ProcessError is called with the result of every operation round trip, so
+	// count the outcome for the server's error rate. Cancellation by the caller
+	// says nothing about the health of the server, so it isn't counted.
+	if !errors.Is(err, context.Canceled) {
+		s.errorRate.record(err != nil)
+	}
+
 	// Ignore nil errors.
 	if err == nil {
 		return driver.NoChange
@@ -622,7 +787,11 @@ func checkServerWithSignal(
 func (s *Server) update() {
 	defer s.closewg.Done()
 	heartbeatTicker := time.NewTicker(s.cfg.heartbeatInterval)
-	rateLimiter := time.NewTicker(minHeartbeatInterval)
+	minInterval := s.cfg.minHeartbeatInterval
+	if minInterval <= 0 {
+		minInterval = defaultMinHeartbeatInterval
+	}
+	rateLimiter := time.NewTicker(minInterval)
 	defer heartbeatTicker.Stop()
 	defer rateLimiter.Stop()
 	checkNow := s.checkNow
@@ -644,6 +813,7 @@ func (s *Server) update() {
 		if s.conn != nil {
 			_ = s.conn.close()
 		}
+		s.releaseStreamingSlot()
 	}
 
 	waitUntilNextCheck := func() {
@@ -740,11 +910,17 @@ func (s *Server) update() {
 		transitionedFromNetworkError := desc.LastError != nil && unwrapConnectionError(desc.LastError) != nil &&
 			previousDescription.Kind != description.Unknown
 
-		if isStreamingEnabled(s) && isStreamable(s) {
+		// Give up the streaming slot while the server cannot stream so that another server can use it.
+		if !isStreamable(s) && !connectionIsStreaming {
+			s.releaseStreamingSlot()
+		}
+
+		if s.streamable() {
 			s.monitorOnce.Do(s.rttMonitor.connect)
 		}
 
-		if isStreamingEnabled(s) && (isStreamable(s) || connectionIsStreaming) || transitionedFromNetworkError {
+		if isStreamingEnabled(s) && (isStreamable(s) && s.acquireStreamingSlot() || connectionIsStreaming) ||
+			transitionedFromNetworkError {
 			continue
 		}
 
@@ -813,11 +989,45 @@ func (s *Server) createConnection() *connection {
 		}),
 		// Override any monitors specified in options with nil to avoid monitoring heartbeats.
 		WithMonitor(func(*event.CommandMonitor) *event.CommandMonitor { return nil }),
+		// Monitoring connections are not authenticated, so they do not need a credential.
+		WithCredentialInjector(func(CredentialInjectorFunc) CredentialInjectorFunc { return nil }),
 	)
 
 	return newConnection(s.address, opts...)
 }
 
+// ServerCompressors dials the server at addr, performs an unauthenticated hello handshake and returns the compressors
+// that the server supports, as reported in the hello response. Every compressor supported by the driver ("snappy",
+// "zlib" and "zstd") is offered to the server, regardless of the compressors configured in cfg. The connection is
+// configured by cfg, so the dialer, TLS or TLCP configuration and connect timeout are respected, but no authentication
+// is performed and no connection pool is created. The connection is closed before ServerCompressors returns.
+func ServerCompressors(ctx context.Context, addr address.Address, cfg *Config) ([]string, error) {
+	scfg := newServerConfig(cfg.ConnectTimeout, cfg.ServerOpts...)
+	opts := copyConnectionOpts(scfg.connectionOpts)
+	opts = append(opts,
+		WithHandshaker(func(Handshaker) Handshaker {
+			return operation.NewHello().AppName(scfg.appname).Compressors([]string{"snappy", "zlib", "zstd"}).
+				ServerAPI(scfg.serverAPI).LoadBalanced(scfg.loadBalanced)
+		}),
+		WithMonitor(func(*event.CommandMonitor) *event.CommandMonitor { return nil }),
+		WithCredentialInjector(func(CredentialInjectorFunc) CredentialInjectorFunc { return nil }),
+	)
+	conn := newConnection(addr, opts...)
+
+	if scfg.connectTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, scfg.connectTimeout)
+		defer cancel()
+	}
+
+	if err := conn.connect(ctx); err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.close() }()
+
+	return conn.desc.Compression, nil
+}
+
 func copyConnectionOpts(opts []ConnectionOption) []ConnectionOption {
 	optsCopy := make([]ConnectionOption, len(opts))
 	copy(optsCopy, opts)
@@ -863,7 +1073,32 @@ func isStreamable(srv *Server) bool {
 }
 
 func (s *Server) streamable() bool {
-	return isStreamingEnabled(s) && isStreamable(s)
+	return isStreamingEnabled(s) && isStreamable(s) && s.acquireStreamingSlot()
+}
+
+// acquireStreamingSlot reports whether the server may use a streaming monitoring connection. If the topology caps the
+// number of streaming monitors, servers acquire a slot the first time they are streamable, in no particular order,
+// and keep it until they are no longer streamable or are disconnected. Servers that cannot acquire a slot poll.
+func (s *Server) acquireStreamingSlot() bool {
+	if s.hasStreamingSlot || s.cfg.streamingLimiter == nil {
+		return true
+	}
+	select {
+	case s.cfg.streamingLimiter <- struct{}{}:
+		s.hasStreamingSlot = true
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseStreamingSlot releases the streaming slot held by the server, if any.
+func (s *Server) releaseStreamingSlot() {
+	if !s.hasStreamingSlot {
+		return
+	}
+	<-s.cfg.streamingLimiter
+	s.hasStreamingSlot = false
 }
 
 // getHeartbeatTimeout will return the maximum allowable duration for streaming
@@ -941,7 +1176,8 @@ func doHandshake(ctx context.Context, srv *Server) (description.Server, error) {
 
 		handshakeOp = handshakeOp.
 			TopologyVersion(srv.Description().TopologyVersion).
-			MaxAwaitTimeMS(maxAwaitTimeMS)
+			MaxAwaitTimeMS(maxAwaitTimeMS).
+			Compress(srv.cfg.compressHeartbeats)
 	}
 
 	// Perform the handshake.
@@ -992,7 +1228,7 @@ func (s *Server) check(ctx context.Context) (description.Server, error) {
 		// An existing connection is being used. Use the server description
 		// properties to execute the right heartbeat.
 
-		streamable := isStreamingEnabled(s) && isStreamable(s)
+		streamable := s.streamable()
 
 		s.publishServerHeartbeatStartedEvent(s.conn.ID(), s.conn.getCurrentlyStreaming() || streamable)
 
@@ -1028,6 +1264,11 @@ func (s *Server) check(ctx context.Context) (description.Server, error) {
 		desc.AverageRTT = s.rttMonitor.EWMA()
 		desc.AverageRTTSet = true
 		desc.HeartbeatInterval = s.cfg.heartbeatInterval
+		if !desc.LocalTime.IsZero() {
+			desc.ClockSkew = estimateClockSkew(desc)
+			desc.ClockSkewSet = true
+			s.checkClockSkew(desc.ClockSkew)
+		}
 
 		return desc, nil
 	}
@@ -1045,6 +1286,36 @@ func (s *Server) check(ctx context.Context) (description.Server, error) {
 	return newServerDescriptionFromError(s.address, err, topologyVersion), nil
 }
 
+// estimateClockSkew returns the estimated difference between the clock of the
+// server described by desc and the local clock. A positive value means the
+// server's clock is ahead. The server is assumed to have read its clock half
+// way through the round trip of the hello command.
+func estimateClockSkew(desc description.Server) time.Duration {
+	local := desc.LastUpdateTime.Add(-desc.AverageRTT / 2)
+	return desc.LocalTime.Sub(local)
+}
+
+// checkClockSkew logs a warning when the estimated clock skew of the server
+// first exceeds the configured threshold. The warning is logged again only
+// after the skew has returned within the threshold.
+func (s *Server) checkClockSkew(skew time.Duration) {
+	threshold := s.cfg.clockSkewThreshold
+	if threshold <= 0 {
+		return
+	}
+	if skew < 0 {
+		skew = -skew
+	}
+
+	skewed := skew > threshold
+	if skewed && !s.clockSkewed && s.cfg.logger != nil &&
+		s.cfg.logger.LevelComponentEnabled(logger.LevelInfo, logger.ComponentTopology) {
+		logServerMessageAtLevel(s, logger.LevelInfo, logger.TopologyServerClockSkewed,
+			logger.KeyClockSkewMS, skew.Milliseconds())
+	}
+	s.clockSkewed = skewed
+}
+
 func extractTopologyVersion(err error) *description.TopologyVersion {
 	if ce, ok := err.(ConnectionError); ok {
 		err = ce.Wrapped
@@ -1141,8 +1412,14 @@ func (s *Server) publishServerOpeningEvent(addr address.Address) {
 
 // publishes a ServerHeartbeatStartedEvent to indicate a hello command has started
 func (s *Server) publishServerHeartbeatStartedEvent(connectionID string, await bool) {
+	var addr address.Address
+	if s != nil {
+		addr = s.address
+	}
+
 	serverHeartbeatStarted := &event.ServerHeartbeatStartedEvent{
 		ConnectionID: connectionID,
+		Address:      addr,
 		Awaited:      await,
 	}
 
@@ -1162,10 +1439,16 @@ func (s *Server) publishServerHeartbeatSucceededEvent(connectionID string,
 	desc description.Server,
 	await bool,
 ) {
+	var addr address.Address
+	if s != nil {
+		addr = s.address
+	}
+
 	serverHeartbeatSucceeded := &event.ServerHeartbeatSucceededEvent{
 		Duration:     duration,
 		Reply:        newEventServerDescription(desc),
 		ConnectionID: connectionID,
+		Address:      addr,
 		Awaited:      await,
 	}
 
@@ -1201,10 +1484,16 @@ func (s *Server) publishServerHeartbeatFailedEvent(connectionID string,
 	err error,
 	await bool,
 ) {
+	var addr address.Address
+	if s != nil {
+		addr = s.address
+	}
+
 	serverHeartbeatFailed := &event.ServerHeartbeatFailedEvent{
 		Duration:     duration,
 		Failure:      err,
 		ConnectionID: connectionID,
+		Address:      addr,
 		Awaited:      await,
 	}
 
@@ -12,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/logger"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/connstring"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/session"
@@ -25,8 +26,13 @@ type serverConfig struct {
 	connectionOpts       []ConnectionOption
 	appname              string
 	heartbeatInterval    time.Duration
+	minHeartbeatInterval time.Duration
+	rttSmoothingFactor   float64
+	errorRateWindow      time.Duration
+	clockSkewThreshold   time.Duration
 	connectTimeout       time.Duration
 	serverMonitoringMode string
+	streamingLimiter     chan struct{}
 	serverMonitor        *event.ServerMonitor
 	registry             *bson.Registry
 	monitoringDisabled   bool
@@ -37,10 +43,17 @@ type serverConfig struct {
 	maxConns             uint64
 	minConns             uint64
 	maxConnecting        uint64
+	waitQueueFailFast    bool
+	maxWaitQueueSize     uint64
+	warmSpares           uint64
+	pinLeakThreshold     time.Duration
+	generationChangedFn  GenerationChangedFunc
+	compressHeartbeats   bool
 	poolMonitor          *event.PoolMonitor
 	logger               *logger.Logger
 	poolMaxIdleTime      time.Duration
 	poolMaintainInterval time.Duration
+	singleConnection     bool
 
 	// Fields provided by a library that wraps the Go Driver.
 	outerLibraryName     string
@@ -50,9 +63,11 @@ type serverConfig struct {
 
 func newServerConfig(connectTimeout time.Duration, opts ...ServerOption) *serverConfig {
 	cfg := &serverConfig{
-		heartbeatInterval: 10 * time.Second,
-		connectTimeout:    connectTimeout,
-		registry:          defaultRegistry,
+		heartbeatInterval:    10 * time.Second,
+		minHeartbeatInterval: defaultMinHeartbeatInterval,
+		clockSkewThreshold:   defaultClockSkewWarningThreshold,
+		connectTimeout:       connectTimeout,
+		registry:             defaultRegistry,
 	}
 
 	for _, opt := range opts {
@@ -132,6 +147,63 @@ func WithHeartbeatInterval(fn func(time.Duration) time.Duration) ServerOption {
 	}
 }
 
+// WithMinHeartbeatInterval configures the minimum time between two checks of a server, which
+// limits how often an immediate check requested by an application operation is run. It is
+// intended for testing against local servers. If it is not positive, the default of 500ms is used.
+func WithMinHeartbeatInterval(fn func(time.Duration) time.Duration) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.minHeartbeatInterval = fn(cfg.minHeartbeatInterval)
+	}
+}
+
+// WithRTTSmoothingFactor configures the smoothing factor of the exponentially weighted moving average of a server's
+// round-trip time, i.e. the weight given to each new RTT sample. It must be in the range (0, 1]. If it is 0, the default
+// of 0.2 is used.
+func WithRTTSmoothingFactor(fn func(float64) float64) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.rttSmoothingFactor = fn(cfg.rttSmoothingFactor)
+	}
+}
+
+// WithOperationErrorRateWindow configures the period over which the outcomes of
+// the operations run against a server are counted to compute its error rate. If
+// it is 0, the default of 1 minute is used.
+func WithOperationErrorRateWindow(fn func(time.Duration) time.Duration) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.errorRateWindow = fn(cfg.errorRateWindow)
+	}
+}
+
+// WithClockSkewWarningThreshold configures the estimated clock skew between a
+// server and the local clock above which a warning is logged. If it is 0, no
+// warning is logged. The default is 1 minute.
+func WithClockSkewWarningThreshold(fn func(time.Duration) time.Duration) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.clockSkewThreshold = fn(cfg.clockSkewThreshold)
+	}
+}
+
+// WithSingleConnection configures the server to run every operation over a
+// single pinned connection. Operations wait for the connection to be closed by
+// the previous operation, so they are sent to the server one at a time.
+func WithSingleConnection(fn func(bool) bool) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.singleConnection = fn(cfg.singleConnection)
+	}
+}
+
+// WithStreamingLimiter configures a semaphore that bounds the number of servers
+// that use a streaming monitoring connection. A server sends to the channel
+// before it starts streaming and receives from it when it stops, so the
+// channel's capacity is the maximum number of streaming servers. The same
+// channel should be shared by every server of a topology. Servers that cannot
+// send to the channel poll instead. If it is nil, streaming is not limited.
+func WithStreamingLimiter(fn func(chan struct{}) chan struct{}) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.streamingLimiter = fn(cfg.streamingLimiter)
+	}
+}
+
 // WithMaxConnections configures the maximum number of connections to allow for
 // a given server. If max is 0, then maximum connection pool size is not limited.
 func WithMaxConnections(fn func(uint64) uint64) ServerOption {
@@ -158,6 +230,70 @@ func WithMaxConnecting(fn func(uint64) uint64) ServerOption {
 	}
 }
 
+// WithWaitQueueFailFast configures whether checking out a connection from a
+// full connection pool should fail immediately with ErrPoolExhausted instead of
+// waiting for a connection to be checked in.
+func WithWaitQueueFailFast(fn func(bool) bool) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.waitQueueFailFast = fn(cfg.waitQueueFailFast)
+	}
+}
+
+// WithMaxWaitQueueSize configures the maximum number of goroutines that may wait
+// to check out a connection from the connection pool. Further check outs fail
+// immediately with ErrWaitQueueFull. If maxWaitQueueSize is 0, the wait queue is
+// unbounded.
+func WithMaxWaitQueueSize(fn func(uint64) uint64) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.maxWaitQueueSize = fn(cfg.maxWaitQueueSize)
+	}
+}
+
+// WithWarmSpares configures the number of idle, pre-authenticated connections
+// that the connection pool keeps ready for checkout in addition to the
+// connections required by the minimum pool size.
+func WithWarmSpares(fn func(uint64) uint64) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.warmSpares = fn(cfg.warmSpares)
+	}
+}
+
+// WithPinLeakThreshold configures the duration after which a connection that is
+// still pinned to a cursor or transaction is logged as a likely leak, along with
+// the stack trace captured when it was pinned. If pinLeakThreshold is 0, pin leak
+// detection is disabled.
+func WithPinLeakThreshold(fn func(time.Duration) time.Duration) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.pinLeakThreshold = fn(cfg.pinLeakThreshold)
+	}
+}
+
+// GenerationChangedFunc is a callback invoked every time the generation of a
+// server's connection pool is incremented, which marks the connections of the
+// previous generation as stale. The serviceID is nil unless the deployment is
+// behind a load balancer, and err is the error that caused the pool to be
+// cleared, if any.
+type GenerationChangedFunc func(addr address.Address, serviceID *bson.ObjectID, oldGeneration, newGeneration uint64, err error)
+
+// WithGenerationChangedFunc configures a callback that is invoked every time the
+// generation of the server's connection pool is incremented. The callback is
+// invoked synchronously while the pool is being cleared, so it must not block.
+func WithGenerationChangedFunc(fn func(GenerationChangedFunc) GenerationChangedFunc) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.generationChangedFn = fn(cfg.generationChangedFn)
+	}
+}
+
+// WithCompressHeartbeats configures whether awaitable hello commands sent by the
+// streaming server monitor are compressed using the compressor negotiated on the
+// monitoring connection, which causes the server to compress the streamed
+// responses.
+func WithCompressHeartbeats(fn func(bool) bool) ServerOption {
+	return func(cfg *serverConfig) {
+		cfg.compressHeartbeats = fn(cfg.compressHeartbeats)
+	}
+}
+
 // WithConnectionPoolMaxIdleTime configures the maximum time that a connection can remain idle in the connection pool
 // before being removed. If connectionPoolMaxIdleTime is 0, then no idle time is set and connections will not be removed
 // because of their age
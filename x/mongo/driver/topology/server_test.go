@@ -28,8 +28,10 @@ import (
 	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/eventtest"
+	"go.mongodb.org/mongo-driver/v2/internal/logger"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
@@ -771,6 +773,7 @@ func TestServer(t *testing.T) {
 			started, ok := publishedEvents[0].(event.ServerHeartbeatStartedEvent)
 			assert.True(t, ok, "expected type %T, got %T", event.ServerHeartbeatStartedEvent{}, publishedEvents[0])
 			assert.Equal(t, started.ConnectionID, s.conn.ID(), "expected connectionID to match")
+			assert.Equal(t, s.address, started.Address, "expected address to match")
 			assert.False(t, started.Awaited, "expected awaited to be false")
 
 			succeeded, ok := publishedEvents[1].(event.ServerHeartbeatSucceededEvent)
@@ -793,11 +796,13 @@ func TestServer(t *testing.T) {
 			started, ok := publishedEvents[0].(event.ServerHeartbeatStartedEvent)
 			assert.True(t, ok, "expected type %T, got %T", event.ServerHeartbeatStartedEvent{}, publishedEvents[0])
 			assert.Equal(t, started.ConnectionID, s.conn.ID(), "expected connectionID to match")
+			assert.Equal(t, s.address, started.Address, "expected address to match")
 			assert.False(t, started.Awaited, "expected awaited to be false")
 
 			failed, ok := publishedEvents[1].(event.ServerHeartbeatFailedEvent)
 			assert.True(t, ok, "expected type %T, got %T", event.ServerHeartbeatFailedEvent{}, publishedEvents[1])
 			assert.Equal(t, failed.ConnectionID, s.conn.ID(), "expected connectionID to match")
+			assert.Equal(t, s.address, failed.Address, "expected address to match")
 			assert.False(t, failed.Awaited, "expected awaited to be false")
 			assert.True(t, errors.Is(failed.Failure, readErr), "expected Failure to be %v, got: %v", readErr, failed.Failure)
 		})
@@ -1153,7 +1158,21 @@ func TestServer_ProcessError(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			server := NewServer(address.Address(""), bson.NewObjectID(), defaultConnectionTimeout)
+			type generationChange struct {
+				oldGeneration, newGeneration uint64
+				err                          error
+			}
+			var changes []generationChange
+			server := NewServer(
+				address.Address(""),
+				bson.NewObjectID(),
+				defaultConnectionTimeout,
+				WithGenerationChangedFunc(func(GenerationChangedFunc) GenerationChangedFunc {
+					return func(_ address.Address, _ *bson.ObjectID, oldGeneration, newGeneration uint64, err error) {
+						changes = append(changes, generationChange{oldGeneration, newGeneration, err})
+					}
+				}),
+			)
 			server.state = serverConnected
 			err := server.pool.ready()
 			require.Nil(t, err, "pool.ready() error: %v", err)
@@ -1163,6 +1182,15 @@ func TestServer_ProcessError(t *testing.T) {
 			got := server.ProcessError(tc.inputErr, tc.inputConn)
 			assert.Equal(t, tc.want, got, "expected and actual ProcessError result are different")
 
+			if tc.wantGeneration > 0 {
+				require.Len(t, changes, 1, "expected the generation changed callback to be called once")
+				assert.Equal(t, tc.wantGeneration-1, changes[0].oldGeneration, "unexpected old generation")
+				assert.Equal(t, tc.wantGeneration, changes[0].newGeneration, "unexpected new generation")
+				assert.NotNil(t, changes[0].err, "expected the pool clear error to be passed")
+			} else {
+				assert.Len(t, changes, 0, "expected the generation changed callback not to be called")
+			}
+
 			desc := server.Description()
 			assert.Equal(t,
 				tc.wantDescription,
@@ -1387,3 +1415,246 @@ func TestCheckServerWithSignal(t *testing.T) {
 		assert.ErrorIs(t, err, context.Canceled)
 	})
 }
+
+func TestServerCompressors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the compressors advertised by the server", func(t *testing.T) {
+		t.Parallel()
+
+		requests := make(chan []byte, 1)
+		closed := make(chan struct{})
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			defer func() { _ = nc.Close() }()
+
+			wm, err := drivertest.ReadWireMessage(nc)
+			if err != nil {
+				return
+			}
+			requests <- wm
+
+			reply := bsoncore.NewDocumentBuilder().
+				AppendInt32("ok", 1).
+				AppendBoolean("isWritablePrimary", true).
+				AppendInt32("maxWireVersion", 21).
+				AppendArray("compression", bsoncore.NewArrayBuilder().AppendString("zstd").AppendString("snappy").Build()).
+				Build()
+			if _, err := nc.Write(drivertest.MakeReply(reply)); err != nil {
+				return
+			}
+
+			// Block until the client closes the connection.
+			_, _ = nc.Read(make([]byte, 1))
+			close(closed)
+		})
+
+		cfg, err := NewConfig(options.Client(), nil)
+		require.NoError(t, err, "NewConfig error")
+
+		got, err := ServerCompressors(context.Background(), address.Address(addr.String()), cfg)
+		require.NoError(t, err, "ServerCompressors error")
+		assert.Equal(t, []string{"zstd", "snappy"}, got, "unexpected compressors")
+
+		// Every compressor supported by the driver must be offered to the server.
+		wm := <-requests
+		_, _, _, _, rem, ok := wiremessage.ReadHeader(wm)
+		require.True(t, ok, "expected a valid wire message header")
+		_, rem, _ = wiremessage.ReadQueryFlags(rem)
+		_, rem, _ = wiremessage.ReadQueryFullCollectionName(rem)
+		_, rem, _ = wiremessage.ReadQueryNumberToSkip(rem)
+		_, rem, _ = wiremessage.ReadQueryNumberToReturn(rem)
+		hello, _, ok := wiremessage.ReadQueryQuery(rem)
+		require.True(t, ok, "expected a hello command")
+		offered, err := hello.Lookup("compression").Array().Values()
+		require.NoError(t, err, "Values error")
+		var names []string
+		for _, val := range offered {
+			names = append(names, val.StringValue())
+		}
+		assert.Equal(t, []string{"snappy", "zlib", "zstd"}, names, "unexpected offered compressors")
+
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the connection to be closed")
+		}
+	})
+	t.Run("respects the connect timeout", func(t *testing.T) {
+		t.Parallel()
+
+		addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+			defer func() { _ = nc.Close() }()
+
+			// Read the hello but never reply, so the connect timeout expires.
+			_, _ = drivertest.ReadWireMessage(nc)
+			_, _ = nc.Read(make([]byte, 1))
+		})
+
+		cfg, err := NewConfig(options.Client().SetConnectTimeout(100*time.Millisecond), nil)
+		require.NoError(t, err, "NewConfig error")
+
+		start := time.Now()
+		_, err = ServerCompressors(context.Background(), address.Address(addr.String()), cfg)
+		assert.Error(t, err, "expected an error when the server does not reply")
+		assert.Less(t, time.Since(start), 5*time.Second, "expected the connect timeout to be respected")
+	})
+}
+
+func TestServer_singleConnection(t *testing.T) {
+	t.Parallel()
+
+	cleanup := make(chan struct{})
+	defer close(cleanup)
+	addr := bootstrapConnections(t, 2, func(nc net.Conn) {
+		<-cleanup
+		_ = nc.Close()
+	})
+
+	s := NewServer(address.Address(addr.String()),
+		bson.NewObjectID(),
+		defaultConnectionTimeout,
+		WithSingleConnection(func(bool) bool { return true }))
+	s.state = serverConnected
+	err := s.pool.ready()
+	require.NoError(t, err)
+	defer s.pool.close(context.Background())
+
+	conn, err := s.Connection(context.Background())
+	require.NoError(t, err)
+	id := conn.ID()
+
+	// The next operation must wait until the connection is closed by the
+	// previous one.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = s.Connection(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "expected Connection to wait for the pinned connection")
+
+	done := make(chan string)
+	go func() {
+		defer close(done)
+
+		c, err := s.Connection(context.Background())
+		if !assert.NoError(t, err) {
+			return
+		}
+		done <- c.ID()
+		_ = c.Close()
+	}()
+
+	// Closing the connection more than once must release it only once.
+	require.NoError(t, conn.Close())
+	require.NoError(t, conn.Close())
+	assert.Equal(t, id, <-done, "expected operations to share one connection")
+	<-done
+
+	for i := 0; i < 3; i++ {
+		c, err := s.Connection(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, id, c.ID(), "expected operations to share one connection")
+		require.NoError(t, c.Close())
+	}
+	assert.Equal(t, 1, s.pool.totalConnectionCount(), "expected only one connection to be checked out")
+
+	// Expiring the connection makes the next operation pin a new one.
+	c, err := s.Connection(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, c.ReadWriteCloser.(driver.Expirable).Expire())
+
+	c, err = s.Connection(context.Background())
+	require.NoError(t, err)
+	assert.NotEqual(t, id, c.ID(), "expected a new connection after the pinned connection expired")
+	require.NoError(t, c.Close())
+}
+
+func TestServer_clockSkew(t *testing.T) {
+	t.Parallel()
+
+	makeSkewedHelloReply := func(skew time.Duration) []byte {
+		doc := bsoncore.NewDocumentBuilder().
+			AppendInt32("ok", 1).
+			AppendBoolean("isWritablePrimary", true).
+			AppendInt32("maxWireVersion", 21).
+			AppendDateTime("localTime", time.Now().Add(skew).UnixMilli()).
+			Build()
+		return drivertest.MakeReply(doc)
+	}
+
+	cnc := &drivertest.ChannelNetConn{
+		Written:  make(chan []byte, 1),
+		ReadResp: make(chan []byte, 2),
+		ReadErr:  make(chan error, 1),
+	}
+	require.NoError(t, cnc.AddResponse(makeSkewedHelloReply(time.Hour)), "error adding response")
+
+	sink := &mockLogSink{}
+	lgr, err := logger.New(sink, 0, map[logger.Component]logger.Level{
+		logger.ComponentTopology: logger.LevelInfo,
+	})
+	require.NoError(t, err, "logger.New error")
+
+	s := NewServer(address.Address("localhost:27017"), bson.NewObjectID(), defaultConnectionTimeout,
+		WithConnectionOptions(func(connOpts ...ConnectionOption) []ConnectionOption {
+			return append(connOpts, WithDialer(func(Dialer) Dialer {
+				return DialerFunc(func(context.Context, string, string) (net.Conn, error) { return cnc, nil })
+			}))
+		}),
+		WithClockSkewWarningThreshold(func(time.Duration) time.Duration { return time.Minute }),
+		withMonitoringDisabled(func(bool) bool { return true }),
+		withLogger(func() *logger.Logger { return lgr }),
+	)
+
+	// check runs a heartbeat against a server whose clock is ahead by skew and
+	// returns the resulting description.
+	check := func(t *testing.T, skew *time.Duration) description.Server {
+		t.Helper()
+
+		if skew != nil {
+			require.NoError(t, cnc.AddResponse(makeSkewedHelloReply(*skew)), "error adding response")
+		}
+		desc, err := s.check(context.Background())
+		require.NoError(t, err, "check error")
+		_ = cnc.GetWrittenMessage()
+		return desc
+	}
+	skewedWarnings := func() int {
+		var n int
+		for _, msg := range sink.msgs {
+			if msg == logger.TopologyServerClockSkewed {
+				n++
+			}
+		}
+		return n
+	}
+
+	// The first check uses the reply to the connection handshake.
+	desc := check(t, nil)
+	assert.True(t, desc.ClockSkewSet, "expected the clock skew to be set")
+	assert.InDelta(t, time.Hour, desc.ClockSkew, float64(5*time.Second), "unexpected clock skew estimate")
+	assert.Equal(t, 1, skewedWarnings(), "expected a clock skew warning")
+
+	// The warning is not repeated while the skew stays above the threshold.
+	behind := -2 * time.Hour
+	desc = check(t, &behind)
+	assert.InDelta(t, behind, desc.ClockSkew, float64(5*time.Second), "unexpected clock skew estimate")
+	assert.Equal(t, 1, skewedWarnings(), "expected no new clock skew warning")
+
+	// Once the skew is back within the threshold, a new warning is logged the
+	// next time it is exceeded.
+	var none time.Duration
+	desc = check(t, &none)
+	assert.InDelta(t, 0, desc.ClockSkew, float64(5*time.Second), "unexpected clock skew estimate")
+	assert.Equal(t, 1, skewedWarnings(), "expected no new clock skew warning")
+
+	ahead := 10 * time.Minute
+	desc = check(t, &ahead)
+	assert.InDelta(t, ahead, desc.ClockSkew, float64(5*time.Second), "unexpected clock skew estimate")
+	assert.Equal(t, 2, skewedWarnings(), "expected a new clock skew warning")
+
+	// No skew is estimated when the server does not report its local time.
+	require.NoError(t, cnc.AddResponse(makeHelloReply()), "error adding response")
+	desc, err = s.check(context.Background())
+	require.NoError(t, err, "check error")
+	_ = cnc.GetWrittenMessage()
+	assert.False(t, desc.ClockSkewSet, "expected the clock skew not to be set")
+}
@@ -35,6 +35,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/internal/driverutil"
 	"go.mongodb.org/mongo-driver/v2/internal/logger"
 	"go.mongodb.org/mongo-driver/v2/internal/randutil"
+	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
@@ -59,6 +60,15 @@ var ErrSubscribeAfterClosed = errors.New("cannot subscribe after closeConnection
 // closed Topology.
 var ErrTopologyClosed = errors.New("topology is closed")
 
+// ErrMaxConcurrentOperations is returned when an operation cannot start because
+// the maximum number of concurrent operations are executing and the Topology
+// is configured to fail fast instead of waiting.
+var ErrMaxConcurrentOperations = errors.New("maximum number of concurrent operations reached")
+
+// ErrTopologyUnknown is returned by server selection when the Topology is
+// configured to require a known topology and no server has been discovered yet.
+var ErrTopologyUnknown = errors.New("topology is unknown because no server has been discovered")
+
 // ErrTopologyConnected is returned whena  user attempts to Connect to an
 // already connected Topology.
 var ErrTopologyConnected = errors.New("topology is connected or connecting")
@@ -79,6 +89,10 @@ const (
 type Topology struct {
 	state int64
 
+	// lastRescan is the time of the last Rescan in nanoseconds since the Unix
+	// epoch. It must be accessed using the atomic package.
+	lastRescan int64
+
 	cfg *Config
 
 	desc atomic.Value // holds a description.Topology
@@ -92,6 +106,7 @@ type Topology struct {
 	pollingwg         sync.WaitGroup
 	rescanSRVInterval time.Duration
 	pollHeartbeatTime atomic.Value // holds a bool
+	srvPollingErr     atomic.Value // holds a *SRVPollingError
 
 	hosts []string
 
@@ -114,12 +129,17 @@ type Topology struct {
 	serversClosed bool
 	servers       map[address.Address]*Server
 
+	// operations holds a token for each executing operation when the number
+	// of concurrent operations is limited. It is nil otherwise.
+	operations chan struct{}
+
 	id bson.ObjectID
 }
 
 var (
-	_ driver.Deployment = &Topology{}
-	_ driver.Subscriber = &Topology{}
+	_ driver.Deployment       = &Topology{}
+	_ driver.Subscriber       = &Topology{}
+	_ driver.OperationLimiter = &Topology{}
 )
 
 // New creates a new topology. A "nil" config is interpreted as the default configuration.
@@ -144,6 +164,16 @@ func New(cfg *Config) (*Topology, error) {
 		id:                bson.NewObjectID(),
 	}
 	t.desc.Store(description.Topology{})
+
+	// Monitoring the same server more than once wastes resources, so remove
+	// duplicate entries from the seed list.
+	seedList, duplicateHosts := connstring.DeduplicateHosts(cfg.SeedList)
+	cfg.SeedList = seedList
+	logDuplicateHostsRemoved(t, duplicateHosts)
+
+	if cfg.MaxConcurrentOperations > 0 {
+		t.operations = make(chan struct{}, cfg.MaxConcurrentOperations)
+	}
 	t.updateCallback = func(desc description.Server) description.Server {
 		return t.apply(context.Background(), desc)
 	}
@@ -155,6 +185,7 @@ func New(cfg *Config) (*Topology, error) {
 		}
 		t.pollingRequired = (connStr.Scheme == connstring.SchemeMongoDBSRV) && !t.cfg.LoadBalanced
 		t.hosts = connStr.RawHosts
+		logSRVRecordsTruncated(t, connStr.SRVRecordsDiscarded)
 	}
 
 	t.publishTopologyOpeningEvent()
@@ -207,6 +238,27 @@ func logTopologyThirdPartyUsage(topo *Topology, parsedHosts []string) {
 	}
 }
 
+// logSRVRecordsTruncated logs that an SRV lookup returned more than
+// dns.MaxSRVRecords records and that discarded of them were not processed.
+func logSRVRecordsTruncated(topo *Topology, discarded int) {
+	if discarded == 0 || !mustLogTopologyMessage(topo, logger.LevelInfo) {
+		return
+	}
+	logTopologyMessage(topo, logger.LevelInfo, logger.TopologySRVRecordsTruncated,
+		logger.KeySRVRecordsProcessed, dns.MaxSRVRecords,
+		logger.KeySRVRecordsDiscarded, discarded)
+}
+
+// logDuplicateHostsRemoved logs the hosts that were removed from the seed list
+// because they duplicate another entry.
+func logDuplicateHostsRemoved(topo *Topology, duplicates []string) {
+	if len(duplicates) == 0 || !mustLogTopologyMessage(topo, logger.LevelInfo) {
+		return
+	}
+	logTopologyMessage(topo, logger.LevelInfo, logger.TopologyDuplicateHostsRemoved,
+		logger.KeyDuplicateHosts, strings.Join(duplicates, ","))
+}
+
 func mustLogServerSelection(topo *Topology, level logger.Level) bool {
 	return topo.cfg.logger != nil && topo.cfg.logger.LevelComponentEnabled(
 		level, logger.ComponentServerSelection)
@@ -424,6 +476,128 @@ func (t *Topology) Description() description.Topology {
 	return td
 }
 
+// PoolStats returns a snapshot of the connection pool counters of every server
+// in the topology, keyed by server address.
+func (t *Topology) PoolStats() map[address.Address]PoolStats {
+	t.serversLock.Lock()
+	defer t.serversLock.Unlock()
+
+	stats := make(map[address.Address]PoolStats, len(t.servers))
+	for addr, server := range t.servers {
+		stats[addr] = server.PoolStats()
+	}
+	return stats
+}
+
+// PrimeConnections establishes connections in the connection pools of the
+// given hosts until each pool holds at least n connections, limited by the
+// maximum pool size. The hosts are primed concurrently. Each host is selected
+// using server selection, so PrimeConnections waits for hosts that have not
+// been discovered yet until ctx is done or the server selection timeout
+// expires. It returns the errors that occurred keyed by host, or nil if every
+// host was primed successfully.
+func (t *Topology) PrimeConnections(ctx context.Context, hosts []string, n int) map[string]error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs map[string]error
+	)
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+
+			if err := t.primeConnections(ctx, host, n); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if errs == nil {
+					errs = make(map[string]error)
+				}
+				errs[host] = err
+			}
+		}(host)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func (t *Topology) primeConnections(ctx context.Context, host string, n int) error {
+	addr := address.Address(host).Canonicalize()
+	selector := serverselector.Func(func(_ description.Topology, candidates []description.Server) ([]description.Server, error) {
+		for _, candidate := range candidates {
+			if candidate.Addr == addr {
+				return []description.Server{candidate}, nil
+			}
+		}
+		return nil, nil
+	})
+
+	srv, err := t.SelectServer(ctx, selector)
+	if err != nil {
+		return err
+	}
+	selected, ok := srv.(*SelectedServer)
+	if !ok {
+		return fmt.Errorf("unexpected server type %T", srv)
+	}
+	// Load balanced topologies select the load balancer regardless of the selector.
+	if selected.address != addr {
+		return fmt.Errorf("server %q is not part of the topology", host)
+	}
+
+	return selected.PrimeConnections(ctx, n)
+}
+
+// PauseCheckOuts pauses connection check outs from the connection pool of the
+// server with the given host. Operations sent to the server fail with
+// ErrPoolCheckOutsPaused until ResumeCheckOuts is called for the host.
+func (t *Topology) PauseCheckOuts(host string) error {
+	s, err := t.serverForHost(host)
+	if err != nil {
+		return err
+	}
+	s.PauseCheckOuts()
+	return nil
+}
+
+// ResumeCheckOuts resumes connection check outs from the connection pool of
+// the server with the given host.
+func (t *Topology) ResumeCheckOuts(host string) error {
+	s, err := t.serverForHost(host)
+	if err != nil {
+		return err
+	}
+	s.ResumeCheckOuts()
+	return nil
+}
+
+func (t *Topology) serverForHost(host string) (*Server, error) {
+	t.serversLock.Lock()
+	defer t.serversLock.Unlock()
+
+	s, ok := t.servers[address.Address(host).Canonicalize()]
+	if !ok {
+		return nil, fmt.Errorf("server %q is not part of the topology", host)
+	}
+	return s, nil
+}
+
+// OperationStats returns a snapshot of the operation outcomes of every server
+// in the topology over the configured error rate window, keyed by server
+// address.
+func (t *Topology) OperationStats() map[address.Address]OperationStats {
+	t.serversLock.Lock()
+	defer t.serversLock.Unlock()
+
+	stats := make(map[address.Address]OperationStats, len(t.servers))
+	for addr, server := range t.servers {
+		stats[addr] = server.OperationStats()
+	}
+	return stats
+}
+
 // Kind returns the topology kind of this Topology.
 func (t *Topology) Kind() description.TopologyKind { return t.Description().Kind }
 
@@ -490,6 +664,34 @@ func (t *Topology) RequestImmediateCheck() {
 	t.serversLock.Unlock()
 }
 
+// minRescanInterval is the minimum time between two Rescan calls that request
+// checks of the servers.
+const minRescanInterval = time.Second
+
+// Rescan requests an immediate check of every server in the topology, like
+// RequestImmediateCheck, and returns without waiting for the checks to
+// complete. To avoid flooding the servers with hello commands, checks are
+// requested at most once per second; calls made more frequently have no
+// effect. Each server also never checks more often than its minimum heartbeat
+// interval. Rescan reports whether the checks were requested.
+func (t *Topology) Rescan() bool {
+	if atomic.LoadInt64(&t.state) != topologyConnected {
+		return false
+	}
+
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&t.lastRescan)
+	if last != 0 && now-last < int64(minRescanInterval) {
+		return false
+	}
+	if !atomic.CompareAndSwapInt64(&t.lastRescan, last, now) {
+		return false
+	}
+
+	t.RequestImmediateCheck()
+	return true
+}
+
 // SelectServer selects a server with given a selector, returning the remaining
 // computedServerSelectionTimeout.
 func (t *Topology) SelectServer(ctx context.Context, ss description.ServerSelector) (driver.Server, error) {
@@ -501,6 +703,17 @@ func (t *Topology) SelectServer(ctx context.Context, ss description.ServerSelect
 		return nil, ErrTopologyClosed
 	}
 
+	if t.cfg.RequireKnownTopology {
+		if desc := t.Description(); !topologyKnown(desc) {
+			err := ServerSelectionError{Desc: desc, Wrapped: ErrTopologyUnknown}
+			if mustLogServerSelection(t, logger.LevelDebug) {
+				logServerSelectionFailed(ctx, t, ss, err)
+			}
+
+			return nil, err
+		}
+	}
+
 	var doneOnce bool
 	var sub *driver.Subscription
 
@@ -659,6 +872,20 @@ func pick2(ds []description.Server) (description.Server, description.Server) {
 	return s1, ds[random.Intn(len(ds))]
 }
 
+// topologyKnown returns whether the topology type has been determined and at
+// least one server has been discovered by a successful hello.
+func topologyKnown(desc description.Topology) bool {
+	if desc.Kind == description.Unknown {
+		return false
+	}
+	for _, srv := range desc.Servers {
+		if srv.Kind != description.Unknown {
+			return true
+		}
+	}
+	return false
+}
+
 // FindServer will attempt to find a server that fits the given server description.
 // This method will return nil, nil if a matching server could not be found.
 func (t *Topology) FindServer(selected description.Server) (*SelectedServer, error) {
@@ -692,7 +919,12 @@ func (t *Topology) selectServerFromSubscription(
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, ServerSelectionError{Wrapped: ctx.Err(), Desc: current}
+			return nil, ServerSelectionError{
+				Wrapped:       ctx.Err(),
+				Desc:          current,
+				SRVPollingErr: t.SRVPollingError(),
+				Exclusions:    serverExclusions(current, srvSelector),
+			}
 		case current = <-subscriptionCh:
 		default:
 		}
@@ -743,11 +975,36 @@ func (t *Topology) selectServerFromDescription(
 
 	suitable, err := srvSelector.SelectServer(desc, allowed)
 	if err != nil {
-		return nil, ServerSelectionError{Wrapped: err, Desc: desc}
+		return nil, ServerSelectionError{Wrapped: err, Desc: desc, SRVPollingErr: t.SRVPollingError()}
 	}
 	return suitable, nil
 }
 
+// serverExclusions reports why each server in desc is not selected by srvSelector.
+func serverExclusions(desc description.Topology, srvSelector description.ServerSelector) []ServerExclusion {
+	if desc.CompatibilityErr != nil || desc.Kind == description.TopologyKindLoadBalanced {
+		return nil
+	}
+
+	var exclusions []ServerExclusion
+	for _, ex := range serverselector.Explain(srvSelector, desc) {
+		exclusions = append(exclusions, ServerExclusion{Addr: ex.Addr, Reason: ex.Reason})
+	}
+	return exclusions
+}
+
+// SRVPollingError returns the error of the last SRV poll if polling the SRV
+// records has failed at least SRVMaxPollingFailures consecutive times, or nil
+// otherwise.
+func (t *Topology) SRVPollingError() *SRVPollingError {
+	err, _ := t.srvPollingErr.Load().(*SRVPollingError)
+	return err
+}
+
+// defaultSRVPollingRetryBackoff is the wait before the first retry of a
+// transient SRV lookup failure if SRVPollingRetryBackoff is not set.
+const defaultSRVPollingRetryBackoff = 100 * time.Millisecond
+
 func (t *Topology) pollSRVRecords(hosts string) {
 	defer t.pollingwg.Done()
 
@@ -758,6 +1015,7 @@ func (t *Topology) pollSRVRecords(hosts string) {
 	defer pollTicker.Stop()
 	t.pollHeartbeatTime.Store(false)
 	var doneOnce bool
+	var failures int
 	defer func() {
 		//  ¯\_(ツ)_/¯
 		if r := recover(); r != nil && !doneOnce {
@@ -777,9 +1035,18 @@ func (t *Topology) pollSRVRecords(hosts string) {
 			break
 		}
 
-		parsedHosts, err := t.dnsResolver.ParseHosts(hosts, t.cfg.SRVServiceName, false)
+		parsedHosts, discarded, stopped, err := t.lookupSRVHosts(hosts)
+		if stopped {
+			doneOnce = true
+			return
+		}
+		logSRVRecordsTruncated(t, discarded)
 		// DNS problem or no verified hosts returned
 		if err != nil || len(parsedHosts) == 0 {
+			failures++
+			if limit := t.cfg.SRVMaxPollingFailures; limit > 0 && failures >= limit {
+				t.srvPollingErr.Store(&SRVPollingError{Failures: failures, Wrapped: err})
+			}
 			if !t.pollHeartbeatTime.Load().(bool) {
 				pollTicker.Stop()
 				pollTicker = time.NewTicker(heartbeatInterval)
@@ -792,6 +1059,8 @@ func (t *Topology) pollSRVRecords(hosts string) {
 			pollTicker = time.NewTicker(t.rescanSRVInterval)
 			t.pollHeartbeatTime.Store(false)
 		}
+		failures = 0
+		t.srvPollingErr.Store((*SRVPollingError)(nil))
 
 		cont := t.processSRVResults(parsedHosts)
 		if !cont {
@@ -802,6 +1071,41 @@ func (t *Topology) pollSRVRecords(hosts string) {
 	doneOnce = true
 }
 
+// lookupSRVHosts looks up the hosts in the SRV records of hosts. Transient DNS
+// errors are retried up to SRVPollingRetries times, waiting
+// SRVPollingRetryBackoff before the first retry and doubling the wait after
+// each retry. If polling is stopped while waiting to retry, stopped is true.
+func (t *Topology) lookupSRVHosts(hosts string) (parsedHosts []string, discarded int, stopped bool, err error) {
+	backoff := t.cfg.SRVPollingRetryBackoff
+	if backoff <= 0 {
+		backoff = defaultSRVPollingRetryBackoff
+	}
+
+	for retry := 0; ; retry++ {
+		parsedHosts, discarded, err = t.dnsResolver.LookupHosts(hosts, t.cfg.SRVServiceName, false)
+		if retry >= t.cfg.SRVPollingRetries || !isTransientDNSError(err) {
+			return parsedHosts, discarded, false, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-t.pollingDone:
+			timer.Stop()
+			return nil, 0, true, err
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientDNSError reports whether err is a DNS error that may not occur
+// again if the lookup is retried, such as a timeout or a temporary failure of
+// the DNS server.
+func isTransientDNSError(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && (dnsErr.IsTimeout || dnsErr.IsTemporary)
+}
+
 // equalTopologies compares two topology descriptions and returns true if they
 // are equal.
 func equalTopologies(topo1, topo2 description.Topology) bool {
@@ -1086,6 +1390,36 @@ func (t *Topology) publishTopologyClosedEvent() {
 	}
 }
 
+// ReadTimeout returns the default timeout for read operations defined on the
+// client options.
+func (t *Topology) ReadTimeout() *time.Duration {
+	if t.cfg == nil {
+		return nil
+	}
+
+	return t.cfg.ReadTimeout
+}
+
+// WriteTimeout returns the default timeout for write operations defined on the
+// client options.
+func (t *Topology) WriteTimeout() *time.Duration {
+	if t.cfg == nil {
+		return nil
+	}
+
+	return t.cfg.WriteTimeout
+}
+
+// HedgeEnabled returns the default for hedged reads defined on the client
+// options.
+func (t *Topology) HedgeEnabled() *bool {
+	if t.cfg == nil {
+		return nil
+	}
+
+	return t.cfg.HedgeEnabled
+}
+
 // GetServerSelectionTimeout returns the server selection timeout defined on
 // the client options.
 func (t *Topology) GetServerSelectionTimeout() time.Duration {
@@ -1096,6 +1430,35 @@ func (t *Topology) GetServerSelectionTimeout() time.Duration {
 	return t.cfg.ServerSelectionTimeout
 }
 
+// AcquireOperation implements the driver.OperationLimiter interface. If the
+// number of concurrent operations is limited and the limit has been reached, it
+// waits until another operation completes or ctx is done, in which case it
+// returns a MaxConcurrentOperationsError. If the Topology is configured to fail
+// fast, it returns ErrMaxConcurrentOperations instead of waiting.
+func (t *Topology) AcquireOperation(ctx context.Context) (func(), error) {
+	if t.operations == nil {
+		return func() {}, nil
+	}
+
+	release := func() { <-t.operations }
+	select {
+	case t.operations <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if t.cfg.MaxConcurrentOperationsFailFast {
+		return nil, ErrMaxConcurrentOperations
+	}
+
+	select {
+	case t.operations <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, MaxConcurrentOperationsError{Wrapped: ctx.Err()}
+	}
+}
+
 func newEventServerDescription(srv description.Server) event.ServerDescription {
 	evtSrv := event.ServerDescription{
 		Addr:                  srv.Addr,
@@ -9,14 +9,20 @@ package topology
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"gitee.com/Trisia/gotlcp/tlcp"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/internal/logger"
 	"go.mongodb.org/mongo-driver/v2/internal/optionsutil"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
@@ -38,12 +44,22 @@ type Config struct {
 	URI                    string
 	ConnectTimeout         time.Duration
 	Timeout                *time.Duration
+	ReadTimeout            *time.Duration
+	WriteTimeout           *time.Duration
+	HedgeEnabled           *bool
 	ServerSelectionTimeout time.Duration
 	ServerMonitor          *event.ServerMonitor
 	SRVMaxHosts            int
 	SRVServiceName         string
+	SRVMaxPollingFailures  int
+	SRVPollingRetries      int
+	SRVPollingRetryBackoff time.Duration
 	LoadBalanced           bool
 	logger                 *logger.Logger
+
+	MaxConcurrentOperations         int
+	MaxConcurrentOperationsFailFast bool
+	RequireKnownTopology            bool
 }
 
 // ConvertToDriverAPIOptions converts a given ServerAPIOptions object from the
@@ -158,7 +174,10 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 	var serverOpts []ServerOption
 
 	cfgp := &Config{
-		Timeout: opts.Timeout,
+		Timeout:      opts.Timeout,
+		ReadTimeout:  opts.ReadTimeout,
+		WriteTimeout: opts.WriteTimeout,
+		HedgeEnabled: opts.HedgeEnabled,
 	}
 
 	// Set the default "ServerSelectionTimeout" to 30 seconds.
@@ -191,6 +210,29 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 		cfgp.SRVMaxHosts = *opts.SRVMaxHosts
 	}
 
+	if opts.SRVMaxPollingFailures != nil {
+		cfgp.SRVMaxPollingFailures = *opts.SRVMaxPollingFailures
+	}
+
+	if opts.SRVPollingRetries != nil {
+		cfgp.SRVPollingRetries = *opts.SRVPollingRetries
+	}
+
+	if opts.SRVPollingRetryBackoff != nil {
+		cfgp.SRVPollingRetryBackoff = *opts.SRVPollingRetryBackoff
+	}
+
+	if opts.MaxConcurrentOperations != nil {
+		cfgp.MaxConcurrentOperations = *opts.MaxConcurrentOperations
+	}
+	if opts.MaxConcurrentOperationsFailFast != nil {
+		cfgp.MaxConcurrentOperationsFailFast = *opts.MaxConcurrentOperationsFailFast
+	}
+
+	if opts.RequireKnownTopology != nil {
+		cfgp.RequireKnownTopology = *opts.RequireKnownTopology
+	}
+
 	// AppName
 	var appName string
 	if opts.AppName != nil {
@@ -249,6 +291,27 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 		))
 	}
 
+	// RequireCompression
+	if opts.RequireCompression != nil {
+		connOpts = append(connOpts, WithRequireCompression(
+			func(bool) bool { return *opts.RequireCompression },
+		))
+	}
+
+	// MaxUncompressedMessageSize
+	if opts.MaxUncompressedMessageSize != nil {
+		connOpts = append(connOpts, WithMaxUncompressedSize(
+			func(int32) int32 { return *opts.MaxUncompressedMessageSize },
+		))
+	}
+
+	// MessageChecksum
+	if opts.MessageChecksum != nil {
+		connOpts = append(connOpts, WithMessageChecksum(
+			func(bool) bool { return *opts.MessageChecksum },
+		))
+	}
+
 	var loadBalanced bool
 	if opts.LoadBalanced != nil {
 		loadBalanced = *opts.LoadBalanced
@@ -256,7 +319,7 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 
 	// Handshaker
 	var handshaker func(driver.Handshaker) driver.Handshaker
-	if authenticator != nil {
+	if authenticator != nil || opts.CredentialProvider != nil {
 		handshakeOpts := &auth.HandshakeOptions{
 			AppName:              appName,
 			Authenticator:        authenticator,
@@ -267,12 +330,24 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 			OuterLibraryName:     outerLibraryName,
 			OuterLibraryVersion:  outerLibraryVersion,
 			OuterLibraryPlatform: outerLibraryPlatform,
+			HTTPClient:           opts.HTTPClient,
+
+			SpeculativeAuthentication: opts.SpeculativeAuth,
 		}
 
-		if opts.Auth.AuthMechanism == "" {
+		if opts.Auth != nil && opts.Auth.AuthMechanism == "" {
 			// Required for SASL mechanism negotiation during handshake
 			handshakeOpts.DBUser = opts.Auth.AuthSource + "." + opts.Auth.Username
 		}
+		if provider := opts.CredentialProvider; provider != nil {
+			handshakeOpts.CredentialProvider = func(ctx context.Context) (string, *driver.Cred, error) {
+				cred, err := provider.Credential(ctx)
+				if err != nil || cred == nil {
+					return "", nil, err
+				}
+				return cred.AuthMechanism, ConvertCreds(cred), nil
+			}
+		}
 		if a := optionsutil.Value(opts.Custom, "authenticateToAnything"); a != nil {
 			if v, ok := a.(bool); ok && v {
 				// Authenticate arbiters
@@ -308,17 +383,55 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 			func(Dialer) Dialer { return opts.Dialer },
 		))
 	}
+	// MaxConcurrentDials
+	if opts.MaxConcurrentDials != nil && *opts.MaxConcurrentDials > 0 {
+		limiter := make(chan struct{}, *opts.MaxConcurrentDials)
+		connOpts = append(connOpts, WithDialLimiter(
+			func(chan struct{}) chan struct{} { return limiter },
+		))
+	}
 	// Direct
 	if opts.Direct != nil && *opts.Direct {
 		cfgp.Mode = SingleMode
 	}
 
+	// SingleConnection
+	if opts.SingleConnection != nil && *opts.SingleConnection {
+		serverOpts = append(serverOpts, WithSingleConnection(
+			func(bool) bool { return true },
+		))
+	}
+
 	// HeartbeatInterval
 	if opts.HeartbeatInterval != nil {
 		serverOpts = append(serverOpts, WithHeartbeatInterval(
 			func(time.Duration) time.Duration { return *opts.HeartbeatInterval },
 		))
 	}
+	// MinHeartbeatInterval
+	if opts.MinHeartbeatInterval != nil {
+		serverOpts = append(serverOpts, WithMinHeartbeatInterval(
+			func(time.Duration) time.Duration { return *opts.MinHeartbeatInterval },
+		))
+	}
+	// RTTSmoothingFactor
+	if opts.RTTSmoothingFactor != nil {
+		serverOpts = append(serverOpts, WithRTTSmoothingFactor(
+			func(float64) float64 { return *opts.RTTSmoothingFactor },
+		))
+	}
+	// ClockSkewWarningThreshold
+	if opts.ClockSkewWarningThreshold != nil {
+		serverOpts = append(serverOpts, WithClockSkewWarningThreshold(
+			func(time.Duration) time.Duration { return *opts.ClockSkewWarningThreshold },
+		))
+	}
+	// OperationErrorRateWindow
+	if opts.OperationErrorRateWindow != nil {
+		serverOpts = append(serverOpts, WithOperationErrorRateWindow(
+			func(time.Duration) time.Duration { return *opts.OperationErrorRateWindow },
+		))
+	}
 	// Hosts
 	cfgp.SeedList = []string{"localhost:27017"} // default host
 	if len(opts.Hosts) > 0 {
@@ -352,6 +465,52 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 			WithMaxConnecting(func(uint64) uint64 { return *opts.MaxConnecting }),
 		)
 	}
+	// WaitQueueFailFast
+	if opts.WaitQueueFailFast != nil {
+		serverOpts = append(
+			serverOpts,
+			WithWaitQueueFailFast(func(bool) bool { return *opts.WaitQueueFailFast }),
+		)
+	}
+	// MaxWaitQueueSize
+	if opts.MaxWaitQueueSize != nil {
+		serverOpts = append(
+			serverOpts,
+			WithMaxWaitQueueSize(func(uint64) uint64 { return uint64(*opts.MaxWaitQueueSize) }),
+		)
+	}
+	// WarmSpares
+	if opts.WarmSpares != nil {
+		serverOpts = append(
+			serverOpts,
+			WithWarmSpares(func(uint64) uint64 { return *opts.WarmSpares }),
+		)
+	}
+	// PinLeakThreshold
+	if opts.PinLeakThreshold != nil {
+		serverOpts = append(
+			serverOpts,
+			WithPinLeakThreshold(func(time.Duration) time.Duration { return *opts.PinLeakThreshold }),
+		)
+	}
+	// PoolGenerationCallback
+	if opts.PoolGenerationCallback != nil {
+		serverOpts = append(
+			serverOpts,
+			WithGenerationChangedFunc(func(GenerationChangedFunc) GenerationChangedFunc {
+				return func(addr address.Address, serviceID *bson.ObjectID, oldGeneration, newGeneration uint64, err error) {
+					opts.PoolGenerationCallback(addr.String(), serviceID, oldGeneration, newGeneration, err)
+				}
+			}),
+		)
+	}
+	// CompressHeartbeats
+	if opts.CompressHeartbeats != nil {
+		serverOpts = append(
+			serverOpts,
+			WithCompressHeartbeats(func(bool) bool { return *opts.CompressHeartbeats }),
+		)
+	}
 	// PoolMonitor
 	if opts.PoolMonitor != nil {
 		serverOpts = append(
@@ -387,9 +546,84 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 	}
 	// TLSConfig
 	if opts.TLSConfig != nil {
+		tlsConfig := opts.TLSConfig
+		if opts.TLSMinVersion != nil && tlsConfig.MinVersion != *opts.TLSMinVersion {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.MinVersion = *opts.TLSMinVersion
+		}
+		if opts.TLSRenegotiation != nil && tlsConfig.Renegotiation != *opts.TLSRenegotiation {
+			if tlsConfig == opts.TLSConfig {
+				tlsConfig = tlsConfig.Clone()
+			}
+			tlsConfig.Renegotiation = *opts.TLSRenegotiation
+		}
+
 		connOpts = append(connOpts, WithTLSConfig(
 			func(*tls.Config) *tls.Config {
-				return opts.TLSConfig
+				return tlsConfig
+			},
+		))
+	}
+
+	if opts.TLSKeyLogWriter != nil {
+		connOpts = append(connOpts, WithTLSKeyLogWriter(
+			func(io.Writer) io.Writer {
+				return opts.TLSKeyLogWriter
+			},
+		))
+	}
+
+	// TCP keep-alive parameters
+	if opts.KeepAliveIdle != nil || opts.KeepAliveInterval != nil || opts.KeepAliveCount != nil {
+		var keepAlive KeepAliveConfig
+		if opts.KeepAliveIdle != nil {
+			keepAlive.Idle = *opts.KeepAliveIdle
+		}
+		if opts.KeepAliveInterval != nil {
+			keepAlive.Interval = *opts.KeepAliveInterval
+		}
+		if opts.KeepAliveCount != nil {
+			keepAlive.Count = *opts.KeepAliveCount
+		}
+		connOpts = append(connOpts, WithKeepAlive(
+			func(*KeepAliveConfig) *KeepAliveConfig { return &keepAlive },
+		))
+	}
+
+	// Raw connection access after dialing
+	if opts.DialedConnCallback != nil {
+		connOpts = append(connOpts, WithDialedConnFunc(
+			func(DialedConnFunc) DialedConnFunc {
+				return func(addr address.Address, nc net.Conn) error {
+					return opts.DialedConnCallback(addr.String(), nc)
+				}
+			},
+		))
+	}
+
+	// Custom TLS peer certificate verification
+	if opts.TLSVerifyPeerCertificate != nil {
+		connOpts = append(connOpts, WithTLSVerifyPeerCertificate(
+			func(VerifyPeerCertificateFunc) VerifyPeerCertificateFunc {
+				return opts.TLSVerifyPeerCertificate
+			},
+		))
+	}
+
+	// TLS certificate expiry warnings
+	if opts.TLSCertExpiryCallback != nil {
+		connOpts = append(connOpts, WithCertificateExpiryFunc(
+			func(CertificateExpiryFunc) CertificateExpiryFunc {
+				return func(addr address.Address, cert *x509.Certificate, isClientCert bool) {
+					opts.TLSCertExpiryCallback(addr.String(), cert, isClientCert)
+				}
+			},
+		))
+	}
+	if opts.TLSCertExpiryWarningDays != nil {
+		connOpts = append(connOpts, WithCertificateExpiryWindow(
+			func(time.Duration) time.Duration {
+				return time.Duration(*opts.TLSCertExpiryWarningDays) * 24 * time.Hour
 			},
 		))
 	}
@@ -402,6 +636,34 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 		))
 	}
 
+	// RequestIDGenerator
+	if opts.RequestIDGenerator != nil {
+		connOpts = append(connOpts, WithRequestIDGenerator(
+			func(func() int32) func() int32 { return opts.RequestIDGenerator },
+		))
+	}
+
+	// ScopedConnectionIDs
+	if opts.ScopedConnectionIDs != nil && *opts.ScopedConnectionIDs {
+		var connectionID uint64
+		connOpts = append(connOpts, WithConnectionIDSequence(
+			func(func() uint64) func() uint64 {
+				return func() uint64 { return atomic.AddUint64(&connectionID, 1) }
+			},
+		))
+	}
+
+	// ConnectionIDFormatter
+	if opts.ConnectionIDFormatter != nil {
+		connOpts = append(connOpts, WithConnectionIDFunc(
+			func(ConnectionIDFunc) ConnectionIDFunc {
+				return func(addr address.Address, seq uint64) string {
+					return opts.ConnectionIDFormatter(addr.String(), seq)
+				}
+			},
+		))
+	}
+
 	// HTTP Client
 	if opts.HTTPClient != nil {
 		connOpts = append(connOpts, WithHTTPClient(
@@ -426,6 +688,14 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 		)
 	}
 
+	// Per-host OCSP policies.
+	if len(opts.OCSPHostPolicies) > 0 {
+		connOpts = append(
+			connOpts,
+			WithOCSPHostPolicies(func(map[string]string) map[string]string { return opts.OCSPHostPolicies }),
+		)
+	}
+
 	// LoadBalanced
 	if opts.LoadBalanced != nil {
 		cfgp.LoadBalanced = *opts.LoadBalanced
@@ -451,6 +721,14 @@ func NewConfigFromOptionsWithAuthenticator(opts *options.ClientOptions, clock *s
 		withServerMonitoringMode(opts.ServerMonitoringMode),
 	)
 
+	// MaxStreamingMonitors
+	if opts.MaxStreamingMonitors != nil && *opts.MaxStreamingMonitors > 0 {
+		limiter := make(chan struct{}, *opts.MaxStreamingMonitors)
+		serverOpts = append(serverOpts, WithStreamingLimiter(
+			func(chan struct{}) chan struct{} { return limiter },
+		))
+	}
+
 	cfgp.logger = lgr
 
 	serverOpts = append(
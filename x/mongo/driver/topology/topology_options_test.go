@@ -8,14 +8,21 @@ package topology
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
@@ -181,6 +188,194 @@ func TestTopologyNewConfig(t *testing.T) {
 		assert.Nil(t, err, "error constructing topology config: %v", err)
 		assert.Equal(t, []string{"localhost:27018"}, cfg.SeedList)
 	})
+	t.Run("TLSMinVersion overrides provided TLSConfig", func(t *testing.T) {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS10}
+		opts := options.Client().SetTLSConfig(tlsConfig).SetTLSMinVersion(tls.VersionTLS13)
+		cfg, err := NewConfig(opts, nil)
+		require.NoError(t, err, "error constructing topology config: %v", err)
+
+		srvrCfg := newServerConfig(defaultConnectionTimeout, cfg.ServerOpts...)
+		connCfg := newConnectionConfig(srvrCfg.connectionOpts...)
+		assert.Equal(t, uint16(tls.VersionTLS13), connCfg.tlsConfig.MinVersion,
+			"expected MinVersion %d, got %d", tls.VersionTLS13, connCfg.tlsConfig.MinVersion)
+		assert.Equal(t, uint16(tls.VersionTLS10), tlsConfig.MinVersion, "expected provided TLSConfig to be unmodified")
+	})
+	t.Run("TLSRenegotiation overrides provided TLSConfig", func(t *testing.T) {
+		tlsConfig := &tls.Config{}
+		opts := options.Client().SetTLSConfig(tlsConfig).SetTLSRenegotiation(tls.RenegotiateOnceAsClient)
+		cfg, err := NewConfig(opts, nil)
+		require.NoError(t, err, "error constructing topology config: %v", err)
+
+		srvrCfg := newServerConfig(defaultConnectionTimeout, cfg.ServerOpts...)
+		connCfg := newConnectionConfig(srvrCfg.connectionOpts...)
+		assert.Equal(t, tls.RenegotiateOnceAsClient, connCfg.tlsConfig.Renegotiation,
+			"expected Renegotiation %d, got %d", tls.RenegotiateOnceAsClient, connCfg.tlsConfig.Renegotiation)
+		assert.Equal(t, tls.RenegotiateNever, tlsConfig.Renegotiation, "expected provided TLSConfig to be unmodified")
+	})
+	t.Run("ScopedConnectionIDs", func(t *testing.T) {
+		newConn := func(cfg *Config) *connection {
+			srvrCfg := newServerConfig(defaultConnectionTimeout, cfg.ServerOpts...)
+			return newConnection(address.Address("localhost:27017"), srvrCfg.connectionOpts...)
+		}
+
+		opts := options.Client().SetScopedConnectionIDs(true)
+		cfg1, err := NewConfig(opts, nil)
+		require.NoError(t, err, "error constructing topology config: %v", err)
+		cfg2, err := NewConfig(opts, nil)
+		require.NoError(t, err, "error constructing topology config: %v", err)
+
+		// Each client numbers its connections independently of the other.
+		assert.Equal(t, "localhost:27017[-1]", newConn(cfg1).ID())
+		assert.Equal(t, "localhost:27017[-2]", newConn(cfg1).ID())
+		assert.Equal(t, "localhost:27017[-1]", newConn(cfg2).ID())
+		assert.Equal(t, "localhost:27017[-3]", newConn(cfg1).ID())
+		assert.Equal(t, "localhost:27017[-2]", newConn(cfg2).ID())
+	})
+}
+
+func TestMaxConcurrentDials(t *testing.T) {
+	t.Parallel()
+
+	// newConns returns n connections to different servers created with the
+	// connection options of a client configured with opts.
+	newConns := func(t *testing.T, opts *options.ClientOptions, n int) []*connection {
+		t.Helper()
+
+		cfg, err := NewConfig(opts, nil)
+		require.NoError(t, err, "error constructing topology config: %v", err)
+		srvrCfg := newServerConfig(defaultConnectionTimeout, cfg.ServerOpts...)
+
+		conns := make([]*connection, n)
+		for i := range conns {
+			addr := address.Address(fmt.Sprintf("host%d:27017", i))
+			conns[i] = newConnection(addr, srvrCfg.connectionOpts...)
+		}
+		return conns
+	}
+
+	t.Run("bounds concurrent dials across servers", func(t *testing.T) {
+		t.Parallel()
+
+		const limit = 2
+
+		var active, maxActive, dials int64
+		dialer := DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+			atomic.AddInt64(&dials, 1)
+			n := atomic.AddInt64(&active, 1)
+			defer atomic.AddInt64(&active, -1)
+			for {
+				m := atomic.LoadInt64(&maxActive)
+				if n <= m || atomic.CompareAndSwapInt64(&maxActive, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil, errors.New("dial error")
+		})
+
+		opts := options.Client().SetDialer(dialer).SetMaxConcurrentDials(limit)
+		var wg sync.WaitGroup
+		for _, conn := range newConns(t, opts, 10) {
+			wg.Add(1)
+			go func(conn *connection) {
+				defer wg.Done()
+
+				err := conn.connect(context.Background())
+				assert.Error(t, err, "expected the dial error")
+			}(conn)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(10), atomic.LoadInt64(&dials), "expected every connection to be dialed")
+		assert.LessOrEqual(t, atomic.LoadInt64(&maxActive), int64(limit),
+			"expected at most %d concurrent dials", limit)
+	})
+	t.Run("waiting for a dial respects the context", func(t *testing.T) {
+		t.Parallel()
+
+		dialing := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		dialer := DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+			close(dialing)
+			<-release
+			return nil, errors.New("dial error")
+		})
+
+		opts := options.Client().SetDialer(dialer).SetMaxConcurrentDials(1)
+		conns := newConns(t, opts, 2)
+		go func() { _ = conns[0].connect(context.Background()) }()
+		<-dialing
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := conns[1].connect(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "expected the wait for a dial to time out")
+	})
+}
+
+func TestMaxStreamingMonitors(t *testing.T) {
+	t.Parallel()
+
+	const (
+		numServers = 20
+		limit      = 3
+	)
+
+	opts := options.Client().
+		SetServerMonitoringMode(options.ServerMonitoringModeStream).
+		SetMaxStreamingMonitors(limit)
+	cfg, err := NewConfig(opts, nil)
+	require.NoError(t, err, "error constructing topology config")
+
+	servers := make([]*Server, numServers)
+	for i := range servers {
+		addr := address.Address(fmt.Sprintf("host%d:27017", i))
+		srv := NewServer(addr, bson.NewObjectID(), defaultConnectionTimeout, cfg.ServerOpts...)
+		srv.desc.Store(description.Server{
+			Addr:            addr,
+			Kind:            description.ServerKindRSSecondary,
+			TopologyVersion: &description.TopologyVersion{ProcessID: bson.NewObjectID()},
+		})
+		servers[i] = srv
+	}
+
+	// countStreaming returns the number of servers that are allowed to stream.
+	countStreaming := func() int {
+		var n int
+		for _, srv := range servers {
+			if srv.streamable() {
+				n++
+			}
+		}
+		return n
+	}
+
+	assert.Equal(t, limit, countStreaming(), "expected the number of streaming servers to be capped")
+	for i, srv := range servers {
+		assert.Equal(t, i < limit, srv.hasStreamingSlot, "unexpected streaming slot for server %d", i)
+	}
+
+	// A server that gives up its slot lets the next server stream.
+	servers[0].releaseStreamingSlot()
+	assert.False(t, servers[limit].hasStreamingSlot, "expected server %d to poll", limit)
+	assert.Equal(t, limit, countStreaming(), "expected the number of streaming servers to be capped")
+	assert.False(t, servers[0].hasStreamingSlot, "expected the released slot to be taken by another server")
+	assert.True(t, servers[limit].hasStreamingSlot, "expected server %d to take the released slot", limit)
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := NewConfig(options.Client().SetServerMonitoringMode(options.ServerMonitoringModeStream), nil)
+		require.NoError(t, err, "error constructing topology config")
+
+		srv := NewServer("host:27017", bson.NewObjectID(), defaultConnectionTimeout, cfg.ServerOpts...)
+		srv.desc.Store(description.Server{
+			Kind:            description.ServerKindRSSecondary,
+			TopologyVersion: &description.TopologyVersion{},
+		})
+		assert.True(t, srv.streamable(), "expected streaming not to be limited")
+	})
 }
 
 // Test that convertOIDCArgs exhaustively copies all fields of a driver.OIDCArgs
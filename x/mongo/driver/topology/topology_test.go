@@ -9,22 +9,29 @@ package topology
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"path"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/internal/assert"
+	"go.mongodb.org/mongo-driver/v2/internal/logger"
 	"go.mongodb.org/mongo-driver/v2/internal/require"
 	"go.mongodb.org/mongo-driver/v2/internal/serverselector"
 	"go.mongodb.org/mongo-driver/v2/internal/spectest"
 	"go.mongodb.org/mongo-driver/v2/mongo/address"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/tag"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/description"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
 )
 
 const testTimeout = 2 * time.Second
@@ -198,9 +205,85 @@ func TestServerSelection(t *testing.T) {
 			t.Errorf("Timed out while trying to retrieve selected servers")
 		}
 
-		want := ServerSelectionError{Wrapped: context.Canceled, Desc: desc}
+		want := ServerSelectionError{
+			Wrapped: context.Canceled,
+			Desc:    desc,
+			Exclusions: []ServerExclusion{
+				{Addr: "one", Reason: serverselector.ReasonSelector},
+				{Addr: "two", Reason: serverselector.ReasonSelector},
+				{Addr: "three", Reason: serverselector.ReasonSelector},
+			},
+		}
 		assert.Equal(t, err, want, "Incorrect error received. got %v; want %v", err, want)
 	})
+	t.Run("timeout reports why each server was excluded", func(t *testing.T) {
+		now := time.Now()
+		secondary := func(addr string, rtt time.Duration, lastWrite time.Time, tags ...tag.Tag) description.Server {
+			return description.Server{
+				Addr:              address.Address(addr),
+				Kind:              description.ServerKindRSSecondary,
+				AverageRTT:        rtt,
+				AverageRTTSet:     true,
+				HeartbeatInterval: 10 * time.Second,
+				LastUpdateTime:    now,
+				LastWriteTime:     lastWrite,
+				Tags:              tags,
+			}
+		}
+		east := tag.Tag{Name: "dc", Value: "east"}
+		desc := description.Topology{
+			Kind: description.TopologyKindReplicaSetWithPrimary,
+			Servers: []description.Server{
+				{
+					Addr:              address.Address("primary"),
+					Kind:              description.ServerKindRSPrimary,
+					AverageRTT:        5 * time.Millisecond,
+					AverageRTTSet:     true,
+					HeartbeatInterval: 10 * time.Second,
+					LastUpdateTime:    now,
+					LastWriteTime:     now,
+				},
+				{Addr: address.Address("arbiter"), Kind: description.ServerKindRSArbiter},
+				{Addr: address.Address("unknown"), Kind: description.Unknown},
+				secondary("stale", 5*time.Millisecond, now.Add(-5*time.Minute), east),
+				secondary("untagged", 5*time.Millisecond, now),
+				secondary("far", 100*time.Millisecond, now, east),
+				secondary("near", 5*time.Millisecond, now, east),
+			},
+		}
+		rp := readpref.Secondary(readpref.WithMaxStaleness(2*time.Minute), readpref.WithTags("dc", "east"))
+		selector := &serverselector.Composite{
+			Selectors: []description.ServerSelector{
+				&serverselector.ReadPref{ReadPref: rp},
+				&serverselector.Latency{Latency: 15 * time.Millisecond},
+				selectNone,
+			},
+		}
+
+		topo, err := New(nil)
+		require.NoError(t, err)
+		subCh := make(chan description.Topology, 1)
+		subCh <- desc
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = topo.selectServerFromSubscription(ctx, subCh, selector)
+
+		var selErr ServerSelectionError
+		require.True(t, errors.As(err, &selErr), "expected a ServerSelectionError, got %v", err)
+		want := []ServerExclusion{
+			{Addr: "primary", Reason: serverselector.ReasonServerType},
+			{Addr: "arbiter", Reason: serverselector.ReasonServerType},
+			{Addr: "unknown", Reason: serverselector.ReasonUnknown},
+			{Addr: "stale", Reason: serverselector.ReasonStale},
+			{Addr: "untagged", Reason: serverselector.ReasonTags},
+			{Addr: "far", Reason: serverselector.ReasonLatency},
+			{Addr: "near", Reason: serverselector.ReasonSelector},
+		}
+		assert.Equal(t, want, selErr.Exclusions, "unexpected exclusions")
+		assert.Contains(t, err.Error(), "stale: "+serverselector.ReasonStale,
+			"expected the error message to include the exclusions")
+	})
 	t.Run("findServer returns topology kind", func(t *testing.T) {
 		topo, err := New(nil)
 		require.NoError(t, err)
@@ -480,6 +563,264 @@ func TestMinPoolSize(t *testing.T) {
 	}
 }
 
+func TestTopology_PrimeConnections(t *testing.T) {
+	t.Parallel()
+
+	cleanup := make(chan struct{})
+	defer close(cleanup)
+	addr := bootstrapConnections(t, 3, func(nc net.Conn) {
+		<-cleanup
+		_ = nc.Close()
+	})
+	srvAddr := address.Address(addr.String())
+
+	topo, err := New(nil)
+	require.NoError(t, err)
+	atomic.StoreInt64(&topo.state, topologyConnected)
+	topo.desc.Store(description.Topology{
+		Kind:    description.TopologyKindReplicaSetNoPrimary,
+		Servers: []description.Server{{Addr: srvAddr, Kind: description.ServerKindRSSecondary}},
+	})
+
+	srv := NewServer(srvAddr, topo.id, defaultConnectionTimeout, withMonitoringDisabled(func(bool) bool { return true }))
+	err = srv.Connect(nil)
+	require.NoError(t, err)
+	defer func() { _ = srv.Disconnect(context.Background()) }()
+	topo.servers[srvAddr] = srv
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	errs := topo.PrimeConnections(ctx, []string{addr.String(), "unknown:27017"}, 3)
+	require.Len(t, errs, 1, "expected one host to fail")
+	assert.Error(t, errs["unknown:27017"], "expected an error for a host that is not in the topology")
+
+	stats := topo.PoolStats()[srvAddr]
+	assert.Equal(t, 3, stats.TotalConnections, "expected the pool to reach the requested size")
+	assert.Equal(t, 3, stats.IdleConnections, "expected the primed connections to be idle")
+}
+
+func TestTopology_AcquireOperation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("caps concurrent operations", func(t *testing.T) {
+		t.Parallel()
+
+		const limit = 3
+		topo, err := New(&Config{MaxConcurrentOperations: limit})
+		require.NoError(t, err)
+
+		var active, maxActive int64
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				release, err := topo.AcquireOperation(context.Background())
+				assert.NoError(t, err)
+				defer release()
+
+				n := atomic.AddInt64(&active, 1)
+				for {
+					m := atomic.LoadInt64(&maxActive)
+					if n <= m || atomic.CompareAndSwapInt64(&maxActive, m, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt64(&active, -1)
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, maxActive, int64(limit), "expected at most %d concurrent operations", limit)
+	})
+	t.Run("waits until context is done", func(t *testing.T) {
+		t.Parallel()
+
+		topo, err := New(&Config{MaxConcurrentOperations: 1})
+		require.NoError(t, err)
+
+		release, err := topo.AcquireOperation(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = topo.AcquireOperation(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, ErrMaxConcurrentOperations)
+
+		release()
+		release, err = topo.AcquireOperation(context.Background())
+		require.NoError(t, err, "expected a slot after release")
+		release()
+	})
+	t.Run("fail fast", func(t *testing.T) {
+		t.Parallel()
+
+		topo, err := New(&Config{MaxConcurrentOperations: 1, MaxConcurrentOperationsFailFast: true})
+		require.NoError(t, err)
+
+		release, err := topo.AcquireOperation(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		_, err = topo.AcquireOperation(context.Background())
+		assert.ErrorIs(t, err, ErrMaxConcurrentOperations)
+	})
+	t.Run("unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		topo, err := New(&Config{})
+		require.NoError(t, err)
+
+		for i := 0; i < 100; i++ {
+			_, err := topo.AcquireOperation(context.Background())
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestTopology_Rescan(t *testing.T) {
+	t.Parallel()
+
+	// The server is a standalone on the first check and a mongos on every
+	// later check, so the topology changes once a second check runs.
+	var hellos int64
+	addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+		defer func() { _ = nc.Close() }()
+
+		for {
+			if _, err := drivertest.ReadWireMessage(nc); err != nil {
+				return
+			}
+
+			reply := bsoncore.NewDocumentBuilder().
+				AppendInt32("ok", 1).
+				AppendBoolean("isWritablePrimary", true).
+				AppendInt32("maxWireVersion", 21)
+			if atomic.AddInt64(&hellos, 1) > 1 {
+				reply.AppendString("msg", "isdbgrid")
+			}
+			if _, err := nc.Write(drivertest.MakeReply(reply.Build())); err != nil {
+				return
+			}
+		}
+	})
+
+	opts := options.Client().
+		SetHosts([]string{addr.String()}).
+		SetHeartbeatInterval(time.Hour).
+		SetServerMonitoringMode(options.ServerMonitoringModePoll)
+	cfg, err := NewConfig(opts, nil)
+	require.NoError(t, err, "NewConfig error")
+	topo, err := New(cfg)
+	require.NoError(t, err, "New error")
+
+	assert.False(t, topo.Rescan(), "expected no rescan before the topology is connected")
+
+	require.NoError(t, topo.Connect(), "Connect error")
+	defer func() { _ = topo.Disconnect(context.Background()) }()
+
+	serverKind := func() description.ServerKind {
+		servers := topo.Description().Servers
+		if len(servers) == 0 {
+			return description.Unknown
+		}
+		return servers[0].Kind
+	}
+	assert.Eventually(t, func() bool { return serverKind() == description.ServerKindStandalone },
+		5*time.Second, 10*time.Millisecond, "expected the initial check to complete")
+
+	assert.True(t, topo.Rescan(), "expected the rescan to be requested")
+	assert.False(t, topo.Rescan(), "expected a second rescan to be rate limited")
+
+	// The heartbeat interval is an hour, so the topology is only updated this
+	// soon because of the rescan.
+	assert.Eventually(t, func() bool { return serverKind() == description.ServerKindMongos },
+		5*time.Second, 10*time.Millisecond, "expected the rescan to update the topology")
+}
+
+func TestTopology_RequireKnownTopology(t *testing.T) {
+	t.Parallel()
+
+	const addr = address.Address("localhost:27017")
+
+	testCases := []struct {
+		name        string
+		require     bool
+		desc        description.Topology
+		wantUnknown bool
+	}{
+		{
+			name:    "unknown topology",
+			require: true,
+			desc: description.Topology{
+				Kind:    description.Unknown,
+				Servers: []description.Server{{Addr: addr, Kind: description.Unknown}},
+			},
+			wantUnknown: true,
+		},
+		{
+			name:    "direct connection without a successful hello",
+			require: true,
+			desc: description.Topology{
+				Kind:    description.TopologyKindSingle,
+				Servers: []description.Server{{Addr: addr, Kind: description.Unknown}},
+			},
+			wantUnknown: true,
+		},
+		{
+			name:    "known topology",
+			require: true,
+			desc: description.Topology{
+				Kind:    description.TopologyKindReplicaSetNoPrimary,
+				Servers: []description.Server{{Addr: addr, Kind: description.ServerKindRSSecondary}},
+			},
+		},
+		{
+			name:    "not required",
+			require: false,
+			desc: description.Topology{
+				Kind:    description.Unknown,
+				Servers: []description.Server{{Addr: addr, Kind: description.Unknown}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			topo, err := New(&Config{RequireKnownTopology: tc.require, ServerSelectionTimeout: time.Minute})
+			require.NoError(t, err)
+			atomic.StoreInt64(&topo.state, topologyConnected)
+			topo.desc.Store(tc.desc)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err = topo.SelectServer(ctx, &serverselector.Write{})
+			require.Error(t, err, "expected server selection to fail")
+
+			var sse ServerSelectionError
+			require.True(t, errors.As(err, &sse), "expected a ServerSelectionError, got %v", err)
+			if tc.wantUnknown {
+				assert.ErrorIs(t, err, ErrTopologyUnknown)
+				assert.Less(t, time.Since(start), 50*time.Millisecond, "expected server selection to fail fast")
+			} else {
+				assert.False(t, errors.Is(err, ErrTopologyUnknown), "expected error not to be ErrTopologyUnknown")
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			}
+		})
+	}
+}
+
 func TestTopology_String_Race(_ *testing.T) {
 	ch := make(chan bool)
 	topo := &Topology{
@@ -531,6 +872,25 @@ func TestTopologyConstruction(t *testing.T) {
 			})
 		}
 	})
+	t.Run("duplicate hosts are removed from the seed list", func(t *testing.T) {
+		sink := &mockLogSink{}
+		loggerOpts := options.Logger().
+			SetSink(sink).
+			SetComponentLevel(options.LogComponentTopology, options.LogLevelInfo)
+		opts := options.Client().
+			SetHosts([]string{"localhost:27017", "LOCALHOST", "localhost:27018", "localhost:27018"}).
+			SetLoggerOptions(loggerOpts)
+		cfg, err := NewConfig(opts, nil)
+		require.NoError(t, err, "error constructing topology config")
+
+		topo, err := New(cfg)
+		require.NoError(t, err, "topology.New error")
+
+		assert.Equal(t, []string{"localhost:27017", "localhost:27018"}, topo.cfg.SeedList,
+			"expected duplicate hosts to be removed")
+		assert.Equal(t, []string{logger.TopologyDuplicateHostsRemoved}, sink.msgs,
+			"expected the removed hosts to be logged")
+	})
 }
 
 type mockLogSink struct {
@@ -16,6 +16,9 @@ package wiremessage
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
 	"strings"
 	"sync/atomic"
 
@@ -428,6 +431,74 @@ func ReadMsgChecksum(src []byte) (checksum uint32, rem []byte, ok bool) {
 	return uint32(i32), rem, ok
 }
 
+// ErrMsgChecksumMismatch is returned when the checksum of an OP_MSG does not
+// match its contents.
+var ErrMsgChecksumMismatch = errors.New("OP_MSG checksum mismatch")
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// AppendMsgChecksum appends the OP_MSG wm to dst with the checksumPresent flag
+// set and its CRC-32C checksum appended, updating the message length. If wm is
+// not an OP_MSG or already has a checksum, it is appended unchanged.
+func AppendMsgChecksum(dst, wm []byte) []byte {
+	if len(wm) < 20 || OpCode(readi32unsafe(wm[12:16])) != OpMsg ||
+		MsgFlag(readi32unsafe(wm[16:20]))&ChecksumPresent == ChecksumPresent {
+		return append(dst, wm...)
+	}
+
+	idx := len(dst)
+	dst = append(dst, wm...)
+	msg := dst[idx:]
+	binary.LittleEndian.PutUint32(msg[0:4], uint32(len(msg)+4))
+	binary.LittleEndian.PutUint32(msg[16:20], binary.LittleEndian.Uint32(msg[16:20])|uint32(ChecksumPresent))
+	return binary.LittleEndian.AppendUint32(dst, crc32.Checksum(msg, crc32cTable))
+}
+
+// RemoveMsgChecksum verifies the checksum of the OP_MSG wm if the
+// checksumPresent flag is set and returns wm without the checksum, with the
+// flag cleared and the message length updated. wm is modified in place. If wm
+// is not an OP_MSG or has no checksum, it is returned unchanged. If the
+// checksum does not match, an error wrapping ErrMsgChecksumMismatch is
+// returned.
+func RemoveMsgChecksum(wm []byte) ([]byte, error) {
+	if len(wm) < 20 || OpCode(readi32unsafe(wm[12:16])) != OpMsg {
+		return wm, nil
+	}
+	flags := binary.LittleEndian.Uint32(wm[16:20])
+	if MsgFlag(flags)&ChecksumPresent != ChecksumPresent {
+		return wm, nil
+	}
+	if len(wm) < 24 {
+		return nil, errors.New("malformed OP_MSG: missing checksum")
+	}
+
+	body := wm[:len(wm)-4]
+	want := binary.LittleEndian.Uint32(wm[len(wm)-4:])
+	if got := crc32.Checksum(body, crc32cTable); got != want {
+		return nil, fmt.Errorf("%w: expected %#08x, computed %#08x", ErrMsgChecksumMismatch, want, got)
+	}
+	binary.LittleEndian.PutUint32(body[0:4], uint32(len(body)))
+	binary.LittleEndian.PutUint32(body[16:20], flags&^uint32(ChecksumPresent))
+	return body, nil
+}
+
+// RemoveMsgBodyChecksum is like RemoveMsgChecksum for the body of a message,
+// i.e. without its header, that was decompressed from an OP_COMPRESSED message
+// with the given request ID, response to and original opcode. The checksum
+// covers the header of the original message, which is rebuilt from them.
+func RemoveMsgBodyChecksum(reqid, respto int32, opcode OpCode, body []byte) ([]byte, error) {
+	if opcode != OpMsg || len(body) < 4 || MsgFlag(binary.LittleEndian.Uint32(body))&ChecksumPresent != ChecksumPresent {
+		return body, nil
+	}
+
+	wm := AppendHeader(make([]byte, 0, 16+len(body)), int32(16+len(body)), reqid, respto, opcode)
+	wm, err := RemoveMsgChecksum(append(wm, body...))
+	if err != nil {
+		return nil, err
+	}
+	return wm[16:], nil
+}
+
 // ReadQueryFlags reads OP_QUERY flags from src.
 //
 // Deprecated: Construct wiremessages with OpMsg and use the ReadMsg* functions
@@ -7,6 +7,8 @@
 package wiremessage
 
 import (
+	"encoding/binary"
+	"hash/crc32"
 	"math"
 	"testing"
 
@@ -486,3 +488,82 @@ func TestReadi64(t *testing.T) {
 		})
 	}
 }
+
+func TestMsgChecksum(t *testing.T) {
+	t.Parallel()
+
+	msg := func() []byte {
+		idx, wm := AppendHeaderStart(nil, 7, 3, OpMsg)
+		wm = AppendMsgFlags(wm, MoreToCome)
+		wm = AppendMsgSectionType(wm, SingleDocument)
+		wm = bsoncore.BuildDocumentFromElements(wm, bsoncore.AppendInt32Element(nil, "ping", 1))
+		return bsoncore.UpdateLength(wm, idx, int32(len(wm[idx:])))
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		want := msg()
+		wm := AppendMsgChecksum(nil, want)
+
+		assert.Equal(t, len(want)+4, len(wm), "expected the checksum to be appended")
+		assert.Equal(t, int32(len(wm)), readi32unsafe(wm[0:4]), "expected the length to be updated")
+		flags, _, _ := ReadMsgFlags(wm[16:])
+		assert.Equal(t, MoreToCome|ChecksumPresent, flags, "expected checksumPresent to be set")
+		checksum, _, _ := ReadMsgChecksum(wm[len(wm)-4:])
+		assert.Equal(t, crc32.Checksum(wm[:len(wm)-4], crc32.MakeTable(crc32.Castagnoli)), checksum,
+			"unexpected checksum")
+		assert.Equal(t, msg(), want, "expected the original message to be unchanged")
+
+		got, err := RemoveMsgChecksum(wm)
+		assert.NoError(t, err, "RemoveMsgChecksum error")
+		assert.Equal(t, want, got, "expected the original message")
+
+		// Adding a checksum to a message that already has one is a no-op.
+		wm = AppendMsgChecksum(nil, want)
+		assert.Equal(t, wm, AppendMsgChecksum(nil, wm), "expected a single checksum")
+	})
+	t.Run("decompressed body", func(t *testing.T) {
+		t.Parallel()
+
+		want := msg()
+		wm := AppendMsgChecksum(nil, want)
+
+		got, err := RemoveMsgBodyChecksum(7, 3, OpMsg, wm[16:])
+		assert.NoError(t, err, "RemoveMsgBodyChecksum error")
+		assert.Equal(t, want[16:], got, "expected the original body")
+
+		wm = AppendMsgChecksum(nil, want)
+		_, err = RemoveMsgBodyChecksum(8, 3, OpMsg, wm[16:])
+		assert.ErrorIs(t, err, ErrMsgChecksumMismatch, "expected a mismatch for a different request ID")
+	})
+	t.Run("corrupted", func(t *testing.T) {
+		t.Parallel()
+
+		wm := AppendMsgChecksum(nil, msg())
+		wm[len(wm)-6] ^= 0xFF
+
+		got, err := RemoveMsgChecksum(wm)
+		assert.ErrorIs(t, err, ErrMsgChecksumMismatch, "expected a checksum mismatch")
+		assert.Nil(t, got, "expected no message")
+	})
+	t.Run("no checksum", func(t *testing.T) {
+		t.Parallel()
+
+		want := msg()
+		got, err := RemoveMsgChecksum(msg())
+		assert.NoError(t, err, "RemoveMsgChecksum error")
+		assert.Equal(t, want, got, "expected the message to be unchanged")
+	})
+	t.Run("not an OP_MSG", func(t *testing.T) {
+		t.Parallel()
+
+		wm := AppendHeader(nil, 20, 1, 0, OpReply)
+		wm = binary.LittleEndian.AppendUint32(wm, uint32(ChecksumPresent))
+
+		assert.Equal(t, wm, AppendMsgChecksum(nil, wm), "expected the message to be unchanged")
+		got, err := RemoveMsgChecksum(wm)
+		assert.NoError(t, err, "RemoveMsgChecksum error")
+		assert.Equal(t, wm, got, "expected the message to be unchanged")
+	})
+}